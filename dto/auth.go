@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/google/uuid"
+
 type AuthDTO struct {
 	Email     string `json:"email"`
 	FirstName string `json:"firstname"`
@@ -10,4 +12,6 @@ type AuthDTO struct {
 type LoginResponseDTO struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+
+	UserID uuid.UUID `json:"-"`
 }
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+)
+
+type CartDTO struct {
+	DTO
+
+	UserUUID   *uuid.UUID    `json:"user_id"`
+	Token      string        `json:"token"`
+	Items      []CartItemDTO `json:"items"`
+	TotalPrice money.Money   `json:"total_price"`
+}
+
+// UnitPrice and Price are worked out from the product's current price, Warning says when it cannot be ordered as it is.
+type CartItemDTO struct {
+	DTO
+
+	CartUUID    uuid.UUID   `json:"cart_id"`
+	ProductUUID uuid.UUID   `json:"product_id"`
+	VariantUUID *uuid.UUID  `json:"variant_id"`
+	Quantity    int         `json:"quantity"`
+	UnitPrice   money.Money `json:"unit_price"`
+	Price       money.Money `json:"price"`
+	Warning     string      `json:"warning"`
+
+	Product ProductDTO         `json:"product"`
+	Variant *ProductVariantDTO `json:"variant"`
+}
@@ -1,25 +1,105 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+)
 
 type ProductDTO struct {
 	DTO
 
-	Slug          string  `json:"slug"`
-	Name          string  `json:"name"`
-	Description   string  `json:"description"`
-	Specification string  `json:"specification"`
-	Price         float64 `json:"price"`
-	SlashPrice    float64 `json:"slash_price"`
-	Stock         int     `json:"stock"`
-	Sales         int     `json:"sales"`
+	Slug          string      `json:"slug"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description"`
+	Specification string      `json:"specification"`
+	Price         money.Money `json:"price"`
+	SlashPrice    money.Money `json:"slash_price"`
+	Stock         int         `json:"stock"`
+	Brand         string      `json:"brand"`
+	Weight        float64     `json:"weight"`
+	Sales         int         `json:"sales"`
+	Rating        float64     `json:"rating"`
+	ReviewCount   int         `json:"review_count"`
+
+	Images        []ImageDTO    `json:"images"`
+	CategoryUUIDs []uuid.UUID   `json:"category_ids"`
+	Categories    []CategoryDTO `json:"categories"`
+
+	Options  []ProductOptionDTO  `json:"options"`
+	Variants []ProductVariantDTO `json:"variants"`
+}
+
+type ProductOptionDTO struct {
+	DTO
+
+	ProductUUID uuid.UUID               `json:"product_id"`
+	Name        string                  `json:"name"`
+	Position    int                     `json:"position"`
+	Values      []ProductOptionValueDTO `json:"values"`
+}
+
+type ProductOptionValueDTO struct {
+	DTO
+
+	OptionUUID uuid.UUID `json:"option_id"`
+	Value      string    `json:"value"`
+	Position   int       `json:"position"`
+}
+
+// Price and SlashPrice are nil when the variant sells at the product's prices.
+type ProductVariantDTO struct {
+	DTO
+
+	ProductUUID uuid.UUID               `json:"product_id"`
+	SKU         string                  `json:"sku"`
+	Price       *money.Money            `json:"price"`
+	SlashPrice  *money.Money            `json:"slash_price"`
+	Stock       int                     `json:"stock"`
+	Values      []ProductOptionValueDTO `json:"values"`
+	Images      []ImageDTO              `json:"images"`
+}
 
-	Images []ImageDTO `json:"images"`
+// UserFirstName and UserLastName name the reviewer, the rest of the user is never shown with a review.
+type ProductReviewDTO struct {
+	DTO
+
+	ProductUUID   uuid.UUID `json:"product_id"`
+	UserUUID      uuid.UUID `json:"user_id"`
+	UserFirstName string    `json:"user_first_name"`
+	UserLastName  string    `json:"user_last_name"`
+	Rating        int       `json:"rating"`
+	Comment       string    `json:"comment"`
+	IsHidden      bool      `json:"is_hidden"`
+}
+
+// Children is only filled in when the categories are returned as a tree.
+type CategoryDTO struct {
+	DTO
+
+	Slug        string        `json:"slug"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	ParentUUID  *uuid.UUID    `json:"parent_id"`
+	Children    []CategoryDTO `json:"children"`
 }
 
 type ImageDTO struct {
 	DTO
 
-	ProductUUID uuid.UUID `json:"product_id"`
-	Key         string    `json:"key"`
+	ProductUUID uuid.UUID  `json:"product_id"`
+	VariantUUID *uuid.UUID `json:"variant_id"`
+	Key         string     `json:"key"`
+}
+
+type InventoryMovementDTO struct {
+	DTO
+
+	ProductUUID   uuid.UUID  `json:"product_id"`
+	VariantUUID   *uuid.UUID `json:"variant_id"`
+	OrderUUID     uuid.UUID  `json:"order_id"`
+	OrderItemUUID uuid.UUID  `json:"order_item_id"`
+	Reference     string     `json:"reference"`
+	Type          string     `json:"type"`
+	Quantity      int        `json:"quantity"`
 }
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type OutboxEmailDTO struct {
+	DTO
+
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Template      string     `json:"template"`
+	Variables     string     `json:"variables"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+}
@@ -1,19 +1,109 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 )
 
 type TransactionDTO struct {
 	DTO
 
-	UserID      uuid.UUID `json:"user_id"`
-	Amount      float64   `json:"amount"`
-	Type        string    `json:"type"`
-	Reference   string    `json:"reference"`
-	Description string    `json:"description"`
-	ShortDesc   string    `json:"short_desc"`
-	Status      string    `json:"status"`
-	Method      string    `json:"method"`
-	Vendor      string    `json:"vendor"`
+	UserID      uuid.UUID   `json:"user_id"`
+	Amount      money.Money `json:"amount"`
+	Type        string      `json:"type"`
+	Reference   string      `json:"reference"`
+	Description string      `json:"description"`
+	ShortDesc   string      `json:"short_desc"`
+	Status      string      `json:"status"`
+	Method      string      `json:"method"`
+	Vendor      string      `json:"vendor"`
+	ParentID    *uuid.UUID  `json:"parent_id"`
+}
+
+// CurrencyRateDTO is what one unit of Currency costs in money.BaseCurrency.
+type CurrencyRateDTO struct {
+	DTO
+
+	Currency string  `json:"currency"`
+	Rate     float64 `json:"rate"`
+}
+
+type LedgerAccountDTO struct {
+	DTO
+
+	Code   string     `json:"code"`
+	Name   string     `json:"name"`
+	Type   string     `json:"type"`
+	UserID *uuid.UUID `json:"user_id"`
+}
+
+// JournalEntryDTO is posted with the accounts of its postings, accounts the ledger does not have yet are opened.
+type JournalEntryDTO struct {
+	DTO
+
+	TransactionID *uuid.UUID         `json:"transaction_id"`
+	Description   string             `json:"description"`
+	Postings      []LedgerPostingDTO `json:"postings"`
+}
+
+// LedgerPostingDTO moves Amount in or out of Account, debits are positive and credits negative.
+type LedgerPostingDTO struct {
+	DTO
+
+	Account LedgerAccountDTO `json:"account"`
+	Amount  money.Money      `json:"amount"`
+}
+
+// TrialBalanceLineDTO is what was debited and credited to one account in one currency.
+type TrialBalanceLineDTO struct {
+	Account LedgerAccountDTO `json:"account"`
+	Debit   money.Money      `json:"debit"`
+	Credit  money.Money      `json:"credit"`
+}
+
+// AccountStatementDTO is a page of an account's postings in one currency, with its balance before and after the period.
+type AccountStatementDTO struct {
+	Account        LedgerAccountDTO          `json:"account"`
+	OpeningBalance money.Money               `json:"opening_balance"`
+	ClosingBalance money.Money               `json:"closing_balance"`
+	Lines          []AccountStatementLineDTO `json:"lines"`
+}
+
+type AccountStatementLineDTO struct {
+	EntryID              uuid.UUID   `json:"entry_id"`
+	Description          string      `json:"description"`
+	TransactionReference string      `json:"transaction_reference"`
+	Amount               money.Money `json:"amount"`
+	CreatedAt            time.Time   `json:"created_at"`
+}
+
+// ReconciliationReportDTO is one run of reconciliation, Errors has a line for each gateway that could not be listed.
+type ReconciliationReportDTO struct {
+	DTO
+
+	FromDate      time.Time               `json:"from_date"`
+	ToDate        time.Time               `json:"to_date"`
+	Matched       int                     `json:"matched"`
+	Discrepancies int                     `json:"discrepancies"`
+	Healed        int                     `json:"healed"`
+	Errors        []string                `json:"errors"`
+	Items         []ReconciliationItemDTO `json:"items"`
+}
+
+type ReconciliationItemDTO struct {
+	DTO
+
+	Gateway        string      `json:"gateway"`
+	Reference      string      `json:"reference"`
+	TransactionID  *uuid.UUID  `json:"transaction_id"`
+	Kind           string      `json:"kind"`
+	LocalStatus    string      `json:"local_status"`
+	LocalAmount    money.Money `json:"local_amount"`
+	ProviderStatus string      `json:"provider_status"`
+	ProviderAmount money.Money `json:"provider_amount"`
+	Healed         bool        `json:"healed"`
+	Note           string      `json:"note"`
 }
@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+)
+
+type CountryDTO struct {
+	DTO
+
+	Name string `json:"name"`
+}
+
+type StateDTO struct {
+	DTO
+
+	CountryUUID uuid.UUID `json:"country_id"`
+	Name        string    `json:"name"`
+
+	Country CountryDTO `json:"country"`
+}
+
+type CityDTO struct {
+	DTO
+
+	StateUUID uuid.UUID   `json:"state_id"`
+	Name      string      `json:"name"`
+	Price     money.Money `json:"price"`
+
+	State StateDTO `json:"state"`
+}
+
+type ShippingAddressDTO struct {
+	DTO
+
+	UserUUID        uuid.UUID `json:"user_id"`
+	FirstName       string    `json:"first_name"`
+	LastName        string    `json:"last_name"`
+	Phone           string    `json:"phone"`
+	AlternatePhone  string    `json:"alternate_phone"`
+	Address         string    `json:"address"`
+	CityUUID        uuid.UUID `json:"city_id"`
+	StateUUID       uuid.UUID `json:"state_id"`
+	ClosestLandmark string    `json:"closest_landmark"`
+	IsDefault       bool      `json:"is_default"`
+
+	City  CityDTO  `json:"city"`
+	State StateDTO `json:"state"`
+}
+
+type OrderShippingAddressDTO struct {
+	FirstName       string `json:"first_name"`
+	LastName        string `json:"last_name"`
+	Phone           string `json:"phone"`
+	AlternatePhone  string `json:"alternate_phone"`
+	Address         string `json:"address"`
+	City            string `json:"city"`
+	State           string `json:"state"`
+	Country         string `json:"country"`
+	ClosestLandmark string `json:"closest_landmark"`
+}
+
+type ShippingTypeDTO struct {
+	DTO
+
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price"`
+	PricePerKg  money.Money `json:"price_per_kg"`
+	IsActive    bool        `json:"is_active"`
+
+	Rates []ShippingRateDTO `json:"rates"`
+}
+
+type ShippingRateDTO struct {
+	DTO
+
+	ShippingTypeUUID uuid.UUID   `json:"shipping_type_id"`
+	StateUUID        uuid.UUID   `json:"state_id"`
+	Price            money.Money `json:"price"`
+}
@@ -1,20 +1,35 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+)
 
 type OrderDTO struct {
 	DTO
 
-	UserID        uuid.UUID  `json:"user_id"`
-	TransactionID uuid.UUID  `json:"transaction_id"`
-	CouponID      *uuid.UUID `json:"coupon_id"`
-	PaymentMethod string     `json:"payment_method"`
-	Reference     string     `json:"reference"`
-	TotalPrice    float64    `json:"total_price"`
-	StatusUUID    uuid.UUID  `json:"status_id"`
+	UserID              uuid.UUID   `json:"user_id"`
+	TransactionID       uuid.UUID   `json:"transaction_id"`
+	CouponID            *uuid.UUID  `json:"coupon_id"`
+	PaymentMethod       string      `json:"payment_method"`
+	Reference           string      `json:"reference"`
+	TotalPrice          money.Money `json:"total_price"`
+	Discount            money.Money `json:"discount"`
+	ExchangeRate        float64     `json:"exchange_rate"` // the price of one unit of the order's currency in money.BaseCurrency
+	WalletAmount        money.Money `json:"wallet_amount"`
+	WalletTransactionID *uuid.UUID  `json:"wallet_transaction_id"`
+	StatusUUID          uuid.UUID   `json:"status_id"`
+
+	ShippingAddressID *uuid.UUID              `json:"shipping_address_id"`
+	ShippingAddress   OrderShippingAddressDTO `json:"shipping_address"`
+	ShippingTypeID    *uuid.UUID              `json:"shipping_type_id"`
 
 	User          UserDTO                 `json:"user"`
 	Items         []OrderItemDTO          `json:"items"`
+	Charges       []OrderChargeDTO        `json:"charges"`
 	Transaction   TransactionDTO          `json:"transaction"`
 	Status        OrderStatusDTO          `json:"status"`
 	StatusHistory []OrderStatusHistoryDTO `json:"status_history"`
@@ -23,12 +38,24 @@ type OrderDTO struct {
 type OrderItemDTO struct {
 	DTO
 
-	OrderUUID   uuid.UUID `json:"order_id"`
-	ProductUUID uuid.UUID `json:"product_id"`
-	Quantity    int       `json:"quantity"`
-	Price       float64   `json:"price"`
+	OrderUUID   uuid.UUID   `json:"order_id"`
+	ProductUUID uuid.UUID   `json:"product_id"`
+	VariantUUID *uuid.UUID  `json:"variant_id"`
+	Quantity    int         `json:"quantity"`
+	Price       money.Money `json:"price"`
+	Discount    money.Money `json:"discount"`
+
+	Product ProductDTO         `json:"product"`
+	Variant *ProductVariantDTO `json:"variant"`
+}
+
+type OrderChargeDTO struct {
+	DTO
 
-	Product ProductDTO `json:"product"`
+	OrderUUID uuid.UUID   `json:"order_id"`
+	Type      string      `json:"type"`
+	Name      string      `json:"name"`
+	Amount    money.Money `json:"amount"`
 }
 
 type OrderStatusDTO struct {
@@ -49,13 +76,76 @@ type OrderStatusHistoryDTO struct {
 type CreateOrderDTO struct {
 	UserID            uuid.UUID            `json:"user_id"`
 	ShippingAddressID uuid.UUID            `json:"shipping_address_id"`
-	CouponID          *uuid.UUID           `json:"coupon_id"`
+	CouponCode        string               `json:"coupon_code"`
 	ShippingTypeID    uuid.UUID            `json:"shipping_type_id"`
 	PaymentMethod     string               `json:"payment_method"`
+	Currency          string               `json:"currency"`
+	WalletAmount      money.Money          `json:"wallet_amount"` // paid from the wallet, the gateway takes the rest
+	FromCart          bool                 `json:"from_cart"`
 	Items             []CreateOrderItemDTO `json:"items"`
 }
 
+// VariantUUID is required for products with variants.
 type CreateOrderItemDTO struct {
 	ProductUUID string `json:"product_id"`
+	VariantUUID string `json:"variant_id"`
 	Quantity    int    `json:"quantity"`
 }
+
+type OrderTransitionDTO struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Guards []string `json:"guards"`
+}
+
+type RefundDTO struct {
+	DTO
+
+	OrderUUID        uuid.UUID   `json:"order_id"`
+	TransactionUUID  uuid.UUID   `json:"transaction_id"`
+	Amount           money.Money `json:"amount"`
+	Status           string      `json:"status"`
+	GatewayReference string      `json:"gateway_reference"`
+	Reason           string      `json:"reason"`
+
+	Items []RefundItemDTO `json:"items"`
+}
+
+type RefundItemDTO struct {
+	DTO
+
+	RefundUUID      uuid.UUID   `json:"refund_id"`
+	OrderItemUUID   *uuid.UUID  `json:"order_item_id"`
+	OrderChargeUUID *uuid.UUID  `json:"order_charge_id"`
+	Quantity        int         `json:"quantity"`
+	Amount          money.Money `json:"amount"`
+}
+
+// RefundCharges also refunds the order's charges, like its shipping fee. A full refund always does.
+type CreateRefundDTO struct {
+	Reason        string                `json:"reason"`
+	RefundCharges bool                  `json:"refund_charges"`
+	ToWallet      bool                  `json:"to_wallet"`
+	Items         []CreateRefundItemDTO `json:"items"`
+}
+
+type CreateRefundItemDTO struct {
+	OrderItemUUID uuid.UUID `json:"order_item_id"`
+	Quantity      int       `json:"quantity"`
+}
+
+// Value is in hundredths like models.Coupon, basis points or minor units.
+type CouponDTO struct {
+	DTO
+
+	Code              string      `json:"code"`
+	Description       string      `json:"description"`
+	Type              string      `json:"type"`
+	Value             int64       `json:"value"`
+	MinOrderAmount    money.Money `json:"min_order_amount"`
+	ExpiresAt         *time.Time  `json:"expires_at"`
+	UsageLimit        int         `json:"usage_limit"`
+	UsageLimitPerUser int         `json:"usage_limit_per_user"`
+	IsActive          bool        `json:"is_active"`
+	ProductUUIDs      []uuid.UUID `json:"product_ids"`
+}
@@ -6,6 +6,7 @@ import "time"
 type InitializeFlutterwaveRequest struct {
 	TxRef          string         `json:"tx_ref"`
 	Amount         float64        `json:"amount"`
+	Currency       string         `json:"currency"`
 	RedirectURL    string         `json:"redirect_url"`
 	Customer       Customer       `json:"customer"`
 	Customizations Customizations `json:"customizations"`
@@ -66,3 +67,38 @@ type VerifyFlutterwaveResponse struct {
 		} `json:"customer"`
 	} `json:"data"`
 }
+
+type RefundFlutterwaveRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+type RefundFlutterwaveResponse struct {
+	Status  string `json:"status"` // success || error
+	Message string `json:"message"`
+	Data    struct {
+		ID             int     `json:"id"`
+		AmountRefunded float64 `json:"amount_refunded"`
+		Status         string  `json:"status"`
+		FlwRef         string  `json:"flw_ref"`
+	} `json:"data"`
+}
+
+type ListFlutterwaveTransactionsResponse struct {
+	Status  string `json:"status"` // success || error
+	Message string `json:"message"`
+	Meta    struct {
+		PageInfo struct {
+			Total       int `json:"total"`
+			CurrentPage int `json:"current_page"`
+			TotalPages  int `json:"total_pages"`
+		} `json:"page_info"`
+	} `json:"meta"`
+	Data []struct {
+		ID        int       `json:"id"`
+		TxRef     string    `json:"tx_ref"`
+		Amount    float64   `json:"amount"`
+		Currency  string    `json:"currency"`
+		Status    string    `json:"status"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"data"`
+}
@@ -1,14 +1,21 @@
 package payment_gateway_dto
 
+import (
+	"time"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+)
+
 type PaymentInitializationDTO struct {
-	Amount    float64 `json:"amount"`
-	Email     string  `json:"email"`
-	Reference string  `json:"reference"`
-	Gateway   string  `json:"gateway"`
+	Amount    money.Money `json:"amount"`
+	Email     string      `json:"email"`
+	Reference string      `json:"reference"`
+	Gateway   string      `json:"gateway"`
 }
 
 type PaymentInitializationResponseDTO struct {
 	Status     bool   `json:"status"`
+	Message    string `json:"message"`
 	PaymentURL string `json:"payment_url"`
 }
 
@@ -17,3 +24,32 @@ type PaymentVerifyResponseDTO struct {
 	PaymentStatus string `json:"payment_status"`
 	Message       string `json:"message"`
 }
+
+// WebhookEventDTO is a payment event when PaymentStatus is set and a refund event when RefundStatus is set.
+type WebhookEventDTO struct {
+	Event           string `json:"event"`
+	Reference       string `json:"reference"`
+	PaymentStatus   string `json:"payment_status"`
+	RefundReference string `json:"refund_reference"`
+	RefundStatus    string `json:"refund_status"`
+}
+
+type RefundDTO struct {
+	Reference string      `json:"reference"`
+	Amount    money.Money `json:"amount"`
+}
+
+type RefundResponseDTO struct {
+	Status           bool   `json:"status"`
+	RefundStatus     string `json:"refund_status"`
+	GatewayReference string `json:"gateway_reference"`
+	Message          string `json:"message"`
+}
+
+// GatewayTransactionDTO is a payment as the gateway sees it, Status is mapped to our transaction statuses.
+type GatewayTransactionDTO struct {
+	Reference string      `json:"reference"`
+	Amount    money.Money `json:"amount"`
+	Status    string      `json:"status"`
+	CreatedAt time.Time   `json:"created_at"`
+}
@@ -2,10 +2,11 @@ package payment_gateway_dto
 
 import "time"
 
+// Paystack takes amounts in the minor unit of the currency, kobo for NGN.
 type Paystack struct {
-	Amount    float64 `json:"amount"`
-	Email     string  `json:"email"`
-	Reference string  `json:"reference"`
+	Amount    int64  `json:"amount"`
+	Email     string `json:"email"`
+	Reference string `json:"reference"`
 }
 
 type InitializePaystackResponse struct {
@@ -42,3 +43,31 @@ type VerifyPaystackResponse struct {
 		TransactionDate time.Time `json:"transaction_date"`
 	}
 }
+
+type RefundPaystackResponse struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+	Data    struct {
+		ID     int    `json:"id"`
+		Amount int    `json:"amount"`
+		Status string `json:"status"`
+	} `json:"data"`
+}
+
+type ListPaystackTransactionsResponse struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+	Data    []struct {
+		ID        int       `json:"id"`
+		Reference string    `json:"reference"`
+		Amount    int64     `json:"amount"`
+		Currency  string    `json:"currency"`
+		Status    string    `json:"status"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"data"`
+	Meta struct {
+		Total     int `json:"total"`
+		Page      int `json:"page"`
+		PageCount int `json:"pageCount"`
+	} `json:"meta"`
+}
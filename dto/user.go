@@ -3,13 +3,14 @@ package dto
 type UserDTO struct {
 	DTO
 
-	FirstName       string `json:"first_name"`
-	LastName        string `json:"last_name"`
-	Email           string `json:"email"`
-	ReferralCode    string `json:"referral_code"`
-	IsEmailVerified bool   `json:"is_email_verified"`
-	Password        string `json:"password"`
-	Role            string `json:"role"`
+	FirstName          string `json:"first_name"`
+	LastName           string `json:"last_name"`
+	Email              string `json:"email"`
+	ReferralCode       string `json:"referral_code"`
+	IsEmailVerified    bool   `json:"is_email_verified"`
+	Password           string `json:"password"`
+	Role               string `json:"role"`
+	NotifyOrderUpdates bool   `json:"notify_order_updates"`
 }
 
 type VerificationCodeDTO struct {
@@ -0,0 +1,210 @@
+package core_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
+	core_validator "github.com/developer-afo/instashop-ecommerce-api/validator/core"
+)
+
+type categoryHandler struct {
+	categoryService core_service.CategoryServiceInterface
+	validator       core_validator.CategoryValidator
+}
+
+type CategoryHandlerInterface interface {
+	GetCategoryTree(c *fiber.Ctx) error
+	CreateCategory(c *fiber.Ctx) error
+	UpdateCategory(c *fiber.Ctx) error
+	DeleteCategory(c *fiber.Ctx) error
+}
+
+func NewCategoryHandler(categoryService core_service.CategoryServiceInterface) CategoryHandlerInterface {
+	return &categoryHandler{categoryService: categoryService}
+}
+
+func ConvertCategoryDTOToResponse(categoryDto dto.CategoryDTO) response.CategoryResponse {
+	var resp response.CategoryResponse
+
+	resp.ID = categoryDto.ID
+	resp.Slug = categoryDto.Slug
+	resp.Name = categoryDto.Name
+	resp.Description = categoryDto.Description
+	resp.ParentID = categoryDto.ParentUUID
+	resp.Children = []response.CategoryResponse{}
+	for _, child := range categoryDto.Children {
+		resp.Children = append(resp.Children, ConvertCategoryDTOToResponse(child))
+	}
+
+	return resp
+}
+
+// ConvertCategoryRequestToDTO expects a validated request.
+func ConvertCategoryRequestToDTO(categoryRequest request.CreateCategoryRequest) dto.CategoryDTO {
+	var categoryDto dto.CategoryDTO
+
+	categoryDto.Name = categoryRequest.Name
+	categoryDto.Description = categoryRequest.Description
+
+	if categoryRequest.ParentID != "" {
+		parentId := uuid.MustParse(categoryRequest.ParentID)
+		categoryDto.ParentUUID = &parentId
+	}
+
+	return categoryDto
+}
+
+// categoryError writes the response for an error returned by the category service.
+func categoryError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Category not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, core_service.ErrCategoryParentNotFound), errors.Is(err, core_service.ErrCategoryParentInvalid):
+		resp.Status = constants.ClientUnProcessableEntity
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	case errors.Is(err, core_service.ErrCategoryHasChildren):
+		resp.Status = constants.ClientErrorBadRequest
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
+	resp.Status = constants.ServerErrorInternal
+
+	return c.Status(http.StatusInternalServerError).JSON(resp)
+}
+
+func (h *categoryHandler) GetCategoryTree(c *fiber.Ctx) error {
+	var resp response.Response
+	categoryResponses := []response.CategoryResponse{}
+
+	categories, err := h.categoryService.FindCategoryTree()
+
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	for _, category := range categories {
+		categoryResponses = append(categoryResponses, ConvertCategoryDTOToResponse(category))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"categories": categoryResponses}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *categoryHandler) CreateCategory(c *fiber.Ctx) error {
+	var resp response.Response
+	var categoryRequest request.CreateCategoryRequest
+
+	if err := c.BodyParser(&categoryRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CategoryValidate(categoryRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	category, err := h.categoryService.CreateCategory(ConvertCategoryRequestToDTO(categoryRequest))
+
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "Category created successfully"
+	resp.Data = map[string]interface{}{"category": ConvertCategoryDTOToResponse(category)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *categoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	var resp response.Response
+	var categoryRequest request.UpdateCategoryRequest
+
+	categoryId, err := uuid.Parse(c.Params("category_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Category ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&categoryRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CategoryValidate(categoryRequest.CreateCategoryRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	categoryDto := ConvertCategoryRequestToDTO(categoryRequest.CreateCategoryRequest)
+	categoryDto.ID = categoryId
+
+	category, err := h.categoryService.UpdateCategory(categoryDto)
+
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Category updated successfully"
+	resp.Data = map[string]interface{}{"category": ConvertCategoryDTOToResponse(category)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *categoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	var resp response.Response
+
+	categoryId, err := uuid.Parse(c.Params("category_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Category ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := h.categoryService.DeleteCategory(categoryId); err != nil {
+		return categoryError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Category deleted successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -1,8 +1,10 @@
 package core_handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -10,17 +12,21 @@ import (
 	"github.com/developer-afo/instashop-ecommerce-api/dto"
 	"github.com/developer-afo/instashop-ecommerce-api/handler"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
 	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
 	coreRepository "github.com/developer-afo/instashop-ecommerce-api/repository/core"
 	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
 	core_validator "github.com/developer-afo/instashop-ecommerce-api/validator/core"
 )
 
 type productHandler struct {
-	productService core_service.ProductServiceInterface
-	imageService   core_service.ImageServiceInterface
-	validator      core_validator.ProductValidator
+	productService   core_service.ProductServiceInterface
+	imageService     core_service.ImageServiceInterface
+	inventoryService core_service.InventoryServiceInterface
+	currencyService  finance_service.CurrencyServiceInterface
+	validator        core_validator.ProductValidator
 }
 
 type ProductHandlerInterface interface {
@@ -32,29 +38,115 @@ type ProductHandlerInterface interface {
 	FindImagesByProductId(c *fiber.Ctx) error
 	DeleteImage(c *fiber.Ctx) error
 	CreateImage(c *fiber.Ctx) error
+	FindInventoryMovementsByProductId(c *fiber.Ctx) error
 }
 
 func NewProductHandler(
 	productService core_service.ProductServiceInterface,
 	imageService core_service.ImageServiceInterface,
+	inventoryService core_service.InventoryServiceInterface,
+	currencyService finance_service.CurrencyServiceInterface,
 ) ProductHandlerInterface {
 	return &productHandler{
-		productService: productService,
-		imageService:   imageService,
+		productService:   productService,
+		imageService:     imageService,
+		inventoryService: inventoryService,
+		currencyService:  currencyService,
 	}
 }
 
-func (h *productHandler) GeneratePageable(c *fiber.Ctx) (pageable coreRepository.ProductPageable) {
+// productCurrency is a currency products are shown in, read from the currency query.
+type productCurrency struct {
+	currency string
+	rate     float64 // the price of one unit of currency in money.BaseCurrency
+}
+
+// displayCurrency reads the currency query, the base currency when it is not set.
+func (h *productHandler) displayCurrency(c *fiber.Ctx) (productCurrency, error) {
+	currency := finance_service.CurrencyCode(c.Query("currency", ""))
+
+	rate, err := h.currencyService.ExchangeRate(currency)
+
+	return productCurrency{currency: currency, rate: rate}, err
+}
+
+// toBase reads a price given in the display currency as a price in money.BaseCurrency.
+func (currency productCurrency) toBase(major float64) *money.Money {
+	amount := money.FromMajor(major, currency.currency).Convert(money.BaseCurrency, 1/currency.rate)
+
+	return &amount
+}
+
+// fromBase is a price in money.BaseCurrency in the display currency.
+func (currency productCurrency) fromBase(amount money.Money) money.Money {
+	return amount.Convert(currency.currency, currency.rate)
+}
+
+// convertProductCurrency is productDto with its prices, and the prices of its variants, in currency.
+func convertProductCurrency(productDto dto.ProductDTO, currency productCurrency) dto.ProductDTO {
+	productDto.Price = currency.fromBase(productDto.Price)
+	productDto.SlashPrice = currency.fromBase(productDto.SlashPrice)
+
+	variants := []dto.ProductVariantDTO{}
+	for _, variant := range productDto.Variants {
+		if variant.Price != nil {
+			price := currency.fromBase(*variant.Price)
+			variant.Price = &price
+		}
+
+		if variant.SlashPrice != nil {
+			slashPrice := currency.fromBase(*variant.SlashPrice)
+			variant.SlashPrice = &slashPrice
+		}
+
+		variants = append(variants, variant)
+	}
+	productDto.Variants = variants
+
+	return productDto
+}
+
+// currencyError writes the response for a currency query that cannot be used.
+func currencyError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	if errors.Is(err, finance_service.ErrCurrencyNotSupported) {
+		resp.Status = constants.CurrencyNotSupported
 
-	basePageable := handler.GeneratePageable(c)
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
 
-	pageable.Page = basePageable.Page
-	pageable.Size = basePageable.Size
-	pageable.SortBy = basePageable.SortBy
-	pageable.SortDirection = basePageable.SortDirection
-	pageable.Search = basePageable.Search
+	resp.Status = constants.ServerErrorInternal
 
-	return pageable
+	return c.Status(http.StatusInternalServerError).JSON(resp)
+}
+
+// GeneratePageable reads the price filters in currency.
+func (h *productHandler) GeneratePageable(c *fiber.Ctx, currency productCurrency) (pageable coreRepository.ProductPageable, err error) {
+
+	basePageable, err := handler.GeneratePageable(c, coreRepository.ProductSortFields)
+
+	if err != nil {
+		return pageable, err
+	}
+
+	pageable.Pageable = basePageable
+	pageable.Category = c.Query("category", "")
+	pageable.Brand = c.Query("brand", "")
+	pageable.InStock = c.QueryBool("in_stock", false)
+	pageable.OnSale = c.QueryBool("on_sale", false)
+
+	if minPrice, err := strconv.ParseFloat(c.Query("min_price", ""), 64); err == nil && minPrice >= 0 {
+		pageable.MinPrice = currency.toBase(minPrice)
+	}
+
+	if maxPrice, err := strconv.ParseFloat(c.Query("max_price", ""), 64); err == nil && maxPrice >= 0 {
+		pageable.MaxPrice = currency.toBase(maxPrice)
+	}
+
+	return pageable, nil
 }
 
 func (handler *productHandler) ConvertToProductResponse(productDto dto.ProductDTO) response.ProductResponse {
@@ -65,10 +157,15 @@ func (handler *productHandler) ConvertToProductResponse(productDto dto.ProductDT
 	productResp.Name = productDto.Name
 	productResp.Description = productDto.Description
 	productResp.Specification = productDto.Specification
-	productResp.Price = productDto.Price
-	productResp.SlashPrice = productDto.SlashPrice
+	productResp.Price = productDto.Price.Major()
+	productResp.SlashPrice = productDto.SlashPrice.Major()
+	productResp.Currency = productDto.Price.Currency
 	productResp.Stock = productDto.Stock
+	productResp.Brand = productDto.Brand
+	productResp.Weight = productDto.Weight
 	productResp.Sales = productDto.Sales
+	productResp.Rating = productDto.Rating
+	productResp.ReviewCount = productDto.ReviewCount
 	productResp.CreatedAt = productDto.CreatedAt
 
 	for _, image := range productDto.Images {
@@ -77,12 +174,40 @@ func (handler *productHandler) ConvertToProductResponse(productDto dto.ProductDT
 		})
 	}
 
+	for _, category := range productDto.Categories {
+		productResp.Categories = append(productResp.Categories, response.ProductCategoryResponse{
+			ID:   category.ID,
+			Slug: category.Slug,
+			Name: category.Name,
+		})
+	}
+
+	for _, option := range productDto.Options {
+		productResp.Options = append(productResp.Options, ConvertOptionDTOToResponse(option))
+	}
+
+	for _, variant := range productDto.Variants {
+		productResp.Variants = append(productResp.Variants, ConvertVariantDTOToResponse(productDto, variant))
+	}
+
 	return productResp
 }
 
+// FindAllProducts shows the prices in the currency query, the price filters are read in it too.
 func (handler *productHandler) FindAllProducts(c *fiber.Ctx) error {
 	var resp response.Response
-	pageable := handler.GeneratePageable(c)
+
+	currency, err := handler.displayCurrency(c)
+	if err != nil {
+		return currencyError(c, err)
+	}
+
+	pageable, err := handler.GeneratePageable(c, currency)
+	if err != nil {
+		resp.Status = http.StatusBadRequest
+		resp.Message = err.Error()
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
 
 	products, pagination, err := handler.productService.FindAllProducts(pageable)
 	if err != nil {
@@ -91,15 +216,31 @@ func (handler *productHandler) FindAllProducts(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(resp)
 	}
 
+	facets, err := handler.productService.FindProductFacets(pageable)
+	if err != nil {
+		resp.Status = http.StatusBadRequest
+		resp.Message = err.Error()
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
 	productsResp := []response.ProductResponse{}
 
 	for _, product := range products {
-		productsResp = append(productsResp, handler.ConvertToProductResponse(product))
+		productsResp = append(productsResp, handler.ConvertToProductResponse(convertProductCurrency(product, currency)))
+	}
+
+	for i, facet := range facets.Prices {
+		facets.Prices[i].Min = currency.fromBase(money.FromMajor(facet.Min, money.BaseCurrency)).Major()
+
+		if facet.Max != nil {
+			max := currency.fromBase(money.FromMajor(*facet.Max, money.BaseCurrency)).Major()
+			facets.Prices[i].Max = &max
+		}
 	}
 
 	resp.Status = http.StatusOK
 	resp.Message = "All Products Fetched Successfully"
-	resp.Data = map[string]interface{}{"results": productsResp, "pagination": pagination}
+	resp.Data = map[string]interface{}{"results": productsResp, "pagination": pagination, "facets": facets}
 
 	return c.Status(http.StatusOK).JSON(resp)
 }
@@ -109,6 +250,11 @@ func (handler *productHandler) FindProduct(c *fiber.Ctx) error {
 	var productResp response.ProductResponse
 	productSlug := c.Params("slug")
 
+	currency, err := handler.displayCurrency(c)
+	if err != nil {
+		return currencyError(c, err)
+	}
+
 	product, err := handler.productService.FindProductBySlug(productSlug)
 
 	if err != nil {
@@ -117,7 +263,7 @@ func (handler *productHandler) FindProduct(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(resp)
 	}
 
-	productResp = handler.ConvertToProductResponse(product)
+	productResp = handler.ConvertToProductResponse(convertProductCurrency(product, currency))
 
 	resp.Status = http.StatusOK
 	resp.Message = "Product Fetched Successfully"
@@ -195,9 +341,14 @@ func (handler *productHandler) UpdateProduct(c *fiber.Ctx) error {
 	productDto.Name = updateProductRequest.Name
 	productDto.Description = updateProductRequest.Description
 	productDto.Specification = updateProductRequest.Specification
-	productDto.Price = float64(updateProductRequest.Price)
-	productDto.SlashPrice = float64(updateProductRequest.SlashPrice)
+	productDto.Price = money.FromMajor(float64(updateProductRequest.Price), money.BaseCurrency)
+	productDto.SlashPrice = money.FromMajor(float64(updateProductRequest.SlashPrice), money.BaseCurrency)
 	productDto.Stock = updateProductRequest.Stock
+	productDto.Brand = updateProductRequest.Brand
+	productDto.Weight = updateProductRequest.Weight
+	for _, categoryId := range updateProductRequest.CategoryIDs {
+		productDto.CategoryUUIDs = append(productDto.CategoryUUIDs, uuid.MustParse(categoryId))
+	}
 
 	_, err = handler.productService.UpdateProduct(productDto)
 
@@ -327,3 +478,34 @@ func (h *productHandler) CreateImage(c *fiber.Ctx) error {
 
 	return c.Status(http.StatusOK).JSON(resp)
 }
+
+func (h *productHandler) FindInventoryMovementsByProductId(c *fiber.Ctx) error {
+	var resp response.Response
+	movementsResp := []response.InventoryMovementResponse{}
+	productID := c.Params("product_id")
+
+	movements, err := h.inventoryService.FindInventoryMovementsByProductId(productID)
+
+	if err != nil {
+		resp.Status = http.StatusBadRequest
+		resp.Message = err.Error()
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	for _, movement := range movements {
+		movementsResp = append(movementsResp, response.InventoryMovementResponse{
+			UUID:      movement.ID,
+			OrderID:   movement.OrderUUID,
+			Reference: movement.Reference,
+			Type:      movement.Type,
+			Quantity:  movement.Quantity,
+			CreatedAt: movement.CreatedAt,
+		})
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = http.StatusText(http.StatusOK)
+	resp.Data = map[string]interface{}{"results": movementsResp}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
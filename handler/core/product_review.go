@@ -0,0 +1,201 @@
+package core_handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	coreRepository "github.com/developer-afo/instashop-ecommerce-api/repository/core"
+	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
+	core_validator "github.com/developer-afo/instashop-ecommerce-api/validator/core"
+)
+
+type productReviewHandler struct {
+	productReviewService core_service.ProductReviewServiceInterface
+	validator            core_validator.ProductReviewValidator
+}
+
+type ProductReviewHandlerInterface interface {
+	FindReviews(c *fiber.Ctx) error
+	FindAllReviews(c *fiber.Ctx) error
+	CreateReview(c *fiber.Ctx) error
+	ModerateReview(c *fiber.Ctx) error
+}
+
+func NewProductReviewHandler(productReviewService core_service.ProductReviewServiceInterface) ProductReviewHandlerInterface {
+	return &productReviewHandler{productReviewService: productReviewService}
+}
+
+// ConvertReviewDTOToResponse names the reviewer by their first name and the initial of their last name.
+func ConvertReviewDTOToResponse(reviewDto dto.ProductReviewDTO) response.ProductReviewResponse {
+	var resp response.ProductReviewResponse
+
+	resp.ID = reviewDto.ID
+	resp.Reviewer = strings.TrimSpace(reviewDto.UserFirstName)
+	if lastName := strings.TrimSpace(reviewDto.UserLastName); lastName != "" {
+		resp.Reviewer += " " + strings.ToUpper(string([]rune(lastName)[:1])) + "."
+	}
+	resp.Rating = reviewDto.Rating
+	resp.Comment = reviewDto.Comment
+	resp.IsHidden = reviewDto.IsHidden
+	resp.CreatedAt = reviewDto.CreatedAt
+
+	return resp
+}
+
+// productReviewError writes the response for an error returned by the product review service.
+func productReviewError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Product not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, core_service.ErrReviewNotFound):
+		resp.Status = constants.ClientErrorResourceNotFound
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, core_service.ErrReviewNotVerifiedBuyer):
+		resp.Status = constants.ClientErrorForbidden
+
+		return c.Status(http.StatusForbidden).JSON(resp)
+	case errors.Is(err, core_service.ErrReviewExists):
+		resp.Status = constants.ClientErrorBadRequest
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
+	resp.Status = constants.ServerErrorInternal
+
+	return c.Status(http.StatusInternalServerError).JSON(resp)
+}
+
+// findReviews writes a page of the reviews of the product, with the hidden ones when includeHidden is set.
+func (h *productReviewHandler) findReviews(c *fiber.Ctx, includeHidden bool) error {
+	var resp response.Response
+
+	basePageable, err := handler.GeneratePageable(c, coreRepository.ProductReviewSortFields)
+
+	if err != nil {
+		return handler.PageableError(c, err)
+	}
+
+	pageable := coreRepository.ProductReviewPageable{Pageable: basePageable, IncludeHidden: includeHidden}
+
+	reviews, pagination, err := h.productReviewService.FindReviews(c.Params("slug"), pageable)
+
+	if err != nil {
+		return productReviewError(c, err)
+	}
+
+	reviewsResp := []response.ProductReviewResponse{}
+	for _, review := range reviews {
+		reviewsResp = append(reviewsResp, ConvertReviewDTOToResponse(review))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Reviews fetched successfully"
+	resp.Data = map[string]interface{}{"results": reviewsResp, "pagination": pagination}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// FindReviews returns the visible reviews of a product.
+func (h *productReviewHandler) FindReviews(c *fiber.Ctx) error {
+	return h.findReviews(c, false)
+}
+
+// FindAllReviews returns the reviews of a product with the hidden ones, for moderation.
+func (h *productReviewHandler) FindAllReviews(c *fiber.Ctx) error {
+	return h.findReviews(c, true)
+}
+
+func (h *productReviewHandler) CreateReview(c *fiber.Ctx) error {
+	var resp response.Response
+	var reviewRequest request.CreateProductReviewRequest
+
+	if err := c.BodyParser(&reviewRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CreateReviewValidate(reviewRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	review, err := h.productReviewService.CreateReview(c.Params("slug"), dto.ProductReviewDTO{
+		UserUUID: handler.GetUserId(c),
+		Rating:   reviewRequest.Rating,
+		Comment:  strings.TrimSpace(reviewRequest.Comment),
+	})
+
+	if err != nil {
+		return productReviewError(c, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "Review created successfully"
+	resp.Data = map[string]interface{}{"review": ConvertReviewDTOToResponse(review)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *productReviewHandler) ModerateReview(c *fiber.Ctx) error {
+	var resp response.Response
+	var moderateRequest request.ModerateProductReviewRequest
+
+	reviewId, err := uuid.Parse(c.Params("review_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Review ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&moderateRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.ModerateReviewValidate(moderateRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	review, err := h.productReviewService.UpdateReviewVisibility(c.Params("slug"), reviewId, *moderateRequest.IsHidden)
+
+	if err != nil {
+		return productReviewError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Review updated successfully"
+	resp.Data = map[string]interface{}{"review": ConvertReviewDTOToResponse(review)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
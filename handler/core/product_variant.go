@@ -0,0 +1,361 @@
+package core_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
+	core_validator "github.com/developer-afo/instashop-ecommerce-api/validator/core"
+)
+
+type productVariantHandler struct {
+	productVariantService core_service.ProductVariantServiceInterface
+	productService        core_service.ProductServiceInterface
+	validator             core_validator.ProductVariantValidator
+}
+
+type ProductVariantHandlerInterface interface {
+	CreateOption(c *fiber.Ctx) error
+	DeleteOption(c *fiber.Ctx) error
+	CreateVariant(c *fiber.Ctx) error
+	UpdateVariant(c *fiber.Ctx) error
+	DeleteVariant(c *fiber.Ctx) error
+}
+
+func NewProductVariantHandler(
+	productVariantService core_service.ProductVariantServiceInterface,
+	productService core_service.ProductServiceInterface,
+) ProductVariantHandlerInterface {
+	return &productVariantHandler{
+		productVariantService: productVariantService,
+		productService:        productService,
+	}
+}
+
+func ConvertOptionDTOToResponse(optionDto dto.ProductOptionDTO) response.ProductOptionResponse {
+	var resp response.ProductOptionResponse
+
+	resp.ID = optionDto.ID
+	resp.Name = optionDto.Name
+	resp.Position = optionDto.Position
+	resp.Values = []response.ProductOptionValueResponse{}
+	for _, value := range optionDto.Values {
+		resp.Values = append(resp.Values, response.ProductOptionValueResponse{ID: value.ID, Value: value.Value})
+	}
+
+	return resp
+}
+
+// ConvertVariantDTOToResponse names the values of the variant with the options of productDto,
+// the names are left out when the product was found without its options.
+func ConvertVariantDTOToResponse(productDto dto.ProductDTO, variantDto dto.ProductVariantDTO) response.ProductVariantResponse {
+	var resp response.ProductVariantResponse
+
+	optionNames := map[uuid.UUID]string{}
+	for _, option := range productDto.Options {
+		optionNames[option.ID] = option.Name
+	}
+
+	resp.ID = variantDto.ID
+	resp.SKU = variantDto.SKU
+	price, slashPrice := core_service.VariantPrices(productDto, &variantDto)
+	resp.Price = price.Major()
+	resp.SlashPrice = slashPrice.Major()
+	resp.Stock = variantDto.Stock
+	resp.Values = []response.VariantValueResponse{}
+	for _, value := range variantDto.Values {
+		resp.Values = append(resp.Values, response.VariantValueResponse{
+			OptionID: value.OptionUUID,
+			Option:   optionNames[value.OptionUUID],
+			ValueID:  value.ID,
+			Value:    value.Value,
+		})
+	}
+	for _, image := range variantDto.Images {
+		resp.Images = append(resp.Images, response.ImageResponse{Key: image.Key})
+	}
+
+	return resp
+}
+
+// ConvertVariantRequestToDTO expects a validated request.
+func ConvertVariantRequestToDTO(productId uuid.UUID, variantRequest request.CreateProductVariantRequest) dto.ProductVariantDTO {
+	var variantDto dto.ProductVariantDTO
+
+	variantDto.ProductUUID = productId
+	variantDto.SKU = variantRequest.SKU
+	variantDto.Price = optionalPrice(variantRequest.Price)
+	variantDto.SlashPrice = optionalPrice(variantRequest.SlashPrice)
+	variantDto.Stock = variantRequest.Stock
+	for _, valueId := range variantRequest.OptionValueIDs {
+		variantDto.Values = append(variantDto.Values, dto.ProductOptionValueDTO{DTO: dto.DTO{ID: uuid.MustParse(valueId)}})
+	}
+	for _, key := range variantRequest.Images {
+		variantDto.Images = append(variantDto.Images, dto.ImageDTO{Key: key})
+	}
+
+	return variantDto
+}
+
+// optionalPrice reads a price a request can leave out, in major units of money.BaseCurrency.
+func optionalPrice(price *float64) *money.Money {
+	if price == nil {
+		return nil
+	}
+
+	amount := money.FromMajor(*price, money.BaseCurrency)
+
+	return &amount
+}
+
+// productVariantError writes the response for an error returned by the product variant service.
+func productVariantError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Product not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, core_service.ErrOptionNotFound), errors.Is(err, core_service.ErrVariantNotFound):
+		resp.Status = constants.ClientErrorResourceNotFound
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, core_service.ErrVariantValuesInvalid):
+		resp.Status = constants.ClientUnProcessableEntity
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	case errors.Is(err, core_service.ErrOptionInUse), errors.Is(err, core_service.ErrProductHasVariants),
+		errors.Is(err, core_service.ErrVariantExists), errors.Is(err, core_service.ErrSKUTaken):
+		resp.Status = constants.ClientErrorBadRequest
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
+	resp.Status = constants.ServerErrorInternal
+
+	return c.Status(http.StatusInternalServerError).JSON(resp)
+}
+
+// variantResponse writes the variant with the option names of its product.
+func (h *productVariantHandler) variantResponse(c *fiber.Ctx, status int, message string, variant dto.ProductVariantDTO) error {
+	var resp response.Response
+
+	product, err := h.productService.FindProductByUUID(variant.ProductUUID.String())
+
+	if err != nil {
+		return productVariantError(c, err)
+	}
+
+	resp.Status = uint16(status)
+	resp.Message = message
+	resp.Data = map[string]interface{}{"variant": ConvertVariantDTOToResponse(product, variant)}
+
+	return c.Status(status).JSON(resp)
+}
+
+func (h *productVariantHandler) CreateOption(c *fiber.Ctx) error {
+	var resp response.Response
+	var optionRequest request.CreateProductOptionRequest
+
+	productId, err := uuid.Parse(c.Params("product_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Product ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&optionRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CreateOptionValidate(optionRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	optionDto := dto.ProductOptionDTO{ProductUUID: productId, Name: optionRequest.Name, Position: optionRequest.Position}
+	for _, value := range optionRequest.Values {
+		optionDto.Values = append(optionDto.Values, dto.ProductOptionValueDTO{Value: value})
+	}
+
+	option, err := h.productVariantService.CreateOption(optionDto)
+
+	if err != nil {
+		return productVariantError(c, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "Option created successfully"
+	resp.Data = map[string]interface{}{"option": ConvertOptionDTOToResponse(option)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *productVariantHandler) DeleteOption(c *fiber.Ctx) error {
+	var resp response.Response
+
+	productId, err := uuid.Parse(c.Params("product_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Product ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	optionId, err := uuid.Parse(c.Params("option_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Option ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := h.productVariantService.DeleteOption(productId, optionId); err != nil {
+		return productVariantError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Option deleted successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *productVariantHandler) CreateVariant(c *fiber.Ctx) error {
+	var resp response.Response
+	var variantRequest request.CreateProductVariantRequest
+
+	productId, err := uuid.Parse(c.Params("product_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Product ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&variantRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.VariantValidate(variantRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	variant, err := h.productVariantService.CreateVariant(ConvertVariantRequestToDTO(productId, variantRequest))
+
+	if err != nil {
+		return productVariantError(c, err)
+	}
+
+	return h.variantResponse(c, http.StatusCreated, "Variant created successfully", variant)
+}
+
+func (h *productVariantHandler) UpdateVariant(c *fiber.Ctx) error {
+	var resp response.Response
+	var variantRequest request.UpdateProductVariantRequest
+
+	productId, err := uuid.Parse(c.Params("product_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Product ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	variantId, err := uuid.Parse(c.Params("variant_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Variant ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&variantRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.VariantValidate(variantRequest.CreateProductVariantRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	variantDto := ConvertVariantRequestToDTO(productId, variantRequest.CreateProductVariantRequest)
+	variantDto.ID = variantId
+
+	variant, err := h.productVariantService.UpdateVariant(variantDto)
+
+	if err != nil {
+		return productVariantError(c, err)
+	}
+
+	return h.variantResponse(c, http.StatusOK, "Variant updated successfully", variant)
+}
+
+func (h *productVariantHandler) DeleteVariant(c *fiber.Ctx) error {
+	var resp response.Response
+
+	productId, err := uuid.Parse(c.Params("product_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Product ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	variantId, err := uuid.Parse(c.Params("variant_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Variant ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := h.productVariantService.DeleteVariant(productId, variantId); err != nil {
+		return productVariantError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Variant deleted successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
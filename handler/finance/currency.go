@@ -0,0 +1,129 @@
+package finance_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	finance_validator "github.com/developer-afo/instashop-ecommerce-api/validator/finance"
+)
+
+type currencyHandler struct {
+	currencyService finance_service.CurrencyServiceInterface
+	validator       finance_validator.CurrencyValidator
+}
+
+type CurrencyHandlerInterface interface {
+	GetCurrencies(c *fiber.Ctx) error
+	SetCurrencyRate(c *fiber.Ctx) error
+	DeleteCurrencyRate(c *fiber.Ctx) error
+}
+
+func NewCurrencyHandler(currencyService finance_service.CurrencyServiceInterface) CurrencyHandlerInterface {
+	return &currencyHandler{currencyService: currencyService}
+}
+
+func ConvertCurrencyRateDTOToResponse(rateDto dto.CurrencyRateDTO) response.CurrencyRateResponse {
+	return response.CurrencyRateResponse{
+		Currency:  rateDto.Currency,
+		Rate:      rateDto.Rate,
+		UpdatedAt: rateDto.UpdatedAt,
+	}
+}
+
+// currencyError writes the response for an error returned by the currency service.
+func currencyError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	switch {
+	case errors.Is(err, finance_service.ErrCurrencyNotSupported):
+		resp.Status = constants.CurrencyNotSupported
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, finance_service.ErrBaseCurrencyRate):
+		resp.Status = constants.ClientErrorBadRequest
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	resp.Status = constants.ServerErrorInternal
+
+	return c.Status(http.StatusInternalServerError).JSON(resp)
+}
+
+// GetCurrencies returns the currencies prices can be shown and paid in, with their rates to the base currency.
+func (h *currencyHandler) GetCurrencies(c *fiber.Ctx) error {
+	var resp response.Response
+	ratesResp := []response.CurrencyRateResponse{}
+
+	rates, err := h.currencyService.FindCurrencyRates()
+
+	if err != nil {
+		return currencyError(c, err)
+	}
+
+	for _, rate := range rates {
+		ratesResp = append(ratesResp, ConvertCurrencyRateDTOToResponse(rate))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"base_currency": rates[0].Currency, "results": ratesResp}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *currencyHandler) SetCurrencyRate(c *fiber.Ctx) error {
+	var resp response.Response
+	var rateRequest request.SetCurrencyRateRequest
+
+	if err := c.BodyParser(&rateRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	rateRequest.Currency = c.Params("currency")
+
+	if vEs, err := h.validator.SetCurrencyRateValidate(rateRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	rate, err := h.currencyService.SetCurrencyRate(rateRequest.Currency, rateRequest.Rate)
+
+	if err != nil {
+		return currencyError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Currency rate updated successfully"
+	resp.Data = map[string]interface{}{"currency": ConvertCurrencyRateDTOToResponse(rate)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *currencyHandler) DeleteCurrencyRate(c *fiber.Ctx) error {
+	var resp response.Response
+
+	if err := h.currencyService.DeleteCurrencyRate(c.Params("currency")); err != nil {
+		return currencyError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Currency removed successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -0,0 +1,188 @@
+package finance_handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+	finance_repository "github.com/developer-afo/instashop-ecommerce-api/repository/finance"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+)
+
+type ledgerHandler struct {
+	ledgerService finance_service.LedgerServiceInterface
+}
+
+type LedgerHandlerInterface interface {
+	GetTrialBalance(c *fiber.Ctx) error
+	GetAccountStatement(c *fiber.Ctx) error
+}
+
+func NewLedgerHandler(ledgerService finance_service.LedgerServiceInterface) LedgerHandlerInterface {
+	return &ledgerHandler{ledgerService: ledgerService}
+}
+
+func ConvertLedgerAccountDTOToResponse(accountDto dto.LedgerAccountDTO) response.LedgerAccountResponse {
+	return response.LedgerAccountResponse{
+		Code:   accountDto.Code,
+		Name:   accountDto.Name,
+		Type:   accountDto.Type,
+		UserID: accountDto.UserID,
+	}
+}
+
+// queryDate reads a YYYY-MM-DD query. A to date takes in the whole day, so it is returned as the start of the next one.
+func queryDate(c *fiber.Ctx, key string, endOfDay bool) (time.Time, error) {
+	value := c.Query(key, "")
+
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+
+	if err != nil {
+		return date, errors.New(key + " must be a date like 2006-01-02")
+	}
+
+	if endOfDay {
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return date, nil
+}
+
+func dateError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Status = constants.ClientUnProcessableEntity
+	resp.Message = err.Error()
+
+	return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+}
+
+// GetTrialBalance returns what was debited and credited to every account up to to_date, with the totals of each
+// currency, which are the same on both sides.
+func (h *ledgerHandler) GetTrialBalance(c *fiber.Ctx) error {
+	var resp response.Response
+	linesResp := []response.TrialBalanceLineResponse{}
+	debits := map[string]money.Money{}
+	credits := map[string]money.Money{}
+
+	toDate, err := queryDate(c, "to_date", true)
+
+	if err != nil {
+		return dateError(c, err)
+	}
+
+	currency := c.Query("currency", "")
+	if currency != "" {
+		currency = finance_service.CurrencyCode(currency)
+	}
+
+	lines, err := h.ledgerService.FindTrialBalance(currency, toDate)
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusInternalServerError).JSON(resp)
+	}
+
+	for _, line := range lines {
+		linesResp = append(linesResp, response.TrialBalanceLineResponse{
+			Account:  ConvertLedgerAccountDTOToResponse(line.Account),
+			Currency: line.Debit.Currency,
+			Debit:    line.Debit.Major(),
+			Credit:   line.Credit.Major(),
+			Balance:  line.Debit.Sub(line.Credit).Major(),
+		})
+
+		debits[line.Debit.Currency] = debits[line.Debit.Currency].Add(line.Debit)
+		credits[line.Credit.Currency] = credits[line.Credit.Currency].Add(line.Credit)
+	}
+
+	totals := map[string]response.TrialBalanceTotalResponse{}
+	for currency, debit := range debits {
+		totals[currency] = response.TrialBalanceTotalResponse{Debit: debit.Major(), Credit: credits[currency].Major()}
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": linesResp, "totals": totals}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// GetAccountStatement returns a page of an account's postings from from_date to to_date, oldest first, with the
+// account's balance before and after the period.
+func (h *ledgerHandler) GetAccountStatement(c *fiber.Ctx) error {
+	var resp response.Response
+	var pageable finance_repository.LedgerPostingPageable
+
+	basePageable, err := handler.GeneratePageable(c, repository.SortFields{})
+
+	if err != nil {
+		return handler.PageableError(c, err)
+	}
+
+	pageable.Pageable = basePageable
+	pageable.Currency = c.Query("currency", "")
+
+	if pageable.FromDate, err = queryDate(c, "from_date", false); err != nil {
+		return dateError(c, err)
+	}
+
+	if pageable.ToDate, err = queryDate(c, "to_date", true); err != nil {
+		return dateError(c, err)
+	}
+
+	statement, pagination, err := h.ledgerService.FindAccountStatement(c.Params("code"), pageable)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Account not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	}
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusInternalServerError).JSON(resp)
+	}
+
+	statementResp := response.AccountStatementResponse{
+		Account:        ConvertLedgerAccountDTOToResponse(statement.Account),
+		Currency:       statement.ClosingBalance.Currency,
+		OpeningBalance: statement.OpeningBalance.Major(),
+		ClosingBalance: statement.ClosingBalance.Major(),
+		Lines:          []response.AccountStatementLineResponse{},
+	}
+
+	for _, line := range statement.Lines {
+		statementResp.Lines = append(statementResp.Lines, response.AccountStatementLineResponse{
+			EntryID:              line.EntryID,
+			Description:          line.Description,
+			TransactionReference: line.TransactionReference,
+			Amount:               line.Amount.Major(),
+			CreatedAt:            line.CreatedAt,
+		})
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"statement": statementResp, "pagination": pagination}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -0,0 +1,37 @@
+package finance_handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+)
+
+type paymentHandler struct {
+	paymentGatewayService payment_gateway_service.PaymentGatewayServiceInterface
+}
+
+type PaymentHandlerInterface interface {
+	GetPaymentMethods(c *fiber.Ctx) error
+}
+
+func NewPaymentHandler(paymentGatewayService payment_gateway_service.PaymentGatewayServiceInterface) PaymentHandlerInterface {
+	return &paymentHandler{paymentGatewayService: paymentGatewayService}
+}
+
+func (h *paymentHandler) GetPaymentMethods(c *fiber.Ctx) error {
+	var resp response.Response
+	paymentMethods := []response.PaymentMethodResponse{}
+
+	for _, gateway := range h.paymentGatewayService.EnabledGateways() {
+		paymentMethods = append(paymentMethods, response.PaymentMethodResponse{Name: gateway})
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": paymentMethods}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
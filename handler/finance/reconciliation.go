@@ -0,0 +1,179 @@
+package finance_handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	finance_validator "github.com/developer-afo/instashop-ecommerce-api/validator/finance"
+)
+
+type reconciliationHandler struct {
+	reconciliationService finance_service.ReconciliationServiceInterface
+	validator             finance_validator.ReconciliationValidator
+}
+
+type ReconciliationHandlerInterface interface {
+	GetReports(c *fiber.Ctx) error
+	GetReport(c *fiber.Ctx) error
+	Reconcile(c *fiber.Ctx) error
+}
+
+func NewReconciliationHandler(reconciliationService finance_service.ReconciliationServiceInterface) ReconciliationHandlerInterface {
+	return &reconciliationHandler{reconciliationService: reconciliationService}
+}
+
+func ConvertReconciliationReportDTOToResponse(reportDto dto.ReconciliationReportDTO) response.ReconciliationReportResponse {
+	reportResp := response.ReconciliationReportResponse{
+		ID:            reportDto.ID,
+		FromDate:      reportDto.FromDate,
+		ToDate:        reportDto.ToDate,
+		Matched:       reportDto.Matched,
+		Discrepancies: reportDto.Discrepancies,
+		Healed:        reportDto.Healed,
+		Errors:        reportDto.Errors,
+		CreatedAt:     reportDto.CreatedAt,
+	}
+
+	for _, item := range reportDto.Items {
+		reportResp.Items = append(reportResp.Items, response.ReconciliationItemResponse{
+			Gateway:          item.Gateway,
+			Reference:        item.Reference,
+			TransactionID:    item.TransactionID,
+			Kind:             item.Kind,
+			LocalStatus:      item.LocalStatus,
+			LocalAmount:      item.LocalAmount.Major(),
+			LocalCurrency:    item.LocalAmount.Currency,
+			ProviderStatus:   item.ProviderStatus,
+			ProviderAmount:   item.ProviderAmount.Major(),
+			ProviderCurrency: item.ProviderAmount.Currency,
+			Healed:           item.Healed,
+			Note:             item.Note,
+		})
+	}
+
+	return reportResp
+}
+
+// GetReports returns a page of the reconciliation reports, newest first and without their items.
+func (h *reconciliationHandler) GetReports(c *fiber.Ctx) error {
+	var resp response.Response
+	reportsResp := []response.ReconciliationReportResponse{}
+
+	pageable, err := handler.GeneratePageable(c, repository.SortFields{})
+
+	if err != nil {
+		return handler.PageableError(c, err)
+	}
+
+	reports, pagination, err := h.reconciliationService.FindReports(pageable)
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusInternalServerError).JSON(resp)
+	}
+
+	for _, report := range reports {
+		reportsResp = append(reportsResp, ConvertReconciliationReportDTOToResponse(report))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": reportsResp, "pagination": pagination}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *reconciliationHandler) GetReport(c *fiber.Ctx) error {
+	var resp response.Response
+
+	reportId, err := uuid.Parse(c.Params("report_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Report ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	report, err := h.reconciliationService.FindReportById(reportId)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Report not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	}
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusInternalServerError).JSON(resp)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"report": ConvertReconciliationReportDTOToResponse(report)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// Reconcile checks the payments made from from_date to the end of to_date against the gateways, and returns the report.
+func (h *reconciliationHandler) Reconcile(c *fiber.Ctx) error {
+	var resp response.Response
+	var reconcileRequest request.ReconcileRequest
+
+	if err := c.BodyParser(&reconcileRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.ReconcileValidate(reconcileRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	from, _ := time.Parse("2006-01-02", reconcileRequest.FromDate)
+	to, _ := time.Parse("2006-01-02", reconcileRequest.ToDate)
+
+	report, err := h.reconciliationService.Reconcile(from, to.AddDate(0, 0, 1))
+
+	if errors.Is(err, finance_service.ErrInvalidReconciliationPeriod) {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusInternalServerError).JSON(resp)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "Reconciliation completed"
+	resp.Data = map[string]interface{}{"report": ConvertReconciliationReportDTOToResponse(report)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
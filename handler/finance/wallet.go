@@ -0,0 +1,187 @@
+package finance_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	finance_repository "github.com/developer-afo/instashop-ecommerce-api/repository/finance"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+	finance_validator "github.com/developer-afo/instashop-ecommerce-api/validator/finance"
+)
+
+type walletHandler struct {
+	walletService finance_service.WalletServiceInterface
+	validator     finance_validator.WalletValidator
+}
+
+type WalletHandlerInterface interface {
+	GetWallet(c *fiber.Ctx) error
+	GetWalletTransactions(c *fiber.Ctx) error
+	TopUp(c *fiber.Ctx) error
+	VerifyTopUp(c *fiber.Ctx) error
+}
+
+func NewWalletHandler(walletService finance_service.WalletServiceInterface) WalletHandlerInterface {
+	return &walletHandler{walletService: walletService}
+}
+
+func ConvertTransactionDTOToResponse(transactionDto dto.TransactionDTO) response.TransactionResponse {
+	return response.TransactionResponse{
+		ID:          transactionDto.ID,
+		Reference:   transactionDto.Reference,
+		Amount:      transactionDto.Amount.Major(),
+		Currency:    transactionDto.Amount.Currency,
+		Status:      transactionDto.Status,
+		Type:        transactionDto.Type,
+		Description: transactionDto.Description,
+		Method:      transactionDto.Method,
+		Vendor:      transactionDto.Vendor,
+		CreatedAt:   transactionDto.CreatedAt,
+		UpdatedAt:   transactionDto.UpdatedAt,
+	}
+}
+
+// walletError writes the response for an error returned by the wallet service.
+func walletError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Transaction not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, finance_service.ErrNotWalletTopUp):
+		resp.Status = constants.ClientErrorBadRequest
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	case errors.Is(err, payment_gateway_service.ErrPaymentGatewayDisabled):
+		resp.Status = constants.InvalidPaymentMethod
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	case errors.Is(err, finance_service.ErrWalletCurrency):
+		resp.Status = constants.CurrencyNotSupported
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	resp.Status = constants.PaymentGatewayError
+
+	return c.Status(http.StatusBadRequest).JSON(resp)
+}
+
+// GetWallet returns the signed in user's balance, less what is held for orders waiting on their payment.
+func (h *walletHandler) GetWallet(c *fiber.Ctx) error {
+	var resp response.Response
+
+	balance, err := h.walletService.FindBalance(handler.GetUserId(c))
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusInternalServerError).JSON(resp)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"wallet": response.WalletResponse{Balance: balance.Major(), Currency: balance.Currency}}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *walletHandler) GetWalletTransactions(c *fiber.Ctx) error {
+	var resp response.Response
+	var pageable finance_repository.TransactionPageable
+	transactionsResp := []response.TransactionResponse{}
+
+	basePageable, err := handler.GeneratePageable(c, finance_repository.TransactionSortFields)
+
+	if err != nil {
+		return handler.PageableError(c, err)
+	}
+
+	pageable.Pageable = basePageable
+	pageable.Type = c.Query("type", "")
+	pageable.Status = c.Query("status", "")
+	pageable.UserID = handler.GetUserId(c)
+
+	transactions, pagination, err := h.walletService.FindWalletTransactions(pageable)
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	for _, transaction := range transactions {
+		transactionsResp = append(transactionsResp, ConvertTransactionDTOToResponse(transaction))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": transactionsResp, "pagination": pagination}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// TopUp starts a gateway payment into the wallet, the balance goes up once the payment is verified.
+func (h *walletHandler) TopUp(c *fiber.Ctx) error {
+	var resp response.Response
+	var topUpRequest request.WalletTopUpRequest
+
+	if err := c.BodyParser(&topUpRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.TopUpValidate(topUpRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	amount := money.FromMajor(topUpRequest.Amount, money.BaseCurrency)
+
+	url, transaction, err := h.walletService.TopUp(handler.GetUserId(c), amount, topUpRequest.PaymentMethod)
+
+	if err != nil {
+		return walletError(c, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "Top up initiated successfully"
+	resp.Data = map[string]interface{}{"payment_url": url, "reference": transaction.Reference}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *walletHandler) VerifyTopUp(c *fiber.Ctx) error {
+	var resp response.Response
+
+	if err := h.walletService.VerifyTopUp(c.Params("reference")); err != nil {
+		return walletError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -0,0 +1,114 @@
+package finance_handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
+)
+
+type webhookHandler struct {
+	paymentGatewayService payment_gateway_service.PaymentGatewayServiceInterface
+	orderService          order_service.OrderServiceInterface
+	refundService         order_service.RefundServiceInterface
+	walletService         finance_service.WalletServiceInterface
+}
+
+type WebhookHandlerInterface interface {
+	HandleWebhook(c *fiber.Ctx) error
+}
+
+func NewWebhookHandler(
+	paymentGatewayService payment_gateway_service.PaymentGatewayServiceInterface,
+	orderService order_service.OrderServiceInterface,
+	refundService order_service.RefundServiceInterface,
+	walletService finance_service.WalletServiceInterface,
+) WebhookHandlerInterface {
+	return &webhookHandler{
+		paymentGatewayService: paymentGatewayService,
+		orderService:          orderService,
+		refundService:         refundService,
+		walletService:         walletService,
+	}
+}
+
+// HandleWebhook acknowledges a gateway event once the payment or refund it refers to has been settled.
+// Any non 2xx response makes the gateway retry the delivery later.
+func (h *webhookHandler) HandleWebhook(c *fiber.Ctx) error {
+	var resp response.Response
+
+	headers := map[string]string{}
+	for key, values := range c.GetReqHeaders() {
+		if len(values) > 0 {
+			headers[strings.ToLower(key)] = values[0]
+		}
+	}
+
+	event, err := h.paymentGatewayService.ParseWebhook(c.Params("gateway"), c.Body(), headers)
+
+	if err == payment_gateway_service.ErrPaymentGatewayDisabled {
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	}
+
+	if err == payment_gateway_service.ErrInvalidWebhookSignature {
+		resp.Status = constants.ClientErrorUnauthorizedAccess
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusUnauthorized).JSON(resp)
+	}
+
+	if err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	switch {
+	case event.RefundStatus != "":
+		err = h.refundService.HandleRefundEvent(event)
+	case event.Reference != "":
+		// the payment is either a wallet top up or an order's
+		err = h.walletService.VerifyTopUp(event.Reference)
+
+		if err == finance_service.ErrNotWalletTopUp {
+			err = h.orderService.VerifyOrderPayment(event.Reference)
+		}
+	default:
+		resp.Status = http.StatusOK
+		resp.Message = "Event ignored"
+
+		return c.Status(http.StatusOK).JSON(resp)
+	}
+
+	// the reference does not belong to an order, top up or refund on this platform
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resp.Status = http.StatusOK
+		resp.Message = "Event ignored"
+
+		return c.Status(http.StatusOK).JSON(resp)
+	}
+
+	if err != nil {
+		resp.Status = constants.PaymentGatewayError
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -17,6 +19,13 @@ func GetUserId(c *fiber.Ctx) uuid.UUID {
 	return userId
 }
 
+// GetOptionalUserId returns uuid.Nil for guests on routes behind middleware.OptionalAuth.
+func GetOptionalUserId(c *fiber.Ctx) uuid.UUID {
+	userId, _ := c.Locals("userId").(uuid.UUID)
+
+	return userId
+}
+
 func Index(c *fiber.Ctx) error {
 
 	var resp response.Response
@@ -47,12 +56,16 @@ func NotFound(c *fiber.Ctx) error {
 	return c.Status(http.StatusNotFound).JSON(resp)
 }
 
-func GeneratePageable(context *fiber.Ctx) (pageable repository.Pageable) {
+// GeneratePageable reads the page, size, search and sort of a list.
+// sort_by is a comma separated list of sortFields, each with an optional :asc or :desc, e.g. price:asc,created_at.
+// sort_dir is the direction of the fields without one and defaults to desc.
+// A cursor query, even an empty one for the first page, pages by keyset on the lists that support it.
+// It returns an error when a field cannot be sorted on, a direction is unknown or the cursor was not made by the API
+// for the same sort.
+func GeneratePageable(context *fiber.Ctx, sortFields repository.SortFields) (pageable repository.Pageable, err error) {
 
 	pageable.Page = 1
 	pageable.Size = 20
-	pageable.SortBy = "created_at"
-	pageable.SortDirection = "desc"
 	pageable.Search = ""
 
 	size, err := strconv.Atoi(context.Query("size", "0"))
@@ -65,14 +78,32 @@ func GeneratePageable(context *fiber.Ctx) (pageable repository.Pageable) {
 		pageable.Page = page
 	}
 
-	orderBy := context.Query("sort_by", "")
-	if orderBy != "" {
-		pageable.SortBy = orderBy
+	sortDir := strings.ToLower(context.Query("sort_dir", repository.SortDesc))
+	if sortDir != repository.SortAsc && sortDir != repository.SortDesc {
+		return pageable, fmt.Errorf("sort_dir must be %s or %s", repository.SortAsc, repository.SortDesc)
 	}
 
-	sortDir := context.Query("sort_dir", "")
-	if sortDir != "" {
-		pageable.SortBy = sortDir
+	for _, key := range strings.Split(context.Query("sort_by", ""), ",") {
+		field, direction, found := strings.Cut(strings.TrimSpace(key), ":")
+
+		if field == "" {
+			continue
+		}
+
+		if _, ok := sortFields[field]; !ok {
+			return pageable, fmt.Errorf("cannot sort by %s, sort by %s", field, strings.Join(sortFields.Names(), ", "))
+		}
+
+		direction = strings.ToLower(direction)
+		if !found {
+			direction = sortDir
+		}
+
+		if direction != repository.SortAsc && direction != repository.SortDesc {
+			return pageable, fmt.Errorf("cannot sort %s %s, sort it %s or %s", field, direction, repository.SortAsc, repository.SortDesc)
+		}
+
+		pageable.Sort = append(pageable.Sort, repository.Sort{Field: field, Direction: direction})
 	}
 
 	search := context.Query("search", "")
@@ -80,5 +111,29 @@ func GeneratePageable(context *fiber.Ctx) (pageable repository.Pageable) {
 		pageable.Search = search
 	}
 
-	return pageable
+	if context.Context().QueryArgs().Has("cursor") {
+		pageable.Keyset = true
+
+		if cursor := context.Query("cursor", ""); cursor != "" {
+			if pageable.Cursor, err = repository.DecodeCursor(cursor); err != nil {
+				return pageable, err
+			}
+
+			if err = pageable.Cursor.CheckSort(pageable.Sort); err != nil {
+				return pageable, err
+			}
+		}
+	}
+
+	return pageable, nil
+}
+
+// PageableError writes the response for an error returned by GeneratePageable.
+func PageableError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Status = http.StatusBadRequest
+	resp.Message = err.Error()
+
+	return c.Status(http.StatusBadRequest).JSON(resp)
 }
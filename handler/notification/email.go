@@ -0,0 +1,124 @@
+package notification_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	notification_repository "github.com/developer-afo/instashop-ecommerce-api/repository/notification"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+)
+
+type emailHandler struct {
+	emailService service.EmailServiceInterface
+}
+
+type EmailHandlerInterface interface {
+	GetOutboxEmails(c *fiber.Ctx) error
+	RetryOutboxEmail(c *fiber.Ctx) error
+}
+
+func NewEmailHandler(emailService service.EmailServiceInterface) EmailHandlerInterface {
+	return &emailHandler{emailService: emailService}
+}
+
+func ConvertOutboxEmailDTOToResponse(emailDto dto.OutboxEmailDTO) response.OutboxEmailResponse {
+	var resp response.OutboxEmailResponse
+
+	resp.ID = emailDto.ID
+	resp.Recipient = emailDto.Recipient
+	resp.Subject = emailDto.Subject
+	resp.Template = emailDto.Template
+	resp.Status = emailDto.Status
+	resp.Attempts = emailDto.Attempts
+	resp.NextAttemptAt = emailDto.NextAttemptAt
+	resp.LastError = emailDto.LastError
+	resp.SentAt = emailDto.SentAt
+	resp.CreatedAt = emailDto.CreatedAt
+
+	return resp
+}
+
+// GetOutboxEmails lists queued emails, use ?status=dead for the ones that gave up.
+func (h *emailHandler) GetOutboxEmails(c *fiber.Ctx) error {
+	var resp response.Response
+	var pageable notification_repository.OutboxEmailPageable
+	emailResponses := []response.OutboxEmailResponse{}
+
+	basePageable, err := handler.GeneratePageable(c, notification_repository.OutboxEmailSortFields)
+
+	if err != nil {
+		return handler.PageableError(c, err)
+	}
+
+	pageable.Pageable = basePageable
+	pageable.Status = c.Query("status", "")
+
+	emails, pagination, err := h.emailService.FindAllOutboxEmails(pageable)
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusInternalServerError).JSON(resp)
+	}
+
+	for _, email := range emails {
+		emailResponses = append(emailResponses, ConvertOutboxEmailDTOToResponse(email))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": emailResponses, "pagination": pagination}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *emailHandler) RetryOutboxEmail(c *fiber.Ctx) error {
+	var resp response.Response
+
+	emailId, err := uuid.Parse(c.Params("email_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Email ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	email, err := h.emailService.RetryOutboxEmail(emailId)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Email not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	}
+
+	if err == service.ErrEmailNotDead {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusInternalServerError).JSON(resp)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Email queued for delivery"
+	resp.Data = map[string]interface{}{"email": ConvertOutboxEmailDTOToResponse(email)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -0,0 +1,271 @@
+package order_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	core_handler "github.com/developer-afo/instashop-ecommerce-api/handler/core"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
+	order_validator "github.com/developer-afo/instashop-ecommerce-api/validator/order"
+)
+
+// CartTokenHeader carries the token of a guest's cart. It is sent back on every cart response.
+const CartTokenHeader = "X-Cart-Token"
+
+type cartHandler struct {
+	cartService order_service.CartServiceInterface
+	validator   order_validator.CartValidator
+}
+
+type CartHandlerInterface interface {
+	GetCart(c *fiber.Ctx) error
+	ClearCart(c *fiber.Ctx) error
+	AddCartItem(c *fiber.Ctx) error
+	UpdateCartItem(c *fiber.Ctx) error
+	RemoveCartItem(c *fiber.Ctx) error
+}
+
+func NewCartHandler(cartService order_service.CartServiceInterface) CartHandlerInterface {
+	return &cartHandler{cartService: cartService}
+}
+
+func ConvertCartDTOToResponse(cartDto dto.CartDTO) response.CartResponse {
+	var resp response.CartResponse
+
+	resp.TotalPrice = cartDto.TotalPrice.Major()
+	resp.Items = []response.CartItemResponse{}
+	for _, item := range cartDto.Items {
+		product := response.ProductResponse{
+			UUID:       item.ProductUUID,
+			Slug:       item.Product.Slug,
+			Name:       item.Product.Name,
+			Price:      item.Product.Price.Major(),
+			SlashPrice: item.Product.SlashPrice.Major(),
+			Stock:      item.Product.Stock,
+			Weight:     item.Product.Weight,
+			CreatedAt:  item.Product.CreatedAt,
+		}
+		for _, image := range item.Product.Images {
+			product.Images = append(product.Images, response.ImageResponse{Key: image.Key})
+		}
+
+		var variant *response.ProductVariantResponse
+
+		if item.Variant != nil {
+			variantResp := core_handler.ConvertVariantDTOToResponse(item.Product, *item.Variant)
+			variant = &variantResp
+		}
+
+		resp.Items = append(resp.Items, response.CartItemResponse{
+			Product:   product,
+			Variant:   variant,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice.Major(),
+			Price:     item.Price.Major(),
+			Warning:   item.Warning,
+		})
+	}
+
+	return resp
+}
+
+// cartResponse writes the cart and hands a guest the token of their cart.
+func cartResponse(c *fiber.Ctx, status int, message string, cartDto dto.CartDTO) error {
+	var resp response.Response
+
+	if cartDto.Token != "" && cartDto.UserUUID == nil {
+		c.Set(CartTokenHeader, cartDto.Token)
+	}
+
+	resp.Status = uint16(status)
+	resp.Message = message
+	resp.Data = map[string]interface{}{"cart": ConvertCartDTOToResponse(cartDto)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// variantIdQuery returns the variant_id in the query, nil when there is none.
+func variantIdQuery(c *fiber.Ctx) (*uuid.UUID, error) {
+	if c.Query("variant_id") == "" {
+		return nil, nil
+	}
+
+	variantId, err := uuid.Parse(c.Query("variant_id"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &variantId, nil
+}
+
+// cartError writes the response for an error returned by the cart service.
+func cartError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	switch {
+	case errors.Is(err, order_service.ErrProductNotFound), errors.Is(err, order_service.ErrCartItemNotFound):
+		resp.Status = constants.InvalidItemID
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, core_service.ErrVariantRequired), errors.Is(err, core_service.ErrVariantNotFound):
+		resp.Status = constants.InvalidItemID
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	resp.Status = constants.ServerErrorInternal
+
+	return c.Status(http.StatusInternalServerError).JSON(resp)
+}
+
+func (h *cartHandler) GetCart(c *fiber.Ctx) error {
+	cart, err := h.cartService.FindCart(handler.GetOptionalUserId(c), c.Get(CartTokenHeader))
+
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return cartResponse(c, http.StatusOK, "Success", cart)
+}
+
+func (h *cartHandler) ClearCart(c *fiber.Ctx) error {
+	var resp response.Response
+
+	if err := h.cartService.ClearCart(handler.GetOptionalUserId(c), c.Get(CartTokenHeader)); err != nil {
+		return cartError(c, err)
+	}
+
+	resp.Status = constants.CartUpdatedSuccessfully
+	resp.Message = "Cart cleared successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *cartHandler) AddCartItem(c *fiber.Ctx) error {
+	var resp response.Response
+	var itemRequest request.AddCartItemRequest
+
+	if err := c.BodyParser(&itemRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.AddCartItemValidate(itemRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	var variantId *uuid.UUID
+
+	if itemRequest.VariantID != "" {
+		id := uuid.MustParse(itemRequest.VariantID)
+		variantId = &id
+	}
+
+	cart, err := h.cartService.AddCartItem(
+		handler.GetOptionalUserId(c),
+		c.Get(CartTokenHeader),
+		uuid.MustParse(itemRequest.ProductID),
+		variantId,
+		itemRequest.Quantity,
+	)
+
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return cartResponse(c, constants.CartUpdatedSuccessfully, "Item added to cart", cart)
+}
+
+func (h *cartHandler) UpdateCartItem(c *fiber.Ctx) error {
+	var resp response.Response
+	var itemRequest request.UpdateCartItemRequest
+
+	productId, err := uuid.Parse(c.Params("product_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Product ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	variantId, err := variantIdQuery(c)
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Variant ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&itemRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.UpdateCartItemValidate(itemRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	cart, err := h.cartService.UpdateCartItem(handler.GetOptionalUserId(c), c.Get(CartTokenHeader), productId, variantId, itemRequest.Quantity)
+
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return cartResponse(c, constants.ItemQuantityUpdatedSuccessfully, "Cart item updated successfully", cart)
+}
+
+func (h *cartHandler) RemoveCartItem(c *fiber.Ctx) error {
+	var resp response.Response
+
+	productId, err := uuid.Parse(c.Params("product_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Product ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	variantId, err := variantIdQuery(c)
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Variant ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	cart, err := h.cartService.RemoveCartItem(handler.GetOptionalUserId(c), c.Get(CartTokenHeader), productId, variantId)
+
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return cartResponse(c, constants.CartUpdatedSuccessfully, "Item removed from cart", cart)
+}
@@ -0,0 +1,251 @@
+package order_handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
+	order_validator "github.com/developer-afo/instashop-ecommerce-api/validator/order"
+)
+
+type couponHandler struct {
+	couponService order_service.CouponServiceInterface
+	validator     order_validator.CouponValidator
+}
+
+type CouponHandlerInterface interface {
+	GetCoupons(c *fiber.Ctx) error
+	GetCoupon(c *fiber.Ctx) error
+	CreateCoupon(c *fiber.Ctx) error
+	UpdateCoupon(c *fiber.Ctx) error
+	DeleteCoupon(c *fiber.Ctx) error
+}
+
+func NewCouponHandler(couponService order_service.CouponServiceInterface) CouponHandlerInterface {
+	return &couponHandler{couponService: couponService}
+}
+
+func ConvertCouponDTOToResponse(couponDto dto.CouponDTO) response.CouponResponse {
+	var resp response.CouponResponse
+
+	resp.ID = couponDto.ID
+	resp.Code = couponDto.Code
+	resp.Description = couponDto.Description
+	resp.Type = couponDto.Type
+	resp.Value = float64(couponDto.Value) / 100
+	resp.MinOrderAmount = couponDto.MinOrderAmount.Major()
+	resp.ExpiresAt = couponDto.ExpiresAt
+	resp.UsageLimit = couponDto.UsageLimit
+	resp.UsageLimitPerUser = couponDto.UsageLimitPerUser
+	resp.IsActive = couponDto.IsActive
+	resp.ProductIDs = append([]uuid.UUID{}, couponDto.ProductUUIDs...)
+	resp.CreatedAt = couponDto.CreatedAt
+
+	return resp
+}
+
+// ConvertCouponRequestToDTO expects a validated request. A coupon is active unless IsActive says otherwise.
+func ConvertCouponRequestToDTO(couponRequest request.CreateCouponRequest) dto.CouponDTO {
+	var couponDto dto.CouponDTO
+
+	couponDto.Code = couponRequest.Code
+	couponDto.Description = couponRequest.Description
+	couponDto.Type = couponRequest.Type
+	// a percentage or an amount with two decimals, both are kept in hundredths
+	couponDto.Value = int64(math.Round(couponRequest.Value * 100))
+	couponDto.MinOrderAmount = money.FromMajor(couponRequest.MinOrderAmount, money.BaseCurrency)
+	couponDto.ExpiresAt = couponRequest.ExpiresAt
+	couponDto.UsageLimit = couponRequest.UsageLimit
+	couponDto.UsageLimitPerUser = couponRequest.UsageLimitPerUser
+	couponDto.IsActive = couponRequest.IsActive == nil || *couponRequest.IsActive
+	for _, productId := range couponRequest.ProductIDs {
+		couponDto.ProductUUIDs = append(couponDto.ProductUUIDs, uuid.MustParse(productId))
+	}
+
+	return couponDto
+}
+
+// couponError writes the response for an error returned by the coupon service.
+func couponError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Coupon not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, order_service.ErrCouponCodeTaken), errors.Is(err, order_service.ErrCouponInUse):
+		resp.Status = constants.ClientErrorBadRequest
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
+	resp.Status = constants.ServerErrorInternal
+
+	return c.Status(http.StatusInternalServerError).JSON(resp)
+}
+
+func (h *couponHandler) GetCoupons(c *fiber.Ctx) error {
+	var resp response.Response
+	couponResponses := []response.CouponResponse{}
+
+	pageable, err := handler.GeneratePageable(c, order_repository.CouponSortFields)
+
+	if err != nil {
+		return handler.PageableError(c, err)
+	}
+
+	coupons, pagination, err := h.couponService.FindAllCoupons(pageable)
+
+	if err != nil {
+		return couponError(c, err)
+	}
+
+	for _, coupon := range coupons {
+		couponResponses = append(couponResponses, ConvertCouponDTOToResponse(coupon))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": couponResponses, "pagination": pagination}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *couponHandler) GetCoupon(c *fiber.Ctx) error {
+	var resp response.Response
+
+	couponId, err := uuid.Parse(c.Params("coupon_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Coupon ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	coupon, err := h.couponService.FindCouponById(couponId)
+
+	if err != nil {
+		return couponError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"coupon": ConvertCouponDTOToResponse(coupon)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *couponHandler) CreateCoupon(c *fiber.Ctx) error {
+	var resp response.Response
+	var couponRequest request.CreateCouponRequest
+
+	if err := c.BodyParser(&couponRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CouponValidate(couponRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	coupon, err := h.couponService.CreateCoupon(ConvertCouponRequestToDTO(couponRequest))
+
+	if err != nil {
+		return couponError(c, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "Coupon created successfully"
+	resp.Data = map[string]interface{}{"coupon": ConvertCouponDTOToResponse(coupon)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *couponHandler) UpdateCoupon(c *fiber.Ctx) error {
+	var resp response.Response
+	var couponRequest request.UpdateCouponRequest
+
+	couponId, err := uuid.Parse(c.Params("coupon_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Coupon ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&couponRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CouponValidate(couponRequest.CreateCouponRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	couponDto := ConvertCouponRequestToDTO(couponRequest.CreateCouponRequest)
+	couponDto.ID = couponId
+
+	coupon, err := h.couponService.UpdateCoupon(couponDto)
+
+	if err != nil {
+		return couponError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Coupon updated successfully"
+	resp.Data = map[string]interface{}{"coupon": ConvertCouponDTOToResponse(coupon)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *couponHandler) DeleteCoupon(c *fiber.Ctx) error {
+	var resp response.Response
+
+	couponId, err := uuid.Parse(c.Params("coupon_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Coupon ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := h.couponService.DeleteCoupon(couponId); err != nil {
+		return couponError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Coupon deleted successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -0,0 +1,513 @@
+package order_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
+	order_validator "github.com/developer-afo/instashop-ecommerce-api/validator/order"
+)
+
+type locationHandler struct {
+	locationService order_service.LocationServiceInterface
+	validator       order_validator.LocationValidator
+}
+
+type LocationHandlerInterface interface {
+	GetCountries(c *fiber.Ctx) error
+	CreateCountry(c *fiber.Ctx) error
+	UpdateCountry(c *fiber.Ctx) error
+	DeleteCountry(c *fiber.Ctx) error
+	GetStates(c *fiber.Ctx) error
+	CreateState(c *fiber.Ctx) error
+	UpdateState(c *fiber.Ctx) error
+	DeleteState(c *fiber.Ctx) error
+	GetCities(c *fiber.Ctx) error
+	CreateCity(c *fiber.Ctx) error
+	UpdateCity(c *fiber.Ctx) error
+	DeleteCity(c *fiber.Ctx) error
+	CreateLocation(c *fiber.Ctx) error
+}
+
+func NewLocationHandler(locationService order_service.LocationServiceInterface) LocationHandlerInterface {
+	return &locationHandler{locationService: locationService}
+}
+
+func ConvertCountryDTOToResponse(countryDto dto.CountryDTO) response.CountryResponse {
+	return response.CountryResponse{ID: countryDto.ID, Name: countryDto.Name}
+}
+
+func ConvertStateDTOToResponse(stateDto dto.StateDTO) response.StateResponse {
+	return response.StateResponse{ID: stateDto.ID, CountryID: stateDto.CountryUUID, Name: stateDto.Name}
+}
+
+func ConvertCityDTOToResponse(cityDto dto.CityDTO) response.CityResponse {
+	return response.CityResponse{ID: cityDto.ID, StateID: cityDto.StateUUID, Name: cityDto.Name, Price: cityDto.Price.Major()}
+}
+
+// locationError writes the response for an error returned by the location service.
+func locationError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Location not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, order_service.ErrLocationInUse):
+		resp.Status = constants.ClientErrorBadRequest
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
+	resp.Status = constants.ServerErrorInternal
+
+	return c.Status(http.StatusInternalServerError).JSON(resp)
+}
+
+func (h *locationHandler) GetCountries(c *fiber.Ctx) error {
+	var resp response.Response
+	countryResponses := []response.CountryResponse{}
+
+	countries, err := h.locationService.FindAllCountries()
+
+	if err != nil {
+		return locationError(c, err)
+	}
+
+	for _, country := range countries {
+		countryResponses = append(countryResponses, ConvertCountryDTOToResponse(country))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": countryResponses}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *locationHandler) CreateCountry(c *fiber.Ctx) error {
+	var resp response.Response
+	var countryRequest request.CreateCountryRequest
+
+	if err := c.BodyParser(&countryRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CountryValidate(countryRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	country, err := h.locationService.CreateCountry(dto.CountryDTO{Name: countryRequest.Name})
+
+	if err != nil {
+		return locationError(c, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "Country created successfully"
+	resp.Data = map[string]interface{}{"country": ConvertCountryDTOToResponse(country)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *locationHandler) UpdateCountry(c *fiber.Ctx) error {
+	var resp response.Response
+	var countryRequest request.UpdateCountryRequest
+	var countryDto dto.CountryDTO
+
+	countryId, err := uuid.Parse(c.Params("country_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Country ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&countryRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CountryValidate(countryRequest.CreateCountryRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	countryDto.ID = countryId
+	countryDto.Name = countryRequest.Name
+
+	country, err := h.locationService.UpdateCountry(countryDto)
+
+	if err != nil {
+		return locationError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Country updated successfully"
+	resp.Data = map[string]interface{}{"country": ConvertCountryDTOToResponse(country)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *locationHandler) DeleteCountry(c *fiber.Ctx) error {
+	var resp response.Response
+
+	countryId, err := uuid.Parse(c.Params("country_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Country ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := h.locationService.DeleteCountry(countryId); err != nil {
+		return locationError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Country deleted successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *locationHandler) GetStates(c *fiber.Ctx) error {
+	var resp response.Response
+	stateResponses := []response.StateResponse{}
+
+	countryId, err := uuid.Parse(c.Params("country_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Country ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	states, err := h.locationService.FindStatesByCountryId(countryId)
+
+	if err != nil {
+		return locationError(c, err)
+	}
+
+	for _, state := range states {
+		stateResponses = append(stateResponses, ConvertStateDTOToResponse(state))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": stateResponses}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *locationHandler) CreateState(c *fiber.Ctx) error {
+	var resp response.Response
+	var stateRequest request.CreateStateRequest
+
+	countryId, err := uuid.Parse(c.Params("country_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Country ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&stateRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.StateValidate(stateRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	state, err := h.locationService.CreateState(dto.StateDTO{CountryUUID: countryId, Name: stateRequest.Name})
+
+	if err != nil {
+		return locationError(c, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "State created successfully"
+	resp.Data = map[string]interface{}{"state": ConvertStateDTOToResponse(state)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *locationHandler) UpdateState(c *fiber.Ctx) error {
+	var resp response.Response
+	var stateRequest request.CreateStateRequest
+	var stateDto dto.StateDTO
+
+	stateId, err := uuid.Parse(c.Params("state_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "State ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&stateRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.StateValidate(stateRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	stateDto.ID = stateId
+	stateDto.Name = stateRequest.Name
+
+	state, err := h.locationService.UpdateState(stateDto)
+
+	if err != nil {
+		return locationError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "State updated successfully"
+	resp.Data = map[string]interface{}{"state": ConvertStateDTOToResponse(state)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *locationHandler) DeleteState(c *fiber.Ctx) error {
+	var resp response.Response
+
+	stateId, err := uuid.Parse(c.Params("state_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "State ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := h.locationService.DeleteState(stateId); err != nil {
+		return locationError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "State deleted successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *locationHandler) GetCities(c *fiber.Ctx) error {
+	var resp response.Response
+	cityResponses := []response.CityResponse{}
+
+	stateId, err := uuid.Parse(c.Params("state_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "State ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	cities, err := h.locationService.FindCitiesByStateId(stateId)
+
+	if err != nil {
+		return locationError(c, err)
+	}
+
+	for _, city := range cities {
+		cityResponses = append(cityResponses, ConvertCityDTOToResponse(city))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": cityResponses}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *locationHandler) CreateCity(c *fiber.Ctx) error {
+	var resp response.Response
+	var cityRequest request.CreateCityRequest
+
+	stateId, err := uuid.Parse(c.Params("state_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "State ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&cityRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CityValidate(cityRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	city, err := h.locationService.CreateCity(dto.CityDTO{StateUUID: stateId, Name: cityRequest.Name, Price: money.FromMajor(cityRequest.Price, money.BaseCurrency)})
+
+	if err != nil {
+		return locationError(c, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "City created successfully"
+	resp.Data = map[string]interface{}{"city": ConvertCityDTOToResponse(city)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *locationHandler) UpdateCity(c *fiber.Ctx) error {
+	var resp response.Response
+	var cityRequest request.CreateCityRequest
+	var cityDto dto.CityDTO
+
+	cityId, err := uuid.Parse(c.Params("city_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "City ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&cityRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CityValidate(cityRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	cityDto.ID = cityId
+	cityDto.Name = cityRequest.Name
+	cityDto.Price = money.FromMajor(cityRequest.Price, money.BaseCurrency)
+
+	city, err := h.locationService.UpdateCity(cityDto)
+
+	if err != nil {
+		return locationError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "City updated successfully"
+	resp.Data = map[string]interface{}{"city": ConvertCityDTOToResponse(city)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *locationHandler) DeleteCity(c *fiber.Ctx) error {
+	var resp response.Response
+
+	cityId, err := uuid.Parse(c.Params("city_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "City ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := h.locationService.DeleteCity(cityId); err != nil {
+		return locationError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "City deleted successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// CreateLocation adds several states and their cities to a country in one request.
+func (h *locationHandler) CreateLocation(c *fiber.Ctx) error {
+	var resp response.Response
+	var locationRequest request.CreateLocationRequest
+	stateResponses := []response.StateResponse{}
+
+	if err := c.BodyParser(&locationRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.CreateLocationValidate(locationRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	states, err := h.locationService.CreateLocation(locationRequest)
+
+	if err != nil {
+		return locationError(c, err)
+	}
+
+	for _, state := range states {
+		stateResponses = append(stateResponses, ConvertStateDTOToResponse(state))
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "Locations created successfully"
+	resp.Data = map[string]interface{}{"results": stateResponses}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
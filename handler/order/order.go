@@ -1,15 +1,19 @@
 package order_handler
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/developer-afo/instashop-ecommerce-api/dto"
 	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	core_handler "github.com/developer-afo/instashop-ecommerce-api/handler/core"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
 	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
 	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
@@ -27,6 +31,7 @@ type orderHandler struct {
 type OrderHandlerInterface interface {
 	CreateOrder(c *fiber.Ctx) error
 	CancelOrder(c *fiber.Ctx) error
+	CancelPaidOrder(c *fiber.Ctx) error
 	GetUserOrders(c *fiber.Ctx) error
 	GetAllOrders(c *fiber.Ctx) error
 	VerifyOrderPayment(c *fiber.Ctx) error
@@ -76,11 +81,17 @@ func (h *orderHandler) ConvertDTOtoResponse(orderDto dto.OrderDTO) response.Orde
 	orderResponse.UpdatedAt = orderDto.UpdatedAt
 	orderResponse.PaymentMethod = orderDto.PaymentMethod
 	orderResponse.Reference = orderDto.Reference
-	orderResponse.TotalPrice = orderDto.TotalPrice
+	orderResponse.TotalPrice = orderDto.TotalPrice.Major()
+	orderResponse.Discount = orderDto.Discount.Major()
+	orderResponse.Currency = orderDto.TotalPrice.Currency
+	orderResponse.ExchangeRate = orderDto.ExchangeRate
+	orderResponse.WalletAmount = orderDto.WalletAmount.Major()
+	orderResponse.CouponID = orderDto.CouponID
 	orderResponse.Transaction = response.TransactionResponse{
 		ID:          orderDto.Transaction.ID,
 		Reference:   orderDto.Transaction.Reference,
-		Amount:      orderDto.Transaction.Amount,
+		Amount:      orderDto.Transaction.Amount.Major(),
+		Currency:    orderDto.Transaction.Amount.Currency,
 		Status:      orderDto.Transaction.Status,
 		Type:        orderDto.Transaction.Type,
 		Description: orderDto.Transaction.Description,
@@ -90,12 +101,21 @@ func (h *orderHandler) ConvertDTOtoResponse(orderDto dto.OrderDTO) response.Orde
 		UpdatedAt:   orderDto.Transaction.UpdatedAt,
 	}
 	for _, item := range orderDto.Items {
+		var variant *response.ProductVariantResponse
+
+		if item.Variant != nil {
+			variantResp := core_handler.ConvertVariantDTOToResponse(item.Product, *item.Variant)
+			variant = &variantResp
+		}
+
 		orderResponse.OrderItems = append(orderResponse.OrderItems, response.OrderItemResponse{
+			ID:      item.ID,
+			Variant: variant,
 			Product: response.ProductResponse{
 				UUID:        item.Product.ID,
 				Name:        item.Product.Name,
 				Description: item.Product.Description,
-				Price:       item.Product.Price,
+				Price:       item.Product.Price.Major(),
 				Images: func() []response.ImageResponse {
 					var images []response.ImageResponse
 
@@ -109,13 +129,24 @@ func (h *orderHandler) ConvertDTOtoResponse(orderDto dto.OrderDTO) response.Orde
 				}(),
 			},
 			Quantity: item.Quantity,
-			Price:    item.Price,
+			Price:    item.Price.Major(),
+			Discount: item.Discount.Major(),
+		})
+	}
+	for _, charge := range orderDto.Charges {
+		orderResponse.Charges = append(orderResponse.Charges, response.OrderChargeResponse{
+			ID:     charge.ID,
+			Type:   charge.Type,
+			Name:   charge.Name,
+			Amount: charge.Amount.Major(),
 		})
 	}
 	for _, statusHistory := range orderDto.StatusHistory {
 		orderResponse.StatusHistory = append(orderResponse.StatusHistory, ConvertOrderStatusHistoryDTOToResponse(statusHistory))
 	}
 	orderResponse.Status = ConvertOrderStatusDTOToResponse(orderDto.Status)
+	orderResponse.ShippingTypeID = orderDto.ShippingTypeID
+	orderResponse.ShippingAddress = response.OrderShippingAddressResponse(orderDto.ShippingAddress)
 	orderResponse.User = response.UserResponseData{
 		FirstName: orderDto.User.FirstName,
 		LastName:  orderDto.User.LastName,
@@ -125,15 +156,21 @@ func (h *orderHandler) ConvertDTOtoResponse(orderDto dto.OrderDTO) response.Orde
 	return orderResponse
 }
 
-func (h *orderHandler) GeneratePageable(c *fiber.Ctx) (pageable order_repository.OrderPageable) {
+func isIllegalTransition(err error) bool {
+	var illegalTransition *order_service.IllegalTransitionError
+
+	return errors.As(err, &illegalTransition)
+}
+
+func (h *orderHandler) GeneratePageable(c *fiber.Ctx) (pageable order_repository.OrderPageable, err error) {
 	var resp response.Response
-	basePageable := handler.GeneratePageable(c)
+	basePageable, err := handler.GeneratePageable(c, order_repository.OrderSortFields)
+
+	if err != nil {
+		return pageable, err
+	}
 
-	pageable.Page = basePageable.Page
-	pageable.Size = basePageable.Size
-	pageable.SortBy = basePageable.SortBy
-	pageable.SortDirection = basePageable.SortDirection
-	pageable.Search = basePageable.Search
+	pageable.Pageable = basePageable
 
 	pageable.Status = ""
 	pageable.UserID = uuid.Nil
@@ -184,7 +221,7 @@ func (h *orderHandler) GeneratePageable(c *fiber.Ctx) (pageable order_repository
 		pageable.ToDate = to.Format("2006-01-02")
 	}
 
-	return pageable
+	return pageable, nil
 }
 
 func (h *orderHandler) CreateOrder(c *fiber.Ctx) error {
@@ -209,11 +246,24 @@ func (h *orderHandler) CreateOrder(c *fiber.Ctx) error {
 
 	createOrderDto.UserID = handler.GetUserId(c)
 	createOrderDto.PaymentMethod = createOrderRequest.PaymentMethod
+	createOrderDto.WalletAmount = money.FromMajor(createOrderRequest.WalletAmount, money.BaseCurrency)
+	createOrderDto.CouponCode = createOrderRequest.CouponCode
+	createOrderDto.Currency = createOrderRequest.Currency
+	createOrderDto.FromCart = createOrderRequest.FromCart
+
+	if createOrderRequest.ShippingTypeID != "" {
+		createOrderDto.ShippingTypeID = uuid.MustParse(createOrderRequest.ShippingTypeID)
+	}
+
+	if createOrderRequest.ShippingAddressID != "" {
+		createOrderDto.ShippingAddressID = uuid.MustParse(createOrderRequest.ShippingAddressID)
+	}
 
 	for _, item := range createOrderRequest.Items {
 
 		createOrderDto.Items = append(createOrderDto.Items, dto.CreateOrderItemDTO{
 			ProductUUID: item.ProductID,
+			VariantUUID: item.VariantID,
 			Quantity:    item.Quantity,
 		})
 	}
@@ -247,17 +297,107 @@ func (h *orderHandler) CancelOrder(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(resp)
 	}
 
-	err = h.orderService.CancelOrder(orderId)
+	err = h.orderService.CancelOrder(orderId, handler.GetUserId(c))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resp.Status = constants.OrderNotFound
+		resp.Message = "Order not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	}
+
+	if isIllegalTransition(err) {
+		resp.Status = constants.IllegalOrderStatusTransition
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Order cancelled successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// CancelPaidOrder cancels any order that has not been processed yet and refunds what was paid for it.
+// The body is optional, {"reason": "", "to_wallet": false}.
+func (h *orderHandler) CancelPaidOrder(c *fiber.Ctx) error {
+	var resp response.Response
+	var cancelRequest request.CancelOrderRequest
+
+	orderId, err := uuid.Parse(c.Params("order_id"))
+	if err != nil {
+		resp.Status = constants.InvalidOrderID
+		resp.Message = "Invalid order ID"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&cancelRequest); err != nil {
+			resp.Status = constants.ClientRequestValidationError
+			resp.Message = err.Error()
+
+			return c.Status(http.StatusBadRequest).JSON(resp)
+		}
+	}
+
+	if validation, err := h.validator.CancelOrderValidate(cancelRequest); err != nil {
+		resp.Status = constants.ClientRequestValidationError
+		resp.Message = err.Error()
+		resp.Data = validation
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	refund, err := h.orderService.CancelPaidOrder(orderId, dto.CreateRefundDTO{Reason: cancelRequest.Reason, ToWallet: cancelRequest.ToWallet})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resp.Status = constants.OrderNotFound
+		resp.Message = "Order not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	}
+
+	if isIllegalTransition(err) {
+		resp.Status = constants.IllegalOrderStatusTransition
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
+	if errors.Is(err, order_service.ErrCancelledOrderNotRefunded) {
+		resp.Status = constants.PaymentGatewayError
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusBadGateway).JSON(resp)
+	}
+
 	if err != nil {
 		resp.Status = constants.ServerErrorInternal
 		resp.Message = err.Error()
 
+		// the order is not cancelled when its refund cannot be made this way
+		if errors.Is(err, order_service.ErrRefundExceedsGatewayPayment) {
+			resp.Status = constants.ClientErrorBadRequest
+		}
+
 		return c.Status(http.StatusBadRequest).JSON(resp)
 	}
 
 	resp.Status = http.StatusOK
 	resp.Message = "Order cancelled successfully"
 
+	if refund.ID != uuid.Nil {
+		resp.Data = map[string]interface{}{"refund": ConvertRefundDTOToResponse(refund)}
+	}
+
 	return c.Status(http.StatusOK).JSON(resp)
 }
 
@@ -266,7 +406,11 @@ func (h *orderHandler) GetUserOrders(c *fiber.Ctx) error {
 	var orderResponses []response.OrderResponse
 
 	userId := handler.GetUserId(c)
-	pageable := h.GeneratePageable(c)
+	pageable, err := h.GeneratePageable(c)
+
+	if err != nil {
+		return handler.PageableError(c, err)
+	}
 
 	pageable.UserID = userId
 
@@ -293,7 +437,11 @@ func (h *orderHandler) GetAllOrders(c *fiber.Ctx) error {
 	var resp response.Response
 	var orderResponses []response.OrderResponse
 
-	pageable := h.GeneratePageable(c)
+	pageable, err := h.GeneratePageable(c)
+
+	if err != nil {
+		return handler.PageableError(c, err)
+	}
 
 	orders, pagination, err := h.orderService.FindAllOrders(pageable)
 	if err != nil {
@@ -360,6 +508,7 @@ func (h *orderHandler) StatusHistoryByOrderId(c *fiber.Ctx) error {
 func (h *orderHandler) GetOrderStatuses(c *fiber.Ctx) error {
 	var resp response.Response
 	var orderStatusesResp []response.OrderStatusResponse
+	var transitionsResp []response.OrderTransitionResponse
 
 	orderStatuses, err := h.orderStatusService.GetOrderStatuses()
 	if err != nil {
@@ -373,9 +522,17 @@ func (h *orderHandler) GetOrderStatuses(c *fiber.Ctx) error {
 		orderStatusesResp = append(orderStatusesResp, ConvertOrderStatusDTOToResponse(orderStatus))
 	}
 
+	for _, transition := range h.orderService.GetOrderTransitions() {
+		transitionsResp = append(transitionsResp, response.OrderTransitionResponse{
+			From:   transition.From,
+			To:     transition.To,
+			Guards: transition.Guards,
+		})
+	}
+
 	resp.Status = fiber.StatusOK
 	resp.Message = "Success"
-	resp.Data = map[string]interface{}{"results": orderStatusesResp}
+	resp.Data = map[string]interface{}{"results": orderStatusesResp, "transitions": transitionsResp}
 
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
@@ -395,6 +552,13 @@ func (h *orderHandler) OrderProcessing(c *fiber.Ctx) error {
 	}
 
 	err = h.orderService.ProcessOrder(orderId)
+	if isIllegalTransition(err) {
+		resp.Status = constants.IllegalOrderStatusTransition
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
 	if err != nil {
 		resp.Status = fiber.StatusBadRequest
 		resp.Message = err.Error()
@@ -423,6 +587,13 @@ func (h *orderHandler) OutForDelivery(c *fiber.Ctx) error {
 	}
 
 	err = h.orderService.OutForDelivery(orderId)
+	if isIllegalTransition(err) {
+		resp.Status = constants.IllegalOrderStatusTransition
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
 	if err != nil {
 		resp.Status = fiber.StatusBadRequest
 		resp.Message = err.Error()
@@ -451,6 +622,13 @@ func (h *orderHandler) Delivered(c *fiber.Ctx) error {
 	}
 
 	err = h.orderService.Delivered(orderId)
+	if isIllegalTransition(err) {
+		resp.Status = constants.IllegalOrderStatusTransition
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusConflict).JSON(resp)
+	}
+
 	if err != nil {
 		resp.Status = fiber.StatusBadRequest
 		resp.Message = err.Error()
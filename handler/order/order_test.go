@@ -0,0 +1,54 @@
+package order_handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
+)
+
+// fakeOrderService fails every status change with err.
+type fakeOrderService struct {
+	order_service.OrderServiceInterface
+
+	err error
+}
+
+func (s *fakeOrderService) ProcessOrder(orderId uuid.UUID) error {
+	return s.err
+}
+
+func TestIllegalTransitionIsConflict(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"moved", nil, fiber.StatusOK},
+		{"illegal", &order_service.IllegalTransitionError{From: order_service.DELIVERED, To: order_service.ORDER_PROCESSING}, fiber.StatusConflict},
+		{"guarded", fmt.Errorf("processing order: %w", &order_service.IllegalTransitionError{From: order_service.ORDER_PLACED, To: order_service.AWAITING_CONFIRMATION, Reason: "payment is not confirmed"}), fiber.StatusConflict},
+		{"other error", errors.New("connection refused"), fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/order/:order_id/process", NewOrderHandler(&fakeOrderService{err: tt.err}, nil, nil).OrderProcessing)
+
+			resp, err := app.Test(httptest.NewRequest("POST", "/order/"+uuid.NewString()+"/process", nil))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("got %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package order_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
+	order_validator "github.com/developer-afo/instashop-ecommerce-api/validator/order"
+)
+
+type refundHandler struct {
+	refundService order_service.RefundServiceInterface
+	validator     order_validator.RefundValidator
+}
+
+type RefundHandlerInterface interface {
+	RefundOrder(c *fiber.Ctx) error
+	GetOrderRefunds(c *fiber.Ctx) error
+}
+
+func NewRefundHandler(refundService order_service.RefundServiceInterface) RefundHandlerInterface {
+	return &refundHandler{refundService: refundService}
+}
+
+func ConvertRefundDTOToResponse(refundDto dto.RefundDTO) response.RefundResponse {
+	var resp response.RefundResponse
+
+	resp.ID = refundDto.ID
+	resp.Amount = refundDto.Amount.Major()
+	resp.Currency = refundDto.Amount.Currency
+	resp.Status = refundDto.Status
+	resp.GatewayReference = refundDto.GatewayReference
+	resp.Reason = refundDto.Reason
+	for _, item := range refundDto.Items {
+		resp.Items = append(resp.Items, response.RefundItemResponse{
+			OrderItemID:   item.OrderItemUUID,
+			OrderChargeID: item.OrderChargeUUID,
+			Quantity:      item.Quantity,
+			Amount:        item.Amount.Major(),
+		})
+	}
+	resp.CreatedAt = refundDto.CreatedAt
+
+	return resp
+}
+
+func (h *refundHandler) RefundOrder(c *fiber.Ctx) error {
+	var createRefundRequest request.CreateRefundRequest
+	var createRefundDto dto.CreateRefundDTO
+	var resp response.Response
+
+	orderId, err := uuid.Parse(c.Params("order_id"))
+
+	if err != nil {
+		resp.Status = constants.InvalidOrderID
+		resp.Message = "Invalid order ID"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if err := c.BodyParser(&createRefundRequest); err != nil {
+		resp.Status = constants.ClientRequestValidationError
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if validation, err := h.validator.CreateRefundValidate(createRefundRequest); err != nil {
+		resp.Status = constants.ClientRequestValidationError
+		resp.Message = err.Error()
+		resp.Data = validation
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	createRefundDto.Reason = createRefundRequest.Reason
+	createRefundDto.RefundCharges = createRefundRequest.RefundCharges
+	createRefundDto.ToWallet = createRefundRequest.ToWallet
+
+	for _, item := range createRefundRequest.Items {
+		orderItemId, err := uuid.Parse(item.OrderItemID)
+
+		if err != nil {
+			resp.Status = constants.InvalidItemID
+			resp.Message = "Invalid order item ID"
+
+			return c.Status(http.StatusBadRequest).JSON(resp)
+		}
+
+		createRefundDto.Items = append(createRefundDto.Items, dto.CreateRefundItemDTO{
+			OrderItemUUID: orderItemId,
+			Quantity:      item.Quantity,
+		})
+	}
+
+	refund, err := h.refundService.RefundOrder(orderId, createRefundDto)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resp.Status = constants.OrderNotFound
+		resp.Message = "Order not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	}
+
+	if err != nil {
+		resp.Status = constants.PaymentGatewayError
+		resp.Message = err.Error()
+
+		switch err {
+		case order_service.ErrOrderNotPaid, order_service.ErrNothingToRefund, order_service.ErrRefundExceedsOrder, order_service.ErrInvalidOrderItem,
+			order_service.ErrRefundExceedsGatewayPayment:
+			resp.Status = constants.ClientErrorBadRequest
+		}
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	resp.Status = constants.PaymentRefundedSuccessfully
+	resp.Message = "Refund initiated successfully"
+	resp.Data = map[string]interface{}{"refund": ConvertRefundDTOToResponse(refund)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *refundHandler) GetOrderRefunds(c *fiber.Ctx) error {
+	var resp response.Response
+	refundsResp := []response.RefundResponse{}
+
+	orderId, err := uuid.Parse(c.Params("order_id"))
+
+	if err != nil {
+		resp.Status = constants.InvalidOrderID
+		resp.Message = "Invalid order ID"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	refunds, err := h.refundService.FindRefundsByOrderId(orderId)
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	for _, refund := range refunds {
+		refundsResp = append(refundsResp, ConvertRefundDTOToResponse(refund))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": refundsResp}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -0,0 +1,264 @@
+package order_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
+	order_validator "github.com/developer-afo/instashop-ecommerce-api/validator/order"
+)
+
+type shippingAddressHandler struct {
+	shippingAddressService order_service.ShippingAddressServiceInterface
+	validator              order_validator.ShippingAddressValidator
+}
+
+type ShippingAddressHandlerInterface interface {
+	GetShippingAddresses(c *fiber.Ctx) error
+	GetShippingAddress(c *fiber.Ctx) error
+	CreateShippingAddress(c *fiber.Ctx) error
+	UpdateShippingAddress(c *fiber.Ctx) error
+	SetDefaultShippingAddress(c *fiber.Ctx) error
+	DeleteShippingAddress(c *fiber.Ctx) error
+}
+
+func NewShippingAddressHandler(shippingAddressService order_service.ShippingAddressServiceInterface) ShippingAddressHandlerInterface {
+	return &shippingAddressHandler{shippingAddressService: shippingAddressService}
+}
+
+func ConvertShippingAddressDTOToResponse(addressDto dto.ShippingAddressDTO) response.ShippingAddressResponse {
+	var resp response.ShippingAddressResponse
+
+	resp.ID = addressDto.ID
+	resp.FirstName = addressDto.FirstName
+	resp.LastName = addressDto.LastName
+	resp.Phone = addressDto.Phone
+	resp.AlternatePhone = addressDto.AlternatePhone
+	resp.Address = addressDto.Address
+	resp.City = ConvertCityDTOToResponse(addressDto.City)
+	resp.State = ConvertStateDTOToResponse(addressDto.State)
+	resp.Country = ConvertCountryDTOToResponse(addressDto.State.Country)
+	resp.ClosestLandmark = addressDto.ClosestLandmark
+	resp.IsDefault = addressDto.IsDefault
+	resp.CreatedAt = addressDto.CreatedAt
+
+	return resp
+}
+
+func ConvertShippingAddressRequestToDTO(addressRequest request.CreateShippingAddressRequest) dto.ShippingAddressDTO {
+	var addressDto dto.ShippingAddressDTO
+
+	addressDto.FirstName = addressRequest.FirstName
+	addressDto.LastName = addressRequest.LastName
+	addressDto.Phone = addressRequest.Phone
+	addressDto.AlternatePhone = addressRequest.AlternatePhone
+	addressDto.Address = addressRequest.Address
+	addressDto.CityUUID = uuid.MustParse(addressRequest.CityID)
+	addressDto.StateUUID = uuid.MustParse(addressRequest.StateID)
+	addressDto.ClosestLandmark = addressRequest.ClosestLandmark
+	addressDto.IsDefault = addressRequest.IsDefault
+
+	return addressDto
+}
+
+// shippingAddressError writes the response for an error returned by the shipping address service.
+func shippingAddressError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Shipping address not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	case errors.Is(err, order_service.ErrInvalidShippingAddress):
+		resp.Status = constants.InvalidShippingAddress
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	resp.Status = constants.ServerErrorInternal
+
+	return c.Status(http.StatusInternalServerError).JSON(resp)
+}
+
+func (h *shippingAddressHandler) GetShippingAddresses(c *fiber.Ctx) error {
+	var resp response.Response
+	addressResponses := []response.ShippingAddressResponse{}
+
+	addresses, err := h.shippingAddressService.FindShippingAddressesByUserId(handler.GetUserId(c))
+
+	if err != nil {
+		return shippingAddressError(c, err)
+	}
+
+	for _, address := range addresses {
+		addressResponses = append(addressResponses, ConvertShippingAddressDTOToResponse(address))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": addressResponses}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *shippingAddressHandler) GetShippingAddress(c *fiber.Ctx) error {
+	var resp response.Response
+
+	addressId, err := uuid.Parse(c.Params("address_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Address ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	address, err := h.shippingAddressService.FindUserShippingAddress(handler.GetUserId(c), addressId)
+
+	if err != nil {
+		return shippingAddressError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"address": ConvertShippingAddressDTOToResponse(address)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *shippingAddressHandler) CreateShippingAddress(c *fiber.Ctx) error {
+	var resp response.Response
+	var addressRequest request.CreateShippingAddressRequest
+
+	if err := c.BodyParser(&addressRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.ShippingAddressValidate(addressRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	addressDto := ConvertShippingAddressRequestToDTO(addressRequest)
+	addressDto.UserUUID = handler.GetUserId(c)
+
+	address, err := h.shippingAddressService.CreateShippingAddress(addressDto)
+
+	if err != nil {
+		return shippingAddressError(c, err)
+	}
+
+	resp.Status = constants.ShippingAddressAddedSuccessfully
+	resp.Message = "Shipping address added successfully"
+	resp.Data = map[string]interface{}{"address": ConvertShippingAddressDTOToResponse(address)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *shippingAddressHandler) UpdateShippingAddress(c *fiber.Ctx) error {
+	var resp response.Response
+	var addressRequest request.UpdateShippingAddressRequest
+
+	addressId, err := uuid.Parse(c.Params("address_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Address ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&addressRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.ShippingAddressValidate(addressRequest.CreateShippingAddressRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	addressDto := ConvertShippingAddressRequestToDTO(addressRequest.CreateShippingAddressRequest)
+	addressDto.ID = addressId
+	addressDto.UserUUID = handler.GetUserId(c)
+
+	address, err := h.shippingAddressService.UpdateShippingAddress(addressDto)
+
+	if err != nil {
+		return shippingAddressError(c, err)
+	}
+
+	resp.Status = constants.ShippingAddressUpdatedSuccessfully
+	resp.Message = "Shipping address updated successfully"
+	resp.Data = map[string]interface{}{"address": ConvertShippingAddressDTOToResponse(address)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *shippingAddressHandler) SetDefaultShippingAddress(c *fiber.Ctx) error {
+	var resp response.Response
+
+	addressId, err := uuid.Parse(c.Params("address_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Address ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := h.shippingAddressService.SetDefaultShippingAddress(handler.GetUserId(c), addressId); err != nil {
+		return shippingAddressError(c, err)
+	}
+
+	resp.Status = constants.ShippingAddressUpdatedSuccessfully
+	resp.Message = "Default shipping address updated"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *shippingAddressHandler) DeleteShippingAddress(c *fiber.Ctx) error {
+	var resp response.Response
+
+	addressId, err := uuid.Parse(c.Params("address_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Address ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := h.shippingAddressService.DeleteShippingAddress(handler.GetUserId(c), addressId); err != nil {
+		return shippingAddressError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Shipping address deleted successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -0,0 +1,218 @@
+package order_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
+	order_validator "github.com/developer-afo/instashop-ecommerce-api/validator/order"
+)
+
+type shippingTypeHandler struct {
+	shippingTypeService order_service.ShippingTypeServiceInterface
+	validator           order_validator.ShippingTypeValidator
+}
+
+type ShippingTypeHandlerInterface interface {
+	GetShippingTypes(c *fiber.Ctx) error
+	GetAllShippingTypes(c *fiber.Ctx) error
+	CreateShippingType(c *fiber.Ctx) error
+	UpdateShippingType(c *fiber.Ctx) error
+	DeleteShippingType(c *fiber.Ctx) error
+}
+
+func NewShippingTypeHandler(shippingTypeService order_service.ShippingTypeServiceInterface) ShippingTypeHandlerInterface {
+	return &shippingTypeHandler{shippingTypeService: shippingTypeService}
+}
+
+func ConvertShippingTypeDTOToResponse(shippingTypeDto dto.ShippingTypeDTO) response.ShippingTypeResponse {
+	var resp response.ShippingTypeResponse
+
+	resp.ID = shippingTypeDto.ID
+	resp.Name = shippingTypeDto.Name
+	resp.Description = shippingTypeDto.Description
+	resp.Price = shippingTypeDto.Price.Major()
+	resp.PricePerKg = shippingTypeDto.PricePerKg.Major()
+	resp.IsActive = shippingTypeDto.IsActive
+	resp.Rates = []response.ShippingRateResponse{}
+	for _, rate := range shippingTypeDto.Rates {
+		resp.Rates = append(resp.Rates, response.ShippingRateResponse{StateID: rate.StateUUID, Price: rate.Price.Major()})
+	}
+
+	return resp
+}
+
+// ConvertShippingTypeRequestToDTO expects a validated request. A shipping type is active unless IsActive says otherwise.
+func ConvertShippingTypeRequestToDTO(shippingTypeRequest request.CreateShippingTypeRequest) dto.ShippingTypeDTO {
+	var shippingTypeDto dto.ShippingTypeDTO
+
+	shippingTypeDto.Name = shippingTypeRequest.Name
+	shippingTypeDto.Description = shippingTypeRequest.Description
+	shippingTypeDto.Price = money.FromMajor(shippingTypeRequest.Price, money.BaseCurrency)
+	shippingTypeDto.PricePerKg = money.FromMajor(shippingTypeRequest.PricePerKg, money.BaseCurrency)
+	shippingTypeDto.IsActive = shippingTypeRequest.IsActive == nil || *shippingTypeRequest.IsActive
+	for _, rate := range shippingTypeRequest.Rates {
+		shippingTypeDto.Rates = append(shippingTypeDto.Rates, dto.ShippingRateDTO{
+			StateUUID: uuid.MustParse(rate.StateID),
+			Price:     money.FromMajor(rate.Price, money.BaseCurrency),
+		})
+	}
+
+	return shippingTypeDto
+}
+
+// shippingTypeError writes the response for an error returned by the shipping type service.
+func shippingTypeError(c *fiber.Ctx, err error) error {
+	var resp response.Response
+
+	resp.Message = err.Error()
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "Shipping type not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	}
+
+	resp.Status = constants.ServerErrorInternal
+
+	return c.Status(http.StatusInternalServerError).JSON(resp)
+}
+
+// GetShippingTypes lists the shipping types customers can choose at checkout.
+func (h *shippingTypeHandler) GetShippingTypes(c *fiber.Ctx) error {
+	return h.getShippingTypes(c, true)
+}
+
+// GetAllShippingTypes lists every shipping type, inactive ones included.
+func (h *shippingTypeHandler) GetAllShippingTypes(c *fiber.Ctx) error {
+	return h.getShippingTypes(c, false)
+}
+
+func (h *shippingTypeHandler) getShippingTypes(c *fiber.Ctx, activeOnly bool) error {
+	var resp response.Response
+	shippingTypeResponses := []response.ShippingTypeResponse{}
+
+	shippingTypes, err := h.shippingTypeService.FindAllShippingTypes(activeOnly)
+
+	if err != nil {
+		return shippingTypeError(c, err)
+	}
+
+	for _, shippingType := range shippingTypes {
+		shippingTypeResponses = append(shippingTypeResponses, ConvertShippingTypeDTOToResponse(shippingType))
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": shippingTypeResponses}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *shippingTypeHandler) CreateShippingType(c *fiber.Ctx) error {
+	var resp response.Response
+	var shippingTypeRequest request.CreateShippingTypeRequest
+
+	if err := c.BodyParser(&shippingTypeRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.ShippingTypeValidate(shippingTypeRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	shippingType, err := h.shippingTypeService.CreateShippingType(ConvertShippingTypeRequestToDTO(shippingTypeRequest))
+
+	if err != nil {
+		return shippingTypeError(c, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Message = "Shipping type created successfully"
+	resp.Data = map[string]interface{}{"shipping_type": ConvertShippingTypeDTOToResponse(shippingType)}
+
+	return c.Status(http.StatusCreated).JSON(resp)
+}
+
+func (h *shippingTypeHandler) UpdateShippingType(c *fiber.Ctx) error {
+	var resp response.Response
+	var shippingTypeRequest request.UpdateShippingTypeRequest
+
+	shippingTypeId, err := uuid.Parse(c.Params("shipping_type_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Shipping type ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := c.BodyParser(&shippingTypeRequest); err != nil {
+		resp.Status = constants.ClientErrorBadRequest
+		resp.Message = "Invalid request payload"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.ShippingTypeValidate(shippingTypeRequest.CreateShippingTypeRequest); err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	shippingTypeDto := ConvertShippingTypeRequestToDTO(shippingTypeRequest.CreateShippingTypeRequest)
+	shippingTypeDto.ID = shippingTypeId
+
+	shippingType, err := h.shippingTypeService.UpdateShippingType(shippingTypeDto)
+
+	if err != nil {
+		return shippingTypeError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Shipping type updated successfully"
+	resp.Data = map[string]interface{}{"shipping_type": ConvertShippingTypeDTOToResponse(shippingType)}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *shippingTypeHandler) DeleteShippingType(c *fiber.Ctx) error {
+	var resp response.Response
+
+	shippingTypeId, err := uuid.Parse(c.Params("shipping_type_id"))
+
+	if err != nil {
+		resp.Status = constants.ClientUnProcessableEntity
+		resp.Message = "Shipping type ID is not a valid UUID format"
+
+		return c.Status(http.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	if err := h.shippingTypeService.DeleteShippingType(shippingTypeId); err != nil {
+		return shippingTypeError(c, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Shipping type deleted successfully"
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
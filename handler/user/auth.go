@@ -6,16 +6,19 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	order_handler "github.com/developer-afo/instashop-ecommerce-api/handler/order"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
 	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
 	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
 	userResponse "github.com/developer-afo/instashop-ecommerce-api/payload/response/user"
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
 	userService "github.com/developer-afo/instashop-ecommerce-api/service/user"
 	"github.com/developer-afo/instashop-ecommerce-api/validator"
 )
 
 type authHandler struct {
 	authService userService.AuthServiceInterface
+	cartService order_service.CartServiceInterface
 	validator   validator.AuthValidator
 }
 
@@ -29,8 +32,8 @@ type AuthHandlerInterface interface {
 	ResetPassword(c *fiber.Ctx) error
 }
 
-func NewAuthHandler(authService userService.AuthServiceInterface) AuthHandlerInterface {
-	return &authHandler{authService: authService}
+func NewAuthHandler(authService userService.AuthServiceInterface, cartService order_service.CartServiceInterface) AuthHandlerInterface {
+	return &authHandler{authService: authService, cartService: cartService}
 }
 
 func (handler *authHandler) Login(c *fiber.Ctx) error {
@@ -58,6 +61,12 @@ func (handler *authHandler) Login(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(resp)
 	}
 
+	// a guest's cart follows them into their account. Failing to merge it must not fail the login,
+	// the guest cart is left as it is and can be merged on the next login.
+	if cartToken := c.Get(order_handler.CartTokenHeader); cartToken != "" {
+		_ = handler.cartService.MergeCart(cartToken, token.UserID)
+	}
+
 	resp.Status = status
 	resp.Message = "Login Successful"
 	resp.Data = token
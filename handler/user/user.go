@@ -0,0 +1,128 @@
+package userHandler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/response"
+	user_repository "github.com/developer-afo/instashop-ecommerce-api/repository/user"
+	userService "github.com/developer-afo/instashop-ecommerce-api/service/user"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type userHandler struct {
+	userService userService.UserServiceInterface
+	validator   validator.UserValidator
+}
+
+type UserHandlerInterface interface {
+	GetUsers(c *fiber.Ctx) error
+	GetNotificationPreferences(c *fiber.Ctx) error
+	UpdateNotificationPreferences(c *fiber.Ctx) error
+}
+
+func NewUserHandler(userService userService.UserServiceInterface) UserHandlerInterface {
+	return &userHandler{userService: userService}
+}
+
+// GetUsers returns a page of users, by page or by cursor.
+func (h *userHandler) GetUsers(c *fiber.Ctx) error {
+	var resp response.Response
+	usersResp := []response.UserListResponse{}
+
+	pageable, err := handler.GeneratePageable(c, user_repository.UserSortFields)
+
+	if err != nil {
+		return handler.PageableError(c, err)
+	}
+
+	users, pagination, err := h.userService.FindAllUsers(pageable)
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusInternalServerError).JSON(resp)
+	}
+
+	for _, user := range users {
+		usersResp = append(usersResp, response.UserListResponse{
+			ID:              user.ID,
+			FirstName:       user.FirstName,
+			LastName:        user.LastName,
+			Email:           user.Email,
+			ReferralCode:    user.ReferralCode,
+			IsEmailVerified: user.IsEmailVerified,
+			CreatedAt:       user.CreatedAt,
+		})
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{"results": usersResp, "pagination": pagination}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *userHandler) GetNotificationPreferences(c *fiber.Ctx) error {
+	var resp response.Response
+
+	user, err := h.userService.FindUserById(handler.GetUserId(c).String())
+
+	if err != nil {
+		resp.Status = constants.ClientErrorResourceNotFound
+		resp.Message = "User not found"
+
+		return c.Status(http.StatusNotFound).JSON(resp)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Success"
+	resp.Data = map[string]interface{}{
+		"preferences": response.NotificationPreferencesResponse{NotifyOrderUpdates: user.NotifyOrderUpdates},
+	}
+
+	return c.JSON(resp)
+}
+
+func (h *userHandler) UpdateNotificationPreferences(c *fiber.Ctx) error {
+	var resp response.Response
+
+	preferencesRequest := new(request.NotificationPreferencesRequest)
+
+	if err := c.BodyParser(preferencesRequest); err != nil {
+		resp.Status = http.StatusBadRequest
+		resp.Message = "Invalid request"
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	if vEs, err := h.validator.NotificationPreferencesValidate(*preferencesRequest); err != nil {
+		resp.Status = http.StatusBadRequest
+		resp.Message = err.Error()
+		resp.Data = vEs
+
+		return c.Status(http.StatusBadRequest).JSON(resp)
+	}
+
+	user, err := h.userService.UpdateNotificationPreferences(handler.GetUserId(c), *preferencesRequest.NotifyOrderUpdates)
+
+	if err != nil {
+		resp.Status = constants.ServerErrorInternal
+		resp.Message = err.Error()
+
+		return c.Status(http.StatusInternalServerError).JSON(resp)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "Notification preferences updated"
+	resp.Data = map[string]interface{}{
+		"preferences": response.NotificationPreferencesResponse{NotifyOrderUpdates: user.NotifyOrderUpdates},
+	}
+
+	return c.JSON(resp)
+}
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+// Command is a task run from the command line instead of serving the API, Run is given the arguments after its name.
+type Command struct {
+	Name  string
+	Usage string
+	Run   func(args []string) error
+}
+
+type RegistryInterface interface {
+	Register(command Command)
+	Run(args []string) error
+}
+
+type registry struct {
+	commands []Command
+}
+
+func NewRegistry() RegistryInterface {
+	return &registry{}
+}
+
+func (r *registry) Register(command Command) {
+	r.commands = append(r.commands, command)
+}
+
+// Run runs the command named by args[0]. ErrUnknownCommand lists the commands there are.
+func (r *registry) Run(args []string) error {
+	var usages []string
+
+	for _, command := range r.commands {
+		if len(args) > 0 && command.Name == args[0] {
+			return command.Run(args[1:])
+		}
+
+		usages = append(usages, command.Usage)
+	}
+
+	return fmt.Errorf("%w, commands are:\n  %s", ErrUnknownCommand, strings.Join(usages, "\n  "))
+}
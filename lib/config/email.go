@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"html/template"
+	"net"
 	"net/smtp"
 	"strconv"
 
@@ -20,6 +21,7 @@ type EmailInterface interface {
 type email struct {
 	Host, Username, Password, From string
 	Port                           int
+	TLS                            bool
 }
 
 func NewEmail(env constants.Env) EmailInterface {
@@ -31,6 +33,7 @@ func NewEmail(env constants.Env) EmailInterface {
 		Username: env.SMTP_USERNAME,
 		Password: env.SMTP_PASSWORD,
 		From:     env.FROM_EMAIL,
+		TLS:      env.SMTP_TLS != "false",
 	}
 }
 
@@ -45,10 +48,7 @@ func (e *email) Send(to, subject, body string) error {
 		body)
 	addr := fmt.Sprintf("%s:%d", e.Host, e.Port)
 
-	conn, err := tls.Dial("tcp", addr, &tls.Config{
-		InsecureSkipVerify: true,
-		ServerName:         e.Host,
-	})
+	conn, err := e.dial(addr)
 	if err != nil {
 		return err
 	}
@@ -57,10 +57,13 @@ func (e *email) Send(to, subject, body string) error {
 	if err != nil {
 		return err
 	}
+	defer client.Close()
 
-	// Authenticating
-	if err = client.Auth(auth); err != nil {
-		return err
+	// Authenticating, local SMTP servers used in development usually take any sender
+	if e.Username != "" {
+		if err = client.Auth(auth); err != nil {
+			return err
+		}
 	}
 
 	// Setting the sender and recipient
@@ -90,6 +93,18 @@ func (e *email) Send(to, subject, body string) error {
 	return client.Quit()
 }
 
+// dial opens a TLS connection unless SMTP_TLS is false, for plain local SMTP servers like MailHog.
+func (e *email) dial(addr string) (net.Conn, error) {
+	if !e.TLS {
+		return net.Dial("tcp", addr)
+	}
+
+	return tls.Dial("tcp", addr, &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         e.Host,
+	})
+}
+
 func (e *email) ParseTemplate(templateFile string, data interface{}) (string, error) {
 	t, err := template.ParseFiles(templateFile, "templates/layout.html")
 	if err != nil {
@@ -1,7 +1,9 @@
 package constants
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 
@@ -31,14 +33,24 @@ type Env struct {
 	SMTP_PORT     string
 	SMTP_USERNAME string
 	SMTP_PASSWORD string
+	SMTP_TLS      string
 
-	PAYSTACK_SECRET_KEY    string
-	FLUTTERWAVE_SECRET_KEY string
-	PAYMENT_CALLBACK_URL   string
+	PAYSTACK_SECRET_KEY     string
+	PAYSTACK_BASE_URL       string
+	FLUTTERWAVE_SECRET_KEY  string
+	FLUTTERWAVE_SECRET_HASH string
+	FLUTTERWAVE_BASE_URL    string
+	PAYMENT_CALLBACK_URL    string
+	PAYMENT_GATEWAYS        string
+
+	ORDER_PAYMENT_TTL string
 }
 
+// init loads the .env file into the environment. Without one, as in tests, the environment is used as it is.
 func init() {
-	if err := godotenv.Load(); err != nil {
+	if err := godotenv.Load(); errors.Is(err, fs.ErrNotExist) {
+		fmt.Println("No .env file, using the environment")
+	} else if err != nil {
 		log.Fatal("Error loading .env file")
 	} else {
 		fmt.Println("Loaded .env file")
@@ -48,26 +60,32 @@ func init() {
 func GetEnv() Env {
 
 	return Env{
-		AWS_SECRET_KEY:         os.Getenv("AWS_SECRET_KEY"),
-		AWS_ACCESS_KEY:         os.Getenv("AWS_ACCESS_KEY"),
-		AWS_REGION:             os.Getenv("AWS_REGION"),
-		AWS_BUCKET:             os.Getenv("AWS_BUCKET"),
-		AWS_BUCKET_FOLDER:      os.Getenv("AWS_BUCKET_FOLDER"),
-		PORT:                   os.Getenv("PORT"),
-		DB_HOST:                os.Getenv("DB_HOST"),
-		DB_USER:                os.Getenv("DB_USER"),
-		DB_PASSWORD:            os.Getenv("DB_PASSWORD"),
-		DB_PORT:                os.Getenv("DB_PORT"),
-		DB_NAME:                os.Getenv("DB_NAME"),
-		JWT_ACCESS_SECRET:      os.Getenv("JWT_ACCESS_SECRET"),
-		JWT_REFRESH_SECRET:     os.Getenv("JWT_REFRESH_SECRET"),
-		FROM_EMAIL:             os.Getenv("FROM_EMAIL"),
-		SMTP_HOST:              os.Getenv("SMTP_HOST"),
-		SMTP_PORT:              os.Getenv("SMTP_PORT"),
-		SMTP_USERNAME:          os.Getenv("SMTP_USERNAME"),
-		SMTP_PASSWORD:          os.Getenv("SMTP_PASSWORD"),
-		PAYSTACK_SECRET_KEY:    os.Getenv("PAYSTACK_SECRET_KEY"),
-		FLUTTERWAVE_SECRET_KEY: os.Getenv("FLUTTERWAVE_SECRET_KEY"),
-		PAYMENT_CALLBACK_URL:   os.Getenv("PAYMENT_CALLBACK_URL"),
+		AWS_SECRET_KEY:          os.Getenv("AWS_SECRET_KEY"),
+		AWS_ACCESS_KEY:          os.Getenv("AWS_ACCESS_KEY"),
+		AWS_REGION:              os.Getenv("AWS_REGION"),
+		AWS_BUCKET:              os.Getenv("AWS_BUCKET"),
+		AWS_BUCKET_FOLDER:       os.Getenv("AWS_BUCKET_FOLDER"),
+		PORT:                    os.Getenv("PORT"),
+		DB_HOST:                 os.Getenv("DB_HOST"),
+		DB_USER:                 os.Getenv("DB_USER"),
+		DB_PASSWORD:             os.Getenv("DB_PASSWORD"),
+		DB_PORT:                 os.Getenv("DB_PORT"),
+		DB_NAME:                 os.Getenv("DB_NAME"),
+		JWT_ACCESS_SECRET:       os.Getenv("JWT_ACCESS_SECRET"),
+		JWT_REFRESH_SECRET:      os.Getenv("JWT_REFRESH_SECRET"),
+		FROM_EMAIL:              os.Getenv("FROM_EMAIL"),
+		SMTP_HOST:               os.Getenv("SMTP_HOST"),
+		SMTP_PORT:               os.Getenv("SMTP_PORT"),
+		SMTP_USERNAME:           os.Getenv("SMTP_USERNAME"),
+		SMTP_PASSWORD:           os.Getenv("SMTP_PASSWORD"),
+		SMTP_TLS:                os.Getenv("SMTP_TLS"),
+		PAYSTACK_SECRET_KEY:     os.Getenv("PAYSTACK_SECRET_KEY"),
+		PAYSTACK_BASE_URL:       os.Getenv("PAYSTACK_BASE_URL"),
+		FLUTTERWAVE_SECRET_KEY:  os.Getenv("FLUTTERWAVE_SECRET_KEY"),
+		FLUTTERWAVE_SECRET_HASH: os.Getenv("FLUTTERWAVE_SECRET_HASH"),
+		FLUTTERWAVE_BASE_URL:    os.Getenv("FLUTTERWAVE_BASE_URL"),
+		PAYMENT_CALLBACK_URL:    os.Getenv("PAYMENT_CALLBACK_URL"),
+		PAYMENT_GATEWAYS:        os.Getenv("PAYMENT_GATEWAYS"),
+		ORDER_PAYMENT_TTL:       os.Getenv("ORDER_PAYMENT_TTL"),
 	}
 }
@@ -49,6 +49,9 @@ const (
 	InvalidOrderID                  = 4208
 	OrderNotFound                   = 4209
 	MinimumOrderAmountNotMet        = 4210
+	IllegalOrderStatusTransition    = 4211
+	InvalidCoupon                   = 4212
+	CouponUsageLimitReached         = 4213
 
 	// Payment Processing
 	PaymentAuthorized           = 4300
@@ -8,6 +8,7 @@ import (
 
 type DatabaseInterface interface {
 	Connection() *gorm.DB
+	Transaction(fn func(tx DatabaseInterface) error) error
 }
 
 type connection struct {
@@ -23,3 +24,26 @@ func StartDatabaseClient(env constants.Env) DatabaseInterface {
 func (conn connection) Connection() *gorm.DB {
 	return conn.pg.Connection()
 }
+
+// Transaction runs fn in a database transaction. It is committed when fn returns nil and rolled back otherwise.
+func (conn connection) Transaction(fn func(tx DatabaseInterface) error) error {
+	return conn.pg.Connection().Transaction(func(db *gorm.DB) error {
+		return fn(txConnection{db: db})
+	})
+}
+
+// txConnection is handed to repositories that take part in a running transaction.
+type txConnection struct {
+	db *gorm.DB
+}
+
+func (conn txConnection) Connection() *gorm.DB {
+	return conn.db
+}
+
+// Transaction nests fn in a savepoint of the running transaction.
+func (conn txConnection) Transaction(fn func(tx DatabaseInterface) error) error {
+	return conn.db.Transaction(func(db *gorm.DB) error {
+		return fn(txConnection{db: db})
+	})
+}
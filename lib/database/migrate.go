@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode"
 )
 
 var MigrationDir = "migrations"
@@ -64,36 +65,116 @@ func Migrate(database DatabaseInterface) {
 		}
 
 		// Split migration file content into individual SQL statements
-		statements := strings.Split(string(content), ";")
+		statements := splitStatements(string(content))
+
+		// Execute the statements and store the migration record in one transaction,
+		// so a migration that fails leaves nothing behind and can be run again once fixed
+		err = database.Transaction(func(tx DatabaseInterface) error {
+			for _, statement := range statements {
+				if err := tx.Connection().Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+
+			return tx.Connection().Create(&MigrationRecord{Filename: filename, AppliedAt: time.Now()}).Error
+		})
+
+		if err != nil {
+			fmt.Println("Failed to execute migration:", filename, err)
+			return
+		}
+
+		fmt.Println("Migration", filename, "has been applied successfully.")
+	}
+
+	fmt.Println("All migrations have been applied.")
+
+}
+
+// splitStatements splits SQL on the semicolons that end its statements. Semicolons in -- and /* */ comments,
+// in quoted strings and identifiers, and in $$ or $tag$ quoted bodies such as a function's are left alone.
+// Statements that are only comments are dropped.
+func splitStatements(content string) []string {
+	var statements []string
+	var statement strings.Builder
+
+	hasSQL := false
+
+	for i := 0; i < len(content); i++ {
+		end := len(content)
+
+		switch {
+		case strings.HasPrefix(content[i:], "--"):
+			if j := strings.IndexByte(content[i:], '\n'); j >= 0 {
+				end = i + j
+			}
+		case strings.HasPrefix(content[i:], "/*"):
+			if j := strings.Index(content[i+2:], "*/"); j >= 0 {
+				end = i + 2 + j + 2
+			}
+		case content[i] == '\'' || content[i] == '"':
+			// a doubled quote inside is read as the end of one quoted part and the start of the next
+			if j := strings.IndexByte(content[i+1:], content[i]); j >= 0 {
+				end = i + 1 + j + 1
+			}
+			hasSQL = true
+		case content[i] == '$':
+			tag, ok := dollarQuoteTag(content[i:])
+
+			if !ok {
+				statement.WriteByte(content[i])
+				hasSQL = true
 
-		// Execute each SQL statement
-		for _, statement := range statements {
-			statement = strings.TrimSpace(statement)
-			if statement == "" {
 				continue
 			}
 
-			// Execute migration SQL
-			if err := database.Connection().Exec(statement).Error; err != nil {
-				fmt.Println("Failed to execute migration:", filename, err)
-				return
+			if j := strings.Index(content[i+len(tag):], tag); j >= 0 {
+				end = i + len(tag) + j + len(tag)
+			}
+			hasSQL = true
+		case content[i] == ';':
+			if hasSQL {
+				statements = append(statements, strings.TrimSpace(statement.String()))
 			}
-		}
 
-		// Store migration record
-		migrationRecord := MigrationRecord{
-			Filename:  filename,
-			AppliedAt: time.Now(),
-		}
+			statement.Reset()
+			hasSQL = false
 
-		if err := database.Connection().Create(&migrationRecord).Error; err != nil {
-			fmt.Println("Failed to store migration record:", filename, err)
-			return
+			continue
+		default:
+			statement.WriteByte(content[i])
+
+			if !unicode.IsSpace(rune(content[i])) {
+				hasSQL = true
+			}
+
+			continue
 		}
 
-		fmt.Println("Migration", filename, "has been applied successfully.")
+		statement.WriteString(content[i:end])
+		i = end - 1
 	}
 
-	fmt.Println("All migrations have been applied.")
+	if hasSQL {
+		statements = append(statements, strings.TrimSpace(statement.String()))
+	}
+
+	return statements
+}
+
+// dollarQuoteTag returns the $tag$ that opens a dollar quoted string at the start of content, $$ when it has no tag.
+func dollarQuoteTag(content string) (string, bool) {
+	for j := 1; j < len(content); j++ {
+		c := content[j]
+
+		if c == '$' {
+			return content[:j+1], true
+		}
+
+		if c != '_' && !unicode.IsLetter(rune(c)) && !(j > 1 && unicode.IsDigit(rune(c))) {
+			return "", false
+		}
+	}
 
+	return "", false
 }
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "statements",
+			content: "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want:    []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:    "line comment",
+			content: "-- a; b\nCREATE TABLE a (id INT);",
+			want:    []string{"-- a; b\nCREATE TABLE a (id INT)"},
+		},
+		{
+			name:    "line comment with a quote",
+			content: "-- it's a; b, not a string\nCREATE TABLE a (id INT); -- $$ is not a body either\nSELECT 1;",
+			want:    []string{"-- it's a; b, not a string\nCREATE TABLE a (id INT)", "-- $$ is not a body either\nSELECT 1"},
+		},
+		{
+			name:    "line comment at the end",
+			content: "SELECT 1; -- done;",
+			want:    []string{"SELECT 1"},
+		},
+		{
+			name:    "block comment",
+			content: "/* a; b */ CREATE TABLE a (id INT);",
+			want:    []string{"/* a; b */ CREATE TABLE a (id INT)"},
+		},
+		{
+			name:    "quoted string",
+			content: "INSERT INTO a VALUES ('x;y', 'it''s; here');SELECT 1",
+			want:    []string{"INSERT INTO a VALUES ('x;y', 'it''s; here')", "SELECT 1"},
+		},
+		{
+			name:    "quoted identifier",
+			content: `SELECT 1 AS "a;b";`,
+			want:    []string{`SELECT 1 AS "a;b"`},
+		},
+		{
+			name:    "dollar quoted body",
+			content: "CREATE FUNCTION f () RETURNS INT AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql;",
+			want:    []string{"CREATE FUNCTION f () RETURNS INT AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql"},
+		},
+		{
+			name:    "tagged dollar quoted body",
+			content: "DO $body$ BEGIN PERFORM '$$;'; END $body$;",
+			want:    []string{"DO $body$ BEGIN PERFORM '$$;'; END $body$"},
+		},
+		{
+			name:    "dollar quoted body with quotes and comments",
+			content: "CREATE FUNCTION f () RETURNS TEXT AS $$ SELECT 'a;b' -- c; d\n $$ LANGUAGE sql; SELECT 2",
+			want:    []string{"CREATE FUNCTION f () RETURNS TEXT AS $$ SELECT 'a;b' -- c; d\n $$ LANGUAGE sql", "SELECT 2"},
+		},
+		{
+			name:    "parameters are not dollar quotes",
+			content: "SELECT $1; SELECT $2",
+			want:    []string{"SELECT $1", "SELECT $2"},
+		},
+		{
+			name:    "comments alone are dropped",
+			content: "CREATE TABLE a (id INT);\n-- the end;\n",
+			want:    []string{"CREATE TABLE a (id INT)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// every statement of the migrations must start with SQL once its leading comments are taken off
+func TestMigrationsSplitIntoStatements(t *testing.T) {
+	comments := regexp.MustCompile(`^(\s*--[^\n]*\n|\s*/\*.*?\*/)*\s*`)
+	keyword := regexp.MustCompile(`^(CREATE|ALTER|DROP|INSERT|UPDATE|DELETE|COMMENT|DO|SELECT)\b`)
+
+	files, err := filepath.Glob(filepath.Join("..", "..", MigrationDir, "*.sql"))
+
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, statement := range splitStatements(string(content)) {
+			sql := comments.ReplaceAllString(statement, "")
+
+			if !keyword.MatchString(sql) {
+				t.Errorf("%s: statement does not start with SQL: %q", filepath.Base(file), statement)
+			}
+		}
+	}
+}
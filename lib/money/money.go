@@ -0,0 +1,250 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Money is an amount in the minor unit of its currency, kobo for NGN, so adding and splitting amounts never rounds.
+// Only the amount is stored, a column of money is in BaseCurrency.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+const NGN = "NGN"
+
+// BaseCurrency is the currency prices and amounts are kept in.
+const BaseCurrency = NGN
+
+// minorUnits is the number of minor units in a major unit, every currency the store takes has two decimals.
+const minorUnits = 100
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// FromMajor rounds an amount in major units, like naira, to the nearest minor unit. Amounts from requests are read with it.
+func FromMajor(major float64, currency string) Money {
+	return Money{Amount: int64(math.Round(major * minorUnits)), Currency: currency}
+}
+
+// Major is the amount in major units, for responses and gateways that take them.
+func (m Money) Major() float64 {
+	return float64(m.Amount) / minorUnits
+}
+
+// Decimal writes the amount in major units with two decimals, without going through a float.
+func (m Money) Decimal() string {
+	amount := m.Amount
+	sign := ""
+
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, amount/minorUnits, amount%minorUnits)
+}
+
+func (m Money) String() string {
+	return m.Currency + " " + m.Decimal()
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// currency is the currency of an amount made from m and other, the zero Money takes the currency of the other amount.
+// Amounts in different currencies are never added, that is a bug in the caller.
+func (m Money) currency(other Money) string {
+	if m.Currency == "" {
+		return other.Currency
+	}
+
+	if other.Currency != "" && other.Currency != m.Currency {
+		panic(fmt.Sprintf("money: %s and %s amounts cannot be combined", m.Currency, other.Currency))
+	}
+
+	return m.Currency
+}
+
+func (m Money) Add(other Money) Money {
+	return Money{Amount: m.Amount + other.Amount, Currency: m.currency(other)}
+}
+
+func (m Money) Sub(other Money) Money {
+	return Money{Amount: m.Amount - other.Amount, Currency: m.currency(other)}
+}
+
+// Neg is m with its sign flipped.
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Mul is the amount of quantity items at m each.
+func (m Money) Mul(quantity int) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.Currency}
+}
+
+// Scale multiplies m by factor, like a price per kg by a weight, rounded to the nearest minor unit.
+func (m Money) Scale(factor float64) Money {
+	return Money{Amount: int64(math.Round(float64(m.Amount) * factor)), Currency: m.Currency}
+}
+
+// Percent is basisPoints hundredths of a percent of m, rounded to the nearest minor unit.
+func (m Money) Percent(basisPoints int64) Money {
+	return Money{Amount: divRound(m.Amount*basisPoints, 10000), Currency: m.Currency}
+}
+
+// Share is numerator/denominator of m, rounded to the nearest minor unit.
+func (m Money) Share(numerator int64, denominator int64) Money {
+	return Money{Amount: divRound(m.Amount*numerator, denominator), Currency: m.Currency}
+}
+
+func (m Money) Min(other Money) Money {
+	if other.Amount < m.Amount {
+		return Money{Amount: other.Amount, Currency: m.currency(other)}
+	}
+
+	return Money{Amount: m.Amount, Currency: m.currency(other)}
+}
+
+// Convert is m in currency, where rate is the price of one unit of currency in m's currency, rounded to the nearest minor unit.
+// Converting back takes 1/rate.
+func (m Money) Convert(currency string, rate float64) Money {
+	if currency == m.Currency {
+		return m
+	}
+
+	return Money{Amount: int64(math.Round(float64(m.Amount) / rate)), Currency: currency}
+}
+
+// Allocate splits m in proportion to weights, which must not be negative. The parts always add up to m, the minor units left over
+// by rounding down go to the parts that lost the most, the first of them on a tie.
+func (m Money) Allocate(weights []int64) []Money {
+	// the parts of a negative amount round towards zero and would leave units over, so its opposite is split instead
+	if m.Amount < 0 {
+		parts := m.Neg().Allocate(weights)
+
+		for i := range parts {
+			parts[i] = parts[i].Neg()
+		}
+
+		return parts
+	}
+
+	parts := make([]Money, len(weights))
+
+	var total int64
+	for i, weight := range weights {
+		parts[i].Currency = m.Currency
+		total += weight
+	}
+
+	if total <= 0 {
+		return parts
+	}
+
+	remainders := make([]int64, len(weights))
+	allocated := int64(0)
+
+	for i, weight := range weights {
+		parts[i].Amount = m.Amount * weight / total
+		remainders[i] = m.Amount * weight % total
+		allocated += parts[i].Amount
+	}
+
+	for left := m.Amount - allocated; left > 0; left-- {
+		largest := -1
+		for i := range remainders {
+			if weights[i] > 0 && (largest == -1 || remainders[i] > remainders[largest]) {
+				largest = i
+			}
+		}
+
+		parts[largest].Amount++
+		remainders[largest] = -1
+	}
+
+	return parts
+}
+
+// Sum adds amounts, it is the zero Money when there are none.
+func Sum(amounts ...Money) Money {
+	var total Money
+
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+
+	return total
+}
+
+// divRound divides, rounding halves away from zero.
+func divRound(numerator int64, denominator int64) int64 {
+	quotient, remainder := numerator/denominator, numerator%denominator
+
+	if 2*abs(remainder) >= abs(denominator) {
+		if (numerator < 0) != (denominator < 0) {
+			return quotient - 1
+		}
+
+		return quotient + 1
+	}
+
+	return quotient
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// Scan implements sql.Scanner, the column holds minor units of BaseCurrency.
+func (m *Money) Scan(value interface{}) error {
+	var amount int64
+
+	switch v := value.(type) {
+	case int64:
+		amount = v
+	case []byte:
+		parsed, err := strconv.ParseInt(string(v), 10, 64)
+
+		if err != nil {
+			return fmt.Errorf("money: %w", err)
+		}
+
+		amount = parsed
+	case string:
+		parsed, err := strconv.ParseInt(v, 10, 64)
+
+		if err != nil {
+			return fmt.Errorf("money: %w", err)
+		}
+
+		amount = parsed
+	case nil:
+		amount = 0
+	default:
+		return fmt.Errorf("money: cannot scan %T", value)
+	}
+
+	*m = Money{Amount: amount, Currency: BaseCurrency}
+
+	return nil
+}
+
+// Value implements driver.Valuer, only the minor units are written.
+func (m Money) Value() (driver.Value, error) {
+	return m.Amount, nil
+}
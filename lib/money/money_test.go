@@ -0,0 +1,158 @@
+package money
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// split is an amount with weights to split it by, kept small enough that amount*weight cannot overflow.
+type split struct {
+	Amount  Money
+	Weights []int64
+}
+
+func (split) Generate(r *rand.Rand, size int) reflect.Value {
+	s := split{Amount: New(r.Int63n(2_000_000_000_000)-1_000_000_000_000, NGN)}
+
+	for i := r.Intn(8); i >= 0; i-- {
+		weight := r.Int63n(1_000_000)
+
+		// zero weights, and many equal ones for ties, are where the leftover units go wrong
+		switch r.Intn(4) {
+		case 0:
+			weight = 0
+		case 1:
+			weight = 1
+		}
+
+		s.Weights = append(s.Weights, weight)
+	}
+
+	return reflect.ValueOf(s)
+}
+
+func TestAllocateAddsUp(t *testing.T) {
+	property := func(s split) bool {
+		parts := s.Amount.Allocate(s.Weights)
+
+		var total int64
+		for _, weight := range s.Weights {
+			total += weight
+		}
+
+		if len(parts) != len(s.Weights) {
+			return false
+		}
+
+		if total == 0 {
+			return Sum(parts...).Amount == 0
+		}
+
+		for i, part := range parts {
+			exact := float64(s.Amount.Amount) * float64(s.Weights[i]) / float64(total)
+
+			// every part is within a minor unit of its exact share, and nothing goes to a zero weight
+			if part.Currency != NGN || part.Amount-int64(exact) > 1 || int64(exact)-part.Amount > 1 || (s.Weights[i] == 0 && part.Amount != 0) {
+				t.Logf("%v by %v: part %d is %v", s.Amount, s.Weights, i, part)
+				return false
+			}
+		}
+
+		return Sum(parts...) == s.Amount
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		amount  int64
+		weights []int64
+		want    []int64
+	}{
+		{100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{-100, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{5, []int64{0, 1, 1}, []int64{0, 3, 2}},
+		{10, []int64{3, 7}, []int64{3, 7}},
+		{10, []int64{0, 0}, []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		var got []int64
+
+		for _, part := range New(tt.amount, NGN).Allocate(tt.weights) {
+			got = append(got, part.Amount)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%d by %v = %v, want %v", tt.amount, tt.weights, got, tt.want)
+		}
+	}
+}
+
+// steps is a price paid for quantity items, and the quantities it is refunded in.
+type steps struct {
+	Paid       Money
+	Quantity   int64
+	Quantities []int64
+}
+
+func (steps) Generate(r *rand.Rand, size int) reflect.Value {
+	s := steps{Paid: New(r.Int63n(1_000_000_000_000), NGN), Quantity: 1 + r.Int63n(1000)}
+
+	for left := s.Quantity; left > 0; {
+		quantity := 1 + r.Int63n(left)
+
+		s.Quantities = append(s.Quantities, quantity)
+		left -= quantity
+	}
+
+	return reflect.ValueOf(s)
+}
+
+// Refunds are the share of what was refunded so far less the share of what was refunded before, so refunding an item
+// in any number of steps gives back exactly what was paid.
+func TestShareAddsUp(t *testing.T) {
+	property := func(s steps) bool {
+		var refunded, done int64
+
+		for _, quantity := range s.Quantities {
+			part := s.Paid.Share(done+quantity, s.Quantity).Sub(s.Paid.Share(done, s.Quantity))
+
+			if part.Amount < 0 {
+				return false
+			}
+
+			refunded += part.Amount
+			done += quantity
+		}
+
+		return refunded == s.Paid.Amount
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShareRounds(t *testing.T) {
+	tests := []struct {
+		amount, numerator, denominator, want int64
+	}{
+		{100, 1, 3, 33},
+		{200, 1, 3, 67},
+		{1, 1, 2, 1},
+		{-1, 1, 2, -1},
+		{-200, 1, 3, -67},
+	}
+
+	for _, tt := range tests {
+		if got := New(tt.amount, NGN).Share(tt.numerator, tt.denominator).Amount; got != tt.want {
+			t.Errorf("%d/%d of %d = %d, want %d", tt.numerator, tt.denominator, tt.amount, got, tt.want)
+		}
+	}
+}
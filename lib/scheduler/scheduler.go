@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+)
+
+// Job is a task the scheduler runs when it starts and then every Interval.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+type SchedulerInterface interface {
+	Register(job Job)
+	Start()
+	Stop()
+}
+
+type scheduler struct {
+	database database.DatabaseInterface
+	jobs     []Job
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func NewScheduler(database database.DatabaseInterface) SchedulerInterface {
+	return &scheduler{
+		database: database,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Register adds a job. Jobs registered after Start are not run.
+func (s *scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+func (s *scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+
+		go s.loop(job)
+	}
+}
+
+// Stop waits for running jobs to finish.
+func (s *scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *scheduler) loop(job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	s.run(job)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.run(job)
+		}
+	}
+}
+
+// run holds a Postgres advisory lock named after the job while it runs,
+// so when several replicas share the database only one of them runs the job at a time.
+// The lock is released with the transaction, even if the replica dies.
+func (s *scheduler) run(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.SetLogger(job.Name, fmt.Sprintf("panic: %v", r))
+		}
+	}()
+
+	err := s.database.Transaction(func(tx database.DatabaseInterface) error {
+		var locked bool
+
+		err := tx.Connection().Raw("SELECT pg_try_advisory_xact_lock(?)", lockKey(job.Name)).Scan(&locked).Error
+
+		if err != nil || !locked {
+			return err
+		}
+
+		return job.Run()
+	})
+
+	if err != nil {
+		s.SetLogger(job.Name, err.Error())
+	}
+}
+
+func (s *scheduler) SetLogger(job string, message string) {
+	currentTime := time.Now()
+
+	logMessage := fmt.Sprintf("TimeStamp: %s, Job: %s, Message: %s",
+		currentTime.Format("2006-01-02 15:04:05"), job, message)
+	fmt.Println(logMessage)
+}
+
+func lockKey(name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("scheduler:" + name))
+
+	return int64(hash.Sum64())
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -10,8 +11,10 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 
+	"github.com/developer-afo/instashop-ecommerce-api/lib/command"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/scheduler"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/seed"
 	"github.com/developer-afo/instashop-ecommerce-api/router"
 )
@@ -37,8 +40,14 @@ func main() {
 	// Start database connection
 	dbConn := database.StartDatabaseClient(env)
 
+	// Background jobs are registered by the routers
+	jobScheduler := scheduler.NewScheduler(dbConn)
+
+	// Commands are registered by the routers too
+	commands := command.NewRegistry()
+
 	// Initialize router
-	router.InitializeRouter(app, dbConn, env)
+	router.InitializeRouter(app, dbConn, env, jobScheduler, commands)
 
 	// Migrate database
 	database.Migrate(dbConn)
@@ -46,5 +55,17 @@ func main() {
 	// Seed database
 	seed.NewSeeder(dbConn).Seed()
 
+	// Run a command instead of serving when one is named, e.g. `go run . reconcile`
+	if len(os.Args) > 1 {
+		if err := commands.Run(os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	// Start background jobs
+	jobScheduler.Start()
+
 	log.Fatal(app.Listen("0.0.0.0:" + env.PORT))
 }
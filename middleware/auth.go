@@ -24,3 +24,26 @@ func Protected() fiber.Handler {
 		return c.Next()
 	}
 }
+
+// OptionalAuth lets guests through without a userId. A bearer token that is sent must still be valid.
+func OptionalAuth() fiber.Handler {
+	authHelper := helper.NewAuth()
+
+	return func(c *fiber.Ctx) (err error) {
+		token := authHelper.ExtractBearerToken(c.Request())
+
+		if token == "" {
+			return c.Next()
+		}
+
+		userId, err := authHelper.ExtractUserID(token, "access")
+
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"message": err.Error()})
+		}
+
+		c.Locals("userId", userId)
+
+		return c.Next()
+	}
+}
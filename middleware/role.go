@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"slices"
+
 	user_repository "github.com/developer-afo/instashop-ecommerce-api/repository/user"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -11,7 +13,7 @@ type roleMiddleware struct {
 }
 
 type RoleMiddlewareInterface interface {
-	ValidateRole(role string) fiber.Handler
+	ValidateRole(roles ...string) fiber.Handler
 }
 
 func NewRoleMiddleware(userRepository user_repository.UserRepositoryInterface) RoleMiddlewareInterface {
@@ -20,7 +22,8 @@ func NewRoleMiddleware(userRepository user_repository.UserRepositoryInterface) R
 	}
 }
 
-func (rm roleMiddleware) ValidateRole(role string) fiber.Handler {
+// ValidateRole lets the request through when the user has one of roles.
+func (rm roleMiddleware) ValidateRole(roles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Get user ID from JWT
 		userID := c.Locals("userId").(uuid.UUID)
@@ -33,7 +36,7 @@ func (rm roleMiddleware) ValidateRole(role string) fiber.Handler {
 			})
 		}
 
-		if user.Role != role {
+		if !slices.Contains(roles, user.Role) {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"message": "Forbidden,you don't have the permission to access this resource",
 			})
@@ -0,0 +1,20 @@
+-- Inventory movements table
+-- every change an order makes to a product's stock, so stock levels can be audited
+CREATE TABLE
+    inventory_movements (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        product_id UUID NOT NULL REFERENCES products (id),
+        order_id UUID NOT NULL REFERENCES orders (id),
+        order_item_id UUID NOT NULL REFERENCES order_items (id),
+        reference VARCHAR(255) NOT NULL,
+        type VARCHAR(50) NOT NULL,
+        quantity INT NOT NULL
+    );
+
+-- an order item is reserved, released and sold at most once
+CREATE UNIQUE INDEX inventory_movements_order_item_type_idx ON inventory_movements (order_item_id, type);
+
+CREATE INDEX inventory_movements_product_id_idx ON inventory_movements (product_id);
@@ -0,0 +1,34 @@
+-- link a transaction to the one it reverses, e.g. a refund credit to the payment
+ALTER TABLE transactions ADD COLUMN parent_id UUID REFERENCES transactions (id);
+
+-- Refunds table
+CREATE TABLE
+    refunds (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        order_id UUID NOT NULL REFERENCES orders (id),
+        transaction_id UUID NOT NULL REFERENCES transactions (id),
+        amount DECIMAL(10, 2) NOT NULL,
+        status VARCHAR(50) NOT NULL,
+        gateway_reference VARCHAR(255) NOT NULL DEFAULT '',
+        reason TEXT NOT NULL DEFAULT ''
+    );
+
+CREATE INDEX refunds_order_id_idx ON refunds (order_id);
+
+CREATE INDEX refunds_gateway_reference_idx ON refunds (gateway_reference);
+
+-- Refund items table
+CREATE TABLE
+    refund_items (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        refund_id UUID NOT NULL REFERENCES refunds (id),
+        order_item_id UUID NOT NULL REFERENCES order_items (id),
+        quantity INT NOT NULL,
+        amount DECIMAL(10, 2) NOT NULL
+    );
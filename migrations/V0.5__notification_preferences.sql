@@ -0,0 +1,4 @@
+-- Notification preferences
+-- customers can opt out of order updates that are not essential, the order confirmation is always sent
+ALTER TABLE users
+ADD COLUMN notify_order_updates BOOLEAN NOT NULL DEFAULT TRUE;
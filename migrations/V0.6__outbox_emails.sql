@@ -0,0 +1,20 @@
+-- Email outbox table
+-- emails are written in the same transaction as the change they announce and delivered by a background job
+CREATE TABLE
+    outbox_emails (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        recipient VARCHAR(255) NOT NULL,
+        subject VARCHAR(255) NOT NULL,
+        template VARCHAR(255) NOT NULL,
+        variables JSONB NOT NULL DEFAULT '{}',
+        status VARCHAR(50) NOT NULL DEFAULT 'pending',
+        attempts INT NOT NULL DEFAULT 0,
+        next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        last_error TEXT NOT NULL DEFAULT '',
+        sent_at TIMESTAMPTZ
+    );
+
+CREATE INDEX email_outbox_status_next_attempt_at_idx ON outbox_emails (status, next_attempt_at);
@@ -0,0 +1,77 @@
+-- Countries table
+CREATE TABLE
+    countries (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        name VARCHAR(255) NOT NULL
+    );
+
+-- States table
+CREATE TABLE
+    states (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        country_id UUID NOT NULL REFERENCES countries (id),
+        name VARCHAR(255) NOT NULL
+    );
+
+CREATE INDEX states_country_id_idx ON states (country_id);
+
+-- Cities table
+-- price is what delivering to the city costs
+CREATE TABLE
+    cities (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        state_id UUID NOT NULL REFERENCES states (id),
+        name VARCHAR(255) NOT NULL,
+        price DECIMAL(10, 2) NOT NULL DEFAULT 0.00
+    );
+
+CREATE INDEX cities_state_id_idx ON cities (state_id);
+
+-- Shipping addresses table
+CREATE TABLE
+    shipping_addresses (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        user_id UUID NOT NULL REFERENCES users (id),
+        first_name VARCHAR(255) NOT NULL,
+        last_name VARCHAR(255) NOT NULL,
+        phone VARCHAR(50) NOT NULL,
+        alternate_phone VARCHAR(50) NOT NULL DEFAULT '',
+        address TEXT NOT NULL,
+        city_id UUID NOT NULL REFERENCES cities (id),
+        state_id UUID NOT NULL REFERENCES states (id),
+        closest_landmark VARCHAR(255) NOT NULL DEFAULT '',
+        is_default BOOLEAN NOT NULL DEFAULT FALSE
+    );
+
+CREATE INDEX shipping_addresses_user_id_idx ON shipping_addresses (user_id);
+
+-- a user has at most one default address
+CREATE UNIQUE INDEX shipping_addresses_user_default_idx ON shipping_addresses (user_id)
+WHERE
+    is_default
+    AND deleted_at IS NULL;
+
+-- the address an order ships to is copied onto it, editing or deleting the address later does not change the order
+ALTER TABLE orders
+ADD COLUMN shipping_address_id UUID REFERENCES shipping_addresses (id),
+ADD COLUMN shipping_first_name VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN shipping_last_name VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN shipping_phone VARCHAR(50) NOT NULL DEFAULT '',
+ADD COLUMN shipping_alternate_phone VARCHAR(50) NOT NULL DEFAULT '',
+ADD COLUMN shipping_address TEXT NOT NULL DEFAULT '',
+ADD COLUMN shipping_city VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN shipping_state VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN shipping_country VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN shipping_closest_landmark VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,59 @@
+-- product weight in kg, shipping types can charge per kg
+ALTER TABLE products
+ADD COLUMN weight DECIMAL(10, 3) NOT NULL DEFAULT 0.000;
+
+-- Shipping types table
+-- e.g. standard, express and pickup, price is charged on every order using the type
+CREATE TABLE
+    shipping_types (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        name VARCHAR(255) NOT NULL,
+        description TEXT NOT NULL DEFAULT '',
+        price DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+        price_per_kg DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+        is_active BOOLEAN NOT NULL DEFAULT TRUE
+    );
+
+-- Shipping rates table
+-- replaces a shipping type's price for addresses in a state
+CREATE TABLE
+    shipping_rates (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        shipping_type_id UUID NOT NULL REFERENCES shipping_types (id),
+        state_id UUID NOT NULL REFERENCES states (id),
+        price DECIMAL(10, 2) NOT NULL
+    );
+
+CREATE UNIQUE INDEX shipping_rates_type_state_idx ON shipping_rates (shipping_type_id, state_id)
+WHERE
+    deleted_at IS NULL;
+
+ALTER TABLE orders
+ADD COLUMN shipping_type_id UUID REFERENCES shipping_types (id);
+
+-- Order charges table
+-- what an order costs on top of its items, each charge is its own line so it can be shown and refunded separately
+CREATE TABLE
+    order_charges (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        order_id UUID NOT NULL REFERENCES orders (id),
+        type VARCHAR(50) NOT NULL,
+        name VARCHAR(255) NOT NULL,
+        amount DECIMAL(10, 2) NOT NULL
+    );
+
+CREATE INDEX order_charges_order_id_idx ON order_charges (order_id);
+
+-- a refund item refunds either an order item or a charge
+ALTER TABLE refund_items
+ALTER COLUMN order_item_id DROP NOT NULL,
+ADD COLUMN order_charge_id UUID REFERENCES order_charges (id);
@@ -0,0 +1,39 @@
+-- Coupons table
+-- type is percentage or fixed, a usage limit of 0 is unlimited
+CREATE TABLE
+    coupons (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        code VARCHAR(50) NOT NULL,
+        description TEXT NOT NULL DEFAULT '',
+        type VARCHAR(20) NOT NULL,
+        value DECIMAL(10, 2) NOT NULL,
+        min_order_amount DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+        expires_at TIMESTAMPTZ,
+        usage_limit INT NOT NULL DEFAULT 0,
+        usage_limit_per_user INT NOT NULL DEFAULT 0,
+        is_active BOOLEAN NOT NULL DEFAULT TRUE
+    );
+
+CREATE UNIQUE INDEX coupons_code_idx ON coupons (code)
+WHERE
+    deleted_at IS NULL;
+
+-- the products a coupon is limited to, a coupon without any applies to every product
+CREATE TABLE
+    coupon_products (
+        coupon_id UUID NOT NULL REFERENCES coupons (id),
+        product_id UUID NOT NULL REFERENCES products (id),
+        PRIMARY KEY (coupon_id, product_id)
+    );
+
+ALTER TABLE orders
+ADD COLUMN coupon_id UUID REFERENCES coupons (id),
+ADD COLUMN discount DECIMAL(10, 2) NOT NULL DEFAULT 0.00;
+
+CREATE INDEX orders_coupon_id_idx ON orders (coupon_id);
+
+ALTER TABLE order_items
+ADD COLUMN discount DECIMAL(10, 2) NOT NULL DEFAULT 0.00;
@@ -0,0 +1,31 @@
+-- Carts table
+-- a cart belongs to a user, or to a guest who holds its token until it is merged on login
+CREATE TABLE
+    carts (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        user_id UUID REFERENCES users (id),
+        token VARCHAR(64) NOT NULL
+    );
+
+CREATE UNIQUE INDEX carts_user_id_idx ON carts (user_id)
+WHERE
+    user_id IS NOT NULL;
+
+CREATE UNIQUE INDEX carts_token_idx ON carts (token);
+
+-- Cart items table
+CREATE TABLE
+    cart_items (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        cart_id UUID NOT NULL REFERENCES carts (id),
+        product_id UUID NOT NULL REFERENCES products (id),
+        quantity INT NOT NULL
+    );
+
+CREATE UNIQUE INDEX cart_items_cart_product_idx ON cart_items (cart_id, product_id);
@@ -0,0 +1,29 @@
+-- Categories table
+-- V0.2 dropped the first categories table, products can now be in several categories
+CREATE TABLE
+    categories (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        slug VARCHAR(255) NOT NULL,
+        name VARCHAR(255) NOT NULL,
+        description TEXT NOT NULL DEFAULT '',
+        parent_id UUID REFERENCES categories (id)
+    );
+
+CREATE UNIQUE INDEX categories_slug_idx ON categories (slug)
+WHERE
+    deleted_at IS NULL;
+
+CREATE INDEX categories_parent_id_idx ON categories (parent_id);
+
+-- Product categories table
+CREATE TABLE
+    product_categories (
+        product_id UUID NOT NULL REFERENCES products (id),
+        category_id UUID NOT NULL REFERENCES categories (id),
+        PRIMARY KEY (product_id, category_id)
+    );
+
+CREATE INDEX product_categories_category_id_idx ON product_categories (category_id);
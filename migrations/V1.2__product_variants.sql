@@ -0,0 +1,78 @@
+-- Product options table
+-- the ways a product varies, like its size or colour
+CREATE TABLE
+    product_options (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        product_id UUID NOT NULL REFERENCES products (id),
+        name VARCHAR(100) NOT NULL,
+        position INT NOT NULL DEFAULT 0
+    );
+
+CREATE INDEX product_options_product_id_idx ON product_options (product_id);
+
+-- Product option values table
+CREATE TABLE
+    product_option_values (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        option_id UUID NOT NULL REFERENCES product_options (id),
+        value VARCHAR(100) NOT NULL,
+        position INT NOT NULL DEFAULT 0
+    );
+
+CREATE INDEX product_option_values_option_id_idx ON product_option_values (option_id);
+
+-- Product variants table
+-- a variant sells at the product's price unless it has its own, and always has its own stock
+CREATE TABLE
+    product_variants (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        product_id UUID NOT NULL REFERENCES products (id),
+        sku VARCHAR(100) NOT NULL,
+        price DECIMAL(10, 2),
+        slash_price DECIMAL(10, 2),
+        stock INT NOT NULL DEFAULT 0
+    );
+
+CREATE UNIQUE INDEX product_variants_sku_idx ON product_variants (sku)
+WHERE
+    deleted_at IS NULL;
+
+CREATE INDEX product_variants_product_id_idx ON product_variants (product_id);
+
+-- Product variant values table
+-- the option values that make up a variant, one for each of the product's options
+CREATE TABLE
+    product_variant_values (
+        variant_id UUID NOT NULL REFERENCES product_variants (id),
+        option_value_id UUID NOT NULL REFERENCES product_option_values (id),
+        PRIMARY KEY (variant_id, option_value_id)
+    );
+
+-- images of a single variant, product images have none
+ALTER TABLE images ADD COLUMN variant_id UUID REFERENCES product_variants (id);
+
+ALTER TABLE order_items ADD COLUMN variant_id UUID REFERENCES product_variants (id);
+
+ALTER TABLE inventory_movements ADD COLUMN variant_id UUID REFERENCES product_variants (id);
+
+-- a cart holds a product without variants once, and each variant of a product once
+ALTER TABLE cart_items ADD COLUMN variant_id UUID REFERENCES product_variants (id);
+
+DROP INDEX cart_items_cart_product_idx;
+
+CREATE UNIQUE INDEX cart_items_cart_product_idx ON cart_items (cart_id, product_id)
+WHERE
+    variant_id IS NULL;
+
+CREATE UNIQUE INDEX cart_items_cart_variant_idx ON cart_items (cart_id, variant_id)
+WHERE
+    variant_id IS NOT NULL;
@@ -0,0 +1,18 @@
+-- full-text search document of a product, the name and brand weigh more than the description and specification
+ALTER TABLE products
+ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(brand, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(specification, '')), 'C')
+) STORED;
+
+CREATE INDEX products_search_vector_idx ON products USING GIN (search_vector);
+
+CREATE INDEX products_brand_idx ON products (LOWER(brand))
+WHERE
+    deleted_at IS NULL;
+
+CREATE INDEX products_price_idx ON products (price)
+WHERE
+    deleted_at IS NULL;
@@ -0,0 +1,23 @@
+-- Product reviews table
+-- a customer reviews a product once, after an order with it was delivered; hidden reviews are kept but not shown
+CREATE TABLE
+    product_reviews (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        product_id UUID NOT NULL REFERENCES products (id),
+        user_id UUID NOT NULL REFERENCES users (id),
+        rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+        comment TEXT NOT NULL DEFAULT '',
+        is_hidden BOOLEAN NOT NULL DEFAULT FALSE
+    );
+
+CREATE UNIQUE INDEX product_reviews_product_user_idx ON product_reviews (product_id, user_id)
+WHERE
+    deleted_at IS NULL;
+
+CREATE INDEX product_reviews_product_visible_idx ON product_reviews (product_id)
+WHERE
+    deleted_at IS NULL
+    AND is_hidden = FALSE;
@@ -0,0 +1,57 @@
+-- money is kept in integer minor units of the base currency (kobo for NGN), so sums never round
+-- coupon values are kept in hundredths too: basis points for percentage coupons, minor units for fixed ones
+ALTER TABLE products
+ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100),
+ALTER COLUMN slash_price DROP DEFAULT,
+ALTER COLUMN slash_price TYPE BIGINT USING ROUND(slash_price * 100),
+ALTER COLUMN slash_price SET DEFAULT 0;
+
+ALTER TABLE product_variants
+ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100),
+ALTER COLUMN slash_price TYPE BIGINT USING ROUND(slash_price * 100);
+
+ALTER TABLE transactions
+ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE orders
+ALTER COLUMN total_price TYPE BIGINT USING ROUND(total_price * 100),
+ALTER COLUMN discount DROP DEFAULT,
+ALTER COLUMN discount TYPE BIGINT USING ROUND(discount * 100),
+ALTER COLUMN discount SET DEFAULT 0;
+
+ALTER TABLE order_items
+ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100),
+ALTER COLUMN discount DROP DEFAULT,
+ALTER COLUMN discount TYPE BIGINT USING ROUND(discount * 100),
+ALTER COLUMN discount SET DEFAULT 0;
+
+ALTER TABLE order_charges
+ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE refunds
+ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE refund_items
+ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE cities
+ALTER COLUMN price DROP DEFAULT,
+ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100),
+ALTER COLUMN price SET DEFAULT 0;
+
+ALTER TABLE shipping_types
+ALTER COLUMN price DROP DEFAULT,
+ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100),
+ALTER COLUMN price SET DEFAULT 0,
+ALTER COLUMN price_per_kg DROP DEFAULT,
+ALTER COLUMN price_per_kg TYPE BIGINT USING ROUND(price_per_kg * 100),
+ALTER COLUMN price_per_kg SET DEFAULT 0;
+
+ALTER TABLE shipping_rates
+ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100);
+
+ALTER TABLE coupons
+ALTER COLUMN value TYPE BIGINT USING ROUND(value * 100),
+ALTER COLUMN min_order_amount DROP DEFAULT,
+ALTER COLUMN min_order_amount TYPE BIGINT USING ROUND(min_order_amount * 100),
+ALTER COLUMN min_order_amount SET DEFAULT 0;
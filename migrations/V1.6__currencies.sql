@@ -0,0 +1,26 @@
+-- Currency rates table
+-- the price of one unit of a currency in the base currency (NGN), kept by admins; the base currency has no row
+CREATE TABLE
+    currency_rates (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        currency CHAR(3) NOT NULL,
+        rate NUMERIC(20, 8) NOT NULL CHECK (rate > 0)
+    );
+
+CREATE UNIQUE INDEX currency_rates_currency_idx ON currency_rates (currency)
+WHERE
+    deleted_at IS NULL;
+
+-- orders, their payments and refunds are kept in the currency the customer paid in
+ALTER TABLE orders
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'NGN',
+ADD COLUMN exchange_rate NUMERIC(20, 8) NOT NULL DEFAULT 1;
+
+ALTER TABLE transactions
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'NGN';
+
+ALTER TABLE refunds
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'NGN';
@@ -0,0 +1,10 @@
+-- a wallet is the user's transactions with the wallet method: successful credits less successful and pending debits
+CREATE INDEX transactions_wallet_idx ON transactions (user_id)
+WHERE
+    method = 'wallet'
+    AND deleted_at IS NULL;
+
+-- the part of an order paid from the wallet, next to the gateway payment in transaction_id
+ALTER TABLE orders
+ADD COLUMN wallet_amount BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN wallet_transaction_id UUID REFERENCES transactions (id);
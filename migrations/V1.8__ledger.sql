@@ -0,0 +1,191 @@
+-- Ledger accounts table
+-- asset: gateway:<vendor> is what a payment gateway holds for us; liability: wallet:<user id> is what a wallet holds;
+-- revenue: sales; expense: refunds
+CREATE TABLE
+    ledger_accounts (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        code VARCHAR(100) NOT NULL,
+        name VARCHAR(255) NOT NULL,
+        type VARCHAR(20) NOT NULL CHECK (type IN ('asset', 'liability', 'revenue', 'expense')),
+        user_id UUID REFERENCES users (id)
+    );
+
+CREATE UNIQUE INDEX ledger_accounts_code_idx ON ledger_accounts (code);
+
+-- Journal entries table
+-- one entry for every transaction that succeeded, its postings are in the entry's currency
+CREATE TABLE
+    journal_entries (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        transaction_id UUID REFERENCES transactions (id),
+        currency CHAR(3) NOT NULL,
+        description VARCHAR(255) NOT NULL
+    );
+
+CREATE UNIQUE INDEX journal_entries_transaction_idx ON journal_entries (transaction_id)
+WHERE
+    transaction_id IS NOT NULL;
+
+-- Ledger postings table
+-- amount is in minor units, debits are positive and credits negative
+CREATE TABLE
+    ledger_postings (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        entry_id UUID NOT NULL REFERENCES journal_entries (id),
+        account_id UUID NOT NULL REFERENCES ledger_accounts (id),
+        amount BIGINT NOT NULL CHECK (amount <> 0)
+    );
+
+CREATE INDEX ledger_postings_entry_idx ON ledger_postings (entry_id);
+
+CREATE INDEX ledger_postings_account_idx ON ledger_postings (account_id, created_at);
+
+-- the postings of an entry add up to zero, checked at commit so they can be inserted one at a time
+CREATE FUNCTION ledger_entry_balanced () RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_postings WHERE entry_id = NEW.entry_id) <> 0 THEN
+        RAISE EXCEPTION 'journal entry % does not balance', NEW.entry_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_postings_balanced
+AFTER INSERT ON ledger_postings DEFERRABLE INITIALLY DEFERRED FOR EACH ROW
+EXECUTE FUNCTION ledger_entry_balanced ();
+
+-- the ledger is only ever added to, a mistake is put right with an entry that reverses it
+CREATE FUNCTION ledger_append_only () RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION '% cannot be changed, post a reversing entry instead', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER journal_entries_append_only BEFORE
+UPDATE
+OR DELETE ON journal_entries FOR EACH ROW
+EXECUTE FUNCTION ledger_append_only ();
+
+CREATE TRIGGER ledger_postings_append_only BEFORE
+UPDATE
+OR DELETE ON ledger_postings FOR EACH ROW
+EXECUTE FUNCTION ledger_append_only ();
+
+-- post the transactions that succeeded before the ledger, each entry's two postings are inserted together
+INSERT INTO
+    ledger_accounts (id, code, name, type)
+VALUES
+    (gen_random_uuid (), 'sales', 'Sales', 'revenue'),
+    (gen_random_uuid (), 'refunds', 'Refunds', 'expense');
+
+INSERT INTO
+    ledger_accounts (id, code, name, type)
+SELECT
+    gen_random_uuid (),
+    'gateway:' || vendor,
+    vendor,
+    'asset'
+FROM
+    (
+        SELECT DISTINCT
+            vendor
+        FROM
+            transactions
+        WHERE
+            status = 'success'
+            AND deleted_at IS NULL
+            AND (
+                method <> 'wallet'
+                OR purpose = 'wallet_top_up'
+            )
+    ) vendors;
+
+INSERT INTO
+    ledger_accounts (id, code, name, type, user_id)
+SELECT
+    gen_random_uuid (),
+    'wallet:' || user_id,
+    'Wallet',
+    'liability',
+    user_id
+FROM
+    (
+        SELECT DISTINCT
+            user_id
+        FROM
+            transactions
+        WHERE
+            status = 'success'
+            AND deleted_at IS NULL
+            AND method = 'wallet'
+    ) wallets;
+
+INSERT INTO
+    journal_entries (id, created_at, updated_at, transaction_id, currency, description)
+SELECT
+    gen_random_uuid (),
+    updated_at,
+    updated_at,
+    id,
+    currency,
+    description
+FROM
+    transactions
+WHERE
+    status = 'success'
+    AND deleted_at IS NULL
+    AND amount <> 0;
+
+INSERT INTO
+    ledger_postings (id, created_at, updated_at, entry_id, account_id, amount)
+SELECT
+    gen_random_uuid (),
+    postings.created_at,
+    postings.created_at,
+    postings.entry_id,
+    ledger_accounts.id,
+    postings.amount
+FROM
+    (
+        SELECT
+            journal_entries.id AS entry_id,
+            journal_entries.created_at,
+            CASE
+                WHEN transactions.method = 'wallet' THEN 'wallet:' || transactions.user_id
+                ELSE 'gateway:' || transactions.vendor
+            END AS code,
+            CASE
+                WHEN transactions.type = 'debit' THEN transactions.amount
+                ELSE - transactions.amount
+            END AS amount
+        FROM
+            journal_entries
+            JOIN transactions ON transactions.id = journal_entries.transaction_id
+        UNION ALL
+        SELECT
+            journal_entries.id,
+            journal_entries.created_at,
+            CASE
+                WHEN transactions.type = 'debit' THEN 'sales'
+                WHEN transactions.purpose = 'wallet_top_up' THEN 'gateway:' || transactions.vendor
+                ELSE 'refunds'
+            END,
+            CASE
+                WHEN transactions.type = 'debit' THEN - transactions.amount
+                ELSE transactions.amount
+            END
+        FROM
+            journal_entries
+            JOIN transactions ON transactions.id = journal_entries.transaction_id
+    ) postings
+    JOIN ledger_accounts ON ledger_accounts.code = postings.code;
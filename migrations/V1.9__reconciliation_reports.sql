@@ -0,0 +1,42 @@
+-- Reconciliation reports table
+-- one run of matching gateway payments against transactions, errors holds the gateways that could not be listed
+CREATE TABLE
+    reconciliation_reports (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        from_date TIMESTAMPTZ NOT NULL,
+        to_date TIMESTAMPTZ NOT NULL,
+        matched INT NOT NULL DEFAULT 0,
+        discrepancies INT NOT NULL DEFAULT 0,
+        healed INT NOT NULL DEFAULT 0,
+        errors TEXT NOT NULL DEFAULT ''
+    );
+
+CREATE INDEX reconciliation_reports_created_at_idx ON reconciliation_reports (created_at);
+
+-- Reconciliation items table
+-- kind is missing_locally, missing_at_provider, amount_differs or status_differs; amounts are in minor units
+CREATE TABLE
+    reconciliation_items (
+        id UUID PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        deleted_at TIMESTAMPTZ,
+        report_id UUID NOT NULL REFERENCES reconciliation_reports (id),
+        gateway VARCHAR(50) NOT NULL,
+        reference VARCHAR(255) NOT NULL,
+        transaction_id UUID REFERENCES transactions (id),
+        kind VARCHAR(50) NOT NULL,
+        local_status VARCHAR(50) NOT NULL DEFAULT '',
+        local_amount BIGINT NOT NULL DEFAULT 0,
+        local_currency CHAR(3) NOT NULL DEFAULT 'NGN',
+        provider_status VARCHAR(50) NOT NULL DEFAULT '',
+        provider_amount BIGINT NOT NULL DEFAULT 0,
+        provider_currency CHAR(3) NOT NULL DEFAULT 'NGN',
+        healed BOOLEAN NOT NULL DEFAULT FALSE,
+        note TEXT NOT NULL DEFAULT ''
+    );
+
+CREATE INDEX reconciliation_items_report_idx ON reconciliation_items (report_id);
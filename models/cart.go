@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+)
+
+// Cart is a user's cart, or a guest's when UserID is nil. Guests find their cart with its Token.
+type Cart struct {
+	database.BaseModel
+
+	UserID *uuid.UUID `json:"user_id"`
+	Token  string     `json:"token"`
+
+	Items []CartItem `json:"items" gorm:"foreignKey:CartID;references:ID"`
+}
+
+type CartItem struct {
+	database.BaseModel
+
+	CartID    uuid.UUID  `json:"cart_id"`
+	ProductID uuid.UUID  `json:"product_id"`
+	VariantID *uuid.UUID `json:"variant_id"`
+	Quantity  int        `json:"quantity"`
+}
@@ -4,27 +4,106 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 )
 
+// Product is sold as it is, or as one of its Variants when it has Options. Stock is only used when it has no variants.
 type Product struct {
 	database.BaseModel
 
-	Slug          string  `json:"slug"`
-	Name          string  `json:"name"`
-	Description   string  `json:"description"`
-	Specification string  `json:"specification"`
-	Price         float64 `json:"price"`
-	SlashPrice    float64 `json:"slash_price"`
-	Stock         int     `json:"stock"`
-	Brand         string  `json:"brand"`
+	Slug          string      `json:"slug"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description"`
+	Specification string      `json:"specification"`
+	Price         money.Money `json:"price"`
+	SlashPrice    money.Money `json:"slash_price"`
+	Stock         int         `json:"stock"`
+	Brand         string      `json:"brand"`
+	Weight        float64     `json:"weight"` // in kg, used for shipping fees
 
-	Sales  int     `json:"sales" gorm:"->"`
-	Images []Image `json:"images" gorm:"foreignKey:ProductID;references:ID"`
+	Sales       int              `json:"sales" gorm:"->"`
+	Rating      float64          `json:"rating" gorm:"->"`
+	ReviewCount int              `json:"review_count" gorm:"->"`
+	SearchRank  float64          `json:"-" gorm:"->"` // only selected when products are searched
+	Images      []Image          `json:"images" gorm:"foreignKey:ProductID;references:ID"`
+	Categories  []Category       `json:"categories" gorm:"many2many:product_categories"`
+	Options     []ProductOption  `json:"options" gorm:"foreignKey:ProductID;references:ID"`
+	Variants    []ProductVariant `json:"variants" gorm:"foreignKey:ProductID;references:ID"`
 }
 
-type Image struct {
+// ProductOption is a way a product varies, like its size or colour.
+type ProductOption struct {
+	database.BaseModel
+
+	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid"`
+	Name      string    `json:"name"`
+	Position  int       `json:"position"`
+
+	Values []ProductOptionValue `json:"values" gorm:"foreignKey:OptionID;references:ID"`
+}
+
+type ProductOptionValue struct {
+	database.BaseModel
+
+	OptionID uuid.UUID `json:"option_id" gorm:"type:uuid"`
+	Value    string    `json:"value"`
+	Position int       `json:"position"`
+}
+
+// ProductVariant sells at the product's prices unless it has its own Price.
+type ProductVariant struct {
+	database.BaseModel
+
+	ProductID  uuid.UUID    `json:"product_id" gorm:"type:uuid"`
+	SKU        string       `json:"sku" gorm:"column:sku"`
+	Price      *money.Money `json:"price"`
+	SlashPrice *money.Money `json:"slash_price"`
+	Stock      int          `json:"stock"`
+
+	Values []ProductOptionValue `json:"values" gorm:"many2many:product_variant_values;joinForeignKey:VariantID;joinReferences:OptionValueID"`
+	Images []Image              `json:"images" gorm:"foreignKey:VariantID;references:ID"`
+}
+
+// ProductReview is left by a customer who had the product delivered, at most once per product.
+// Hidden reviews are kept for the admins but left out of the product's rating.
+type ProductReview struct {
 	database.BaseModel
 
 	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid"`
-	Key       string    `json:"key"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment"`
+	IsHidden  bool      `json:"is_hidden"`
+
+	User User `json:"user" gorm:"foreignKey:UserID;references:ID"`
+}
+
+// Category is a node of the category tree, top level categories have no ParentID.
+type Category struct {
+	database.BaseModel
+
+	Slug        string     `json:"slug"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	ParentID    *uuid.UUID `json:"parent_id" gorm:"type:uuid"`
+}
+
+type Image struct {
+	database.BaseModel
+
+	ProductID uuid.UUID  `json:"product_id" gorm:"type:uuid"`
+	VariantID *uuid.UUID `json:"variant_id" gorm:"type:uuid"`
+	Key       string     `json:"key"`
+}
+
+type InventoryMovement struct {
+	database.BaseModel
+
+	ProductID   uuid.UUID  `json:"product_id" gorm:"type:uuid"`
+	VariantID   *uuid.UUID `json:"variant_id" gorm:"type:uuid"`
+	OrderID     uuid.UUID  `json:"order_id" gorm:"type:uuid"`
+	OrderItemID uuid.UUID  `json:"order_item_id" gorm:"type:uuid"`
+	Reference   string     `json:"reference"`
+	Type        string     `json:"type"`
+	Quantity    int        `json:"quantity"`
 }
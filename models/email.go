@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+)
+
+type OutboxEmail struct {
+	database.BaseModel
+
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Template      string     `json:"template"`
+	Variables     string     `json:"variables" gorm:"type:jsonb"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+}
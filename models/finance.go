@@ -1,21 +1,125 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 )
 
 type Transaction struct {
 	database.BaseModel
 
-	UserID      uuid.UUID `json:"user_id"`
-	Amount      float64   `json:"amount"`
-	Type        string    `json:"type"` // credit or debit
-	Reference   string    `json:"reference"`
-	Description string    `json:"description"` // use this to differentiate what the transaction is for
-	ShortDesc   string    `json:"short_desc" gorm:"column:purpose"`
-	Status      string    `json:"status"`
-	Method      string    `json:"method"`
-	Vendor      string    `json:"vendor"`
+	UserID      uuid.UUID   `json:"user_id"`
+	Amount      money.Money `json:"amount"`
+	Currency    string      `json:"currency"`
+	Type        string      `json:"type"` // credit or debit
+	Reference   string      `json:"reference"`
+	Description string      `json:"description"` // use this to differentiate what the transaction is for
+	ShortDesc   string      `json:"short_desc" gorm:"column:purpose"`
+	Status      string      `json:"status"`
+	Method      string      `json:"method"`
+	Vendor      string      `json:"vendor"`
+	ParentID    *uuid.UUID  `json:"parent_id" gorm:"type:uuid"` // the transaction this one reverses
+}
+
+// AfterFind puts the transaction's currency on its amount, the column only holds minor units.
+func (transaction *Transaction) AfterFind(tx *gorm.DB) error {
+	transaction.Amount.Currency = transaction.Currency
+
+	return nil
+}
+
+// CurrencyRate is what one unit of Currency costs in money.BaseCurrency. Prices can be shown and paid in the currencies
+// that have a rate.
+type CurrencyRate struct {
+	database.BaseModel
+
+	Currency string  `json:"currency"`
+	Rate     float64 `json:"rate"`
+}
+
+// LedgerAccount is an account of the double-entry ledger, Type is asset, liability, revenue or expense.
+// A wallet has an account of its own, with the wallet's UserID.
+type LedgerAccount struct {
+	database.BaseModel
+
+	Code   string     `json:"code"`
+	Name   string     `json:"name"`
+	Type   string     `json:"type"`
+	UserID *uuid.UUID `json:"user_id" gorm:"type:uuid"`
+}
+
+// JournalEntry is one movement of money in the ledger. Its postings are in Currency and add up to zero.
+type JournalEntry struct {
+	database.BaseModel
+
+	TransactionID *uuid.UUID      `json:"transaction_id" gorm:"type:uuid"`
+	Currency      string          `json:"currency"`
+	Description   string          `json:"description"`
+	Postings      []LedgerPosting `json:"postings" gorm:"foreignKey:EntryID"`
+}
+
+// AfterFind puts the entry's currency on the amounts of its postings.
+func (entry *JournalEntry) AfterFind(tx *gorm.DB) error {
+	for i := range entry.Postings {
+		entry.Postings[i].Amount.Currency = entry.Currency
+	}
+
+	return nil
+}
+
+// LedgerPosting moves Amount in or out of an account, debits are positive and credits negative.
+type LedgerPosting struct {
+	database.BaseModel
+
+	EntryID   uuid.UUID     `json:"entry_id"`
+	AccountID uuid.UUID     `json:"account_id"`
+	Account   LedgerAccount `json:"account" gorm:"foreignKey:AccountID"`
+	Amount    money.Money   `json:"amount"`
+}
+
+// ReconciliationReport is one run of matching the payments the gateways hold from FromDate up to ToDate against our
+// transactions. Errors lists the gateways that could not be listed.
+type ReconciliationReport struct {
+	database.BaseModel
+
+	FromDate      time.Time            `json:"from_date"`
+	ToDate        time.Time            `json:"to_date"`
+	Matched       int                  `json:"matched"`
+	Discrepancies int                  `json:"discrepancies"`
+	Healed        int                  `json:"healed"`
+	Errors        string               `json:"errors"`
+	Items         []ReconciliationItem `json:"items" gorm:"foreignKey:ReportID"`
+}
+
+// ReconciliationItem is a payment that does not match between a gateway and the transactions table. Healed is set
+// when the transaction was settled from the gateway's status, Note says why healing failed.
+type ReconciliationItem struct {
+	database.BaseModel
+
+	ReportID         uuid.UUID   `json:"report_id"`
+	Gateway          string      `json:"gateway"`
+	Reference        string      `json:"reference"`
+	TransactionID    *uuid.UUID  `json:"transaction_id" gorm:"type:uuid"`
+	Kind             string      `json:"kind"`
+	LocalStatus      string      `json:"local_status"`
+	LocalAmount      money.Money `json:"local_amount"`
+	LocalCurrency    string      `json:"local_currency"`
+	ProviderStatus   string      `json:"provider_status"`
+	ProviderAmount   money.Money `json:"provider_amount"`
+	ProviderCurrency string      `json:"provider_currency"`
+	Healed           bool        `json:"healed"`
+	Note             string      `json:"note"`
+}
+
+// AfterFind puts the item's currencies on its amounts.
+func (item *ReconciliationItem) AfterFind(tx *gorm.DB) error {
+	item.LocalAmount.Currency = item.LocalCurrency
+	item.ProviderAmount.Currency = item.ProviderCurrency
+
+	return nil
 }
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+)
+
+type Country struct {
+	database.BaseModel
+
+	Name string `json:"name"`
+
+	States []State `json:"states" gorm:"foreignKey:CountryID;references:ID"`
+}
+
+type State struct {
+	database.BaseModel
+
+	CountryID uuid.UUID `json:"country_id"`
+	Name      string    `json:"name"`
+
+	Country Country `json:"country" gorm:"foreignKey:CountryID;references:ID"`
+	Cities  []City  `json:"cities" gorm:"foreignKey:StateID;references:ID"`
+}
+
+type City struct {
+	database.BaseModel
+
+	StateID uuid.UUID   `json:"state_id"`
+	Name    string      `json:"name"`
+	Price   money.Money `json:"price"`
+
+	State State `json:"state" gorm:"foreignKey:StateID;references:ID"`
+}
+
+type ShippingAddress struct {
+	database.BaseModel
+
+	UserID          uuid.UUID `json:"user_id"`
+	FirstName       string    `json:"first_name"`
+	LastName        string    `json:"last_name"`
+	Phone           string    `json:"phone"`
+	AlternatePhone  string    `json:"alternate_phone"`
+	Address         string    `json:"address"`
+	CityID          uuid.UUID `json:"city_id"`
+	StateID         uuid.UUID `json:"state_id"`
+	ClosestLandmark string    `json:"closest_landmark"`
+	IsDefault       bool      `json:"is_default"`
+
+	City  City  `json:"city" gorm:"foreignKey:CityID;references:ID"`
+	State State `json:"state" gorm:"foreignKey:StateID;references:ID"`
+}
+
+type ShippingType struct {
+	database.BaseModel
+
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price"`
+	PricePerKg  money.Money `json:"price_per_kg"`
+	IsActive    bool        `json:"is_active" gorm:"default:true"`
+
+	Rates []ShippingRate `json:"rates" gorm:"foreignKey:ShippingTypeID;references:ID"`
+}
+
+// ShippingRate replaces a shipping type's price for addresses in a state.
+type ShippingRate struct {
+	database.BaseModel
+
+	ShippingTypeID uuid.UUID   `json:"shipping_type_id"`
+	StateID        uuid.UUID   `json:"state_id"`
+	Price          money.Money `json:"price"`
+
+	State State `json:"state" gorm:"foreignKey:StateID;references:ID"`
+}
+
+// OrderShippingAddress is the copy of a shipping address kept on an order, stored in the orders shipping_ columns.
+type OrderShippingAddress struct {
+	FirstName       string `json:"first_name"`
+	LastName        string `json:"last_name"`
+	Phone           string `json:"phone"`
+	AlternatePhone  string `json:"alternate_phone"`
+	Address         string `json:"address"`
+	City            string `json:"city"`
+	State           string `json:"state"`
+	Country         string `json:"country"`
+	ClosestLandmark string `json:"closest_landmark"`
+}
@@ -1,37 +1,90 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 )
 
+// Order is priced in Currency, the currency the customer paid in. ExchangeRate is the price of one unit of it in
+// money.BaseCurrency when the order was placed.
 type Order struct {
 	database.BaseModel
 
-	UserID        uuid.UUID `json:"user_id"`
-	TransactionID uuid.UUID `json:"transaction_id"`
-	PaymentMethod string    `json:"payment_method"`
-	Reference     string    `json:"reference"`
-	TotalPrice    float64   `json:"total_price"`
-	StatusID      uuid.UUID `json:"status_id"`
+	UserID        uuid.UUID   `json:"user_id"`
+	TransactionID uuid.UUID   `json:"transaction_id"`
+	PaymentMethod string      `json:"payment_method"`
+	Reference     string      `json:"reference"`
+	TotalPrice    money.Money `json:"total_price"`
+	Currency      string      `json:"currency"`
+	ExchangeRate  float64     `json:"exchange_rate"`
+	StatusID      uuid.UUID   `json:"status_id"`
+
+	CouponID *uuid.UUID  `json:"coupon_id"`
+	Discount money.Money `json:"discount"`
+
+	// the part of TotalPrice paid from the wallet, Transaction pays the rest. An order paid in full
+	// from the wallet has no WalletTransactionID, its Transaction is the wallet debit.
+	WalletAmount        money.Money `json:"wallet_amount"`
+	WalletTransactionID *uuid.UUID  `json:"wallet_transaction_id"`
+
+	ShippingAddressID *uuid.UUID           `json:"shipping_address_id"`
+	ShippingAddress   OrderShippingAddress `json:"shipping_address" gorm:"embedded;embeddedPrefix:shipping_"`
+	ShippingTypeID    *uuid.UUID           `json:"shipping_type_id"`
 
 	User          User                 `json:"user" gorm:"foreignKey:UserID;references:ID"`
 	OrderItems    []OrderItem          `json:"order_items" gorm:"foreignKey:OrderID;references:ID"`
+	Charges       []OrderCharge        `json:"charges" gorm:"foreignKey:OrderID;references:ID"`
 	Status        OrderStatus          `json:"status" gorm:"foreignKey:StatusID;references:ID"`
 	Transaction   Transaction          `json:"transaction" gorm:"foreignKey:TransactionID;references:ID"`
 	StatusHistory []OrderStatusHistory `json:"status_history" gorm:"foreignKey:OrderID;references:ID"`
 }
 
+// AfterFind puts the order's currency on its amounts, the columns only hold minor units. It runs after the
+// associations are preloaded, so the items and charges get it too.
+func (order *Order) AfterFind(tx *gorm.DB) error {
+	order.TotalPrice.Currency = order.Currency
+	order.Discount.Currency = order.Currency
+	order.WalletAmount.Currency = order.Currency
+
+	for i := range order.OrderItems {
+		order.OrderItems[i].Price.Currency = order.Currency
+		order.OrderItems[i].Discount.Currency = order.Currency
+	}
+
+	for i := range order.Charges {
+		order.Charges[i].Amount.Currency = order.Currency
+	}
+
+	return nil
+}
+
 type OrderItem struct {
 	database.BaseModel
 
-	OrderID   uuid.UUID `json:"order_id"`
-	ProductID uuid.UUID `json:"product_id"`
-	Quantity  int       `json:"quantity"`
-	Price     float64   `json:"price"`
+	OrderID   uuid.UUID   `json:"order_id"`
+	ProductID uuid.UUID   `json:"product_id"`
+	VariantID *uuid.UUID  `json:"variant_id"`
+	Quantity  int         `json:"quantity"`
+	Price     money.Money `json:"price"`
+	Discount  money.Money `json:"discount"` // the line's share of the order's coupon discount
+
+	Product Product         `json:"product" gorm:"foreignKey:ProductID;references:ID"`
+	Variant *ProductVariant `json:"variant" gorm:"foreignKey:VariantID;references:ID"`
+}
+
+// OrderCharge is a line on an order that is not a product, like its shipping fee.
+type OrderCharge struct {
+	database.BaseModel
 
-	Product Product `json:"product" gorm:"foreignKey:ProductID;references:ID"`
+	OrderID uuid.UUID   `json:"order_id"`
+	Type    string      `json:"type"`
+	Name    string      `json:"name"`
+	Amount  money.Money `json:"amount"`
 }
 
 type OrderStatus struct {
@@ -49,3 +102,57 @@ type OrderStatusHistory struct {
 
 	Status OrderStatus `json:"status" gorm:"foreignKey:StatusID;references:ID"`
 }
+
+type Refund struct {
+	database.BaseModel
+
+	OrderID          uuid.UUID   `json:"order_id"`
+	TransactionID    uuid.UUID   `json:"transaction_id"` // the credit transaction paying the refund out
+	Amount           money.Money `json:"amount"`
+	Currency         string      `json:"currency"` // the currency of the order
+	Status           string      `json:"status"`
+	GatewayReference string      `json:"gateway_reference"`
+	Reason           string      `json:"reason"`
+
+	Items       []RefundItem `json:"items" gorm:"foreignKey:RefundID;references:ID"`
+	Transaction Transaction  `json:"transaction" gorm:"foreignKey:TransactionID;references:ID"`
+}
+
+// AfterFind puts the refund's currency on its amounts.
+func (refund *Refund) AfterFind(tx *gorm.DB) error {
+	refund.Amount.Currency = refund.Currency
+
+	for i := range refund.Items {
+		refund.Items[i].Amount.Currency = refund.Currency
+	}
+
+	return nil
+}
+
+type RefundItem struct {
+	database.BaseModel
+
+	RefundID      uuid.UUID   `json:"refund_id"`
+	OrderItemID   *uuid.UUID  `json:"order_item_id"`
+	OrderChargeID *uuid.UUID  `json:"order_charge_id"`
+	Quantity      int         `json:"quantity"`
+	Amount        money.Money `json:"amount"`
+}
+
+// Coupon takes a percentage or an amount off the items in Products, or off every item when it has no products.
+// Value is in hundredths: basis points for a percentage coupon, minor units of money.BaseCurrency for a fixed one.
+type Coupon struct {
+	database.BaseModel
+
+	Code              string      `json:"code"`
+	Description       string      `json:"description"`
+	Type              string      `json:"type"`
+	Value             int64       `json:"value"`
+	MinOrderAmount    money.Money `json:"min_order_amount"`
+	ExpiresAt         *time.Time  `json:"expires_at"`
+	UsageLimit        int         `json:"usage_limit"`          // 0 is unlimited
+	UsageLimitPerUser int         `json:"usage_limit_per_user"` // 0 is unlimited
+	IsActive          bool        `json:"is_active" gorm:"default:true"`
+
+	Products []Product `json:"products" gorm:"many2many:coupon_products"`
+}
@@ -9,12 +9,13 @@ import (
 type User struct {
 	database.BaseModel
 
-	FirstName       string `json:"first_name"`
-	LastName        string `json:"last_name"`
-	Email           string `json:"email"`
-	IsEmailVerified bool   `json:"is_email_verified"`
-	Password        string `json:"password"`
-	Role            string `json:"role"`
+	FirstName          string `json:"first_name"`
+	LastName           string `json:"last_name"`
+	Email              string `json:"email"`
+	IsEmailVerified    bool   `json:"is_email_verified"`
+	Password           string `json:"password"`
+	Role               string `json:"role"`
+	NotifyOrderUpdates bool   `json:"notify_order_updates" gorm:"default:true"`
 }
 
 type VerificationCode struct {
@@ -35,3 +35,7 @@ type VerifyEmailAndCodeRequest struct {
 	Email string `json:"email"`
 	Code  string `json:"code"`
 }
+
+type NotificationPreferencesRequest struct {
+	NotifyOrderUpdates *bool `json:"notify_order_updates"`
+}
@@ -7,13 +7,57 @@ type CreateProductRequest struct {
 	Price         int      `json:"price"`
 	Stock         int      `json:"stock"`
 	SlashPrice    int      `json:"slash_price"`
+	Brand         string   `json:"brand"`
+	Weight        float64  `json:"weight"`
 	Images        []string `json:"images"`
+	CategoryIDs   []string `json:"category_ids"`
 }
 
 type UpdateProductRequest struct {
 	CreateProductRequest
 }
 
+type CreateCategoryRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ParentID    string `json:"parent_id"`
+}
+
+type UpdateCategoryRequest struct {
+	CreateCategoryRequest
+}
+
 type ImageRequest struct {
 	Key string `json:"key"`
 }
+
+// Values are the values of the option in the order they are shown, e.g. ["S", "M", "L"] for a size.
+type CreateProductOptionRequest struct {
+	Name     string   `json:"name"`
+	Position int      `json:"position"`
+	Values   []string `json:"values"`
+}
+
+// Price and SlashPrice are left out for a variant that sells at the product's prices.
+// OptionValueIDs take one value of every option of the product.
+type CreateProductVariantRequest struct {
+	SKU            string   `json:"sku"`
+	Price          *float64 `json:"price"`
+	SlashPrice     *float64 `json:"slash_price"`
+	Stock          int      `json:"stock"`
+	OptionValueIDs []string `json:"option_value_ids"`
+	Images         []string `json:"images"`
+}
+
+type UpdateProductVariantRequest struct {
+	CreateProductVariantRequest
+}
+
+type CreateProductReviewRequest struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+type ModerateProductReviewRequest struct {
+	IsHidden *bool `json:"is_hidden"`
+}
@@ -1,6 +1,14 @@
 package request
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEvent is read first to tell which payload a gateway sent.
+type WebhookEvent struct {
+	Event string `json:"event"`
+}
 
 type PaystackWebhook struct {
 	Event string `json:"event"`
@@ -67,11 +75,11 @@ type FlutterwaveWebhook struct {
 		TxRef             string      `json:"tx_ref"`
 		FlwRef            string      `json:"flw_ref"`
 		DeviceFingerprint string      `json:"device_fingerprint"`
-		Amount            int         `json:"amount"`
+		Amount            float64     `json:"amount"`
 		Currency          string      `json:"currency"`
-		ChargedAmount     int         `json:"charged_amount"`
+		ChargedAmount     float64     `json:"charged_amount"`
 		AppFee            interface{} `json:"app_fee"`
-		MerchantFee       int         `json:"merchant_fee"`
+		MerchantFee       float64     `json:"merchant_fee"`
 		ProcessorResponse string      `json:"processor_response"`
 		AuthModel         string      `json:"auth_model"`
 		IP                string      `json:"ip"`
@@ -97,3 +105,42 @@ type FlutterwaveWebhook struct {
 		} `json:"card"`
 	} `json:"data"`
 }
+
+type PaystackRefundWebhook struct {
+	Event string `json:"event"`
+	Data  struct {
+		ID                   json.Number `json:"id"`
+		Status               string      `json:"status"`
+		TransactionReference string      `json:"transaction_reference"`
+		Amount               json.Number `json:"amount"`
+		Currency             string      `json:"currency"`
+	} `json:"data"`
+}
+
+type FlutterwaveRefundWebhook struct {
+	Event string `json:"event"`
+	Data  struct {
+		ID             int     `json:"id"`
+		TxID           int     `json:"tx_id"`
+		FlwRef         string  `json:"flw_ref"`
+		AmountRefunded float64 `json:"amount_refunded"`
+		Status         string  `json:"status"`
+	} `json:"data"`
+}
+
+// Rate is the price of one unit of Currency in the base currency, Currency is read from the path.
+type SetCurrencyRateRequest struct {
+	Currency string  `json:"-"`
+	Rate     float64 `json:"rate"`
+}
+
+type WalletTopUpRequest struct {
+	Amount        float64 `json:"amount"`
+	PaymentMethod string  `json:"payment_method"`
+}
+
+// FromDate and ToDate are YYYY-MM-DD, the report takes in the whole of ToDate.
+type ReconcileRequest struct {
+	FromDate string `json:"from_date"`
+	ToDate   string `json:"to_date"`
+}
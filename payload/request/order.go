@@ -1,19 +1,60 @@
 package request
 
+import "time"
+
+// ShippingAddressID defaults to the user's default address when it is empty.
+// Items are ignored when FromCart is set, the order is placed with the items in the customer's cart.
+// WalletAmount is paid from the wallet and the payment method pays the rest, it is not needed when the wallet pays it all.
 type CreateOrderRequest struct {
-	PaymentMethod string                   `json:"payment_method"`
-	Items         []CreateOrderRequestItem `json:"items"`
+	PaymentMethod     string                   `json:"payment_method"`
+	WalletAmount      float64                  `json:"wallet_amount"`
+	ShippingAddressID string                   `json:"shipping_address_id"`
+	ShippingTypeID    string                   `json:"shipping_type_id"`
+	CouponCode        string                   `json:"coupon_code"`
+	Currency          string                   `json:"currency"`
+	FromCart          bool                     `json:"from_cart"`
+	Items             []CreateOrderRequestItem `json:"items"`
 }
 
+// VariantID is required for products with variants.
 type CreateOrderRequestItem struct {
 	ProductID string `json:"product_id"`
-	Quantity  int    `json:"quantity"` // TODO: quantity should be greater than 0
+	VariantID string `json:"variant_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+type CreateRefundRequest struct {
+	Reason        string                    `json:"reason"`
+	RefundCharges bool                      `json:"refund_charges"`
+	ToWallet      bool                      `json:"to_wallet"`
+	Items         []CreateRefundRequestItem `json:"items"`
+}
+
+// CancelOrderRequest refunds a cancelled order in full, to the wallet when ToWallet is set.
+type CancelOrderRequest struct {
+	Reason   string `json:"reason"`
+	ToWallet bool   `json:"to_wallet"`
+}
+
+// Quantity 0 refunds whatever is left of the order item.
+type CreateRefundRequestItem struct {
+	OrderItemID string `json:"order_item_id"`
+	Quantity    int    `json:"quantity"`
 }
 
+// Rates replace Price for addresses in their state. PricePerKg is added for every kg the order weighs.
 type CreateShippingTypeRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Price       int    `json:"price"`
+	Name        string                      `json:"name"`
+	Description string                      `json:"description"`
+	Price       float64                     `json:"price"`
+	PricePerKg  float64                     `json:"price_per_kg"`
+	IsActive    *bool                       `json:"is_active"`
+	Rates       []CreateShippingRateRequest `json:"rates"`
+}
+
+type CreateShippingRateRequest struct {
+	StateID string  `json:"state_id"`
+	Price   float64 `json:"price"`
 }
 
 type UpdateShippingTypeRequest struct {
@@ -29,6 +70,7 @@ type CreateShippingAddressRequest struct {
 	CityID          string `json:"city_id"`
 	StateID         string `json:"state_id"`
 	ClosestLandmark string `json:"closest_landmark"`
+	IsDefault       bool   `json:"is_default"`
 }
 
 type UpdateShippingAddressRequest struct {
@@ -63,8 +105,32 @@ type CreateCityRequest struct {
 	Price float64 `json:"price"`
 }
 
+// A coupon without ProductIDs applies to every product. A usage limit of 0 is unlimited.
 type CreateCouponRequest struct {
-	Type        string `json:"type"`
-	Value       int    `json:"value"`
-	Description string `json:"description"`
+	Code              string     `json:"code"`
+	Type              string     `json:"type"`
+	Value             float64    `json:"value"`
+	Description       string     `json:"description"`
+	MinOrderAmount    float64    `json:"min_order_amount"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+	UsageLimit        int        `json:"usage_limit"`
+	UsageLimitPerUser int        `json:"usage_limit_per_user"`
+	IsActive          *bool      `json:"is_active"`
+	ProductIDs        []string   `json:"product_ids"`
+}
+
+type UpdateCouponRequest struct {
+	CreateCouponRequest
+}
+
+// VariantID is required for products with variants.
+type AddCartItemRequest struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// Quantity 0 removes the product from the cart.
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity"`
 }
@@ -7,19 +7,91 @@ import (
 )
 
 type ProductResponse struct {
-	UUID          uuid.UUID       `json:"id"`
-	Slug          string          `json:"slug"`
-	Name          string          `json:"name"`
-	Description   string          `json:"description"`
-	Specification string          `json:"specification"`
-	Price         float64         `json:"price"`
-	SlashPrice    float64         `json:"slash_price"`
-	Stock         int             `json:"stock"`
-	Sales         int             `json:"sales"`
-	Images        []ImageResponse `json:"images"`
-	CreatedAt     time.Time       `json:"created_at"`
+	UUID          uuid.UUID                 `json:"id"`
+	Slug          string                    `json:"slug"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description"`
+	Specification string                    `json:"specification"`
+	Price         float64                   `json:"price"`
+	SlashPrice    float64                   `json:"slash_price"`
+	Currency      string                    `json:"currency"`
+	Stock         int                       `json:"stock"`
+	Brand         string                    `json:"brand"`
+	Weight        float64                   `json:"weight"`
+	Sales         int                       `json:"sales"`
+	Rating        float64                   `json:"rating"`
+	ReviewCount   int                       `json:"review_count"`
+	Images        []ImageResponse           `json:"images"`
+	Categories    []ProductCategoryResponse `json:"categories"`
+	Options       []ProductOptionResponse   `json:"options"`
+	Variants      []ProductVariantResponse  `json:"variants"`
+	CreatedAt     time.Time                 `json:"created_at"`
+}
+
+type ProductOptionResponse struct {
+	ID       uuid.UUID                    `json:"id"`
+	Name     string                       `json:"name"`
+	Position int                          `json:"position"`
+	Values   []ProductOptionValueResponse `json:"values"`
+}
+
+type ProductOptionValueResponse struct {
+	ID    uuid.UUID `json:"id"`
+	Value string    `json:"value"`
+}
+
+// Price and SlashPrice are what the variant sells at, the product's prices when it has none of its own.
+type ProductVariantResponse struct {
+	ID         uuid.UUID              `json:"id"`
+	SKU        string                 `json:"sku"`
+	Price      float64                `json:"price"`
+	SlashPrice float64                `json:"slash_price"`
+	Stock      int                    `json:"stock"`
+	Values     []VariantValueResponse `json:"values"`
+	Images     []ImageResponse        `json:"images"`
+}
+
+type VariantValueResponse struct {
+	OptionID uuid.UUID `json:"option_id"`
+	Option   string    `json:"option"`
+	ValueID  uuid.UUID `json:"value_id"`
+	Value    string    `json:"value"`
+}
+
+// Reviewer is the first name and the initial of the last name of the customer.
+type ProductReviewResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Reviewer  string    `json:"reviewer"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment"`
+	IsHidden  bool      `json:"is_hidden"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ProductCategoryResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Slug string    `json:"slug"`
+	Name string    `json:"name"`
+}
+
+type CategoryResponse struct {
+	ID          uuid.UUID          `json:"id"`
+	Slug        string             `json:"slug"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	ParentID    *uuid.UUID         `json:"parent_id"`
+	Children    []CategoryResponse `json:"children"`
 }
 
 type ImageResponse struct {
 	Key string `json:"key"`
 }
+
+type InventoryMovementResponse struct {
+	UUID      uuid.UUID `json:"id"`
+	OrderID   uuid.UUID `json:"order_id"`
+	Reference string    `json:"reference"`
+	Type      string    `json:"type"`
+	Quantity  int       `json:"quantity"`
+	CreatedAt time.Time `json:"created_at"`
+}
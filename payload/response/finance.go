@@ -9,6 +9,7 @@ import (
 type TransactionResponse struct {
 	ID          uuid.UUID `json:"id"`
 	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
 	Type        string    `json:"type"`
 	Reference   string    `json:"reference"`
 	Description string    `json:"description"`
@@ -18,3 +19,83 @@ type TransactionResponse struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+type PaymentMethodResponse struct {
+	Name string `json:"name"`
+}
+
+type CurrencyRateResponse struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WalletResponse struct {
+	Balance  float64 `json:"balance"`
+	Currency string  `json:"currency"`
+}
+
+type LedgerAccountResponse struct {
+	Code   string     `json:"code"`
+	Name   string     `json:"name"`
+	Type   string     `json:"type"`
+	UserID *uuid.UUID `json:"user_id"`
+}
+
+// Balance is Debit less Credit.
+type TrialBalanceLineResponse struct {
+	Account  LedgerAccountResponse `json:"account"`
+	Currency string                `json:"currency"`
+	Debit    float64               `json:"debit"`
+	Credit   float64               `json:"credit"`
+	Balance  float64               `json:"balance"`
+}
+
+type TrialBalanceTotalResponse struct {
+	Debit  float64 `json:"debit"`
+	Credit float64 `json:"credit"`
+}
+
+type AccountStatementResponse struct {
+	Account        LedgerAccountResponse          `json:"account"`
+	Currency       string                         `json:"currency"`
+	OpeningBalance float64                        `json:"opening_balance"`
+	ClosingBalance float64                        `json:"closing_balance"`
+	Lines          []AccountStatementLineResponse `json:"lines"`
+}
+
+type AccountStatementLineResponse struct {
+	EntryID              uuid.UUID `json:"entry_id"`
+	Description          string    `json:"description"`
+	TransactionReference string    `json:"transaction_reference"`
+	Amount               float64   `json:"amount"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+type ReconciliationReportResponse struct {
+	ID            uuid.UUID                    `json:"id"`
+	FromDate      time.Time                    `json:"from_date"`
+	ToDate        time.Time                    `json:"to_date"`
+	Matched       int                          `json:"matched"`
+	Discrepancies int                          `json:"discrepancies"`
+	Healed        int                          `json:"healed"`
+	Errors        []string                     `json:"errors"`
+	Items         []ReconciliationItemResponse `json:"items,omitempty"`
+	CreatedAt     time.Time                    `json:"created_at"`
+}
+
+// The local and provider amounts are in their own currency, zero on the side that does not have the payment.
+type ReconciliationItemResponse struct {
+	Gateway          string     `json:"gateway"`
+	Reference        string     `json:"reference"`
+	TransactionID    *uuid.UUID `json:"transaction_id"`
+	Kind             string     `json:"kind"`
+	LocalStatus      string     `json:"local_status"`
+	LocalAmount      float64    `json:"local_amount"`
+	LocalCurrency    string     `json:"local_currency"`
+	ProviderStatus   string     `json:"provider_status"`
+	ProviderAmount   float64    `json:"provider_amount"`
+	ProviderCurrency string     `json:"provider_currency"`
+	Healed           bool       `json:"healed"`
+	Note             string     `json:"note"`
+}
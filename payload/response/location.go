@@ -0,0 +1,67 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CountryResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type StateResponse struct {
+	ID        uuid.UUID `json:"id"`
+	CountryID uuid.UUID `json:"country_id"`
+	Name      string    `json:"name"`
+}
+
+type CityResponse struct {
+	ID      uuid.UUID `json:"id"`
+	StateID uuid.UUID `json:"state_id"`
+	Name    string    `json:"name"`
+	Price   float64   `json:"price"`
+}
+
+type ShippingAddressResponse struct {
+	ID              uuid.UUID       `json:"id"`
+	FirstName       string          `json:"first_name"`
+	LastName        string          `json:"last_name"`
+	Phone           string          `json:"phone"`
+	AlternatePhone  string          `json:"alternate_phone"`
+	Address         string          `json:"address"`
+	City            CityResponse    `json:"city"`
+	State           StateResponse   `json:"state"`
+	Country         CountryResponse `json:"country"`
+	ClosestLandmark string          `json:"closest_landmark"`
+	IsDefault       bool            `json:"is_default"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+type OrderShippingAddressResponse struct {
+	FirstName       string `json:"first_name"`
+	LastName        string `json:"last_name"`
+	Phone           string `json:"phone"`
+	AlternatePhone  string `json:"alternate_phone"`
+	Address         string `json:"address"`
+	City            string `json:"city"`
+	State           string `json:"state"`
+	Country         string `json:"country"`
+	ClosestLandmark string `json:"closest_landmark"`
+}
+
+type ShippingTypeResponse struct {
+	ID          uuid.UUID              `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Price       float64                `json:"price"`
+	PricePerKg  float64                `json:"price_per_kg"`
+	IsActive    bool                   `json:"is_active"`
+	Rates       []ShippingRateResponse `json:"rates"`
+}
+
+type ShippingRateResponse struct {
+	StateID uuid.UUID `json:"state_id"`
+	Price   float64   `json:"price"`
+}
@@ -13,17 +13,36 @@ type OrderResponse struct {
 	PaymentMethod string                       `json:"payment_method"`
 	Reference     string                       `json:"reference"`
 	TotalPrice    float64                      `json:"total_price"`
+	Discount      float64                      `json:"discount"`
+	Currency      string                       `json:"currency"`
+	ExchangeRate  float64                      `json:"exchange_rate"`
+	WalletAmount  float64                      `json:"wallet_amount"`
+	CouponID      *uuid.UUID                   `json:"coupon_id"`
 	OrderItems    []OrderItemResponse          `json:"order_items"`
+	Charges       []OrderChargeResponse        `json:"charges"`
 	Status        OrderStatusResponse          `json:"status"`
 	StatusHistory []OrderStatusHistoryResponse `json:"status_history"`
 	Transaction   TransactionResponse          `json:"transaction"`
 	User          UserResponseData             `json:"user"`
+
+	ShippingTypeID  *uuid.UUID                   `json:"shipping_type_id"`
+	ShippingAddress OrderShippingAddressResponse `json:"shipping_address"`
+}
+
+type OrderChargeResponse struct {
+	ID     uuid.UUID `json:"id"`
+	Type   string    `json:"type"`
+	Name   string    `json:"name"`
+	Amount float64   `json:"amount"`
 }
 
 type OrderItemResponse struct {
-	Product  ProductResponse `json:"product"`
-	Quantity int             `json:"quantity"`
-	Price    float64         `json:"price"`
+	ID       uuid.UUID               `json:"id"`
+	Product  ProductResponse         `json:"product"`
+	Variant  *ProductVariantResponse `json:"variant"`
+	Quantity int                     `json:"quantity"`
+	Price    float64                 `json:"price"`
+	Discount float64                 `json:"discount"`
 }
 
 type OrderStatusResponse struct {
@@ -35,3 +54,56 @@ type OrderStatusHistoryResponse struct {
 	Status    OrderStatusResponse `json:"status"`
 	CreatedAt time.Time           `json:"created_at"`
 }
+
+type OrderTransitionResponse struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Guards []string `json:"guards"`
+}
+
+type RefundResponse struct {
+	ID               uuid.UUID            `json:"id"`
+	Amount           float64              `json:"amount"`
+	Currency         string               `json:"currency"`
+	Status           string               `json:"status"`
+	GatewayReference string               `json:"gateway_reference"`
+	Reason           string               `json:"reason"`
+	Items            []RefundItemResponse `json:"items"`
+	CreatedAt        time.Time            `json:"created_at"`
+}
+
+type RefundItemResponse struct {
+	OrderItemID   *uuid.UUID `json:"order_item_id"`
+	OrderChargeID *uuid.UUID `json:"order_charge_id"`
+	Quantity      int        `json:"quantity"`
+	Amount        float64    `json:"amount"`
+}
+
+type CouponResponse struct {
+	ID                uuid.UUID   `json:"id"`
+	Code              string      `json:"code"`
+	Description       string      `json:"description"`
+	Type              string      `json:"type"`
+	Value             float64     `json:"value"`
+	MinOrderAmount    float64     `json:"min_order_amount"`
+	ExpiresAt         *time.Time  `json:"expires_at"`
+	UsageLimit        int         `json:"usage_limit"`
+	UsageLimitPerUser int         `json:"usage_limit_per_user"`
+	IsActive          bool        `json:"is_active"`
+	ProductIDs        []uuid.UUID `json:"product_ids"`
+	CreatedAt         time.Time   `json:"created_at"`
+}
+
+type CartResponse struct {
+	Items      []CartItemResponse `json:"items"`
+	TotalPrice float64            `json:"total_price"`
+}
+
+type CartItemResponse struct {
+	Product   ProductResponse         `json:"product"`
+	Variant   *ProductVariantResponse `json:"variant"`
+	Quantity  int                     `json:"quantity"`
+	UnitPrice float64                 `json:"unit_price"`
+	Price     float64                 `json:"price"`
+	Warning   string                  `json:"warning,omitempty"`
+}
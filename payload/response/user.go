@@ -1,6 +1,10 @@
 package response
 
 import (
+	"time"
+
+	"github.com/google/uuid"
+
 	"github.com/developer-afo/instashop-ecommerce-api/dto"
 )
 
@@ -20,3 +24,17 @@ type UserResponseData struct {
 	LastName  string `json:"last_name"`
 	Email     string `json:"email"`
 }
+
+type UserListResponse struct {
+	ID              uuid.UUID `json:"id"`
+	FirstName       string    `json:"first_name"`
+	LastName        string    `json:"last_name"`
+	Email           string    `json:"email"`
+	ReferralCode    string    `json:"referral_code"`
+	IsEmailVerified bool      `json:"is_email_verified"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type NotificationPreferencesResponse struct {
+	NotifyOrderUpdates bool `json:"notify_order_updates"`
+}
@@ -0,0 +1,124 @@
+package core_repository
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+)
+
+// categorySubtreeQuery selects the ids of a category and all of its descendants.
+// The category is matched on the condition it is formatted with.
+const categorySubtreeQuery = `WITH RECURSIVE category_tree AS (
+	SELECT categories.id FROM categories WHERE %s AND categories.deleted_at IS NULL
+	UNION ALL
+	SELECT categories.id FROM categories
+	JOIN category_tree ON categories.parent_id = category_tree.id
+	WHERE categories.deleted_at IS NULL
+) SELECT id FROM category_tree`
+
+// CategoryRepositoryInterface is a contract that defines the methods to be implemented by CategoryRepository.
+type CategoryRepositoryInterface interface {
+	CreateCategory(category models.Category) (models.Category, error)
+	FindCategoryById(uuid uuid.UUID) (models.Category, error)
+	FindCategoryBySlug(slug string) (models.Category, error)
+	FindCategoriesByIds(uuids []uuid.UUID) ([]models.Category, error)
+	FindAllCategories() ([]models.Category, error)
+	FindSubtreeIds(uuid uuid.UUID) ([]uuid.UUID, error)
+	CountChildren(uuid uuid.UUID) (int64, error)
+	UpdateCategory(category models.Category) (models.Category, error)
+	DeleteCategory(uuid uuid.UUID) error
+	WithTx(tx database.DatabaseInterface) CategoryRepositoryInterface
+}
+
+// categoryRepository is a struct that defines the database connection.
+type categoryRepository struct {
+	database database.DatabaseInterface
+}
+
+// NewCategoryRepository is a function that returns a new instance of CategoryRepository.
+func NewCategoryRepository(database database.DatabaseInterface) CategoryRepositoryInterface {
+	return &categoryRepository{database: database}
+}
+
+// WithTx is a method that returns a CategoryRepository bound to the given transaction.
+func (c *categoryRepository) WithTx(tx database.DatabaseInterface) CategoryRepositoryInterface {
+	return &categoryRepository{database: tx}
+}
+
+// CreateCategory is a method that creates a new category.
+func (c *categoryRepository) CreateCategory(category models.Category) (models.Category, error) {
+	category.Prepare()
+
+	err := c.database.Connection().Create(&category).Error
+
+	return category, err
+}
+
+// FindCategoryById is a method that returns a category by its ID.
+func (c *categoryRepository) FindCategoryById(uuid uuid.UUID) (category models.Category, err error) {
+	err = c.database.Connection().Model(&models.Category{}).Where("id = ?", uuid).First(&category).Error
+
+	return category, err
+}
+
+// FindCategoryBySlug is a method that returns a category by its slug.
+func (c *categoryRepository) FindCategoryBySlug(slug string) (category models.Category, err error) {
+	err = c.database.Connection().Model(&models.Category{}).Where("slug = ?", slug).First(&category).Error
+
+	return category, err
+}
+
+// FindCategoriesByIds is a method that returns the categories found with the given IDs.
+func (c *categoryRepository) FindCategoriesByIds(uuids []uuid.UUID) (categories []models.Category, err error) {
+	err = c.database.Connection().Model(&models.Category{}).Where("id IN ?", uuids).Find(&categories).Error
+
+	return categories, err
+}
+
+// FindAllCategories is a method that returns every category, sorted by name.
+func (c *categoryRepository) FindAllCategories() (categories []models.Category, err error) {
+	err = c.database.Connection().Model(&models.Category{}).Order("name ASC").Find(&categories).Error
+
+	return categories, err
+}
+
+// FindSubtreeIds is a method that returns the IDs of a category and of all the categories below it.
+func (c *categoryRepository) FindSubtreeIds(uuid uuid.UUID) (ids []uuid.UUID, err error) {
+	err = c.database.Connection().
+		Raw(fmt.Sprintf(categorySubtreeQuery, "categories.id = ?"), uuid).
+		Scan(&ids).Error
+
+	return ids, err
+}
+
+// CountChildren is a method that returns the number of categories directly below a category.
+func (c *categoryRepository) CountChildren(uuid uuid.UUID) (count int64, err error) {
+	err = c.database.Connection().Model(&models.Category{}).Where("parent_id = ?", uuid).Count(&count).Error
+
+	return count, err
+}
+
+// UpdateCategory is a method that updates a category. The slug is kept so links to the category keep working.
+func (c *categoryRepository) UpdateCategory(category models.Category) (models.Category, error) {
+	err := c.database.Connection().
+		Model(&models.Category{}).
+		Where("id = ?", category.ID).
+		Select("name", "description", "parent_id").
+		Updates(&category).Error
+
+	return category, err
+}
+
+// DeleteCategory is a method that deletes a category.
+func (c *categoryRepository) DeleteCategory(uuid uuid.UUID) error {
+	category, err := c.FindCategoryById(uuid)
+
+	if err != nil {
+		return err
+	}
+
+	return c.database.Connection().Delete(&category).Error
+}
@@ -0,0 +1,55 @@
+package core_repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+)
+
+type InventoryMovementRepositoryInterface interface {
+	CreateInventoryMovement(movement models.InventoryMovement) (int64, error)
+	FindInventoryMovementsByProductId(productId uuid.UUID) ([]models.InventoryMovement, error)
+	WithTx(tx database.DatabaseInterface) InventoryMovementRepositoryInterface
+}
+
+type inventoryMovementRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewInventoryMovementRepository(database database.DatabaseInterface) InventoryMovementRepositoryInterface {
+	return &inventoryMovementRepository{database: database}
+}
+
+// WithTx implements InventoryMovementRepositoryInterface.
+func (i *inventoryMovementRepository) WithTx(tx database.DatabaseInterface) InventoryMovementRepositoryInterface {
+	return &inventoryMovementRepository{database: tx}
+}
+
+// CreateInventoryMovement implements InventoryMovementRepositoryInterface.
+// It returns 0 rows affected when the order item already has a movement of the same type.
+func (i *inventoryMovementRepository) CreateInventoryMovement(movement models.InventoryMovement) (int64, error) {
+	movement.Prepare()
+
+	result := i.database.Connection().
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "order_item_id"}, {Name: "type"}},
+			DoNothing: true,
+		}).
+		Create(&movement)
+
+	return result.RowsAffected, result.Error
+}
+
+// FindInventoryMovementsByProductId implements InventoryMovementRepositoryInterface.
+func (i *inventoryMovementRepository) FindInventoryMovementsByProductId(productId uuid.UUID) (movements []models.InventoryMovement, err error) {
+
+	err = i.database.Connection().
+		Model(&models.InventoryMovement{}).
+		Where("product_id = ?", productId).
+		Order("created_at DESC").
+		Find(&movements).Error
+
+	return movements, err
+}
@@ -1,28 +1,105 @@
 package core_repository
 
 import (
+	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 	"github.com/developer-afo/instashop-ecommerce-api/models"
 	"github.com/developer-afo/instashop-ecommerce-api/repository"
 )
 
+// ProductPageable filters products on Category, a category slug. Products in the categories below it are included.
+// MinPrice and MaxPrice are ignored when nil, InStock and OnSale when false.
 type ProductPageable struct {
 	repository.Pageable
+
+	Category string
+	Brand    string
+	MinPrice *money.Money
+	MaxPrice *money.Money
+	InStock  bool
+	OnSale   bool
+}
+
+// ProductSortFields are the fields products can be sorted on, in stock products always come first.
+var ProductSortFields = repository.SortFields{
+	"price":      "products.price",
+	"sales":      productSalesQuery,
+	"rating":     productRatingQuery,
+	"created_at": "products.created_at",
+	"name":       "products.name",
+}
+
+// ProductFacets counts the products found with a ProductPageable by brand and by price.
+// Each facet ignores its own filter, so a client can show the other brands or prices it could pick.
+type ProductFacets struct {
+	Brands []BrandFacet `json:"brands"`
+	Prices []PriceFacet `json:"prices"`
+}
+
+type BrandFacet struct {
+	Brand string `json:"brand"`
+	Count int64  `json:"count"`
+}
+
+// PriceFacet counts the products with Min <= price < Max, the last bucket has no Max. Both are in major units.
+type PriceFacet struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int64    `json:"count"`
 }
 
+// PriceFacetBounds are the lower bounds of the price buckets of ProductFacets, in major units of money.BaseCurrency.
+var PriceFacetBounds = []float64{0, 5000, 10000, 25000, 50000, 100000}
+
+// productSalesQuery is the number of items of a product that were ordered, it needs the join on order_items.
+const productSalesQuery = "COALESCE(SUM(order_items.quantity), 0)"
+
+// productRatingQuery is the average rating of the visible reviews of a product, 0 without reviews.
+// It is rounded so a keyset cursor compares the same value it was made with.
+const productRatingQuery = `(SELECT ROUND(COALESCE(AVG(product_reviews.rating), 0), 2) FROM product_reviews
+	WHERE product_reviews.product_id = products.id AND product_reviews.is_hidden = FALSE AND product_reviews.deleted_at IS NULL)`
+
+// productReviewCountQuery is the number of visible reviews of a product.
+const productReviewCountQuery = `(SELECT COUNT(*) FROM product_reviews
+	WHERE product_reviews.product_id = products.id AND product_reviews.is_hidden = FALSE AND product_reviews.deleted_at IS NULL)`
+
+// productAggregateSelects are the columns of a product with its sales, rating and review count, it needs the join on order_items.
+const productAggregateSelects = "products.*, " + productSalesQuery + " as sales, " +
+	productRatingQuery + " as rating, " + productReviewCountQuery + " as review_count"
+
+// productInStockQuery is true for a product with stock, a product with variants has stock when one of its variants has.
+const productInStockQuery = `(products.stock > 0 OR EXISTS (
+	SELECT 1 FROM product_variants
+	WHERE product_variants.product_id = products.id AND product_variants.stock > 0 AND product_variants.deleted_at IS NULL
+))`
+
+// facets a ProductPageable can be counted on, filterProducts leaves out the filter of the facet it is counting
+const (
+	facetBrand = "brand"
+	facetPrice = "price"
+)
+
 // ProductRepositoryInterface is a contract that defines the methods to be implemented by ProductRepository.
 type ProductRepositoryInterface interface {
 	FindAllProducts(pageable ProductPageable) ([]models.Product, repository.Pagination, error)
+	FindProductFacets(pageable ProductPageable) (ProductFacets, error)
 	CreateProduct(product models.Product) (models.Product, error)
 	FindProductByUUID(uuid uuid.UUID) (models.Product, error)
 	FindProductBySlug(slug string) (models.Product, error)
 	UpdateProduct(product models.Product) (models.Product, error)
 	DeleteProduct(uuid uuid.UUID) error
+	DecrementStock(uuid uuid.UUID, quantity int) (int64, error)
+	IncrementStock(uuid uuid.UUID, quantity int) error
+	DecrementVariantStock(uuid uuid.UUID, quantity int) (int64, error)
+	IncrementVariantStock(uuid uuid.UUID, quantity int) error
+	WithTx(tx database.DatabaseInterface) ProductRepositoryInterface
 }
 
 // productRepository is a struct that defines the database connection.
@@ -35,6 +112,28 @@ func NewProductRepository(database database.DatabaseInterface) ProductRepository
 	return &productRepository{database: database}
 }
 
+// WithTx is a method that returns a ProductRepository bound to the given transaction.
+func (p *productRepository) WithTx(tx database.DatabaseInterface) ProductRepositoryInterface {
+	return &productRepository{database: tx}
+}
+
+// preloadVariants is a method that loads a product's images, options and variants, in the order they are shown.
+func preloadVariants(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Images", "variant_id IS NULL").
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("product_options.position ASC, product_options.created_at ASC")
+		}).
+		Preload("Options.Values", func(db *gorm.DB) *gorm.DB {
+			return db.Order("product_option_values.position ASC, product_option_values.created_at ASC")
+		}).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("product_variants.created_at ASC")
+		}).
+		Preload("Variants.Values").
+		Preload("Variants.Images")
+}
+
 // FindAllProducts is a method that returns all products.
 func (p *productRepository) FindAllProducts(pageable ProductPageable) ([]models.Product, repository.Pagination, error) {
 	var products []models.Product
@@ -48,20 +147,35 @@ func (p *productRepository) FindAllProducts(pageable ProductPageable) ([]models.
 	pagination.TotalPages = 1
 
 	offset := (pageable.Page - 1) * pageable.Size
-	model := p.database.Connection().
-		Model(&product).
-		Preload("Images").
-		Select("products.*, COALESCE(SUM(order_items.quantity), 0) as sales").
+	selects := productAggregateSelects
+
+	// without a sort the best matches of a search come first, searchQuery only leaves letters and digits to quote
+	fallback := repository.SortKey{Column: "products.created_at", Desc: true}
+	if query := searchQuery(pageable.Search); query != "" {
+		fallback = repository.SortKey{Column: fmt.Sprintf("ts_rank(products.search_vector, to_tsquery('english', '%s'))", query), Desc: true}
+		selects += ", " + fallback.Column + " as search_rank"
+	}
+
+	// a product with variants is out of stock when none of its variants has stock
+	keys := append(
+		[]repository.SortKey{{Column: "CASE WHEN " + productInStockQuery + " THEN 0 ELSE 1 END"}},
+		ProductSortFields.Keys(pageable.Sort, "products.id", fallback)...,
+	)
+
+	model := preloadVariants(p.database.Connection().Model(&product)).
+		Preload("Categories").
+		Select(selects).
 		Joins("LEFT JOIN order_items ON order_items.product_id = products.id").
-		Group("products.id").
-		Order("CASE WHEN products.stock = 0 THEN 1 ELSE 0 END ASC")
+		Group("products.id")
 
-	if len(strings.TrimSpace(pageable.Search)) > 0 {
-		model = model.Where("LOWER(products.name) LIKE ?", "%"+strings.ToLower(pageable.Search)+"%") // Specify the table name 'products' for the 'name' column
+	model = filterProducts(model, pageable, "")
+
+	if pageable.Keyset {
+		return p.findProductsByKeyset(model, keys, pageable)
 	}
 
 	errCount = model.Count(&pagination.TotalItems).Error
-	paginatedQuery := model.Offset(int(offset)).Limit(int(pageable.Size))
+	paginatedQuery := model.Offset(int(offset)).Limit(int(pageable.Size)).Order(repository.OrderBy(keys, false))
 	result = paginatedQuery.Model(&models.Product{}).Where(product).Find(&products)
 
 	if result.Error != nil {
@@ -77,21 +191,200 @@ func (p *productRepository) FindAllProducts(pageable ProductPageable) ([]models.
 	return products, pagination, nil
 }
 
+// findProductsByKeyset is a method that returns the page of products after or before the cursor of pageable.
+// The sales are an aggregate, so the cursor is applied in HAVING.
+func (p *productRepository) findProductsByKeyset(model *gorm.DB, keys []repository.SortKey, pageable ProductPageable) ([]models.Product, repository.Pagination, error) {
+	var products []models.Product
+
+	query, err := repository.KeysetQuery(model, keys, pageable.Pageable, true)
+
+	if err != nil {
+		return nil, repository.Pagination{}, err
+	}
+
+	if err := query.Find(&products).Error; err != nil {
+		return nil, repository.Pagination{}, err
+	}
+
+	products, pagination := repository.KeysetPage(products, pageable.Pageable, func(product models.Product) []interface{} {
+		values := []interface{}{productOutOfStock(product)}
+
+		for _, key := range keys[1:] {
+			switch key.Column {
+			case ProductSortFields["price"]:
+				values = append(values, product.Price.Amount)
+			case ProductSortFields["sales"]:
+				values = append(values, product.Sales)
+			case ProductSortFields["rating"]:
+				values = append(values, product.Rating)
+			case ProductSortFields["created_at"]:
+				values = append(values, product.CreatedAt)
+			case ProductSortFields["name"]:
+				values = append(values, product.Name)
+			case "products.id":
+				values = append(values, product.ID)
+			default:
+				values = append(values, product.SearchRank)
+			}
+		}
+
+		return values
+	})
+
+	return products, pagination, nil
+}
+
+// productOutOfStock is a function that returns the value productInStockQuery sorts a loaded product on.
+func productOutOfStock(product models.Product) int {
+	if product.Stock > 0 {
+		return 0
+	}
+
+	for _, variant := range product.Variants {
+		if variant.Stock > 0 {
+			return 0
+		}
+	}
+
+	return 1
+}
+
+// searchQuery is a function that turns a search into a tsquery matching every word, the last one as a prefix.
+// Characters with a meaning in a tsquery are dropped, it returns an empty string when no word is left.
+func searchQuery(search string) string {
+	words := []string{}
+
+	for _, word := range strings.Fields(strings.ToLower(search)) {
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+
+			return -1
+		}, word)
+
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+
+	if len(words) == 0 {
+		return ""
+	}
+
+	// a word being typed is matched as a prefix, "sneak" finds sneakers
+	words[len(words)-1] += ":*"
+
+	return strings.Join(words, " & ")
+}
+
+// filterProducts is a function that applies the filters of pageable to a query on products, except the one of the facet.
+func filterProducts(model *gorm.DB, pageable ProductPageable, except string) *gorm.DB {
+	if len(strings.TrimSpace(pageable.Category)) > 0 {
+		model = model.Where(
+			"products.id IN (SELECT product_categories.product_id FROM product_categories WHERE product_categories.category_id IN ("+
+				fmt.Sprintf(categorySubtreeQuery, "categories.slug = ?")+"))",
+			strings.TrimSpace(pageable.Category),
+		)
+	}
+
+	if query := searchQuery(pageable.Search); query != "" {
+		model = model.Where("products.search_vector @@ to_tsquery('english', ?)", query)
+	}
+
+	if len(strings.TrimSpace(pageable.Brand)) > 0 && except != facetBrand {
+		model = model.Where("LOWER(products.brand) = ?", strings.ToLower(strings.TrimSpace(pageable.Brand)))
+	}
+
+	if pageable.MinPrice != nil && except != facetPrice {
+		model = model.Where("products.price >= ?", *pageable.MinPrice)
+	}
+
+	if pageable.MaxPrice != nil && except != facetPrice {
+		model = model.Where("products.price <= ?", *pageable.MaxPrice)
+	}
+
+	if pageable.InStock {
+		model = model.Where(productInStockQuery)
+	}
+
+	if pageable.OnSale {
+		model = model.Where("products.slash_price > 0")
+	}
+
+	return model
+}
+
+// FindProductFacets is a method that counts the products found with pageable by brand and by price bucket.
+// Products without a brand are left out of the brands.
+func (p *productRepository) FindProductFacets(pageable ProductPageable) (facets ProductFacets, err error) {
+	facets.Brands = []BrandFacet{}
+	facets.Prices = []PriceFacet{}
+
+	err = filterProducts(p.database.Connection().Model(&models.Product{}), pageable, facetBrand).
+		Select("products.brand AS brand, COUNT(*) AS count").
+		Where("products.brand <> ''").
+		Group("products.brand").
+		Order("count DESC, brand ASC").
+		Scan(&facets.Brands).Error
+
+	if err != nil {
+		return facets, err
+	}
+
+	for i, min := range PriceFacetBounds {
+		facet := PriceFacet{Min: min}
+
+		query := filterProducts(p.database.Connection().Model(&models.Product{}), pageable, facetPrice).
+			Where("products.price >= ?", money.FromMajor(min, money.BaseCurrency))
+
+		if i+1 < len(PriceFacetBounds) {
+			max := PriceFacetBounds[i+1]
+			facet.Max = &max
+
+			query = query.Where("products.price < ?", money.FromMajor(max, money.BaseCurrency))
+		}
+
+		if err := query.Count(&facet.Count).Error; err != nil {
+			return facets, err
+		}
+
+		facets.Prices = append(facets.Prices, facet)
+	}
+
+	return facets, nil
+}
+
 // CreateProduct is a method that creates a new product.
 func (p *productRepository) CreateProduct(product models.Product) (models.Product, error) {
 	product.Prepare()
 
-	err := p.database.Connection().Create(&product).Error
+	if err := p.database.Connection().Omit("Categories").Create(&product).Error; err != nil {
+		return product, err
+	}
 
-	return product, err
+	return product, p.saveProductCategories(product)
+}
+
+// saveProductCategories is a method that puts a product in its categories. The categories themselves are never written.
+func (p *productRepository) saveProductCategories(product models.Product) error {
+	if len(product.Categories) == 0 {
+		return nil
+	}
+
+	rows := []map[string]interface{}{}
+	for _, category := range product.Categories {
+		rows = append(rows, map[string]interface{}{"product_id": product.ID, "category_id": category.ID})
+	}
+
+	return p.database.Connection().Table("product_categories").Create(&rows).Error
 }
 
 // FindProductByUUID is a method that returns a product by its ID.
 func (p *productRepository) FindProductByUUID(uuid uuid.UUID) (models.Product, error) {
 	var product models.Product
 
-	err := p.database.Connection().
-		Model(&models.Product{}).
+	err := preloadVariants(p.database.Connection().Model(&models.Product{})).
 		Where("id = ?", uuid).
 		First(&product).Error
 
@@ -102,11 +395,10 @@ func (p *productRepository) FindProductByUUID(uuid uuid.UUID) (models.Product, e
 func (p *productRepository) FindProductBySlug(slug string) (models.Product, error) {
 	var product models.Product
 
-	err := p.database.Connection().
-		Model(&models.Product{}).
+	err := preloadVariants(p.database.Connection().Model(&models.Product{})).
 		Where("products.slug = ?", slug).
-		Preload("Images").
-		Select("products.*, COALESCE(SUM(order_items.quantity), 0) as sales").
+		Preload("Categories").
+		Select(productAggregateSelects).
 		Joins("LEFT JOIN order_items ON order_items.product_id = products.id").
 		Group("products.id").
 		First(&product).Error
@@ -114,16 +406,24 @@ func (p *productRepository) FindProductBySlug(slug string) (models.Product, erro
 	return product, err
 }
 
-// UpdateProduct is a method that updates a product.
+// UpdateProduct is a method that updates a product. The product's categories are replaced with the ones given.
 func (p *productRepository) UpdateProduct(product models.Product) (models.Product, error) {
 
 	err := p.database.Connection().
 		Model(&models.Product{}).
 		Where("id = ?", product.ID).
-		Select("category_id", "name", "description", "specification", "price", "slash_price", "stock", "brand").
+		Select("name", "description", "specification", "price", "slash_price", "stock", "brand", "weight").
 		Updates(&product).Error
 
-	return product, err
+	if err != nil {
+		return product, err
+	}
+
+	if err := p.database.Connection().Exec("DELETE FROM product_categories WHERE product_id = ?", product.ID).Error; err != nil {
+		return product, err
+	}
+
+	return product, p.saveProductCategories(product)
 }
 
 // DeleteProduct is a method that deletes a product.
@@ -139,3 +439,49 @@ func (p *productRepository) DeleteProduct(uuid uuid.UUID) error {
 
 	return err
 }
+
+// DecrementStock is a method that takes quantity off a product's stock if enough is left.
+// The check and the write are a single statement, so concurrent checkouts cannot oversell.
+// It returns the number of rows affected, which is 0 when the stock is not enough.
+func (p *productRepository) DecrementStock(uuid uuid.UUID, quantity int) (int64, error) {
+
+	result := p.database.Connection().
+		Model(&models.Product{}).
+		Where("id = ? AND stock >= ?", uuid, quantity).
+		UpdateColumn("stock", gorm.Expr("stock - ?", quantity))
+
+	return result.RowsAffected, result.Error
+}
+
+// IncrementStock is a method that puts quantity back on a product's stock.
+func (p *productRepository) IncrementStock(uuid uuid.UUID, quantity int) error {
+
+	err := p.database.Connection().
+		Model(&models.Product{}).
+		Where("id = ?", uuid).
+		UpdateColumn("stock", gorm.Expr("stock + ?", quantity)).Error
+
+	return err
+}
+
+// DecrementVariantStock is a method that takes quantity off a variant's stock if enough is left, like DecrementStock.
+func (p *productRepository) DecrementVariantStock(uuid uuid.UUID, quantity int) (int64, error) {
+
+	result := p.database.Connection().
+		Model(&models.ProductVariant{}).
+		Where("id = ? AND stock >= ?", uuid, quantity).
+		UpdateColumn("stock", gorm.Expr("stock - ?", quantity))
+
+	return result.RowsAffected, result.Error
+}
+
+// IncrementVariantStock is a method that puts quantity back on a variant's stock.
+func (p *productRepository) IncrementVariantStock(uuid uuid.UUID, quantity int) error {
+
+	err := p.database.Connection().
+		Model(&models.ProductVariant{}).
+		Where("id = ?", uuid).
+		UpdateColumn("stock", gorm.Expr("stock + ?", quantity)).Error
+
+	return err
+}
@@ -0,0 +1,143 @@
+package core_repository
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+)
+
+// ProductReviewPageable finds the reviews of ProductID, hidden reviews are only included with IncludeHidden.
+type ProductReviewPageable struct {
+	repository.Pageable
+
+	ProductID     uuid.UUID
+	IncludeHidden bool
+}
+
+// ProductReviewSortFields are the fields reviews can be sorted on.
+var ProductReviewSortFields = repository.SortFields{
+	"created_at": "product_reviews.created_at",
+	"rating":     "product_reviews.rating",
+}
+
+// ProductReviewRepositoryInterface is a contract that defines the methods to be implemented by ProductReviewRepository.
+type ProductReviewRepositoryInterface interface {
+	CreateReview(review models.ProductReview) (models.ProductReview, error)
+	FindReviewById(uuid uuid.UUID) (models.ProductReview, error)
+	FindReviewByProductAndUser(productId uuid.UUID, userId uuid.UUID) (models.ProductReview, error)
+	FindReviews(pageable ProductReviewPageable) ([]models.ProductReview, repository.Pagination, error)
+	UpdateReviewVisibility(uuid uuid.UUID, isHidden bool) error
+	HasDeliveredProduct(userId uuid.UUID, productId uuid.UUID) (bool, error)
+	WithTx(tx database.DatabaseInterface) ProductReviewRepositoryInterface
+}
+
+// productReviewRepository is a struct that defines the database connection.
+type productReviewRepository struct {
+	database database.DatabaseInterface
+}
+
+// NewProductReviewRepository is a function that returns a new instance of ProductReviewRepository.
+func NewProductReviewRepository(database database.DatabaseInterface) ProductReviewRepositoryInterface {
+	return &productReviewRepository{database: database}
+}
+
+// WithTx is a method that returns a ProductReviewRepository bound to the given transaction.
+func (p *productReviewRepository) WithTx(tx database.DatabaseInterface) ProductReviewRepositoryInterface {
+	return &productReviewRepository{database: tx}
+}
+
+// CreateReview is a method that creates a review.
+func (p *productReviewRepository) CreateReview(review models.ProductReview) (models.ProductReview, error) {
+	review.Prepare()
+
+	err := p.database.Connection().Omit("User").Create(&review).Error
+
+	return review, err
+}
+
+// FindReviewById is a method that returns a review with its reviewer.
+func (p *productReviewRepository) FindReviewById(uuid uuid.UUID) (review models.ProductReview, err error) {
+	err = p.database.Connection().
+		Model(&models.ProductReview{}).
+		Preload("User").
+		Where("id = ?", uuid).
+		First(&review).Error
+
+	return review, err
+}
+
+// FindReviewByProductAndUser is a method that returns the review a user left on a product, hidden or not.
+func (p *productReviewRepository) FindReviewByProductAndUser(productId uuid.UUID, userId uuid.UUID) (review models.ProductReview, err error) {
+	err = p.database.Connection().
+		Model(&models.ProductReview{}).
+		Where("product_id = ? AND user_id = ?", productId, userId).
+		First(&review).Error
+
+	return review, err
+}
+
+// FindReviews is a method that returns a page of the reviews of a product, newest first unless sorted.
+func (p *productReviewRepository) FindReviews(pageable ProductReviewPageable) ([]models.ProductReview, repository.Pagination, error) {
+	var reviews []models.ProductReview
+	var pagination repository.Pagination
+
+	pagination.CurrentPage = int64(pageable.Page)
+	pagination.TotalItems = 0
+	pagination.TotalPages = 1
+
+	offset := (pageable.Page - 1) * pageable.Size
+	model := p.database.Connection().
+		Model(&models.ProductReview{}).
+		Preload("User").
+		Where("product_reviews.product_id = ?", pageable.ProductID)
+
+	if !pageable.IncludeHidden {
+		model = model.Where("product_reviews.is_hidden = FALSE")
+	}
+
+	keys := ProductReviewSortFields.Keys(pageable.Sort, "product_reviews.id", repository.SortKey{Column: "product_reviews.created_at", Desc: true})
+
+	if err := model.Count(&pagination.TotalItems).Error; err != nil {
+		return nil, pagination, err
+	}
+
+	err := model.Offset(int(offset)).Limit(int(pageable.Size)).Order(repository.OrderBy(keys, false)).Find(&reviews).Error
+
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	if pagination.TotalItems > 0 {
+		pagination.TotalPages = (pagination.TotalItems + int64(pageable.Size) - 1) / int64(pageable.Size)
+	}
+
+	return reviews, pagination, nil
+}
+
+// UpdateReviewVisibility is a method that hides or shows a review.
+// The column is updated by name because Updates skips false when given a struct.
+func (p *productReviewRepository) UpdateReviewVisibility(uuid uuid.UUID, isHidden bool) error {
+	err := p.database.Connection().
+		Model(&models.ProductReview{}).
+		Where("id = ?", uuid).
+		Update("is_hidden", isHidden).Error
+
+	return err
+}
+
+// HasDeliveredProduct is a method that reports whether the user has a delivered order with the product in it.
+func (p *productReviewRepository) HasDeliveredProduct(userId uuid.UUID, productId uuid.UUID) (bool, error) {
+	var count int64
+
+	err := p.database.Connection().
+		Model(&models.Order{}).
+		Joins("JOIN order_statuses ON orders.status_id = order_statuses.id").
+		Joins("JOIN order_items ON order_items.order_id = orders.id").
+		Where("orders.user_id = ? AND order_items.product_id = ?", userId, productId).
+		Where("order_statuses.short_name = ?", "delivered").
+		Count(&count).Error
+
+	return count > 0, err
+}
@@ -0,0 +1,210 @@
+package core_repository
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+)
+
+// ProductVariantRepositoryInterface is a contract that defines the methods to be implemented by ProductVariantRepository.
+type ProductVariantRepositoryInterface interface {
+	CreateOption(option models.ProductOption) (models.ProductOption, error)
+	FindOptionById(uuid uuid.UUID) (models.ProductOption, error)
+	FindOptionsByProductId(productId uuid.UUID) ([]models.ProductOption, error)
+	DeleteOption(uuid uuid.UUID) error
+	CreateVariant(variant models.ProductVariant) (models.ProductVariant, error)
+	FindVariantById(uuid uuid.UUID) (models.ProductVariant, error)
+	FindVariantBySKU(sku string) (models.ProductVariant, error)
+	FindVariantsByProductId(productId uuid.UUID) ([]models.ProductVariant, error)
+	CountVariantsByOptionId(optionId uuid.UUID) (int64, error)
+	UpdateVariant(variant models.ProductVariant) (models.ProductVariant, error)
+	DeleteVariant(uuid uuid.UUID) error
+	WithTx(tx database.DatabaseInterface) ProductVariantRepositoryInterface
+}
+
+// productVariantRepository is a struct that defines the database connection.
+type productVariantRepository struct {
+	database database.DatabaseInterface
+}
+
+// NewProductVariantRepository is a function that returns a new instance of ProductVariantRepository.
+func NewProductVariantRepository(database database.DatabaseInterface) ProductVariantRepositoryInterface {
+	return &productVariantRepository{database: database}
+}
+
+// WithTx is a method that returns a ProductVariantRepository bound to the given transaction.
+func (p *productVariantRepository) WithTx(tx database.DatabaseInterface) ProductVariantRepositoryInterface {
+	return &productVariantRepository{database: tx}
+}
+
+// CreateOption is a method that creates an option with its values.
+func (p *productVariantRepository) CreateOption(option models.ProductOption) (models.ProductOption, error) {
+	option.Prepare()
+
+	for i := range option.Values {
+		option.Values[i].Prepare()
+		option.Values[i].OptionID = option.ID
+	}
+
+	err := p.database.Connection().Create(&option).Error
+
+	return option, err
+}
+
+// FindOptionById is a method that returns an option with its values.
+func (p *productVariantRepository) FindOptionById(uuid uuid.UUID) (option models.ProductOption, err error) {
+	err = p.database.Connection().
+		Model(&models.ProductOption{}).
+		Preload("Values").
+		Where("id = ?", uuid).
+		First(&option).Error
+
+	return option, err
+}
+
+// FindOptionsByProductId is a method that returns the options of a product with their values.
+func (p *productVariantRepository) FindOptionsByProductId(productId uuid.UUID) (options []models.ProductOption, err error) {
+	err = p.database.Connection().
+		Model(&models.ProductOption{}).
+		Preload("Values").
+		Where("product_id = ?", productId).
+		Find(&options).Error
+
+	return options, err
+}
+
+// DeleteOption is a method that deletes an option and its values.
+func (p *productVariantRepository) DeleteOption(uuid uuid.UUID) error {
+	err := p.database.Connection().Where("option_id = ?", uuid).Delete(&models.ProductOptionValue{}).Error
+
+	if err != nil {
+		return err
+	}
+
+	return p.database.Connection().Where("id = ?", uuid).Delete(&models.ProductOption{}).Error
+}
+
+// CreateVariant is a method that creates a variant with its option values and images.
+func (p *productVariantRepository) CreateVariant(variant models.ProductVariant) (models.ProductVariant, error) {
+	variant.Prepare()
+
+	if err := p.database.Connection().Omit("Values", "Images").Create(&variant).Error; err != nil {
+		return variant, err
+	}
+
+	if err := p.saveVariantValues(variant); err != nil {
+		return variant, err
+	}
+
+	return variant, p.saveVariantImages(variant)
+}
+
+// saveVariantValues is a method that links a variant to its option values. The values themselves are never written.
+func (p *productVariantRepository) saveVariantValues(variant models.ProductVariant) error {
+	if len(variant.Values) == 0 {
+		return nil
+	}
+
+	rows := []map[string]interface{}{}
+	for _, value := range variant.Values {
+		rows = append(rows, map[string]interface{}{"variant_id": variant.ID, "option_value_id": value.ID})
+	}
+
+	return p.database.Connection().Table("product_variant_values").Create(&rows).Error
+}
+
+// saveVariantImages is a method that creates the images of a variant.
+func (p *productVariantRepository) saveVariantImages(variant models.ProductVariant) error {
+	if len(variant.Images) == 0 {
+		return nil
+	}
+
+	for i := range variant.Images {
+		variant.Images[i].Prepare()
+		variant.Images[i].ProductID = variant.ProductID
+		variant.Images[i].VariantID = &variant.ID
+	}
+
+	return p.database.Connection().Create(&variant.Images).Error
+}
+
+// FindVariantById is a method that returns a variant with its option values and images.
+func (p *productVariantRepository) FindVariantById(uuid uuid.UUID) (variant models.ProductVariant, err error) {
+	err = p.database.Connection().
+		Model(&models.ProductVariant{}).
+		Preload("Values").
+		Preload("Images").
+		Where("id = ?", uuid).
+		First(&variant).Error
+
+	return variant, err
+}
+
+// FindVariantBySKU is a method that returns a variant by its SKU.
+func (p *productVariantRepository) FindVariantBySKU(sku string) (variant models.ProductVariant, err error) {
+	err = p.database.Connection().Model(&models.ProductVariant{}).Where("sku = ?", sku).First(&variant).Error
+
+	return variant, err
+}
+
+// FindVariantsByProductId is a method that returns the variants of a product with their option values.
+func (p *productVariantRepository) FindVariantsByProductId(productId uuid.UUID) (variants []models.ProductVariant, err error) {
+	err = p.database.Connection().
+		Model(&models.ProductVariant{}).
+		Preload("Values").
+		Where("product_id = ?", productId).
+		Find(&variants).Error
+
+	return variants, err
+}
+
+// CountVariantsByOptionId is a method that returns the number of variants made with one of the option's values.
+func (p *productVariantRepository) CountVariantsByOptionId(optionId uuid.UUID) (count int64, err error) {
+	err = p.database.Connection().
+		Model(&models.ProductVariant{}).
+		Joins("JOIN product_variant_values ON product_variant_values.variant_id = product_variants.id").
+		Joins("JOIN product_option_values ON product_option_values.id = product_variant_values.option_value_id").
+		Where("product_option_values.option_id = ?", optionId).
+		Count(&count).Error
+
+	return count, err
+}
+
+// UpdateVariant is a method that updates a variant. Its option values and images are replaced with the ones given.
+func (p *productVariantRepository) UpdateVariant(variant models.ProductVariant) (models.ProductVariant, error) {
+	err := p.database.Connection().
+		Model(&models.ProductVariant{}).
+		Where("id = ?", variant.ID).
+		Select("sku", "price", "slash_price", "stock").
+		Updates(&variant).Error
+
+	if err != nil {
+		return variant, err
+	}
+
+	if err := p.database.Connection().Exec("DELETE FROM product_variant_values WHERE variant_id = ?", variant.ID).Error; err != nil {
+		return variant, err
+	}
+
+	if err := p.saveVariantValues(variant); err != nil {
+		return variant, err
+	}
+
+	if err := p.database.Connection().Where("variant_id = ?", variant.ID).Delete(&models.Image{}).Error; err != nil {
+		return variant, err
+	}
+
+	return variant, p.saveVariantImages(variant)
+}
+
+// DeleteVariant is a method that deletes a variant. Orders and carts keep pointing at it.
+func (p *productVariantRepository) DeleteVariant(uuid uuid.UUID) error {
+	variant, err := p.FindVariantById(uuid)
+
+	if err != nil {
+		return err
+	}
+
+	return p.database.Connection().Delete(&variant).Error
+}
@@ -0,0 +1,72 @@
+package finance_repository
+
+import (
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+)
+
+type CurrencyRateRepositoryInterface interface {
+	FindCurrencyRates() ([]models.CurrencyRate, error)
+	FindCurrencyRate(currency string) (models.CurrencyRate, error)
+	CreateCurrencyRate(rate models.CurrencyRate) (models.CurrencyRate, error)
+	UpdateCurrencyRate(rate models.CurrencyRate) (models.CurrencyRate, error)
+	DeleteCurrencyRate(currency string) (int64, error)
+	WithTx(tx database.DatabaseInterface) CurrencyRateRepositoryInterface
+}
+
+type currencyRateRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewCurrencyRateRepository(database database.DatabaseInterface) CurrencyRateRepositoryInterface {
+	return &currencyRateRepository{database: database}
+}
+
+// WithTx implements CurrencyRateRepositoryInterface.
+func (r *currencyRateRepository) WithTx(tx database.DatabaseInterface) CurrencyRateRepositoryInterface {
+	return &currencyRateRepository{database: tx}
+}
+
+// FindCurrencyRates implements CurrencyRateRepositoryInterface.
+func (r *currencyRateRepository) FindCurrencyRates() (rates []models.CurrencyRate, err error) {
+
+	err = r.database.Connection().Model(&models.CurrencyRate{}).Order("currency").Find(&rates).Error
+
+	return rates, err
+}
+
+// FindCurrencyRate implements CurrencyRateRepositoryInterface.
+func (r *currencyRateRepository) FindCurrencyRate(currency string) (rate models.CurrencyRate, err error) {
+
+	err = r.database.Connection().Model(&models.CurrencyRate{}).Where("currency = ?", currency).First(&rate).Error
+
+	return rate, err
+}
+
+// CreateCurrencyRate implements CurrencyRateRepositoryInterface.
+func (r *currencyRateRepository) CreateCurrencyRate(rate models.CurrencyRate) (models.CurrencyRate, error) {
+	rate.Prepare()
+
+	err := r.database.Connection().Create(&rate).Error
+
+	return rate, err
+}
+
+// UpdateCurrencyRate implements CurrencyRateRepositoryInterface.
+func (r *currencyRateRepository) UpdateCurrencyRate(rate models.CurrencyRate) (models.CurrencyRate, error) {
+
+	err := r.database.Connection().
+		Model(&models.CurrencyRate{}).
+		Where("id = ?", rate.ID).
+		Update("rate", rate.Rate).Error
+
+	return rate, err
+}
+
+// DeleteCurrencyRate implements CurrencyRateRepositoryInterface and returns the rows affected.
+func (r *currencyRateRepository) DeleteCurrencyRate(currency string) (int64, error) {
+
+	result := r.database.Connection().Where("currency = ?", currency).Delete(&models.CurrencyRate{})
+
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,210 @@
+package finance_repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+)
+
+// LedgerPostingPageable pages the postings of AccountID in Currency, created from FromDate and before ToDate.
+// A zero date leaves that end of the period open.
+type LedgerPostingPageable struct {
+	repository.Pageable
+
+	AccountID uuid.UUID
+	Currency  string
+	FromDate  time.Time
+	ToDate    time.Time
+}
+
+// TrialBalanceRow is what was debited and credited to one account in one currency, in minor units.
+type TrialBalanceRow struct {
+	AccountID uuid.UUID
+	Code      string
+	Name      string
+	Type      string
+	UserID    *uuid.UUID
+	Currency  string
+	Debit     int64
+	Credit    int64
+}
+
+// AccountStatementRow is one posting of an account statement, in minor units.
+type AccountStatementRow struct {
+	EntryID     uuid.UUID
+	Description string
+	Reference   string
+	Amount      int64
+	CreatedAt   time.Time
+}
+
+type LedgerRepositoryInterface interface {
+	FindAccountByCode(code string) (models.LedgerAccount, error)
+	FindOrCreateAccount(account models.LedgerAccount) (models.LedgerAccount, error)
+	CreateJournalEntry(entry models.JournalEntry) (models.JournalEntry, error)
+	TrialBalance(currency string, toDate time.Time) ([]TrialBalanceRow, error)
+	AccountBalance(accountId uuid.UUID, currency string, before time.Time) (int64, error)
+	FindAccountPostings(pageable LedgerPostingPageable) ([]AccountStatementRow, repository.Pagination, error)
+	WithTx(tx database.DatabaseInterface) LedgerRepositoryInterface
+}
+
+type ledgerRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewLedgerRepository(database database.DatabaseInterface) LedgerRepositoryInterface {
+	return &ledgerRepository{database: database}
+}
+
+// WithTx implements LedgerRepositoryInterface.
+func (r *ledgerRepository) WithTx(tx database.DatabaseInterface) LedgerRepositoryInterface {
+	return &ledgerRepository{database: tx}
+}
+
+// FindAccountByCode implements LedgerRepositoryInterface.
+func (r *ledgerRepository) FindAccountByCode(code string) (account models.LedgerAccount, err error) {
+
+	err = r.database.Connection().Model(&models.LedgerAccount{}).Where("code = ?", code).First(&account).Error
+
+	return account, err
+}
+
+// FindOrCreateAccount implements LedgerRepositoryInterface, it opens the account when there is none with its code.
+func (r *ledgerRepository) FindOrCreateAccount(account models.LedgerAccount) (models.LedgerAccount, error) {
+	account.Prepare()
+
+	err := r.database.Connection().
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, DoNothing: true}).
+		Create(&account).Error
+
+	if err != nil {
+		return account, err
+	}
+
+	return r.FindAccountByCode(account.Code)
+}
+
+// CreateJournalEntry implements LedgerRepositoryInterface. It expects to run in a transaction, the entry only balances
+// once all its postings are in.
+func (r *ledgerRepository) CreateJournalEntry(entry models.JournalEntry) (models.JournalEntry, error) {
+	entry.Prepare()
+
+	postings := entry.Postings
+	entry.Postings = nil
+
+	if err := r.database.Connection().Omit(clause.Associations).Create(&entry).Error; err != nil {
+		return entry, err
+	}
+
+	for i := range postings {
+		postings[i].Prepare()
+		postings[i].EntryID = entry.ID
+	}
+
+	err := r.database.Connection().Omit(clause.Associations).Create(&postings).Error
+
+	entry.Postings = postings
+
+	return entry, err
+}
+
+// TrialBalance implements LedgerRepositoryInterface. It sums the postings made before toDate, every one when it is
+// zero, and only those in currency when it is given.
+func (r *ledgerRepository) TrialBalance(currency string, toDate time.Time) (rows []TrialBalanceRow, err error) {
+
+	query := r.database.Connection().
+		Model(&models.LedgerPosting{}).
+		Select(`ledger_accounts.id as account_id, ledger_accounts.code, ledger_accounts.name, ledger_accounts.type,
+			ledger_accounts.user_id, journal_entries.currency,
+			COALESCE(SUM(ledger_postings.amount) FILTER (WHERE ledger_postings.amount > 0), 0) as debit,
+			COALESCE(-SUM(ledger_postings.amount) FILTER (WHERE ledger_postings.amount < 0), 0) as credit`).
+		Joins("JOIN journal_entries ON journal_entries.id = ledger_postings.entry_id").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_postings.account_id")
+
+	if currency != "" {
+		query = query.Where("journal_entries.currency = ?", currency)
+	}
+
+	if !toDate.IsZero() {
+		query = query.Where("ledger_postings.created_at < ?", toDate)
+	}
+
+	err = query.
+		Group("ledger_accounts.id, journal_entries.currency").
+		Order("journal_entries.currency, ledger_accounts.type, ledger_accounts.code").
+		Scan(&rows).Error
+
+	return rows, err
+}
+
+// AccountBalance implements LedgerRepositoryInterface, the balance of the postings in currency made before before,
+// every one when it is zero. Debits are positive.
+func (r *ledgerRepository) AccountBalance(accountId uuid.UUID, currency string, before time.Time) (int64, error) {
+	var balance int64
+
+	query := r.database.Connection().
+		Model(&models.LedgerPosting{}).
+		Select("COALESCE(SUM(ledger_postings.amount), 0)").
+		Joins("JOIN journal_entries ON journal_entries.id = ledger_postings.entry_id").
+		Where("ledger_postings.account_id = ? AND journal_entries.currency = ?", accountId, currency)
+
+	if !before.IsZero() {
+		query = query.Where("ledger_postings.created_at < ?", before)
+	}
+
+	err := query.Scan(&balance).Error
+
+	return balance, err
+}
+
+// FindAccountPostings implements LedgerRepositoryInterface, oldest first.
+func (r *ledgerRepository) FindAccountPostings(pageable LedgerPostingPageable) ([]AccountStatementRow, repository.Pagination, error) {
+	var rows []AccountStatementRow
+	var pagination repository.Pagination
+
+	pagination.CurrentPage = int64(pageable.Page)
+	pagination.TotalItems = 0
+	pagination.TotalPages = 1
+
+	offset := (pageable.Page - 1) * pageable.Size
+	model := r.database.Connection().
+		Model(&models.LedgerPosting{}).
+		Joins("JOIN journal_entries ON journal_entries.id = ledger_postings.entry_id").
+		Joins("LEFT JOIN transactions ON transactions.id = journal_entries.transaction_id").
+		Where("ledger_postings.account_id = ? AND journal_entries.currency = ?", pageable.AccountID, pageable.Currency)
+
+	if !pageable.FromDate.IsZero() {
+		model = model.Where("ledger_postings.created_at >= ?", pageable.FromDate)
+	}
+
+	if !pageable.ToDate.IsZero() {
+		model = model.Where("ledger_postings.created_at < ?", pageable.ToDate)
+	}
+
+	if err := model.Count(&pagination.TotalItems).Error; err != nil {
+		return nil, pagination, err
+	}
+
+	err := model.
+		Select(`ledger_postings.entry_id, journal_entries.description, COALESCE(transactions.reference, '') as reference,
+			ledger_postings.amount, ledger_postings.created_at`).
+		Order("ledger_postings.created_at, ledger_postings.id").
+		Offset(int(offset)).
+		Limit(int(pageable.Size)).
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	if pagination.TotalItems > 0 {
+		pagination.TotalPages = (pagination.TotalItems + int64(pageable.Size) - 1) / int64(pageable.Size)
+	}
+
+	return rows, pagination, nil
+}
@@ -0,0 +1,109 @@
+package finance_repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+)
+
+type ReconciliationRepositoryInterface interface {
+	CreateReport(report models.ReconciliationReport) (models.ReconciliationReport, error)
+	FindReports(pageable repository.Pageable) ([]models.ReconciliationReport, repository.Pagination, error)
+	FindReportById(id uuid.UUID) (models.ReconciliationReport, error)
+	FindLatestReport() (models.ReconciliationReport, error)
+	WithTx(tx database.DatabaseInterface) ReconciliationRepositoryInterface
+}
+
+type reconciliationRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewReconciliationRepository(database database.DatabaseInterface) ReconciliationRepositoryInterface {
+	return &reconciliationRepository{database: database}
+}
+
+// WithTx implements ReconciliationRepositoryInterface.
+func (r *reconciliationRepository) WithTx(tx database.DatabaseInterface) ReconciliationRepositoryInterface {
+	return &reconciliationRepository{database: tx}
+}
+
+// CreateReport implements ReconciliationRepositoryInterface, it saves the report with its items.
+func (r *reconciliationRepository) CreateReport(report models.ReconciliationReport) (models.ReconciliationReport, error) {
+	report.Prepare()
+
+	items := report.Items
+	report.Items = nil
+
+	if err := r.database.Connection().Omit(clause.Associations).Create(&report).Error; err != nil {
+		return report, err
+	}
+
+	for i := range items {
+		items[i].Prepare()
+		items[i].ReportID = report.ID
+	}
+
+	var err error
+
+	if len(items) > 0 {
+		err = r.database.Connection().Create(&items).Error
+	}
+
+	report.Items = items
+
+	return report, err
+}
+
+// FindReports implements ReconciliationRepositoryInterface, newest first and without their items.
+func (r *reconciliationRepository) FindReports(pageable repository.Pageable) ([]models.ReconciliationReport, repository.Pagination, error) {
+	var reports []models.ReconciliationReport
+	var pagination repository.Pagination
+
+	pagination.CurrentPage = int64(pageable.Page)
+	pagination.TotalItems = 0
+	pagination.TotalPages = 1
+
+	offset := (pageable.Page - 1) * pageable.Size
+	model := r.database.Connection().Model(&models.ReconciliationReport{})
+
+	if err := model.Count(&pagination.TotalItems).Error; err != nil {
+		return nil, pagination, err
+	}
+
+	if err := model.Offset(int(offset)).Limit(int(pageable.Size)).Order("created_at DESC").Find(&reports).Error; err != nil {
+		return nil, pagination, err
+	}
+
+	if pagination.TotalItems > 0 {
+		pagination.TotalPages = (pagination.TotalItems + int64(pageable.Size) - 1) / int64(pageable.Size)
+	}
+
+	return reports, pagination, nil
+}
+
+// FindReportById implements ReconciliationRepositoryInterface.
+func (r *reconciliationRepository) FindReportById(id uuid.UUID) (report models.ReconciliationReport, err error) {
+
+	err = r.database.Connection().
+		Model(&models.ReconciliationReport{}).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("gateway, kind, reference") }).
+		Where("id = ?", id).
+		First(&report).Error
+
+	return report, err
+}
+
+// FindLatestReport implements ReconciliationRepositoryInterface, the report reaching furthest, without its items.
+func (r *reconciliationRepository) FindLatestReport() (report models.ReconciliationReport, err error) {
+
+	err = r.database.Connection().
+		Model(&models.ReconciliationReport{}).
+		Order("to_date DESC").
+		First(&report).Error
+
+	return report, err
+}
@@ -2,10 +2,12 @@ package finance_repository
 
 import (
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 	"github.com/developer-afo/instashop-ecommerce-api/models"
 	"github.com/developer-afo/instashop-ecommerce-api/repository"
 )
@@ -20,12 +22,23 @@ type TransactionPageable struct {
 	UserID uuid.UUID
 }
 
+// TransactionSortFields are the fields transactions can be sorted on.
+var TransactionSortFields = repository.SortFields{
+	"created_at": "transactions.created_at",
+	"amount":     "transactions.amount",
+}
+
 type TransactionRepositoryInterface interface {
 	FindTransactionByUUID(uuid uuid.UUID) (models.Transaction, error)
 	FindAllTransactions(pageable TransactionPageable) ([]models.Transaction, repository.Pagination, error)
 	FindTransactionByReference(reference string) (models.Transaction, error)
 	CreateTransaction(transaction models.Transaction) (models.Transaction, error)
 	UpdateTransaction(transaction models.Transaction) (models.Transaction, error)
+	UpdateTransactionStatus(uuid uuid.UUID, fromStatus string, toStatus string) (int64, error)
+	WalletBalance(userId uuid.UUID) (money.Money, error)
+	LockWallet(userId uuid.UUID) error
+	FindGatewayPayments(vendor string, from time.Time, to time.Time) ([]models.Transaction, error)
+	WithTx(tx database.DatabaseInterface) TransactionRepositoryInterface
 }
 
 type transactionRepository struct {
@@ -36,6 +49,11 @@ func NewTransactionRepository(database database.DatabaseInterface) TransactionRe
 	return &transactionRepository{database: database}
 }
 
+// WithTx implements TransactionRepositoryInterface.
+func (t *transactionRepository) WithTx(tx database.DatabaseInterface) TransactionRepositoryInterface {
+	return &transactionRepository{database: tx}
+}
+
 // FindAllTransactions is a method that returns all transactions.
 func (t *transactionRepository) FindAllTransactions(pageable TransactionPageable) ([]models.Transaction, repository.Pagination, error) {
 	var transactions []models.Transaction
@@ -75,7 +93,7 @@ func (t *transactionRepository) FindAllTransactions(pageable TransactionPageable
 	}
 
 	errCount = model.Count(&pagination.TotalItems).Error
-	paginatedQuery := model.Offset(int(offset)).Limit(int(pageable.Size)).Order(pageable.SortBy + " " + pageable.SortDirection)
+	paginatedQuery := model.Offset(int(offset)).Limit(int(pageable.Size)).Order(TransactionSortFields.OrderBy(pageable.Sort, "transactions.created_at DESC"))
 
 	if err := paginatedQuery.Model(&models.Transaction{}).Where(transaction).Find(&transactions).Error; err != nil {
 		return nil, pagination, err
@@ -129,3 +147,50 @@ func (t *transactionRepository) UpdateTransaction(transaction models.Transaction
 
 	return transaction, err
 }
+
+// UpdateTransactionStatus moves a transaction to toStatus only if it is still in fromStatus and returns the rows affected.
+func (t *transactionRepository) UpdateTransactionStatus(uuid uuid.UUID, fromStatus string, toStatus string) (int64, error) {
+
+	result := t.database.Connection().
+		Model(&models.Transaction{}).
+		Where("id = ? AND status = ?", uuid, fromStatus).
+		Update("status", toStatus)
+
+	return result.RowsAffected, result.Error
+}
+
+// WalletBalance is a method that returns what is left in the user's wallet, in money.BaseCurrency.
+// Pending debits are taken off already, so money held by an unpaid order cannot be spent twice.
+func (t *transactionRepository) WalletBalance(userId uuid.UUID) (money.Money, error) {
+	var balance int64
+
+	err := t.database.Connection().
+		Model(&models.Transaction{}).
+		Select("COALESCE(SUM(CASE WHEN type = 'credit' THEN amount ELSE -amount END), 0)").
+		Where("user_id = ? AND method = 'wallet'", userId).
+		Where("((type = 'credit' AND status = 'success') OR (type = 'debit' AND status IN ('success', 'pending')))").
+		Scan(&balance).Error
+
+	return money.New(balance, money.BaseCurrency), err
+}
+
+// LockWallet is a method that holds the user's wallet until the database transaction ends,
+// so balance checks and the debits that follow them happen one at a time.
+func (t *transactionRepository) LockWallet(userId uuid.UUID) error {
+
+	return t.database.Connection().Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "wallet:"+userId.String()).Error
+}
+
+// FindGatewayPayments is a method that returns the payments made through vendor from from up to to: order payments
+// and wallet top ups. Refunds are left out, the gateway lists them apart.
+func (t *transactionRepository) FindGatewayPayments(vendor string, from time.Time, to time.Time) (transactions []models.Transaction, err error) {
+
+	err = t.database.Connection().
+		Model(&models.Transaction{}).
+		Where("vendor = ? AND created_at >= ? AND created_at < ?", vendor, from, to).
+		Where("((method = 'gateway' AND type = 'debit') OR purpose = 'wallet_top_up')").
+		Order("created_at").
+		Find(&transactions).Error
+
+	return transactions, err
+}
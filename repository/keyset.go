@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCursor      = errors.New("cursor is not valid for this list")
+	ErrCursorSortMismatch = errors.New("cursor was made for another sort, send the sort_by and sort_dir of the page it came from")
+)
+
+// Cursor points at the row a keyset page starts after, or ends before when Before is set.
+// Values are the sort keys of that row, its id last, and Sort is the SortSpec of the list they were taken from.
+type Cursor struct {
+	Values []interface{} `json:"v"`
+	Sort   string        `json:"s,omitempty"`
+	Before bool          `json:"b,omitempty"`
+}
+
+// SortSpec returns sorts as field:direction pairs, the default sort of a list is empty.
+func SortSpec(sorts []Sort) string {
+	spec := []string{}
+
+	for _, s := range sorts {
+		spec = append(spec, s.Field+":"+s.Direction)
+	}
+
+	return strings.Join(spec, ",")
+}
+
+// CheckSort returns ErrCursorSortMismatch when the cursor was not made for sorts, its values would be compared
+// with other keys.
+func (cursor *Cursor) CheckSort(sorts []Sort) error {
+	if cursor.Sort != SortSpec(sorts) {
+		return ErrCursorSortMismatch
+	}
+
+	return nil
+}
+
+// SortKey is a column of an ORDER BY, or an expression when the list is sorted on one.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// EncodeCursor returns the cursor as an opaque string for a client to send back.
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads a cursor made by EncodeCursor.
+func DecodeCursor(value string) (*Cursor, error) {
+	var cursor Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	// numbers are kept as integers when they are, a float would not compare equal to an integer column
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&cursor); err != nil || len(cursor.Values) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	for i, value := range cursor.Values {
+		number, ok := value.(json.Number)
+
+		if !ok {
+			continue
+		}
+
+		if integer, err := number.Int64(); err == nil {
+			cursor.Values[i] = integer
+		} else if float, err := number.Float64(); err == nil {
+			cursor.Values[i] = float
+		}
+	}
+
+	return &cursor, nil
+}
+
+// Keys returns the sort keys of sorts, or fallback when there are none, followed by idColumn to break ties.
+func (fields SortFields) Keys(sorts []Sort, idColumn string, fallback ...SortKey) []SortKey {
+	keys := []SortKey{}
+
+	for _, s := range sorts {
+		if column, ok := fields[s.Field]; ok {
+			keys = append(keys, SortKey{Column: column, Desc: s.Direction == SortDesc})
+		}
+	}
+
+	if len(keys) == 0 {
+		keys = append(keys, fallback...)
+	}
+
+	return append(keys, SortKey{Column: idColumn})
+}
+
+// OrderBy returns the ORDER BY of keys, reversed when reverse is set.
+func OrderBy(keys []SortKey, reverse bool) string {
+	columns := []string{}
+
+	for _, key := range keys {
+		direction := "ASC"
+		if key.Desc != reverse {
+			direction = "DESC"
+		}
+
+		columns = append(columns, key.Column+" "+direction)
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+// KeysetQuery orders query by keys and limits it to the page of pageable.Cursor.
+// One row more than the page is fetched to know if there is a next page, KeysetPage trims it.
+// The condition goes in HAVING when having is set, for lists sorted on an aggregate.
+func KeysetQuery(query *gorm.DB, keys []SortKey, pageable Pageable, having bool) (*gorm.DB, error) {
+	cursor := pageable.Cursor
+
+	if cursor == nil {
+		return query.Order(OrderBy(keys, false)).Limit(pageable.Size + 1), nil
+	}
+
+	if err := cursor.CheckSort(pageable.Sort); err != nil {
+		return nil, err
+	}
+
+	if len(cursor.Values) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+
+	// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..., with < for the keys sorted the other way
+	var args []interface{}
+	conditions := []string{}
+
+	for i, key := range keys {
+		parts := []string{}
+
+		for j := 0; j < i; j++ {
+			parts = append(parts, keys[j].Column+" = ?")
+			args = append(args, cursor.Values[j])
+		}
+
+		operator := ">"
+		if key.Desc != cursor.Before {
+			operator = "<"
+		}
+
+		parts = append(parts, key.Column+" "+operator+" ?")
+		args = append(args, cursor.Values[i])
+
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	condition := "(" + strings.Join(conditions, " OR ") + ")"
+
+	if having {
+		query = query.Having(condition, args...)
+	} else {
+		query = query.Where(condition, args...)
+	}
+
+	return query.Order(OrderBy(keys, cursor.Before)).Limit(pageable.Size + 1), nil
+}
+
+// KeysetPage trims the extra row fetched by KeysetQuery, puts the rows of a page before the cursor back in order,
+// and returns the cursors of the pages around it. values returns the sort keys of a row in the order of the keys.
+func KeysetPage[T any](rows []T, pageable Pageable, values func(T) []interface{}) ([]T, Pagination) {
+	var pagination Pagination
+
+	before := pageable.Cursor != nil && pageable.Cursor.Before
+	more := len(rows) > pageable.Size
+
+	if more {
+		rows = rows[:pageable.Size]
+	}
+
+	if before {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	if len(rows) == 0 {
+		return rows, pagination
+	}
+
+	// going back, the page the client came from is after this one
+	if more || before {
+		pagination.NextCursor = EncodeCursor(Cursor{Values: values(rows[len(rows)-1]), Sort: SortSpec(pageable.Sort)})
+	}
+
+	if (more && before) || (pageable.Cursor != nil && !before) {
+		pagination.PrevCursor = EncodeCursor(Cursor{Values: values(rows[0]), Sort: SortSpec(pageable.Sort), Before: true})
+	}
+
+	return rows, pagination
+}
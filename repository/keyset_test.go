@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCursorKeepsItsSort(t *testing.T) {
+	sorts := []Sort{{Field: "price", Direction: SortAsc}, {Field: "created_at", Direction: SortDesc}}
+
+	cursor, err := DecodeCursor(EncodeCursor(Cursor{Values: []interface{}{int64(150000), "2024-03-01T10:30:00Z", "0b9e1a4e"}, Sort: SortSpec(sorts)}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cursor.Values[0] != int64(150000) || cursor.Sort != "price:asc,created_at:desc" {
+		t.Errorf("decoded %v sorted by %q", cursor.Values, cursor.Sort)
+	}
+
+	tests := []struct {
+		name  string
+		sorts []Sort
+		err   error
+	}{
+		{"same sort", sorts, nil},
+		{"default sort", nil, ErrCursorSortMismatch},
+		{"other direction", []Sort{{Field: "price", Direction: SortDesc}, {Field: "created_at", Direction: SortDesc}}, ErrCursorSortMismatch},
+		{"other field", []Sort{{Field: "name", Direction: SortAsc}, {Field: "created_at", Direction: SortDesc}}, ErrCursorSortMismatch},
+		{"fewer fields", sorts[:1], ErrCursorSortMismatch},
+	}
+
+	for _, tt := range tests {
+		if err := cursor.CheckSort(tt.sorts); !errors.Is(err, tt.err) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.err)
+		}
+
+		// KeysetQuery turns the cursor down before it builds the query
+		if tt.err != nil {
+			if _, err := KeysetQuery(nil, nil, Pageable{Sort: tt.sorts, Cursor: cursor}, false); !errors.Is(err, tt.err) {
+				t.Errorf("%s: KeysetQuery got %v, want %v", tt.name, err, tt.err)
+			}
+		}
+	}
+}
+
+func TestKeysetPageCursorsCarryTheSort(t *testing.T) {
+	pageable := Pageable{Size: 2, Sort: []Sort{{Field: "price", Direction: SortDesc}}, Cursor: &Cursor{Values: []interface{}{int64(5), "a"}, Sort: "price:desc"}}
+
+	_, pagination := KeysetPage([]int{3, 2, 1}, pageable, func(row int) []interface{} { return []interface{}{int64(row), "id"} })
+
+	for _, value := range []string{pagination.NextCursor, pagination.PrevCursor} {
+		cursor, err := DecodeCursor(value)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := cursor.CheckSort(pageable.Sort); err != nil {
+			t.Errorf("cursor %v: %v", cursor, err)
+		}
+	}
+}
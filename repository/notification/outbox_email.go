@@ -0,0 +1,129 @@
+package notification_repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+)
+
+type OutboxEmailPageable struct {
+	repository.Pageable
+
+	Status string
+}
+
+// OutboxEmailSortFields are the fields queued emails can be sorted on.
+var OutboxEmailSortFields = repository.SortFields{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+type OutboxEmailRepositoryInterface interface {
+	CreateOutboxEmail(email models.OutboxEmail) (models.OutboxEmail, error)
+	FindOutboxEmailById(uuid uuid.UUID) (models.OutboxEmail, error)
+	FindAllOutboxEmails(pageable OutboxEmailPageable) ([]models.OutboxEmail, repository.Pagination, error)
+	FindDueOutboxEmails(status string, dueBefore time.Time, limit int) ([]models.OutboxEmail, error)
+	UpdateOutboxEmailAttempt(email models.OutboxEmail, fromStatus string, fromAttempts int) (int64, error)
+	WithTx(tx database.DatabaseInterface) OutboxEmailRepositoryInterface
+}
+
+type outboxEmailRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewOutboxEmailRepository(database database.DatabaseInterface) OutboxEmailRepositoryInterface {
+	return &outboxEmailRepository{database: database}
+}
+
+// WithTx implements OutboxEmailRepositoryInterface.
+func (r *outboxEmailRepository) WithTx(tx database.DatabaseInterface) OutboxEmailRepositoryInterface {
+	return &outboxEmailRepository{database: tx}
+}
+
+// CreateOutboxEmail implements OutboxEmailRepositoryInterface.
+func (r *outboxEmailRepository) CreateOutboxEmail(email models.OutboxEmail) (models.OutboxEmail, error) {
+	email.Prepare()
+
+	err := r.database.Connection().Create(&email).Error
+
+	return email, err
+}
+
+// FindOutboxEmailById implements OutboxEmailRepositoryInterface.
+func (r *outboxEmailRepository) FindOutboxEmailById(uuid uuid.UUID) (email models.OutboxEmail, err error) {
+	err = r.database.Connection().Model(&models.OutboxEmail{}).Where("id = ?", uuid).First(&email).Error
+
+	return email, err
+}
+
+// FindAllOutboxEmails implements OutboxEmailRepositoryInterface.
+func (r *outboxEmailRepository) FindAllOutboxEmails(pageable OutboxEmailPageable) ([]models.OutboxEmail, repository.Pagination, error) {
+	var emails []models.OutboxEmail
+	var pagination repository.Pagination
+
+	pagination.CurrentPage = int64(pageable.Page)
+	pagination.TotalItems = 0
+	pagination.TotalPages = 1
+
+	offset := (pageable.Page - 1) * pageable.Size
+	model := r.database.Connection().Model(&models.OutboxEmail{})
+
+	if pageable.Status != "" {
+		model = model.Where("status = ?", pageable.Status)
+	}
+
+	if pageable.Search != "" {
+		model = model.Where("recipient LIKE ?", "%"+pageable.Search+"%")
+	}
+
+	if err := model.Count(&pagination.TotalItems).Error; err != nil {
+		return nil, pagination, err
+	}
+
+	err := model.Offset(offset).Limit(pageable.Size).Order(OutboxEmailSortFields.OrderBy(pageable.Sort, "created_at DESC")).Find(&emails).Error
+
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	if pagination.TotalItems > 0 {
+		pagination.TotalPages = (pagination.TotalItems + int64(pageable.Size) - 1) / int64(pageable.Size)
+	}
+
+	return emails, pagination, nil
+}
+
+// FindDueOutboxEmails implements OutboxEmailRepositoryInterface.
+func (r *outboxEmailRepository) FindDueOutboxEmails(status string, dueBefore time.Time, limit int) (emails []models.OutboxEmail, err error) {
+	err = r.database.Connection().
+		Model(&models.OutboxEmail{}).
+		Where("status = ? AND next_attempt_at <= ?", status, dueBefore).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&emails).Error
+
+	return emails, err
+}
+
+// UpdateOutboxEmailAttempt implements OutboxEmailRepositoryInterface.
+// It only updates the email while it still has the status and attempt count it was read with,
+// so an attempt is never recorded twice and returns the number of rows updated.
+func (r *outboxEmailRepository) UpdateOutboxEmailAttempt(email models.OutboxEmail, fromStatus string, fromAttempts int) (int64, error) {
+	result := r.database.Connection().
+		Model(&models.OutboxEmail{}).
+		Where("id = ? AND status = ? AND attempts = ?", email.ID, fromStatus, fromAttempts).
+		Updates(map[string]interface{}{
+			"status":          email.Status,
+			"attempts":        email.Attempts,
+			"next_attempt_at": email.NextAttemptAt,
+			"last_error":      email.LastError,
+			"sent_at":         email.SentAt,
+			"updated_at":      time.Now(),
+		})
+
+	return result.RowsAffected, result.Error
+}
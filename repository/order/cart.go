@@ -0,0 +1,130 @@
+package order_repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+)
+
+type CartRepositoryInterface interface {
+	CreateCart(cart models.Cart) (models.Cart, error)
+	FindCartByUserId(userId uuid.UUID) (models.Cart, error)
+	FindGuestCartByToken(token string) (models.Cart, error)
+	AddCartItem(item models.CartItem) error
+	UpdateCartItemQuantity(cartId uuid.UUID, productId uuid.UUID, variantId *uuid.UUID, quantity int) (int64, error)
+	DeleteCartItem(cartId uuid.UUID, productId uuid.UUID, variantId *uuid.UUID) error
+	DeleteCartItems(cartId uuid.UUID) error
+	DeleteCart(cartId uuid.UUID) error
+	WithTx(tx database.DatabaseInterface) CartRepositoryInterface
+}
+
+type cartRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewCartRepository(database database.DatabaseInterface) CartRepositoryInterface {
+	return &cartRepository{database: database}
+}
+
+// WithTx implements CartRepositoryInterface.
+func (c *cartRepository) WithTx(tx database.DatabaseInterface) CartRepositoryInterface {
+	return &cartRepository{database: tx}
+}
+
+// CreateCart implements CartRepositoryInterface.
+func (c *cartRepository) CreateCart(cart models.Cart) (models.Cart, error) {
+	cart.Prepare()
+
+	err := c.database.Connection().Omit("Items").Create(&cart).Error
+
+	return cart, err
+}
+
+func (c *cartRepository) findCart(query *gorm.DB) (cart models.Cart, err error) {
+	err = query.
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("cart_items.created_at ASC")
+		}).
+		First(&cart).Error
+
+	return cart, err
+}
+
+// FindCartByUserId implements CartRepositoryInterface.
+func (c *cartRepository) FindCartByUserId(userId uuid.UUID) (models.Cart, error) {
+	return c.findCart(c.database.Connection().Model(&models.Cart{}).Where("user_id = ?", userId))
+}
+
+// FindGuestCartByToken implements CartRepositoryInterface.
+// Carts that were merged into a user's cart are not found.
+func (c *cartRepository) FindGuestCartByToken(token string) (models.Cart, error) {
+	return c.findCart(c.database.Connection().Model(&models.Cart{}).Where("token = ? AND user_id IS NULL", token))
+}
+
+// AddCartItem implements CartRepositoryInterface.
+// The quantity is added to the item already in the cart for the same product, or the same variant.
+func (c *cartRepository) AddCartItem(item models.CartItem) error {
+	item.Prepare()
+
+	conflict := clause.OnConflict{
+		Columns:     []clause.Column{{Name: "cart_id"}, {Name: "product_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "variant_id IS NULL"}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"quantity":   gorm.Expr("cart_items.quantity + excluded.quantity"),
+			"updated_at": gorm.Expr("NOW()"),
+		}),
+	}
+
+	if item.VariantID != nil {
+		conflict.Columns = []clause.Column{{Name: "cart_id"}, {Name: "variant_id"}}
+		conflict.TargetWhere = clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "variant_id IS NOT NULL"}}}
+	}
+
+	return c.database.Connection().Clauses(conflict).Create(&item).Error
+}
+
+// whereCartItem matches the cart's item for the product, or for the product's variant when variantId is not nil.
+func whereCartItem(query *gorm.DB, cartId uuid.UUID, productId uuid.UUID, variantId *uuid.UUID) *gorm.DB {
+	query = query.Where("cart_id = ? AND product_id = ?", cartId, productId)
+
+	if variantId == nil {
+		return query.Where("variant_id IS NULL")
+	}
+
+	return query.Where("variant_id = ?", *variantId)
+}
+
+// UpdateCartItemQuantity implements CartRepositoryInterface.
+func (c *cartRepository) UpdateCartItemQuantity(cartId uuid.UUID, productId uuid.UUID, variantId *uuid.UUID, quantity int) (int64, error) {
+	result := whereCartItem(c.database.Connection().Model(&models.CartItem{}), cartId, productId, variantId).
+		Update("quantity", quantity)
+
+	return result.RowsAffected, result.Error
+}
+
+// DeleteCartItem implements CartRepositoryInterface.
+// Cart items are removed for good, a cart only holds what is in it now.
+func (c *cartRepository) DeleteCartItem(cartId uuid.UUID, productId uuid.UUID, variantId *uuid.UUID) error {
+	return whereCartItem(c.database.Connection().Unscoped(), cartId, productId, variantId).
+		Delete(&models.CartItem{}).Error
+}
+
+// DeleteCartItems implements CartRepositoryInterface.
+func (c *cartRepository) DeleteCartItems(cartId uuid.UUID) error {
+	return c.database.Connection().
+		Unscoped().
+		Where("cart_id = ?", cartId).
+		Delete(&models.CartItem{}).Error
+}
+
+// DeleteCart implements CartRepositoryInterface. The cart's items are deleted with it.
+func (c *cartRepository) DeleteCart(cartId uuid.UUID) error {
+	if err := c.DeleteCartItems(cartId); err != nil {
+		return err
+	}
+
+	return c.database.Connection().Unscoped().Where("id = ?", cartId).Delete(&models.Cart{}).Error
+}
@@ -0,0 +1,192 @@
+package order_repository
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+)
+
+// CouponSortFields are the fields coupons can be sorted on.
+var CouponSortFields = repository.SortFields{
+	"created_at": "created_at",
+	"code":       "code",
+	"expires_at": "expires_at",
+}
+
+type CouponRepositoryInterface interface {
+	CreateCoupon(coupon models.Coupon) (models.Coupon, error)
+	FindCouponById(uuid uuid.UUID) (models.Coupon, error)
+	FindCouponByCode(code string) (models.Coupon, error)
+	FindCouponByCodeForUpdate(code string) (models.Coupon, error)
+	FindAllCoupons(pageable repository.Pageable) ([]models.Coupon, repository.Pagination, error)
+	CountCouponRedemptions(couponId uuid.UUID, userId uuid.UUID, excludeStatus string) (int64, error)
+	UpdateCoupon(coupon models.Coupon) (models.Coupon, error)
+	DeleteCoupon(uuid uuid.UUID) error
+	WithTx(tx database.DatabaseInterface) CouponRepositoryInterface
+}
+
+type couponRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewCouponRepository(database database.DatabaseInterface) CouponRepositoryInterface {
+	return &couponRepository{database: database}
+}
+
+// WithTx implements CouponRepositoryInterface.
+func (c *couponRepository) WithTx(tx database.DatabaseInterface) CouponRepositoryInterface {
+	return &couponRepository{database: tx}
+}
+
+// CreateCoupon implements CouponRepositoryInterface.
+func (c *couponRepository) CreateCoupon(coupon models.Coupon) (models.Coupon, error) {
+	coupon.Prepare()
+
+	if err := c.database.Connection().Omit("Products").Create(&coupon).Error; err != nil {
+		return coupon, err
+	}
+
+	return coupon, c.saveCouponProducts(coupon)
+}
+
+// saveCouponProducts links the coupon to its products. The products themselves are never written.
+func (c *couponRepository) saveCouponProducts(coupon models.Coupon) error {
+	if len(coupon.Products) == 0 {
+		return nil
+	}
+
+	rows := []map[string]interface{}{}
+	for _, product := range coupon.Products {
+		rows = append(rows, map[string]interface{}{"coupon_id": coupon.ID, "product_id": product.ID})
+	}
+
+	return c.database.Connection().Table("coupon_products").Create(&rows).Error
+}
+
+// FindCouponById implements CouponRepositoryInterface.
+func (c *couponRepository) FindCouponById(uuid uuid.UUID) (coupon models.Coupon, err error) {
+	err = c.database.Connection().
+		Model(&models.Coupon{}).
+		Preload("Products").
+		Where("id = ?", uuid).
+		First(&coupon).Error
+
+	return coupon, err
+}
+
+// FindCouponByCode implements CouponRepositoryInterface.
+func (c *couponRepository) FindCouponByCode(code string) (coupon models.Coupon, err error) {
+	err = c.database.Connection().
+		Model(&models.Coupon{}).
+		Preload("Products").
+		Where("code = ?", code).
+		First(&coupon).Error
+
+	return coupon, err
+}
+
+// FindCouponByCodeForUpdate implements CouponRepositoryInterface.
+// The coupon row stays locked until the surrounding transaction ends, so its usage limits hold under concurrent checkouts.
+func (c *couponRepository) FindCouponByCodeForUpdate(code string) (coupon models.Coupon, err error) {
+	err = c.database.Connection().
+		Model(&models.Coupon{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Products").
+		Where("code = ?", code).
+		First(&coupon).Error
+
+	return coupon, err
+}
+
+// FindAllCoupons implements CouponRepositoryInterface.
+func (c *couponRepository) FindAllCoupons(pageable repository.Pageable) ([]models.Coupon, repository.Pagination, error) {
+	var coupons []models.Coupon
+	var pagination repository.Pagination
+
+	pagination.CurrentPage = int64(pageable.Page)
+	pagination.TotalItems = 0
+	pagination.TotalPages = 1
+
+	offset := (pageable.Page - 1) * pageable.Size
+	model := c.database.Connection().Model(&models.Coupon{}).Preload("Products")
+
+	if len(strings.TrimSpace(pageable.Search)) > 0 {
+		model = model.Where("code LIKE ?", "%"+strings.ToUpper(strings.TrimSpace(pageable.Search))+"%")
+	}
+
+	if err := model.Count(&pagination.TotalItems).Error; err != nil {
+		return nil, pagination, err
+	}
+
+	err := model.Offset(offset).Limit(pageable.Size).Order(CouponSortFields.OrderBy(pageable.Sort, "created_at DESC")).Find(&coupons).Error
+
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	if pagination.TotalItems > 0 {
+		pagination.TotalPages = (pagination.TotalItems + int64(pageable.Size) - 1) / int64(pageable.Size)
+	}
+
+	return coupons, pagination, nil
+}
+
+// CountCouponRedemptions implements CouponRepositoryInterface.
+// It counts the orders placed with the coupon, leaving out orders in excludeStatus. A nil userId counts every user's orders.
+func (c *couponRepository) CountCouponRedemptions(couponId uuid.UUID, userId uuid.UUID, excludeStatus string) (int64, error) {
+	var count int64
+
+	query := c.database.Connection().
+		Model(&models.Order{}).
+		Joins("JOIN order_statuses ON orders.status_id = order_statuses.id").
+		Where("orders.coupon_id = ?", couponId).
+		Where("order_statuses.short_name <> ?", excludeStatus)
+
+	if userId != uuid.Nil {
+		query = query.Where("orders.user_id = ?", userId)
+	}
+
+	err := query.Count(&count).Error
+
+	return count, err
+}
+
+// UpdateCoupon implements CouponRepositoryInterface.
+// The coupon's products are replaced with the ones given.
+func (c *couponRepository) UpdateCoupon(coupon models.Coupon) (models.Coupon, error) {
+	if _, err := c.FindCouponById(coupon.ID); err != nil {
+		return models.Coupon{}, err
+	}
+
+	err := c.database.Connection().
+		Model(&models.Coupon{}).
+		Where("id = ?", coupon.ID).
+		Select("code", "description", "type", "value", "min_order_amount", "expires_at", "usage_limit", "usage_limit_per_user", "is_active").
+		Updates(&coupon).Error
+
+	if err != nil {
+		return coupon, err
+	}
+
+	if err := c.database.Connection().Exec("DELETE FROM coupon_products WHERE coupon_id = ?", coupon.ID).Error; err != nil {
+		return coupon, err
+	}
+
+	return coupon, c.saveCouponProducts(coupon)
+}
+
+// DeleteCoupon implements CouponRepositoryInterface.
+func (c *couponRepository) DeleteCoupon(uuid uuid.UUID) error {
+	coupon, err := c.FindCouponById(uuid)
+
+	if err != nil {
+		return err
+	}
+
+	return c.database.Connection().Delete(&coupon).Error
+}
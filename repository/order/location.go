@@ -0,0 +1,192 @@
+package order_repository
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+)
+
+type LocationRepositoryInterface interface {
+	CreateCountry(country models.Country) (models.Country, error)
+	FindAllCountries() ([]models.Country, error)
+	FindCountryById(uuid uuid.UUID) (models.Country, error)
+	UpdateCountry(country models.Country) (models.Country, error)
+	DeleteCountry(uuid uuid.UUID) error
+	CreateState(state models.State) (models.State, error)
+	FindStatesByCountryId(countryId uuid.UUID) ([]models.State, error)
+	FindStateById(uuid uuid.UUID) (models.State, error)
+	UpdateState(state models.State) (models.State, error)
+	DeleteState(uuid uuid.UUID) error
+	CreateCity(city models.City) (models.City, error)
+	FindCitiesByStateId(stateId uuid.UUID) ([]models.City, error)
+	FindCityById(uuid uuid.UUID) (models.City, error)
+	UpdateCity(city models.City) (models.City, error)
+	DeleteCity(uuid uuid.UUID) error
+	WithTx(tx database.DatabaseInterface) LocationRepositoryInterface
+}
+
+type locationRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewLocationRepository(database database.DatabaseInterface) LocationRepositoryInterface {
+	return &locationRepository{database: database}
+}
+
+// WithTx implements LocationRepositoryInterface.
+func (l *locationRepository) WithTx(tx database.DatabaseInterface) LocationRepositoryInterface {
+	return &locationRepository{database: tx}
+}
+
+// CreateCountry implements LocationRepositoryInterface.
+func (l *locationRepository) CreateCountry(country models.Country) (models.Country, error) {
+	country.Prepare()
+
+	err := l.database.Connection().Create(&country).Error
+
+	return country, err
+}
+
+// FindAllCountries implements LocationRepositoryInterface.
+func (l *locationRepository) FindAllCountries() (countries []models.Country, err error) {
+	err = l.database.Connection().Model(&models.Country{}).Order("name").Find(&countries).Error
+
+	return countries, err
+}
+
+// FindCountryById implements LocationRepositoryInterface.
+func (l *locationRepository) FindCountryById(uuid uuid.UUID) (country models.Country, err error) {
+	err = l.database.Connection().Model(&models.Country{}).Where("id = ?", uuid).First(&country).Error
+
+	return country, err
+}
+
+// UpdateCountry implements LocationRepositoryInterface.
+func (l *locationRepository) UpdateCountry(country models.Country) (models.Country, error) {
+	if _, err := l.FindCountryById(country.ID); err != nil {
+		return models.Country{}, err
+	}
+
+	err := l.database.Connection().
+		Model(&models.Country{}).
+		Where("id = ?", country.ID).
+		Select("name").
+		Updates(&country).Error
+
+	return country, err
+}
+
+// DeleteCountry implements LocationRepositoryInterface.
+func (l *locationRepository) DeleteCountry(uuid uuid.UUID) error {
+	country, err := l.FindCountryById(uuid)
+
+	if err != nil {
+		return err
+	}
+
+	return l.database.Connection().Delete(&country).Error
+}
+
+// CreateState implements LocationRepositoryInterface.
+func (l *locationRepository) CreateState(state models.State) (models.State, error) {
+	state.Prepare()
+
+	err := l.database.Connection().Omit("Country", "Cities").Create(&state).Error
+
+	return state, err
+}
+
+// FindStatesByCountryId implements LocationRepositoryInterface.
+func (l *locationRepository) FindStatesByCountryId(countryId uuid.UUID) (states []models.State, err error) {
+	err = l.database.Connection().Model(&models.State{}).Where("country_id = ?", countryId).Order("name").Find(&states).Error
+
+	return states, err
+}
+
+// FindStateById implements LocationRepositoryInterface.
+func (l *locationRepository) FindStateById(uuid uuid.UUID) (state models.State, err error) {
+	err = l.database.Connection().Model(&models.State{}).Preload("Country").Where("id = ?", uuid).First(&state).Error
+
+	return state, err
+}
+
+// UpdateState implements LocationRepositoryInterface.
+func (l *locationRepository) UpdateState(state models.State) (models.State, error) {
+	if _, err := l.FindStateById(state.ID); err != nil {
+		return models.State{}, err
+	}
+
+	err := l.database.Connection().
+		Model(&models.State{}).
+		Where("id = ?", state.ID).
+		Select("name").
+		Updates(&state).Error
+
+	return state, err
+}
+
+// DeleteState implements LocationRepositoryInterface.
+func (l *locationRepository) DeleteState(uuid uuid.UUID) error {
+	state, err := l.FindStateById(uuid)
+
+	if err != nil {
+		return err
+	}
+
+	return l.database.Connection().Delete(&state).Error
+}
+
+// CreateCity implements LocationRepositoryInterface.
+func (l *locationRepository) CreateCity(city models.City) (models.City, error) {
+	city.Prepare()
+
+	err := l.database.Connection().Omit("State").Create(&city).Error
+
+	return city, err
+}
+
+// FindCitiesByStateId implements LocationRepositoryInterface.
+func (l *locationRepository) FindCitiesByStateId(stateId uuid.UUID) (cities []models.City, err error) {
+	err = l.database.Connection().Model(&models.City{}).Where("state_id = ?", stateId).Order("name").Find(&cities).Error
+
+	return cities, err
+}
+
+// FindCityById implements LocationRepositoryInterface.
+func (l *locationRepository) FindCityById(uuid uuid.UUID) (city models.City, err error) {
+	err = l.database.Connection().
+		Model(&models.City{}).
+		Preload("State").
+		Preload("State.Country").
+		Where("id = ?", uuid).
+		First(&city).Error
+
+	return city, err
+}
+
+// UpdateCity implements LocationRepositoryInterface.
+func (l *locationRepository) UpdateCity(city models.City) (models.City, error) {
+	if _, err := l.FindCityById(city.ID); err != nil {
+		return models.City{}, err
+	}
+
+	err := l.database.Connection().
+		Model(&models.City{}).
+		Where("id = ?", city.ID).
+		Select("name", "price").
+		Updates(&city).Error
+
+	return city, err
+}
+
+// DeleteCity implements LocationRepositoryInterface.
+func (l *locationRepository) DeleteCity(uuid uuid.UUID) error {
+	city, err := l.FindCityById(uuid)
+
+	if err != nil {
+		return err
+	}
+
+	return l.database.Connection().Delete(&city).Error
+}
@@ -2,8 +2,11 @@ package order_repository
 
 import (
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
 	"github.com/developer-afo/instashop-ecommerce-api/models"
@@ -19,15 +22,25 @@ type OrderPageable struct {
 	ToDate   string
 }
 
+// OrderSortFields are the fields orders can be sorted on.
+var OrderSortFields = repository.SortFields{
+	"created_at":  "orders.created_at",
+	"total_price": "orders.total_price",
+}
+
 type OrderRepositoryInterface interface {
 	CreateOrder(order models.Order) (models.Order, error)
 	FindOrderById(uuid uuid.UUID) (models.Order, error)
+	FindOrderByIdForUpdate(uuid uuid.UUID) (models.Order, error)
 	FindOrderByReference(reference string) (models.Order, error)
 	FindOrderByTransactionId(transactionId uuid.UUID) (models.Order, error)
 	FindAllOrders(pageable OrderPageable) ([]models.Order, repository.Pagination, error)
 	CheckOrderExistByCouponId(couponId uuid.UUID) (bool, error)
+	FindUnpaidOrders(placedBefore time.Time, afterCreatedAt time.Time, afterId uuid.UUID, limit int) ([]models.Order, error)
 	UpdateOrder(order models.Order) (models.Order, error)
+	UpdateOrderStatus(uuid uuid.UUID, fromStatusId uuid.UUID, toStatusId uuid.UUID) (int64, error)
 	DeleteOrder(uuid uuid.UUID) error
+	WithTx(tx database.DatabaseInterface) OrderRepositoryInterface
 }
 
 type orderRepository struct {
@@ -38,15 +51,30 @@ func NewOrderRepository(database database.DatabaseInterface) OrderRepositoryInte
 	return &orderRepository{database: database}
 }
 
+// WithTx implements OrderRepositoryInterface.
+func (o *orderRepository) WithTx(tx database.DatabaseInterface) OrderRepositoryInterface {
+	return &orderRepository{database: tx}
+}
+
 // CreateOrder implements OrderRepositoryInterface.
 func (o *orderRepository) CreateOrder(order models.Order) (models.Order, error) {
 	order.Prepare()
 
+	for i := range order.Charges {
+		order.Charges[i].Prepare()
+		order.Charges[i].OrderID = order.ID
+	}
+
 	err := o.database.Connection().Create(&order).Error
 
 	return order, err
 }
 
+// unscoped preloads records that were deleted since, an order keeps showing what was ordered.
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
 // FindOrderById implements OrderRepositoryInterface.
 func (o *orderRepository) FindOrderById(uuid uuid.UUID) (order models.Order, err error) {
 
@@ -56,7 +84,10 @@ func (o *orderRepository) FindOrderById(uuid uuid.UUID) (order models.Order, err
 		Preload("Status").
 		Preload("Transaction").
 		Preload("OrderItems").
+		Preload("Charges").
 		Preload("OrderItems.Product").
+		Preload("OrderItems.Variant", unscoped).
+		Preload("OrderItems.Variant.Values", unscoped).
 		Where("id = ?", uuid).
 		First(&order).Error
 
@@ -71,6 +102,7 @@ func (o *orderRepository) FindOrderByReference(reference string) (order models.O
 		Preload("Status").
 		Preload("Transaction").
 		Preload("OrderItems").
+		Preload("Charges").
 		Where("reference = ?", reference).
 		First(&order).Error
 
@@ -85,6 +117,44 @@ func (o *orderRepository) FindOrderByTransactionId(transactionId uuid.UUID) (ord
 	return order, err
 }
 
+// FindOrderByIdForUpdate implements OrderRepositoryInterface.
+// The order row stays locked until the surrounding transaction ends.
+func (o *orderRepository) FindOrderByIdForUpdate(uuid uuid.UUID) (order models.Order, err error) {
+
+	err = o.database.Connection().
+		Model(&models.Order{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Status").
+		Preload("Transaction").
+		Preload("OrderItems").
+		Preload("Charges").
+		Where("id = ?", uuid).
+		First(&order).Error
+
+	return order, err
+}
+
+// FindUnpaidOrders implements OrderRepositoryInterface.
+// It returns orders still in order_placed with a pending transaction that were placed before placedBefore, oldest first,
+// from after the order placed at afterCreatedAt with afterId. The zero time and id start from the oldest.
+func (o *orderRepository) FindUnpaidOrders(placedBefore time.Time, afterCreatedAt time.Time, afterId uuid.UUID, limit int) (orders []models.Order, err error) {
+
+	err = o.database.Connection().
+		Model(&models.Order{}).
+		Preload("Transaction").
+		Joins("JOIN order_statuses ON orders.status_id = order_statuses.id").
+		Joins("JOIN transactions ON orders.transaction_id = transactions.id").
+		Where("order_statuses.short_name = ?", "order_placed").
+		Where("transactions.status = ?", "pending").
+		Where("orders.created_at < ?", placedBefore).
+		Where("(orders.created_at, orders.id) > (?, ?)", afterCreatedAt, afterId).
+		Order("orders.created_at ASC, orders.id ASC").
+		Limit(limit).
+		Find(&orders).Error
+
+	return orders, err
+}
+
 // FindAllOrders implements OrderRepositoryInterface.
 func (o *orderRepository) FindAllOrders(pageable OrderPageable) ([]models.Order, repository.Pagination, error) {
 	var orders []models.Order
@@ -105,8 +175,11 @@ func (o *orderRepository) FindAllOrders(pageable OrderPageable) ([]models.Order,
 		Preload("StatusHistory.Status").
 		Preload("Transaction").
 		Preload("OrderItems").
+		Preload("Charges").
 		Preload("OrderItems.Product").
-		Preload("OrderItems.Product.Images")
+		Preload("OrderItems.Product.Images", "variant_id IS NULL").
+		Preload("OrderItems.Variant", unscoped).
+		Preload("OrderItems.Variant.Values", unscoped)
 
 	// Apply search filters
 	if len(strings.TrimSpace(pageable.Search)) > 0 {
@@ -126,13 +199,19 @@ func (o *orderRepository) FindAllOrders(pageable OrderPageable) ([]models.Order,
 		model = model.Where("orders.user_id = ?", pageable.UserID)
 	}
 
+	keys := OrderSortFields.Keys(pageable.Sort, "orders.id", repository.SortKey{Column: "orders.created_at", Desc: true})
+
+	if pageable.Keyset {
+		return o.findOrdersByKeyset(model, keys, pageable)
+	}
+
 	// Count total items for pagination
 	if errCount = model.Count(&pagination.TotalItems).Error; errCount != nil {
 		return nil, pagination, errCount
 	}
 
 	// Apply pagination
-	paginatedQuery := model.Offset(int(offset)).Limit(int(pageable.Size)).Order(pageable.SortBy + " " + pageable.SortDirection)
+	paginatedQuery := model.Offset(int(offset)).Limit(int(pageable.Size)).Order(repository.OrderBy(keys, false))
 
 	// Execute the query
 	if err := paginatedQuery.Find(&orders).Error; err != nil {
@@ -149,6 +228,40 @@ func (o *orderRepository) FindAllOrders(pageable OrderPageable) ([]models.Order,
 	return orders, pagination, nil
 }
 
+// findOrdersByKeyset returns the page of orders after or before the cursor of pageable, without counting them.
+func (o *orderRepository) findOrdersByKeyset(model *gorm.DB, keys []repository.SortKey, pageable OrderPageable) ([]models.Order, repository.Pagination, error) {
+	var orders []models.Order
+
+	query, err := repository.KeysetQuery(model, keys, pageable.Pageable, false)
+
+	if err != nil {
+		return nil, repository.Pagination{}, err
+	}
+
+	if err := query.Find(&orders).Error; err != nil {
+		return nil, repository.Pagination{}, err
+	}
+
+	orders, pagination := repository.KeysetPage(orders, pageable.Pageable, func(order models.Order) []interface{} {
+		values := []interface{}{}
+
+		for _, key := range keys {
+			switch key.Column {
+			case OrderSortFields["created_at"]:
+				values = append(values, order.CreatedAt)
+			case OrderSortFields["total_price"]:
+				values = append(values, order.TotalPrice.Amount)
+			case "orders.id":
+				values = append(values, order.ID)
+			}
+		}
+
+		return values
+	})
+
+	return orders, pagination, nil
+}
+
 // CheckOrderExistByCouponId implements OrderRepositoryInterface.
 func (o *orderRepository) CheckOrderExistByCouponId(couponId uuid.UUID) (bool, error) {
 
@@ -173,6 +286,18 @@ func (o *orderRepository) UpdateOrder(order models.Order) (models.Order, error)
 	return order, err
 }
 
+// UpdateOrderStatus implements OrderRepositoryInterface.
+// The status only changes if the order is still in fromStatusId, so concurrent updates cannot both apply.
+func (o *orderRepository) UpdateOrderStatus(uuid uuid.UUID, fromStatusId uuid.UUID, toStatusId uuid.UUID) (int64, error) {
+
+	result := o.database.Connection().
+		Model(&models.Order{}).
+		Where("id = ? AND status_id = ?", uuid, fromStatusId).
+		Update("status_id", toStatusId)
+
+	return result.RowsAffected, result.Error
+}
+
 // DeleteOrder implements OrderRepositoryInterface.
 func (o *orderRepository) DeleteOrder(uuid uuid.UUID) error {
 
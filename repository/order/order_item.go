@@ -13,6 +13,7 @@ type OrderItemRepositoryInterface interface {
 	FindOrderItemById(uuid uuid.UUID) (models.OrderItem, error)
 	FindOrderItemsByOrderId(orderId uuid.UUID) ([]models.OrderItem, error)
 	UpdateOrderItem(orderItem models.OrderItem) (models.OrderItem, error)
+	WithTx(tx database.DatabaseInterface) OrderItemRepositoryInterface
 }
 
 type orderItemRepository struct {
@@ -23,6 +24,11 @@ func NewOrderItemRepository(database database.DatabaseInterface) OrderItemReposi
 	return &orderItemRepository{database: database}
 }
 
+// WithTx implements OrderItemRepositoryInterface.
+func (o *orderItemRepository) WithTx(tx database.DatabaseInterface) OrderItemRepositoryInterface {
+	return &orderItemRepository{database: tx}
+}
+
 // CreateOrderItem implements OrderItemRepositoryInterface.
 func (o *orderItemRepository) CreateOrderItem(orderItem models.OrderItem) (models.OrderItem, error) {
 	orderItem.Prepare()
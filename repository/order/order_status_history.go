@@ -12,6 +12,7 @@ type OrderStatusHistoryRepositoryInterface interface {
 	FindOrderStatusHistoryById(uuid uuid.UUID) (models.OrderStatusHistory, error)
 	FindOrderStatusHistoriesByOrderId(orderId uuid.UUID) ([]models.OrderStatusHistory, error)
 	UpdateOrderStatusHistory(orderStatusHistory models.OrderStatusHistory) (models.OrderStatusHistory, error)
+	WithTx(tx database.DatabaseInterface) OrderStatusHistoryRepositoryInterface
 }
 
 type orderStatusHistoryRepository struct {
@@ -22,6 +23,11 @@ func NewOrderStatusHistoryRepository(database database.DatabaseInterface) OrderS
 	return &orderStatusHistoryRepository{database: database}
 }
 
+// WithTx implements OrderStatusHistoryRepositoryInterface.
+func (o *orderStatusHistoryRepository) WithTx(tx database.DatabaseInterface) OrderStatusHistoryRepositoryInterface {
+	return &orderStatusHistoryRepository{database: tx}
+}
+
 // CreateOrderStatusHistory implements OrderStatusHistoryRepositoryInterface.
 func (o *orderStatusHistoryRepository) CreateOrderStatusHistory(orderStatusHistory models.OrderStatusHistory) (models.OrderStatusHistory, error) {
 	orderStatusHistory.Prepare()
@@ -46,6 +52,7 @@ func (o *orderStatusHistoryRepository) FindOrderStatusHistoriesByOrderId(orderId
 		Model(&models.OrderStatusHistory{}).
 		Where("order_id = ?", orderId).
 		Preload("Status").
+		Order("created_at").
 		Find(&orderStatusHistories).Error
 
 	return orderStatusHistories, err
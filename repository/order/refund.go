@@ -0,0 +1,192 @@
+package order_repository
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+)
+
+type RefundRepositoryInterface interface {
+	CreateRefund(refund models.Refund) (models.Refund, error)
+	FindRefundById(uuid uuid.UUID) (models.Refund, error)
+	FindRefundsByOrderId(orderId uuid.UUID) ([]models.Refund, error)
+	FindRefundByGatewayReference(gatewayReference string) (models.Refund, error)
+	FindPendingRefundByPaymentReference(reference string) (models.Refund, error)
+	RefundedQuantities(orderId uuid.UUID, excludeStatus string) (map[uuid.UUID]int, error)
+	RefundedCharges(orderId uuid.UUID, excludeStatus string) (map[uuid.UUID]money.Money, error)
+	RefundedByMethod(orderId uuid.UUID, method string, excludeStatus string) (money.Money, error)
+	UpdateRefundStatus(uuid uuid.UUID, fromStatus string, toStatus string, gatewayReference string) (int64, error)
+	WithTx(tx database.DatabaseInterface) RefundRepositoryInterface
+}
+
+type refundRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewRefundRepository(database database.DatabaseInterface) RefundRepositoryInterface {
+	return &refundRepository{database: database}
+}
+
+// WithTx implements RefundRepositoryInterface.
+func (r *refundRepository) WithTx(tx database.DatabaseInterface) RefundRepositoryInterface {
+	return &refundRepository{database: tx}
+}
+
+// CreateRefund implements RefundRepositoryInterface.
+func (r *refundRepository) CreateRefund(refund models.Refund) (models.Refund, error) {
+	refund.Prepare()
+
+	for i := range refund.Items {
+		refund.Items[i].Prepare()
+		refund.Items[i].RefundID = refund.ID
+	}
+
+	err := r.database.Connection().Create(&refund).Error
+
+	return refund, err
+}
+
+// FindRefundById implements RefundRepositoryInterface.
+func (r *refundRepository) FindRefundById(uuid uuid.UUID) (refund models.Refund, err error) {
+
+	err = r.database.Connection().
+		Model(&models.Refund{}).
+		Preload("Items").
+		Preload("Transaction").
+		Where("id = ?", uuid).
+		First(&refund).Error
+
+	return refund, err
+}
+
+// FindRefundsByOrderId implements RefundRepositoryInterface.
+func (r *refundRepository) FindRefundsByOrderId(orderId uuid.UUID) (refunds []models.Refund, err error) {
+
+	err = r.database.Connection().
+		Model(&models.Refund{}).
+		Preload("Items").
+		Where("order_id = ?", orderId).
+		Order("created_at ASC").
+		Find(&refunds).Error
+
+	return refunds, err
+}
+
+// FindRefundByGatewayReference implements RefundRepositoryInterface.
+func (r *refundRepository) FindRefundByGatewayReference(gatewayReference string) (refund models.Refund, err error) {
+
+	err = r.database.Connection().
+		Model(&models.Refund{}).
+		Preload("Transaction").
+		Where("gateway_reference = ?", gatewayReference).
+		First(&refund).Error
+
+	return refund, err
+}
+
+// FindPendingRefundByPaymentReference implements RefundRepositoryInterface.
+// It returns the oldest pending refund of the payment with the given reference.
+func (r *refundRepository) FindPendingRefundByPaymentReference(reference string) (refund models.Refund, err error) {
+
+	err = r.database.Connection().
+		Model(&models.Refund{}).
+		Preload("Transaction").
+		Joins("JOIN transactions ON refunds.transaction_id = transactions.id").
+		Joins("JOIN transactions payments ON transactions.parent_id = payments.id").
+		Where("payments.reference = ?", reference).
+		Where("refunds.status = ?", "pending").
+		Order("refunds.created_at ASC").
+		First(&refund).Error
+
+	return refund, err
+}
+
+// RefundedQuantities implements RefundRepositoryInterface.
+// It returns the quantity of each order item already refunded, leaving out refunds in excludeStatus.
+func (r *refundRepository) RefundedQuantities(orderId uuid.UUID, excludeStatus string) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		OrderItemID uuid.UUID
+		Quantity    int
+	}
+
+	err := r.database.Connection().
+		Model(&models.RefundItem{}).
+		Select("refund_items.order_item_id, SUM(refund_items.quantity) as quantity").
+		Joins("JOIN refunds ON refund_items.refund_id = refunds.id").
+		Where("refunds.order_id = ? AND refunds.status <> ?", orderId, excludeStatus).
+		Where("refund_items.order_item_id IS NOT NULL").
+		Group("refund_items.order_item_id").
+		Scan(&rows).Error
+
+	quantities := map[uuid.UUID]int{}
+	for _, row := range rows {
+		quantities[row.OrderItemID] = row.Quantity
+	}
+
+	return quantities, err
+}
+
+// RefundedCharges implements RefundRepositoryInterface.
+// It returns the amount already refunded on each order charge in the order's currency, leaving out refunds in excludeStatus.
+func (r *refundRepository) RefundedCharges(orderId uuid.UUID, excludeStatus string) (map[uuid.UUID]money.Money, error) {
+	var rows []struct {
+		OrderChargeID uuid.UUID
+		Amount        money.Money
+		Currency      string
+	}
+
+	err := r.database.Connection().
+		Model(&models.RefundItem{}).
+		Select("refund_items.order_charge_id, SUM(refund_items.amount) as amount, MIN(refunds.currency) as currency").
+		Joins("JOIN refunds ON refund_items.refund_id = refunds.id").
+		Where("refunds.order_id = ? AND refunds.status <> ?", orderId, excludeStatus).
+		Where("refund_items.order_charge_id IS NOT NULL").
+		Group("refund_items.order_charge_id").
+		Scan(&rows).Error
+
+	amounts := map[uuid.UUID]money.Money{}
+	for _, row := range rows {
+		amounts[row.OrderChargeID] = money.New(row.Amount.Amount, row.Currency)
+	}
+
+	return amounts, err
+}
+
+// RefundedByMethod implements RefundRepositoryInterface.
+// It returns the amount refunded on the order through a transaction method, like the gateway or the wallet,
+// in the order's currency, leaving out refunds in excludeStatus.
+func (r *refundRepository) RefundedByMethod(orderId uuid.UUID, method string, excludeStatus string) (money.Money, error) {
+	var row struct {
+		Amount   money.Money
+		Currency string
+	}
+
+	err := r.database.Connection().
+		Model(&models.Refund{}).
+		Select("COALESCE(SUM(refunds.amount), 0) as amount, COALESCE(MIN(refunds.currency), '') as currency").
+		Joins("JOIN transactions ON refunds.transaction_id = transactions.id").
+		Where("refunds.order_id = ? AND refunds.status <> ?", orderId, excludeStatus).
+		Where("transactions.method = ?", method).
+		Scan(&row).Error
+
+	return money.New(row.Amount.Amount, row.Currency), err
+}
+
+// UpdateRefundStatus implements RefundRepositoryInterface.
+// The refund only changes if it is still in fromStatus. An empty gatewayReference keeps the stored one.
+func (r *refundRepository) UpdateRefundStatus(uuid uuid.UUID, fromStatus string, toStatus string, gatewayReference string) (int64, error) {
+	updates := map[string]interface{}{"status": toStatus}
+
+	if gatewayReference != "" {
+		updates["gateway_reference"] = gatewayReference
+	}
+
+	result := r.database.Connection().
+		Model(&models.Refund{}).
+		Where("id = ? AND status = ?", uuid, fromStatus).
+		Updates(updates)
+
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,121 @@
+package order_repository
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+)
+
+type ShippingAddressRepositoryInterface interface {
+	CreateShippingAddress(address models.ShippingAddress) (models.ShippingAddress, error)
+	FindShippingAddressById(uuid uuid.UUID) (models.ShippingAddress, error)
+	FindShippingAddressesByUserId(userId uuid.UUID) ([]models.ShippingAddress, error)
+	FindDefaultShippingAddress(userId uuid.UUID) (models.ShippingAddress, error)
+	UpdateShippingAddress(address models.ShippingAddress) (models.ShippingAddress, error)
+	SetDefaultShippingAddress(userId uuid.UUID, uuid uuid.UUID) error
+	DeleteShippingAddress(uuid uuid.UUID) error
+	WithTx(tx database.DatabaseInterface) ShippingAddressRepositoryInterface
+}
+
+type shippingAddressRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewShippingAddressRepository(database database.DatabaseInterface) ShippingAddressRepositoryInterface {
+	return &shippingAddressRepository{database: database}
+}
+
+// WithTx implements ShippingAddressRepositoryInterface.
+func (s *shippingAddressRepository) WithTx(tx database.DatabaseInterface) ShippingAddressRepositoryInterface {
+	return &shippingAddressRepository{database: tx}
+}
+
+// CreateShippingAddress implements ShippingAddressRepositoryInterface.
+func (s *shippingAddressRepository) CreateShippingAddress(address models.ShippingAddress) (models.ShippingAddress, error) {
+	address.Prepare()
+
+	err := s.database.Connection().Omit("City", "State").Create(&address).Error
+
+	return address, err
+}
+
+// FindShippingAddressById implements ShippingAddressRepositoryInterface.
+func (s *shippingAddressRepository) FindShippingAddressById(uuid uuid.UUID) (address models.ShippingAddress, err error) {
+	err = s.database.Connection().
+		Model(&models.ShippingAddress{}).
+		Preload("City").
+		Preload("State").
+		Preload("State.Country").
+		Where("id = ?", uuid).
+		First(&address).Error
+
+	return address, err
+}
+
+// FindShippingAddressesByUserId implements ShippingAddressRepositoryInterface.
+func (s *shippingAddressRepository) FindShippingAddressesByUserId(userId uuid.UUID) (addresses []models.ShippingAddress, err error) {
+	err = s.database.Connection().
+		Model(&models.ShippingAddress{}).
+		Preload("City").
+		Preload("State").
+		Preload("State.Country").
+		Where("user_id = ?", userId).
+		Order("is_default DESC, created_at DESC").
+		Find(&addresses).Error
+
+	return addresses, err
+}
+
+// FindDefaultShippingAddress implements ShippingAddressRepositoryInterface.
+func (s *shippingAddressRepository) FindDefaultShippingAddress(userId uuid.UUID) (address models.ShippingAddress, err error) {
+	err = s.database.Connection().
+		Model(&models.ShippingAddress{}).
+		Preload("City").
+		Preload("State").
+		Preload("State.Country").
+		Where("user_id = ? AND is_default", userId).
+		First(&address).Error
+
+	return address, err
+}
+
+// UpdateShippingAddress implements ShippingAddressRepositoryInterface.
+func (s *shippingAddressRepository) UpdateShippingAddress(address models.ShippingAddress) (models.ShippingAddress, error) {
+	err := s.database.Connection().
+		Model(&models.ShippingAddress{}).
+		Where("id = ?", address.ID).
+		Select("first_name", "last_name", "phone", "alternate_phone", "address", "city_id", "state_id", "closest_landmark").
+		Updates(&address).Error
+
+	return address, err
+}
+
+// SetDefaultShippingAddress implements ShippingAddressRepositoryInterface.
+// The previous default is cleared first, so it should run in a transaction.
+func (s *shippingAddressRepository) SetDefaultShippingAddress(userId uuid.UUID, uuid uuid.UUID) error {
+	err := s.database.Connection().
+		Model(&models.ShippingAddress{}).
+		Where("user_id = ? AND is_default AND id <> ?", userId, uuid).
+		Update("is_default", false).Error
+
+	if err != nil {
+		return err
+	}
+
+	return s.database.Connection().
+		Model(&models.ShippingAddress{}).
+		Where("user_id = ? AND id = ?", userId, uuid).
+		Update("is_default", true).Error
+}
+
+// DeleteShippingAddress implements ShippingAddressRepositoryInterface.
+func (s *shippingAddressRepository) DeleteShippingAddress(uuid uuid.UUID) error {
+	address, err := s.FindShippingAddressById(uuid)
+
+	if err != nil {
+		return err
+	}
+
+	return s.database.Connection().Delete(&address).Error
+}
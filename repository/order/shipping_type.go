@@ -0,0 +1,115 @@
+package order_repository
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+)
+
+type ShippingTypeRepositoryInterface interface {
+	CreateShippingType(shippingType models.ShippingType) (models.ShippingType, error)
+	FindShippingTypeById(uuid uuid.UUID) (models.ShippingType, error)
+	FindAllShippingTypes(activeOnly bool) ([]models.ShippingType, error)
+	UpdateShippingType(shippingType models.ShippingType) (models.ShippingType, error)
+	DeleteShippingType(uuid uuid.UUID) error
+	WithTx(tx database.DatabaseInterface) ShippingTypeRepositoryInterface
+}
+
+type shippingTypeRepository struct {
+	database database.DatabaseInterface
+}
+
+func NewShippingTypeRepository(database database.DatabaseInterface) ShippingTypeRepositoryInterface {
+	return &shippingTypeRepository{database: database}
+}
+
+// WithTx implements ShippingTypeRepositoryInterface.
+func (s *shippingTypeRepository) WithTx(tx database.DatabaseInterface) ShippingTypeRepositoryInterface {
+	return &shippingTypeRepository{database: tx}
+}
+
+// CreateShippingType implements ShippingTypeRepositoryInterface.
+func (s *shippingTypeRepository) CreateShippingType(shippingType models.ShippingType) (models.ShippingType, error) {
+	shippingType.Prepare()
+
+	for i := range shippingType.Rates {
+		shippingType.Rates[i].Prepare()
+		shippingType.Rates[i].ShippingTypeID = shippingType.ID
+	}
+
+	err := s.database.Connection().Omit("Rates.State").Create(&shippingType).Error
+
+	return shippingType, err
+}
+
+// FindShippingTypeById implements ShippingTypeRepositoryInterface.
+func (s *shippingTypeRepository) FindShippingTypeById(uuid uuid.UUID) (shippingType models.ShippingType, err error) {
+	err = s.database.Connection().
+		Model(&models.ShippingType{}).
+		Preload("Rates").
+		Where("id = ?", uuid).
+		First(&shippingType).Error
+
+	return shippingType, err
+}
+
+// FindAllShippingTypes implements ShippingTypeRepositoryInterface.
+func (s *shippingTypeRepository) FindAllShippingTypes(activeOnly bool) (shippingTypes []models.ShippingType, err error) {
+	query := s.database.Connection().Model(&models.ShippingType{}).Preload("Rates")
+
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	err = query.Order("price").Find(&shippingTypes).Error
+
+	return shippingTypes, err
+}
+
+// UpdateShippingType implements ShippingTypeRepositoryInterface.
+// The shipping type's rates are replaced with the ones given.
+func (s *shippingTypeRepository) UpdateShippingType(shippingType models.ShippingType) (models.ShippingType, error) {
+	if _, err := s.FindShippingTypeById(shippingType.ID); err != nil {
+		return models.ShippingType{}, err
+	}
+
+	err := s.database.Connection().
+		Model(&models.ShippingType{}).
+		Where("id = ?", shippingType.ID).
+		Select("name", "description", "price", "price_per_kg", "is_active").
+		Updates(&shippingType).Error
+
+	if err != nil {
+		return shippingType, err
+	}
+
+	err = s.database.Connection().
+		Unscoped().
+		Where("shipping_type_id = ?", shippingType.ID).
+		Delete(&models.ShippingRate{}).Error
+
+	if err != nil || len(shippingType.Rates) == 0 {
+		return shippingType, err
+	}
+
+	for i := range shippingType.Rates {
+		shippingType.Rates[i].Prepare()
+		shippingType.Rates[i].ShippingTypeID = shippingType.ID
+	}
+
+	err = s.database.Connection().Omit("State").Create(&shippingType.Rates).Error
+
+	return shippingType, err
+}
+
+// DeleteShippingType implements ShippingTypeRepositoryInterface.
+func (s *shippingTypeRepository) DeleteShippingType(uuid uuid.UUID) error {
+	shippingType, err := s.FindShippingTypeById(uuid)
+
+	if err != nil {
+		return err
+	}
+
+	return s.database.Connection().Delete(&shippingType).Error
+}
@@ -1,23 +1,88 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
 
 type Respository struct{}
 
+// Sort is empty when the client did not ask for an order, each repository then falls back to its own.
+// Keyset pages by Cursor instead of Page, the first page has no Cursor.
 type Pageable struct {
-	Page          int    `json:"page"`
-	Size          int    `json:"size"`
-	SortBy        string `json:"sort_by"`
-	SortDirection string `json:"sort_dir"`
-	Search        string `json:"search"`
+	Page   int     `json:"page"`
+	Size   int     `json:"size"`
+	Sort   []Sort  `json:"sort"`
+	Search string  `json:"search"`
+	Keyset bool    `json:"keyset"`
+	Cursor *Cursor `json:"cursor"`
+}
+
+// Sort is one key of an ORDER BY. Direction is SortAsc or SortDesc.
+type Sort struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"`
 }
 
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// SortFields maps the fields a list can be sorted on to the column they sort.
+// Only these columns are ever written to a query, a field from a client is never.
+type SortFields map[string]string
+
+// Pagination of a keyset page only has cursors, the rows are not counted.
 type Pagination struct {
-	CurrentPage int64 `json:"current_page"`
-	TotalPages  int64 `json:"total_pages"`
-	TotalItems  int64 `json:"total_items"`
+	CurrentPage int64  `json:"current_page"`
+	TotalPages  int64  `json:"total_pages"`
+	TotalItems  int64  `json:"total_items"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	PrevCursor  string `json:"prev_cursor,omitempty"`
 }
 
 func GeneratePageable(database *gorm.DB) (pageable Pageable) {
 	return pageable
 }
+
+// Names returns the fields that can be sorted on in alphabetical order.
+func (fields SortFields) Names() []string {
+	names := []string{}
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// OrderBy returns the ORDER BY for sorts, or fallback when there is none.
+// Fields that cannot be sorted on are skipped, handlers reject them before they get here.
+func (fields SortFields) OrderBy(sorts []Sort, fallback string) string {
+	keys := []string{}
+
+	for _, s := range sorts {
+		column, ok := fields[s.Field]
+
+		if !ok {
+			continue
+		}
+
+		direction := "ASC"
+		if s.Direction == SortDesc {
+			direction = "DESC"
+		}
+
+		keys = append(keys, column+" "+direction)
+	}
+
+	if len(keys) == 0 {
+		return fallback
+	}
+
+	return strings.Join(keys, ", ")
+}
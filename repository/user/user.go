@@ -4,12 +4,24 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
 	"github.com/developer-afo/instashop-ecommerce-api/models"
 	"github.com/developer-afo/instashop-ecommerce-api/repository"
 )
 
+// userListColumns are the columns of the users in a list.
+var userListColumns = []string{"id", "first_name", "last_name", "referral_code", "email", "is_email_verified", "created_at"}
+
+// UserSortFields are the fields users can be sorted on.
+var UserSortFields = repository.SortFields{
+	"created_at": "created_at",
+	"first_name": "first_name",
+	"last_name":  "last_name",
+	"email":      "email",
+}
+
 type UserRepositoryInterface interface {
 	Create(user models.User) (models.User, error)
 	FindAllUsers(pageable repository.Pageable) ([]models.User, repository.Pagination, error)
@@ -17,6 +29,7 @@ type UserRepositoryInterface interface {
 	FindUserByEmail(email string) (models.User, error)
 	FindUserByReferralCode(referralCode string) (models.User, error)
 	UpdateUser(user models.User) (models.User, error)
+	UpdateNotificationPreferences(uuid uuid.UUID, notifyOrderUpdates bool) (models.User, error)
 	DeleteUser(uuid uuid.UUID) error
 }
 
@@ -78,6 +91,12 @@ func (u *userRepository) FindAllUsers(pageable repository.Pageable) (users []mod
 		model = model.Where("first_name LIKE ? OR last_name LIKE ? OR email LIKE ?", "%"+search+"%", "%"+search+"%", "%"+search+"%")
 	}
 
+	keys := UserSortFields.Keys(pageable.Sort, "id", repository.SortKey{Column: "created_at", Desc: true})
+
+	if pageable.Keyset {
+		return u.findUsersByKeyset(model, keys, pageable)
+	}
+
 	// Get total items
 	if err = model.Count(&pagination.TotalItems).Error; err != nil {
 		return nil, pagination, err
@@ -85,10 +104,10 @@ func (u *userRepository) FindAllUsers(pageable repository.Pageable) (users []mod
 
 	// apply pagination
 	paginatedQuery := model.
-		Select("id", "first_name", "last_name", "referral_code", "email", "is_email_verified", "created_at").
+		Select(userListColumns).
 		Offset(int(offset)).
 		Limit(int(pageable.Size)).
-		Order(pageable.SortBy + " " + pageable.SortDirection)
+		Order(repository.OrderBy(keys, false))
 
 	// execute query
 	if err = paginatedQuery.Find(&users).Error; err != nil {
@@ -105,6 +124,44 @@ func (u *userRepository) FindAllUsers(pageable repository.Pageable) (users []mod
 	return users, pagination, nil
 }
 
+// findUsersByKeyset returns the page of users after or before the cursor of pageable, without counting them.
+func (u *userRepository) findUsersByKeyset(model *gorm.DB, keys []repository.SortKey, pageable repository.Pageable) ([]models.User, repository.Pagination, error) {
+	var users []models.User
+
+	query, err := repository.KeysetQuery(model.Select(userListColumns), keys, pageable, false)
+
+	if err != nil {
+		return nil, repository.Pagination{}, err
+	}
+
+	if err := query.Find(&users).Error; err != nil {
+		return nil, repository.Pagination{}, err
+	}
+
+	users, pagination := repository.KeysetPage(users, pageable, func(user models.User) []interface{} {
+		values := []interface{}{}
+
+		for _, key := range keys {
+			switch key.Column {
+			case UserSortFields["created_at"]:
+				values = append(values, user.CreatedAt)
+			case UserSortFields["first_name"]:
+				values = append(values, user.FirstName)
+			case UserSortFields["last_name"]:
+				values = append(values, user.LastName)
+			case UserSortFields["email"]:
+				values = append(values, user.Email)
+			case "id":
+				values = append(values, user.ID)
+			}
+		}
+
+		return values
+	})
+
+	return users, pagination, nil
+}
+
 // FindUserByEmail implements UserRepositoryInterface.
 func (u *userRepository) FindUserByEmail(email string) (user models.User, err error) {
 	err = u.database.Connection().Model(&models.User{}).Where("email = ?", email).First(&user).Error
@@ -145,3 +202,23 @@ func (u *userRepository) UpdateUser(user models.User) (models.User, error) {
 	return checkRow, err
 
 }
+
+// UpdateNotificationPreferences implements UserRepositoryInterface.
+// The column is updated by name because Updates skips false when given a struct.
+func (u *userRepository) UpdateNotificationPreferences(uuid uuid.UUID, notifyOrderUpdates bool) (models.User, error) {
+
+	checkRow, err := u.FindUserById(uuid)
+
+	if err != nil {
+		return checkRow, err
+	}
+
+	err = u.database.Connection().Model(&checkRow).Update("notify_order_updates", notifyOrderUpdates).Error
+
+	if err != nil {
+
+		return models.User{}, err
+	}
+
+	return checkRow, nil
+}
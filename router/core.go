@@ -9,8 +9,10 @@ import (
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
 	"github.com/developer-afo/instashop-ecommerce-api/middleware"
 	core_repository "github.com/developer-afo/instashop-ecommerce-api/repository/core"
+	finance_repository "github.com/developer-afo/instashop-ecommerce-api/repository/finance"
 	user_repository "github.com/developer-afo/instashop-ecommerce-api/repository/user"
 	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
 	userService "github.com/developer-afo/instashop-ecommerce-api/service/user"
 )
 
@@ -18,21 +20,36 @@ func InitializeCoreRouter(router fiber.Router, db database.DatabaseInterface, en
 	// Repositories
 	userRepository := user_repository.NewUserRepository(db)
 	productRepository := core_repository.NewProductRepository(db)
+	categoryRepository := core_repository.NewCategoryRepository(db)
+	productVariantRepository := core_repository.NewProductVariantRepository(db)
+	productReviewRepository := core_repository.NewProductReviewRepository(db)
 	imageRepository := core_repository.NewImageRepository(db)
+	inventoryMovementRepository := core_repository.NewInventoryMovementRepository(db)
+	currencyRateRepository := finance_repository.NewCurrencyRateRepository(db)
 
 	// Services
 	imageService := core_service.NewImageService(imageRepository)
 	productService := core_service.NewProductService(
+		db,
 		productRepository,
+		categoryRepository,
 		imageService,
 	)
+	categoryService := core_service.NewCategoryService(db, categoryRepository)
+	productVariantService := core_service.NewProductVariantService(db, productVariantRepository, productRepository, productService)
+	productReviewService := core_service.NewProductReviewService(db, productReviewRepository, productRepository)
+	inventoryService := core_service.NewInventoryService(productRepository, inventoryMovementRepository)
+	currencyService := finance_service.NewCurrencyService(db, currencyRateRepository)
 
 	// config
 	mediaConfig := config.NewMediaHelper(env)
 
 	// Handlers
-	productHandler := core_handler.NewProductHandler(productService, imageService)
+	productHandler := core_handler.NewProductHandler(productService, imageService, inventoryService, currencyService)
 	mediaHandler := core_handler.NewMediaHandler(mediaConfig)
+	categoryHandler := core_handler.NewCategoryHandler(categoryService)
+	productVariantHandler := core_handler.NewProductVariantHandler(productVariantService, productService)
+	productReviewHandler := core_handler.NewProductReviewHandler(productReviewService)
 
 	// middlewares
 	authMiddleware := middleware.Protected()
@@ -41,6 +58,7 @@ func InitializeCoreRouter(router fiber.Router, db database.DatabaseInterface, en
 	// Base routes
 	productRoute := router.Group("/products")
 	mediaRouter := router.Group("/media")
+	categoryRoute := router.Group("/categories")
 
 	// Routes
 
@@ -53,6 +71,23 @@ func InitializeCoreRouter(router fiber.Router, db database.DatabaseInterface, en
 		Get("/", productHandler.FindImagesByProductId).
 		Post("/", productHandler.CreateImage).
 		Delete("/:key", productHandler.DeleteImage)
+	productRoute.Group("/:product_id/options", authMiddleware, roleMiddleware.ValidateRole(userService.UserRoleAdmin)).
+		Post("/", productVariantHandler.CreateOption).
+		Delete("/:option_id", productVariantHandler.DeleteOption)
+	productRoute.Group("/:product_id/variants", authMiddleware, roleMiddleware.ValidateRole(userService.UserRoleAdmin)).
+		Post("/", productVariantHandler.CreateVariant).
+		Put("/:variant_id", productVariantHandler.UpdateVariant).
+		Delete("/:variant_id", productVariantHandler.DeleteVariant)
+	productRoute.Get("/:slug/reviews", productReviewHandler.FindReviews)
+	productRoute.Post("/:slug/reviews", authMiddleware, productReviewHandler.CreateReview)
+	productRoute.Get("/:slug/reviews/all", authMiddleware, roleMiddleware.ValidateRole(userService.UserRoleAdmin), productReviewHandler.FindAllReviews)
+	productRoute.Put("/:slug/reviews/:review_id", authMiddleware, roleMiddleware.ValidateRole(userService.UserRoleAdmin), productReviewHandler.ModerateReview)
+	productRoute.Get("/:product_id/inventory-movements", authMiddleware, roleMiddleware.ValidateRole(userService.UserRoleAdmin), productHandler.FindInventoryMovementsByProductId)
+
+	categoryRoute.Get("/", categoryHandler.GetCategoryTree)
+	categoryRoute.Post("/", authMiddleware, roleMiddleware.ValidateRole(userService.UserRoleAdmin), categoryHandler.CreateCategory)
+	categoryRoute.Put("/:category_id", authMiddleware, roleMiddleware.ValidateRole(userService.UserRoleAdmin), categoryHandler.UpdateCategory)
+	categoryRoute.Delete("/:category_id", authMiddleware, roleMiddleware.ValidateRole(userService.UserRoleAdmin), categoryHandler.DeleteCategory)
 
 	mediaRouter.Post("/upload", mediaHandler.UploadMedia, authMiddleware, roleMiddleware.ValidateRole(userService.UserRoleAdmin))
 	mediaRouter.Get("/:key", mediaHandler.GetMedia)
@@ -0,0 +1,61 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	finance_handler "github.com/developer-afo/instashop-ecommerce-api/handler/finance"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/middleware"
+	finance_repository "github.com/developer-afo/instashop-ecommerce-api/repository/finance"
+	user_repository "github.com/developer-afo/instashop-ecommerce-api/repository/user"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+	user_service "github.com/developer-afo/instashop-ecommerce-api/service/user"
+)
+
+func InitializeFinanceRouter(router fiber.Router, db database.DatabaseInterface, env constants.Env) {
+	// Repositories
+	userRepository := user_repository.NewUserRepository(db)
+	currencyRateRepository := finance_repository.NewCurrencyRateRepository(db)
+	transactionRepository := finance_repository.NewTransactionRepository(db)
+	ledgerRepository := finance_repository.NewLedgerRepository(db)
+
+	// Services
+	httpService := service.NewHTTPService()
+
+	currencyService := finance_service.NewCurrencyService(db, currencyRateRepository)
+	ledgerService := finance_service.NewLedgerService(db, ledgerRepository)
+	transactionService := finance_service.NewTransactionService(db, transactionRepository, ledgerService)
+	paymentGatewayService := payment_gateway_service.NewPaymentGatewayService(env, paymentProviders(httpService, env)...)
+	userService := user_service.NewUserService(userRepository)
+	walletService := finance_service.NewWalletService(db, transactionRepository, transactionService, paymentGatewayService, userService)
+
+	// Handlers
+	currencyHandler := finance_handler.NewCurrencyHandler(currencyService)
+	walletHandler := finance_handler.NewWalletHandler(walletService)
+	ledgerHandler := finance_handler.NewLedgerHandler(ledgerService)
+
+	// middlewares
+	authMiddleware := middleware.Protected()
+	roleMiddleware := middleware.NewRoleMiddleware(userRepository)
+
+	// Base routes
+	currencyRouter := router.Group("/currencies")
+	walletRouter := router.Group("/wallet", authMiddleware)
+	ledgerRouter := router.Group("/ledger", authMiddleware, roleMiddleware.ValidateRole(user_service.UserRoleAdmin, user_service.UserRoleFinance))
+
+	// Routes
+	currencyRouter.Get("/", currencyHandler.GetCurrencies)
+	currencyRouter.Put("/:currency", authMiddleware, roleMiddleware.ValidateRole(user_service.UserRoleAdmin), currencyHandler.SetCurrencyRate)
+	currencyRouter.Delete("/:currency", authMiddleware, roleMiddleware.ValidateRole(user_service.UserRoleAdmin), currencyHandler.DeleteCurrencyRate)
+
+	walletRouter.Get("/", walletHandler.GetWallet)
+	walletRouter.Get("/transactions", walletHandler.GetWalletTransactions)
+	walletRouter.Post("/top-up", walletHandler.TopUp)
+	walletRouter.Get("/verify-top-up/:reference", walletHandler.VerifyTopUp)
+
+	ledgerRouter.Get("/trial-balance", ledgerHandler.GetTrialBalance)
+	ledgerRouter.Get("/accounts/:code/statement", ledgerHandler.GetAccountStatement)
+}
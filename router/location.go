@@ -0,0 +1,75 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	order_handler "github.com/developer-afo/instashop-ecommerce-api/handler/order"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/middleware"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+	user_repository "github.com/developer-afo/instashop-ecommerce-api/repository/user"
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
+	user_service "github.com/developer-afo/instashop-ecommerce-api/service/user"
+)
+
+func InitializeLocationRouter(router fiber.Router, db database.DatabaseInterface, env constants.Env) {
+	// Repositories
+	userRepository := user_repository.NewUserRepository(db)
+	locationRepository := order_repository.NewLocationRepository(db)
+	shippingAddressRepository := order_repository.NewShippingAddressRepository(db)
+	shippingTypeRepository := order_repository.NewShippingTypeRepository(db)
+
+	// Services
+	locationService := order_service.NewLocationService(db, locationRepository)
+	shippingAddressService := order_service.NewShippingAddressService(db, shippingAddressRepository, locationService)
+	shippingTypeService := order_service.NewShippingTypeService(db, shippingTypeRepository)
+
+	// Handlers
+	locationHandler := order_handler.NewLocationHandler(locationService)
+	shippingAddressHandler := order_handler.NewShippingAddressHandler(shippingAddressService)
+	shippingTypeHandler := order_handler.NewShippingTypeHandler(shippingTypeService)
+
+	// middlewares
+	roleMiddleware := middleware.NewRoleMiddleware(userRepository)
+	authMiddleware := middleware.Protected()
+	adminMiddleware := roleMiddleware.ValidateRole(user_service.UserRoleAdmin)
+
+	// Base routes
+	countryRouter := router.Group("/countries")
+	stateRouter := router.Group("/states")
+	cityRouter := router.Group("/cities")
+	shippingTypeRouter := router.Group("/shipping-types")
+	addressRouter := router.Group("/addresses", authMiddleware, roleMiddleware.ValidateRole(user_service.UserRoleCustomer))
+
+	// Routes
+	countryRouter.Get("/", locationHandler.GetCountries)
+	countryRouter.Post("/", authMiddleware, adminMiddleware, locationHandler.CreateCountry)
+	countryRouter.Put("/:country_id", authMiddleware, adminMiddleware, locationHandler.UpdateCountry)
+	countryRouter.Delete("/:country_id", authMiddleware, adminMiddleware, locationHandler.DeleteCountry)
+	countryRouter.Get("/:country_id/states", locationHandler.GetStates)
+	countryRouter.Post("/:country_id/states", authMiddleware, adminMiddleware, locationHandler.CreateState)
+
+	stateRouter.Put("/:state_id", authMiddleware, adminMiddleware, locationHandler.UpdateState)
+	stateRouter.Delete("/:state_id", authMiddleware, adminMiddleware, locationHandler.DeleteState)
+	stateRouter.Get("/:state_id/cities", locationHandler.GetCities)
+	stateRouter.Post("/:state_id/cities", authMiddleware, adminMiddleware, locationHandler.CreateCity)
+
+	cityRouter.Put("/:city_id", authMiddleware, adminMiddleware, locationHandler.UpdateCity)
+	cityRouter.Delete("/:city_id", authMiddleware, adminMiddleware, locationHandler.DeleteCity)
+
+	router.Post("/locations", authMiddleware, adminMiddleware, locationHandler.CreateLocation)
+
+	shippingTypeRouter.Get("/", shippingTypeHandler.GetShippingTypes)
+	shippingTypeRouter.Get("/all", authMiddleware, adminMiddleware, shippingTypeHandler.GetAllShippingTypes)
+	shippingTypeRouter.Post("/", authMiddleware, adminMiddleware, shippingTypeHandler.CreateShippingType)
+	shippingTypeRouter.Put("/:shipping_type_id", authMiddleware, adminMiddleware, shippingTypeHandler.UpdateShippingType)
+	shippingTypeRouter.Delete("/:shipping_type_id", authMiddleware, adminMiddleware, shippingTypeHandler.DeleteShippingType)
+
+	addressRouter.Get("/", shippingAddressHandler.GetShippingAddresses)
+	addressRouter.Post("/", shippingAddressHandler.CreateShippingAddress)
+	addressRouter.Get("/:address_id", shippingAddressHandler.GetShippingAddress)
+	addressRouter.Put("/:address_id", shippingAddressHandler.UpdateShippingAddress)
+	addressRouter.Delete("/:address_id", shippingAddressHandler.DeleteShippingAddress)
+	addressRouter.Post("/:address_id/default", shippingAddressHandler.SetDefaultShippingAddress)
+}
@@ -0,0 +1,55 @@
+package router
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	notification_handler "github.com/developer-afo/instashop-ecommerce-api/handler/notification"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/config"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/scheduler"
+	"github.com/developer-afo/instashop-ecommerce-api/middleware"
+	notification_repository "github.com/developer-afo/instashop-ecommerce-api/repository/notification"
+	user_repository "github.com/developer-afo/instashop-ecommerce-api/repository/user"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+	user_service "github.com/developer-afo/instashop-ecommerce-api/service/user"
+)
+
+func InitializeNotificationRouter(router fiber.Router, db database.DatabaseInterface, env constants.Env, jobScheduler scheduler.SchedulerInterface) {
+	// Repositories
+	userRepository := user_repository.NewUserRepository(db)
+	outboxEmailRepository := notification_repository.NewOutboxEmailRepository(db)
+
+	// config
+	mailConfig := config.NewEmail(env)
+
+	// Services
+	emailService := service.NewEmailService(mailConfig, outboxEmailRepository)
+
+	// Handlers
+	emailHandler := notification_handler.NewEmailHandler(emailService)
+
+	// middlewares
+	roleMiddleware := middleware.NewRoleMiddleware(userRepository)
+	authMiddleware := middleware.Protected()
+
+	// Base routes
+	emailRouter := router.Group("/emails", authMiddleware, roleMiddleware.ValidateRole(user_service.UserRoleAdmin))
+
+	// Routes
+	emailRouter.Get("/", emailHandler.GetOutboxEmails)
+	emailRouter.Post("/:email_id/retry", emailHandler.RetryOutboxEmail)
+
+	// Jobs
+	jobScheduler.Register(scheduler.Job{
+		Name:     "deliver-emails",
+		Interval: 10 * time.Second,
+		Run: func() error {
+			_, err := emailService.DeliverPendingEmails(service.DeliverEmailsBatchSize)
+
+			return err
+		},
+	})
+}
@@ -1,14 +1,22 @@
 package router
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 
+	finance_handler "github.com/developer-afo/instashop-ecommerce-api/handler/finance"
 	order_handler "github.com/developer-afo/instashop-ecommerce-api/handler/order"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/command"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/config"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/scheduler"
 	"github.com/developer-afo/instashop-ecommerce-api/middleware"
 	coreRepository "github.com/developer-afo/instashop-ecommerce-api/repository/core"
 	finance_repository "github.com/developer-afo/instashop-ecommerce-api/repository/finance"
+	notification_repository "github.com/developer-afo/instashop-ecommerce-api/repository/notification"
 	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
 	user_repository "github.com/developer-afo/instashop-ecommerce-api/repository/user"
 	"github.com/developer-afo/instashop-ecommerce-api/service"
@@ -19,47 +27,90 @@ import (
 	user_service "github.com/developer-afo/instashop-ecommerce-api/service/user"
 )
 
-func InitializeOrderRouter(router fiber.Router, db database.DatabaseInterface, env constants.Env) {
+func InitializeOrderRouter(router fiber.Router, db database.DatabaseInterface, env constants.Env, jobScheduler scheduler.SchedulerInterface, commands command.RegistryInterface) {
 	// Repositories
 	userRepository := user_repository.NewUserRepository(db)
 	orderRepository := order_repository.NewOrderRepository(db)
 	orderItemRepository := order_repository.NewOrderItemRepository(db)
 	orderStatusRepository := order_repository.NewOrderStatusRepository(db)
 	orderStatusHistoryRepository := order_repository.NewOrderStatusHistoryRepository(db)
+	refundRepository := order_repository.NewRefundRepository(db)
+	locationRepository := order_repository.NewLocationRepository(db)
+	shippingAddressRepository := order_repository.NewShippingAddressRepository(db)
+	shippingTypeRepository := order_repository.NewShippingTypeRepository(db)
+	couponRepository := order_repository.NewCouponRepository(db)
+	cartRepository := order_repository.NewCartRepository(db)
 	imageRepository := coreRepository.NewImageRepository(db)
 	productRepository := coreRepository.NewProductRepository(db)
+	categoryRepository := coreRepository.NewCategoryRepository(db)
+	inventoryMovementRepository := coreRepository.NewInventoryMovementRepository(db)
 	transactionRepository := finance_repository.NewTransactionRepository(db)
+	ledgerRepository := finance_repository.NewLedgerRepository(db)
+	currencyRateRepository := finance_repository.NewCurrencyRateRepository(db)
+	reconciliationRepository := finance_repository.NewReconciliationRepository(db)
+	outboxEmailRepository := notification_repository.NewOutboxEmailRepository(db)
+
+	// config
+	mailConfig := config.NewEmail(env)
 
 	// Services
 	httpService := service.NewHTTPService()
+	emailService := service.NewEmailService(mailConfig, outboxEmailRepository)
 
 	imageService := core_service.NewImageService(imageRepository)
-	productService := core_service.NewProductService(productRepository, imageService)
+	productService := core_service.NewProductService(db, productRepository, categoryRepository, imageService)
+	inventoryService := core_service.NewInventoryService(productRepository, inventoryMovementRepository)
 
 	orderItemService := order_service.NewOrderItemService(orderItemRepository, productService)
 	orderStatusService := order_service.NewOrderStatusService(orderStatusRepository)
 	orderStatusHistoryService := order_service.NewOrderStatusHistoryService(orderStatusHistoryRepository, orderStatusService)
+	locationService := order_service.NewLocationService(db, locationRepository)
+	shippingAddressService := order_service.NewShippingAddressService(db, shippingAddressRepository, locationService)
+	shippingTypeService := order_service.NewShippingTypeService(db, shippingTypeRepository)
+	couponService := order_service.NewCouponService(db, couponRepository, orderRepository)
+	cartService := order_service.NewCartService(db, cartRepository, productService)
 
-	transactionService := finance_service.NewTransactionService(transactionRepository)
-	paystackPaymentService := payment_gateway_service.NewPaystackService(httpService, env)
-	flutterwavePaymentService := payment_gateway_service.NewFlutterwaveService(httpService, env)
-	paymentGatewayService := payment_gateway_service.NewPaymentGatewayService(paystackPaymentService, flutterwavePaymentService)
+	ledgerService := finance_service.NewLedgerService(db, ledgerRepository)
+	transactionService := finance_service.NewTransactionService(db, transactionRepository, ledgerService)
+	currencyService := finance_service.NewCurrencyService(db, currencyRateRepository)
+	paymentGatewayService := payment_gateway_service.NewPaymentGatewayService(env, paymentProviders(httpService, env)...)
 
 	userService := user_service.NewUserService(userRepository)
+	walletService := finance_service.NewWalletService(db, transactionRepository, transactionService, paymentGatewayService, userService)
+
+	refundService := order_service.NewRefundService(db, refundRepository, orderRepository, transactionService, walletService, paymentGatewayService)
 
 	orderService := order_service.NewOrderService(
+		db,
 		orderRepository,
 		orderItemService,
 		orderStatusService,
 		orderStatusHistoryService,
+		shippingAddressService,
+		shippingTypeService,
+		couponService,
+		cartService,
 		productService,
+		inventoryService,
 		transactionService,
+		currencyService,
+		walletService,
 		paymentGatewayService,
+		refundService,
 		userService,
+		emailService,
 	)
 
+	reconciliationService := finance_service.NewReconciliationService(db, reconciliationRepository, transactionRepository, paymentGatewayService, walletService, orderService)
+
 	// Handlers
 	orderHandler := order_handler.NewOrderHandler(orderService, orderStatusHistoryService, orderStatusService)
+	webhookHandler := finance_handler.NewWebhookHandler(paymentGatewayService, orderService, refundService, walletService)
+	paymentHandler := finance_handler.NewPaymentHandler(paymentGatewayService)
+	refundHandler := order_handler.NewRefundHandler(refundService)
+	couponHandler := order_handler.NewCouponHandler(couponService)
+	cartHandler := order_handler.NewCartHandler(cartService)
+	reconciliationHandler := finance_handler.NewReconciliationHandler(reconciliationService)
 
 	// middlewares
 	roleMiddleware := middleware.NewRoleMiddleware(userRepository)
@@ -67,6 +118,11 @@ func InitializeOrderRouter(router fiber.Router, db database.DatabaseInterface, e
 
 	// Base routes
 	orderRouter := router.Group("/order", authMiddleware)
+	webhookRouter := router.Group("/webhook")
+	paymentRouter := router.Group("/payment")
+	couponRouter := router.Group("/coupons", authMiddleware, roleMiddleware.ValidateRole(user_service.UserRoleAdmin))
+	cartRouter := router.Group("/cart", middleware.OptionalAuth())
+	reconciliationRouter := router.Group("/reconciliation", authMiddleware, roleMiddleware.ValidateRole(user_service.UserRoleAdmin))
 
 	// Routes
 	orderRouter.Post("/", roleMiddleware.ValidateRole(user_service.UserRoleCustomer), orderHandler.CreateOrder)
@@ -79,5 +135,95 @@ func InitializeOrderRouter(router fiber.Router, db database.DatabaseInterface, e
 	orderRouter.Group("/:order_id").
 		Post("/process", roleMiddleware.ValidateRole(user_service.UserRoleAdmin), orderHandler.OrderProcessing).
 		Post("/out-for-delivery", roleMiddleware.ValidateRole(user_service.UserRoleAdmin), orderHandler.OutForDelivery).
-		Post("/delivered", roleMiddleware.ValidateRole(user_service.UserRoleCustomer), orderHandler.Delivered)
+		Post("/delivered", roleMiddleware.ValidateRole(user_service.UserRoleCustomer), orderHandler.Delivered).
+		Post("/cancel", roleMiddleware.ValidateRole(user_service.UserRoleAdmin), orderHandler.CancelPaidOrder).
+		Post("/refund", roleMiddleware.ValidateRole(user_service.UserRoleAdmin), refundHandler.RefundOrder).
+		Get("/refunds", roleMiddleware.ValidateRole(user_service.UserRoleAdmin), refundHandler.GetOrderRefunds)
+
+	webhookRouter.Post("/:gateway", webhookHandler.HandleWebhook)
+
+	paymentRouter.Get("/methods", paymentHandler.GetPaymentMethods)
+
+	couponRouter.Get("/", couponHandler.GetCoupons)
+	couponRouter.Post("/", couponHandler.CreateCoupon)
+	couponRouter.Get("/:coupon_id", couponHandler.GetCoupon)
+	couponRouter.Put("/:coupon_id", couponHandler.UpdateCoupon)
+	couponRouter.Delete("/:coupon_id", couponHandler.DeleteCoupon)
+
+	cartRouter.Get("/", cartHandler.GetCart)
+	cartRouter.Delete("/", cartHandler.ClearCart)
+	cartRouter.Post("/items", cartHandler.AddCartItem)
+	cartRouter.Put("/items/:product_id", cartHandler.UpdateCartItem)
+	cartRouter.Delete("/items/:product_id", cartHandler.RemoveCartItem)
+
+	reconciliationRouter.Get("/reports", reconciliationHandler.GetReports)
+	reconciliationRouter.Post("/reports", reconciliationHandler.Reconcile)
+	reconciliationRouter.Get("/reports/:report_id", reconciliationHandler.GetReport)
+
+	// Jobs
+	paymentTTL, err := time.ParseDuration(env.ORDER_PAYMENT_TTL)
+	if err != nil {
+		paymentTTL = order_service.DefaultOrderPaymentTTL
+	}
+
+	jobScheduler.Register(scheduler.Job{
+		Name:     "expire-unpaid-orders",
+		Interval: time.Minute,
+		Run: func() error {
+			_, err := orderService.ExpireUnpaidOrders(paymentTTL)
+
+			return err
+		},
+	})
+
+	// checked every hour and run once a day, counted from the latest report so a restart does not hold it back
+	jobScheduler.Register(scheduler.Job{
+		Name:     "reconcile-gateway-payments",
+		Interval: time.Hour,
+		Run: func() error {
+			_, err := reconciliationService.ReconcileDue(24 * time.Hour)
+
+			return err
+		},
+	})
+
+	// Commands
+	commands.Register(command.Command{
+		Name:  "reconcile",
+		Usage: "reconcile [from_date] [to_date]  checks the gateway payments from from_date to the end of to_date, yesterday and today by default",
+		Run: func(args []string) error {
+			today := time.Now().UTC().Truncate(24 * time.Hour)
+			dates := []time.Time{today.AddDate(0, 0, -1), today}
+
+			for i := 0; i < len(args) && i < len(dates); i++ {
+				date, err := time.Parse("2006-01-02", args[i])
+
+				if err != nil {
+					return fmt.Errorf("%s is not a date like 2006-01-02", args[i])
+				}
+
+				dates[i] = date
+			}
+
+			report, err := reconciliationService.Reconcile(dates[0], dates[1].AddDate(0, 0, 1))
+
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Reconciliation report %s: %d matched, %d discrepancies, %d healed\n",
+				report.ID, report.Matched, report.Discrepancies, report.Healed)
+
+			for _, item := range report.Items {
+				fmt.Printf("  %s %s %s: local %s %v, provider %s %v, healed %t %s\n", item.Gateway, item.Reference, item.Kind,
+					item.LocalStatus, item.LocalAmount, item.ProviderStatus, item.ProviderAmount, item.Healed, item.Note)
+			}
+
+			for _, reportErr := range report.Errors {
+				fmt.Println("  error: " + reportErr)
+			}
+
+			return nil
+		},
+	})
 }
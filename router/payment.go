@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+	flutterwave_gateway "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway/flutterwave"
+	paystack_gateway "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway/paystack"
+)
+
+// paymentProviders lists every gateway the API can talk to. PAYMENT_GATEWAYS decides which of them are enabled.
+func paymentProviders(httpService service.HttpServiceInterface, env constants.Env) []payment_gateway_service.Provider {
+	return []payment_gateway_service.Provider{
+		paystack_gateway.NewPaystackProvider(httpService, env),
+		flutterwave_gateway.NewFlutterwaveProvider(httpService, env),
+	}
+}
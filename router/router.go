@@ -5,17 +5,22 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/monitor"
 
 	"github.com/developer-afo/instashop-ecommerce-api/handler"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/command"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/scheduler"
 )
 
-func InitializeRouter(router *fiber.App, dbConn database.DatabaseInterface, env constants.Env) {
+func InitializeRouter(router *fiber.App, dbConn database.DatabaseInterface, env constants.Env, jobScheduler scheduler.SchedulerInterface, commands command.RegistryInterface) {
 
 	router.Get("/monitor", monitor.New(monitor.Config{Title: "Instashop API Monitor"}))
 
 	InitializeUserRouter(router, dbConn, env)
 	InitializeCoreRouter(router, dbConn, env)
-	InitializeOrderRouter(router, dbConn, env)
+	InitializeLocationRouter(router, dbConn, env)
+	InitializeOrderRouter(router, dbConn, env, jobScheduler, commands)
+	InitializeNotificationRouter(router, dbConn, env, jobScheduler)
+	InitializeFinanceRouter(router, dbConn, env)
 
 	router.Get("/health", func(c *fiber.Ctx) error {
 		return c.SendString("OK")
@@ -7,8 +7,14 @@ import (
 	"github.com/developer-afo/instashop-ecommerce-api/lib/config"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/middleware"
+	coreRepository "github.com/developer-afo/instashop-ecommerce-api/repository/core"
+	notification_repository "github.com/developer-afo/instashop-ecommerce-api/repository/notification"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
 	user_repository "github.com/developer-afo/instashop-ecommerce-api/repository/user"
 	"github.com/developer-afo/instashop-ecommerce-api/service"
+	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
+	order_service "github.com/developer-afo/instashop-ecommerce-api/service/order"
 	user_service "github.com/developer-afo/instashop-ecommerce-api/service/user"
 )
 
@@ -16,21 +22,35 @@ func InitializeUserRouter(router fiber.Router, db database.DatabaseInterface, en
 	// Repositories
 	userRepository := user_repository.NewUserRepository(db)
 	verificationCodeRepository := user_repository.NewVerificationCodeRepository(db)
+	outboxEmailRepository := notification_repository.NewOutboxEmailRepository(db)
+	imageRepository := coreRepository.NewImageRepository(db)
+	productRepository := coreRepository.NewProductRepository(db)
+	categoryRepository := coreRepository.NewCategoryRepository(db)
+	cartRepository := order_repository.NewCartRepository(db)
 
 	// config
 	mailConfig := config.NewEmail(env)
 
 	// Services
-	emailService := service.NewEmailService(mailConfig)
+	emailService := service.NewEmailService(mailConfig, outboxEmailRepository)
 	userService := user_service.NewUserService(userRepository)
 	verificationCodeService := user_service.NewVerficationCodeService(userRepository, verificationCodeRepository)
 	authService := user_service.NewAuthService(userService, verificationCodeService, emailService)
+	imageService := core_service.NewImageService(imageRepository)
+	productService := core_service.NewProductService(db, productRepository, categoryRepository, imageService)
+	cartService := order_service.NewCartService(db, cartRepository, productService)
 
 	// Handler
-	authHandler := userHandler.NewAuthHandler(authService)
+	authHandler := userHandler.NewAuthHandler(authService, cartService)
+	userProfileHandler := userHandler.NewUserHandler(userService)
+
+	// middlewares
+	authMiddleware := middleware.Protected()
+	roleMiddleware := middleware.NewRoleMiddleware(userRepository)
 
 	// Routers
 	authRoute := router.Group("/auth")
+	userRoute := router.Group("/user", authMiddleware)
 
 	// Routes
 	authRoute.Post("/login", authHandler.Login)
@@ -41,4 +61,7 @@ func InitializeUserRouter(router fiber.Router, db database.DatabaseInterface, en
 	authRoute.Post("/forgot-password", authHandler.ForgotPassword)
 	authRoute.Post("/reset-password", authHandler.ResetPassword)
 
+	userRoute.Get("/all", roleMiddleware.ValidateRole(user_service.UserRoleAdmin), userProfileHandler.GetUsers)
+	userRoute.Get("/notification-preferences", userProfileHandler.GetNotificationPreferences)
+	userRoute.Put("/notification-preferences", userProfileHandler.UpdateNotificationPreferences)
 }
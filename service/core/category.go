@@ -0,0 +1,231 @@
+package core_service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/helper"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	coreRepository "github.com/developer-afo/instashop-ecommerce-api/repository/core"
+)
+
+var (
+	ErrCategoryParentNotFound = errors.New("parent category not found")
+	ErrCategoryParentInvalid  = errors.New("a category cannot be moved below itself")
+	ErrCategoryHasChildren    = errors.New("category has sub-categories, move or delete them first")
+)
+
+type CategoryServiceInterface interface {
+	CreateCategory(category dto.CategoryDTO) (dto.CategoryDTO, error)
+	FindCategoryById(id uuid.UUID) (dto.CategoryDTO, error)
+	FindCategoryTree() ([]dto.CategoryDTO, error)
+	UpdateCategory(category dto.CategoryDTO) (dto.CategoryDTO, error)
+	DeleteCategory(id uuid.UUID) error
+}
+
+type categoryService struct {
+	database           database.DatabaseInterface
+	categoryRepository coreRepository.CategoryRepositoryInterface
+}
+
+func NewCategoryService(
+	database database.DatabaseInterface,
+	categoryRepository coreRepository.CategoryRepositoryInterface,
+) CategoryServiceInterface {
+	return &categoryService{
+		database:           database,
+		categoryRepository: categoryRepository,
+	}
+}
+
+func (service *categoryService) withTx(tx database.DatabaseInterface) *categoryService {
+	return &categoryService{
+		database:           tx,
+		categoryRepository: service.categoryRepository.WithTx(tx),
+	}
+}
+
+// CategoryToDTO converts a category without its children.
+func CategoryToDTO(category models.Category) (categoryDto dto.CategoryDTO) {
+
+	categoryDto.ID = category.ID
+	categoryDto.Slug = category.Slug
+	categoryDto.Name = category.Name
+	categoryDto.Description = category.Description
+	categoryDto.ParentUUID = category.ParentID
+	categoryDto.CreatedAt = category.CreatedAt
+	categoryDto.UpdatedAt = category.UpdatedAt
+	categoryDto.DeletedAt = category.DeletedAt.Time
+
+	return categoryDto
+}
+
+func (service *categoryService) ConvertToModel(categoryDto dto.CategoryDTO) (category models.Category) {
+
+	category.ID = categoryDto.ID
+	category.Slug = categoryDto.Slug
+	category.Name = categoryDto.Name
+	category.Description = categoryDto.Description
+	category.ParentID = categoryDto.ParentUUID
+
+	return category
+}
+
+// checkParent returns an error when parentId cannot be the parent of categoryId.
+// A new category is checked with a nil categoryId.
+func (service *categoryService) checkParent(categoryId uuid.UUID, parentId *uuid.UUID) error {
+	if parentId == nil {
+		return nil
+	}
+
+	if _, err := service.categoryRepository.FindCategoryById(*parentId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryParentNotFound
+		}
+
+		return err
+	}
+
+	if categoryId == uuid.Nil {
+		return nil
+	}
+
+	// the parent must not be the category or one below it, the tree would become a loop
+	subtree, err := service.categoryRepository.FindSubtreeIds(categoryId)
+
+	if err != nil {
+		return err
+	}
+
+	for _, id := range subtree {
+		if id == *parentId {
+			return ErrCategoryParentInvalid
+		}
+	}
+
+	return nil
+}
+
+// CreateCategory implements CategoryServiceInterface. The slug is made from the name.
+func (service *categoryService) CreateCategory(categoryDto dto.CategoryDTO) (dto.CategoryDTO, error) {
+	if err := service.checkParent(uuid.Nil, categoryDto.ParentUUID); err != nil {
+		return dto.CategoryDTO{}, err
+	}
+
+	slug := helper.GenerateSlug(categoryDto.Name)
+
+	_, err := service.categoryRepository.FindCategoryBySlug(slug)
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.CategoryDTO{}, err
+	}
+
+	categoryDto.Slug = slug
+
+	if err == nil {
+		categoryDto.Slug = slug + "-" + helper.GenerateTimestamp()
+	}
+
+	category, err := service.categoryRepository.CreateCategory(service.ConvertToModel(categoryDto))
+
+	if err != nil {
+		return dto.CategoryDTO{}, err
+	}
+
+	return CategoryToDTO(category), nil
+}
+
+// FindCategoryById implements CategoryServiceInterface.
+func (service *categoryService) FindCategoryById(id uuid.UUID) (dto.CategoryDTO, error) {
+	category, err := service.categoryRepository.FindCategoryById(id)
+
+	if err != nil {
+		return dto.CategoryDTO{}, err
+	}
+
+	return CategoryToDTO(category), nil
+}
+
+// FindCategoryTree implements CategoryServiceInterface.
+// It returns the top level categories with the categories below them nested in Children, each level sorted by name.
+func (service *categoryService) FindCategoryTree() ([]dto.CategoryDTO, error) {
+	categories, err := service.categoryRepository.FindAllCategories()
+
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[uuid.UUID][]models.Category{}
+	roots := []models.Category{}
+
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+
+			continue
+		}
+
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	var build func(nodes []models.Category) []dto.CategoryDTO
+	build = func(nodes []models.Category) []dto.CategoryDTO {
+		tree := []dto.CategoryDTO{}
+
+		for _, node := range nodes {
+			categoryDto := CategoryToDTO(node)
+			categoryDto.Children = build(children[node.ID])
+
+			tree = append(tree, categoryDto)
+		}
+
+		return tree
+	}
+
+	return build(roots), nil
+}
+
+// UpdateCategory implements CategoryServiceInterface. The parent is checked and changed in one transaction.
+func (service *categoryService) UpdateCategory(categoryDto dto.CategoryDTO) (dto.CategoryDTO, error) {
+	err := service.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := service.withTx(tx)
+
+		if _, err := txService.categoryRepository.FindCategoryById(categoryDto.ID); err != nil {
+			return err
+		}
+
+		if err := txService.checkParent(categoryDto.ID, categoryDto.ParentUUID); err != nil {
+			return err
+		}
+
+		_, err := txService.categoryRepository.UpdateCategory(txService.ConvertToModel(categoryDto))
+
+		return err
+	})
+
+	if err != nil {
+		return dto.CategoryDTO{}, err
+	}
+
+	return service.FindCategoryById(categoryDto.ID)
+}
+
+// DeleteCategory implements CategoryServiceInterface.
+// Products stay in the deleted category but are no longer found with it.
+func (service *categoryService) DeleteCategory(id uuid.UUID) error {
+	children, err := service.categoryRepository.CountChildren(id)
+
+	if err != nil {
+		return err
+	}
+
+	if children > 0 {
+		return ErrCategoryHasChildren
+	}
+
+	return service.categoryRepository.DeleteCategory(id)
+}
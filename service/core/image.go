@@ -27,6 +27,7 @@ func (service *imageService) ConvertToDTO(image models.Image) (imageDto dto.Imag
 
 	imageDto.ID = image.ID
 	imageDto.ProductUUID = image.ProductID
+	imageDto.VariantUUID = image.VariantID
 	imageDto.Key = image.Key
 	imageDto.CreatedAt = image.CreatedAt
 	imageDto.UpdatedAt = image.UpdatedAt
@@ -0,0 +1,192 @@
+package core_service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	core_repository "github.com/developer-afo/instashop-ecommerce-api/repository/core"
+)
+
+var (
+	InventoryMovementReservation = "reservation"
+	InventoryMovementRelease     = "release"
+	InventoryMovementSale        = "sale"
+)
+
+type InventoryServiceInterface interface {
+	ReserveOrderItems(reference string, items []dto.OrderItemDTO) error
+	ReleaseOrderItems(reference string, items []dto.OrderItemDTO) error
+	SellOrderItems(reference string, items []dto.OrderItemDTO) error
+	FindInventoryMovementsByProductId(productId string) ([]dto.InventoryMovementDTO, error)
+	WithTx(tx database.DatabaseInterface) InventoryServiceInterface
+}
+
+type inventoryService struct {
+	productRepository           core_repository.ProductRepositoryInterface
+	inventoryMovementRepository core_repository.InventoryMovementRepositoryInterface
+}
+
+func NewInventoryService(
+	productRepository core_repository.ProductRepositoryInterface,
+	inventoryMovementRepository core_repository.InventoryMovementRepositoryInterface,
+) InventoryServiceInterface {
+	return &inventoryService{
+		productRepository:           productRepository,
+		inventoryMovementRepository: inventoryMovementRepository,
+	}
+}
+
+// WithTx implements InventoryServiceInterface.
+func (s *inventoryService) WithTx(tx database.DatabaseInterface) InventoryServiceInterface {
+	return &inventoryService{
+		productRepository:           s.productRepository.WithTx(tx),
+		inventoryMovementRepository: s.inventoryMovementRepository.WithTx(tx),
+	}
+}
+
+func (s *inventoryService) ConvertToDTO(movement models.InventoryMovement) (movementDto dto.InventoryMovementDTO) {
+
+	movementDto.ID = movement.ID
+	movementDto.ProductUUID = movement.ProductID
+	movementDto.VariantUUID = movement.VariantID
+	movementDto.OrderUUID = movement.OrderID
+	movementDto.OrderItemUUID = movement.OrderItemID
+	movementDto.Reference = movement.Reference
+	movementDto.Type = movement.Type
+	movementDto.Quantity = movement.Quantity
+	movementDto.CreatedAt = movement.CreatedAt
+	movementDto.UpdatedAt = movement.UpdatedAt
+	movementDto.DeletedAt = movement.DeletedAt.Time
+
+	return movementDto
+}
+
+// stockId is the row the item's stock is kept on, its variant or else its product.
+func stockId(item dto.OrderItemDTO) uuid.UUID {
+	if item.VariantUUID != nil {
+		return *item.VariantUUID
+	}
+
+	return item.ProductUUID
+}
+
+// ReserveOrderItems implements InventoryServiceInterface.
+// Stock is taken off the variant of an item, or off its product when it has none.
+// Rows are updated in id order so concurrent checkouts lock rows in the same order and cannot deadlock.
+// It returns ErrInsufficientStock when a product or variant has less stock left than was ordered.
+func (s *inventoryService) ReserveOrderItems(reference string, items []dto.OrderItemDTO) error {
+	sorted := make([]dto.OrderItemDTO, len(items))
+	copy(sorted, items)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return stockId(sorted[i]).String() < stockId(sorted[j]).String()
+	})
+
+	for _, item := range sorted {
+		var affected int64
+		var err error
+
+		if item.VariantUUID != nil {
+			affected, err = s.productRepository.DecrementVariantStock(*item.VariantUUID, item.Quantity)
+		} else {
+			affected, err = s.productRepository.DecrementStock(item.ProductUUID, item.Quantity)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return fmt.Errorf("product: %s: %w", stockId(item), ErrInsufficientStock)
+		}
+
+		if _, err := s.recordMovement(reference, item, InventoryMovementReservation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReleaseOrderItems implements InventoryServiceInterface.
+// Stock is only given back for items that have not been released before, so it is safe to call repeatedly.
+func (s *inventoryService) ReleaseOrderItems(reference string, items []dto.OrderItemDTO) error {
+	for _, item := range items {
+		recorded, err := s.recordMovement(reference, item, InventoryMovementRelease)
+
+		if err != nil {
+			return err
+		}
+
+		if !recorded {
+			continue
+		}
+
+		if item.VariantUUID != nil {
+			err = s.productRepository.IncrementVariantStock(*item.VariantUUID, item.Quantity)
+		} else {
+			err = s.productRepository.IncrementStock(item.ProductUUID, item.Quantity)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SellOrderItems implements InventoryServiceInterface.
+// The stock was already taken by the reservation, so only the movement is recorded.
+func (s *inventoryService) SellOrderItems(reference string, items []dto.OrderItemDTO) error {
+	for _, item := range items {
+		if _, err := s.recordMovement(reference, item, InventoryMovementSale); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindInventoryMovementsByProductId implements InventoryServiceInterface.
+func (s *inventoryService) FindInventoryMovementsByProductId(productId string) ([]dto.InventoryMovementDTO, error) {
+	movements := []dto.InventoryMovementDTO{}
+
+	_uuid, err := uuid.Parse(productId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	movementModels, err := s.inventoryMovementRepository.FindInventoryMovementsByProductId(_uuid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, movement := range movementModels {
+		movements = append(movements, s.ConvertToDTO(movement))
+	}
+
+	return movements, nil
+}
+
+// recordMovement reports false when the item already has a movement of the same type.
+func (s *inventoryService) recordMovement(reference string, item dto.OrderItemDTO, movementType string) (bool, error) {
+	affected, err := s.inventoryMovementRepository.CreateInventoryMovement(models.InventoryMovement{
+		ProductID:   item.ProductUUID,
+		VariantID:   item.VariantUUID,
+		OrderID:     item.OrderUUID,
+		OrderItemID: item.ID,
+		Reference:   reference,
+		Type:        movementType,
+		Quantity:    item.Quantity,
+	})
+
+	return affected > 0, err
+}
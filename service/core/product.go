@@ -1,10 +1,14 @@
 package core_service
 
 import (
+	"errors"
+
 	"gorm.io/gorm"
 
 	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/helper"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 	"github.com/developer-afo/instashop-ecommerce-api/models"
 	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
 	"github.com/developer-afo/instashop-ecommerce-api/repository"
@@ -12,28 +16,58 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInsufficientStock = errors.New("product stock is not enough")
+	ErrCategoryNotFound  = errors.New("category not found")
+	ErrVariantRequired   = errors.New("choose a variant of the product")
+	ErrVariantNotFound   = errors.New("variant not found")
+)
+
 type ProductServiceInterface interface {
 	CreateProduct(dto request.CreateProductRequest) (dto.ProductDTO, error)
 	FindAllProducts(pageable coreRepository.ProductPageable) ([]dto.ProductDTO, repository.Pagination, error)
+	FindProductFacets(pageable coreRepository.ProductPageable) (coreRepository.ProductFacets, error)
 	FindProductByUUID(id string) (dto.ProductDTO, error)
 	FindProductBySlug(slug string) (dto.ProductDTO, error)
 	UpdateProduct(dto dto.ProductDTO) (dto.ProductDTO, error)
 	DeleteProduct(id uuid.UUID) error
+	WithTx(tx database.DatabaseInterface) ProductServiceInterface
 	ConvertToDTO(product models.Product) dto.ProductDTO
+	VariantToDTO(variant models.ProductVariant) dto.ProductVariantDTO
 }
 
 type productService struct {
-	productRepository coreRepository.ProductRepositoryInterface
-	imageService      ImageServiceInterface
+	database           database.DatabaseInterface
+	productRepository  coreRepository.ProductRepositoryInterface
+	categoryRepository coreRepository.CategoryRepositoryInterface
+	imageService       ImageServiceInterface
 }
 
 func NewProductService(
+	database database.DatabaseInterface,
 	productRepository coreRepository.ProductRepositoryInterface,
+	categoryRepository coreRepository.CategoryRepositoryInterface,
 	imageService ImageServiceInterface,
 ) ProductServiceInterface {
 	return &productService{
-		productRepository: productRepository,
-		imageService:      imageService,
+		database:           database,
+		productRepository:  productRepository,
+		categoryRepository: categoryRepository,
+		imageService:       imageService,
+	}
+}
+
+// WithTx implements ProductServiceInterface.
+func (service *productService) WithTx(tx database.DatabaseInterface) ProductServiceInterface {
+	return service.withTx(tx)
+}
+
+func (service *productService) withTx(tx database.DatabaseInterface) *productService {
+	return &productService{
+		database:           tx,
+		productRepository:  service.productRepository.WithTx(tx),
+		categoryRepository: service.categoryRepository.WithTx(tx),
+		imageService:       service.imageService,
 	}
 }
 
@@ -47,16 +81,133 @@ func (service *productService) ConvertToDTO(product models.Product) (productDto
 	productDto.Price = product.Price
 	productDto.SlashPrice = product.SlashPrice
 	productDto.Stock = product.Stock
+	productDto.Brand = product.Brand
+	productDto.Weight = product.Weight
 	productDto.Sales = product.Sales
+	productDto.Rating = product.Rating
+	productDto.ReviewCount = product.ReviewCount
 	productDto.CreatedAt = product.CreatedAt
 	productDto.UpdatedAt = product.UpdatedAt
 	productDto.DeletedAt = product.DeletedAt.Time
 	for _, image := range product.Images {
 		productDto.Images = append(productDto.Images, service.imageService.ConvertToDTO(image))
 	}
+	for _, category := range product.Categories {
+		productDto.CategoryUUIDs = append(productDto.CategoryUUIDs, category.ID)
+		productDto.Categories = append(productDto.Categories, CategoryToDTO(category))
+	}
+	for _, option := range product.Options {
+		productDto.Options = append(productDto.Options, OptionToDTO(option))
+	}
+	if len(product.Variants) > 0 {
+		// a product with variants has the stock of its variants
+		productDto.Stock = 0
+	}
+	for _, variant := range product.Variants {
+		variantDto := service.VariantToDTO(variant)
+
+		productDto.Stock += variantDto.Stock
+		productDto.Variants = append(productDto.Variants, variantDto)
+	}
 	return productDto
 }
 
+// OptionToDTO converts an option with its values.
+func OptionToDTO(option models.ProductOption) (optionDto dto.ProductOptionDTO) {
+
+	optionDto.ID = option.ID
+	optionDto.ProductUUID = option.ProductID
+	optionDto.Name = option.Name
+	optionDto.Position = option.Position
+	optionDto.CreatedAt = option.CreatedAt
+	optionDto.UpdatedAt = option.UpdatedAt
+	for _, value := range option.Values {
+		optionDto.Values = append(optionDto.Values, OptionValueToDTO(value))
+	}
+
+	return optionDto
+}
+
+func OptionValueToDTO(value models.ProductOptionValue) (valueDto dto.ProductOptionValueDTO) {
+
+	valueDto.ID = value.ID
+	valueDto.OptionUUID = value.OptionID
+	valueDto.Value = value.Value
+	valueDto.Position = value.Position
+	valueDto.CreatedAt = value.CreatedAt
+	valueDto.UpdatedAt = value.UpdatedAt
+
+	return valueDto
+}
+
+func (service *productService) VariantToDTO(variant models.ProductVariant) (variantDto dto.ProductVariantDTO) {
+
+	variantDto.ID = variant.ID
+	variantDto.ProductUUID = variant.ProductID
+	variantDto.SKU = variant.SKU
+	variantDto.Price = variant.Price
+	variantDto.SlashPrice = variant.SlashPrice
+	variantDto.Stock = variant.Stock
+	variantDto.CreatedAt = variant.CreatedAt
+	variantDto.UpdatedAt = variant.UpdatedAt
+	variantDto.DeletedAt = variant.DeletedAt.Time
+	for _, value := range variant.Values {
+		variantDto.Values = append(variantDto.Values, OptionValueToDTO(value))
+	}
+	for _, image := range variant.Images {
+		variantDto.Images = append(variantDto.Images, service.imageService.ConvertToDTO(image))
+	}
+
+	return variantDto
+}
+
+// ProductVariant returns the variant of the product with the given id.
+// It returns ErrVariantRequired when the product has variants and none was chosen,
+// and a nil variant when the product has none.
+func ProductVariant(product dto.ProductDTO, variantId uuid.UUID) (*dto.ProductVariantDTO, error) {
+	if len(product.Variants) == 0 {
+		if variantId != uuid.Nil {
+			return nil, ErrVariantNotFound
+		}
+
+		return nil, nil
+	}
+
+	if variantId == uuid.Nil {
+		return nil, ErrVariantRequired
+	}
+
+	for i := range product.Variants {
+		if product.Variants[i].ID == variantId {
+			return &product.Variants[i], nil
+		}
+	}
+
+	return nil, ErrVariantNotFound
+}
+
+// VariantPrices returns the price and slash price a variant sells at, the product's unless it has its own price.
+func VariantPrices(product dto.ProductDTO, variant *dto.ProductVariantDTO) (price money.Money, slashPrice money.Money) {
+	if variant == nil || variant.Price == nil {
+		return product.Price, product.SlashPrice
+	}
+
+	if variant.SlashPrice != nil {
+		slashPrice = *variant.SlashPrice
+	}
+
+	return *variant.Price, slashPrice
+}
+
+// VariantStock returns the stock of the variant, or of the product when it is sold without variants.
+func VariantStock(product dto.ProductDTO, variant *dto.ProductVariantDTO) int {
+	if variant == nil {
+		return product.Stock
+	}
+
+	return variant.Stock
+}
+
 func (service *productService) ConvertToModel(productDto dto.ProductDTO) (product models.Product) {
 
 	product.ID = productDto.ID
@@ -67,6 +218,21 @@ func (service *productService) ConvertToModel(productDto dto.ProductDTO) (produc
 	product.Price = productDto.Price
 	product.SlashPrice = productDto.SlashPrice
 	product.Stock = productDto.Stock
+	product.Brand = productDto.Brand
+	product.Weight = productDto.Weight
+	seen := map[uuid.UUID]bool{}
+	for _, categoryId := range productDto.CategoryUUIDs {
+		var category models.Category
+
+		// a product is only put in a category once
+		if seen[categoryId] {
+			continue
+		}
+		seen[categoryId] = true
+
+		category.ID = categoryId
+		product.Categories = append(product.Categories, category)
+	}
 	product.CreatedAt = productDto.CreatedAt
 	product.UpdatedAt = productDto.UpdatedAt
 	product.DeletedAt.Time = productDto.DeletedAt
@@ -97,12 +263,29 @@ func (service *productService) CreateProduct(createProduct request.CreateProduct
 	productDto.Name = createProduct.Name
 	productDto.Description = createProduct.Description
 	productDto.Specification = createProduct.Specification
-	productDto.Price = float64(createProduct.Price)
-	productDto.SlashPrice = float64(createProduct.SlashPrice)
+	productDto.Price = money.FromMajor(float64(createProduct.Price), money.BaseCurrency)
+	productDto.SlashPrice = money.FromMajor(float64(createProduct.SlashPrice), money.BaseCurrency)
 	productDto.Stock = createProduct.Stock
+	productDto.Brand = createProduct.Brand
+	productDto.Weight = createProduct.Weight
+	for _, categoryId := range createProduct.CategoryIDs {
+		productDto.CategoryUUIDs = append(productDto.CategoryUUIDs, uuid.MustParse(categoryId))
+	}
+
+	if err := service.checkCategoriesExist(productDto.CategoryUUIDs); err != nil {
+		return dto.ProductDTO{}, err
+	}
+
+	var newRecord models.Product
 
-	product := service.ConvertToModel(productDto)
-	newRecord, err := service.productRepository.CreateProduct(product)
+	// the product and its categories are saved together
+	err = service.database.Transaction(func(tx database.DatabaseInterface) error {
+		var err error
+
+		newRecord, err = service.withTx(tx).productRepository.CreateProduct(service.ConvertToModel(productDto))
+
+		return err
+	})
 
 	if err != nil {
 		return dto.ProductDTO{}, err
@@ -143,6 +326,11 @@ func (service *productService) FindAllProducts(pageable coreRepository.ProductPa
 	return productDtos, pagination, nil
 }
 
+// FindProductFacets implements ProductServiceInterface.
+func (service *productService) FindProductFacets(pageable coreRepository.ProductPageable) (coreRepository.ProductFacets, error) {
+	return service.productRepository.FindProductFacets(pageable)
+}
+
 // FindProductByUUID implements ProductServiceInterface.
 func (service *productService) FindProductByUUID(id string) (dto.ProductDTO, error) {
 	uuid, err := uuid.Parse(id)
@@ -170,11 +358,22 @@ func (service *productService) FindProductBySlug(slug string) (dto.ProductDTO, e
 	return service.ConvertToDTO(product), nil
 }
 
-// UpdateProduct implements ProductServiceInterface.
+// UpdateProduct implements ProductServiceInterface. The product's categories are replaced with CategoryUUIDs.
 func (service *productService) UpdateProduct(productDtoArg dto.ProductDTO) (dto.ProductDTO, error) {
+	var product models.Product
+
+	if err := service.checkCategoriesExist(productDtoArg.CategoryUUIDs); err != nil {
+		return dto.ProductDTO{}, err
+	}
+
+	err := service.database.Transaction(func(tx database.DatabaseInterface) error {
+		var err error
+
+		product, err = service.withTx(tx).productRepository.UpdateProduct(service.ConvertToModel(productDtoArg))
+
+		return err
+	})
 
-	product := service.ConvertToModel(productDtoArg)
-	product, err := service.productRepository.UpdateProduct(product)
 	if err != nil {
 		return dto.ProductDTO{}, err
 	}
@@ -182,6 +381,32 @@ func (service *productService) UpdateProduct(productDtoArg dto.ProductDTO) (dto.
 	return service.ConvertToDTO(product), nil
 }
 
+// checkCategoriesExist returns ErrCategoryNotFound when one of the categories does not exist.
+func (service *productService) checkCategoriesExist(categoryIds []uuid.UUID) error {
+	if len(categoryIds) == 0 {
+		return nil
+	}
+
+	categories, err := service.categoryRepository.FindCategoriesByIds(categoryIds)
+
+	if err != nil {
+		return err
+	}
+
+	found := map[uuid.UUID]bool{}
+	for _, category := range categories {
+		found[category.ID] = true
+	}
+
+	for _, categoryId := range categoryIds {
+		if !found[categoryId] {
+			return ErrCategoryNotFound
+		}
+	}
+
+	return nil
+}
+
 func (s *productService) DeleteProduct(id uuid.UUID) error {
 	return s.productRepository.DeleteProduct(id)
 }
@@ -0,0 +1,179 @@
+package core_service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+	coreRepository "github.com/developer-afo/instashop-ecommerce-api/repository/core"
+)
+
+var (
+	ErrReviewNotVerifiedBuyer = errors.New("only customers who had the product delivered can review it")
+	ErrReviewExists           = errors.New("you have reviewed this product already")
+	ErrReviewNotFound         = errors.New("review not found")
+)
+
+// ProductReviewServiceInterface manages the reviews of a product, found by its slug.
+type ProductReviewServiceInterface interface {
+	FindReviews(slug string, pageable coreRepository.ProductReviewPageable) ([]dto.ProductReviewDTO, repository.Pagination, error)
+	CreateReview(slug string, review dto.ProductReviewDTO) (dto.ProductReviewDTO, error)
+	UpdateReviewVisibility(slug string, reviewId uuid.UUID, isHidden bool) (dto.ProductReviewDTO, error)
+}
+
+type productReviewService struct {
+	database                database.DatabaseInterface
+	productReviewRepository coreRepository.ProductReviewRepositoryInterface
+	productRepository       coreRepository.ProductRepositoryInterface
+}
+
+func NewProductReviewService(
+	database database.DatabaseInterface,
+	productReviewRepository coreRepository.ProductReviewRepositoryInterface,
+	productRepository coreRepository.ProductRepositoryInterface,
+) ProductReviewServiceInterface {
+	return &productReviewService{
+		database:                database,
+		productReviewRepository: productReviewRepository,
+		productRepository:       productRepository,
+	}
+}
+
+func (service *productReviewService) withTx(tx database.DatabaseInterface) *productReviewService {
+	return &productReviewService{
+		database:                tx,
+		productReviewRepository: service.productReviewRepository.WithTx(tx),
+		productRepository:       service.productRepository.WithTx(tx),
+	}
+}
+
+// ConvertToDTO only keeps the name of the reviewer.
+func (service *productReviewService) ConvertToDTO(review models.ProductReview) (reviewDto dto.ProductReviewDTO) {
+
+	reviewDto.ID = review.ID
+	reviewDto.ProductUUID = review.ProductID
+	reviewDto.UserUUID = review.UserID
+	reviewDto.UserFirstName = review.User.FirstName
+	reviewDto.UserLastName = review.User.LastName
+	reviewDto.Rating = review.Rating
+	reviewDto.Comment = review.Comment
+	reviewDto.IsHidden = review.IsHidden
+	reviewDto.CreatedAt = review.CreatedAt
+	reviewDto.UpdatedAt = review.UpdatedAt
+
+	return reviewDto
+}
+
+// FindReviews implements ProductReviewServiceInterface. The product of pageable is the one of the slug.
+func (service *productReviewService) FindReviews(slug string, pageable coreRepository.ProductReviewPageable) ([]dto.ProductReviewDTO, repository.Pagination, error) {
+	product, err := service.productRepository.FindProductBySlug(slug)
+
+	if err != nil {
+		return nil, repository.Pagination{}, err
+	}
+
+	pageable.ProductID = product.ID
+
+	reviews, pagination, err := service.productReviewRepository.FindReviews(pageable)
+
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	reviewDtos := []dto.ProductReviewDTO{}
+	for _, review := range reviews {
+		reviewDtos = append(reviewDtos, service.ConvertToDTO(review))
+	}
+
+	return reviewDtos, pagination, nil
+}
+
+// CreateReview implements ProductReviewServiceInterface.
+// The reviewer needs a delivered order with the product in it, and can review a product once.
+func (service *productReviewService) CreateReview(slug string, reviewDto dto.ProductReviewDTO) (dto.ProductReviewDTO, error) {
+	var review models.ProductReview
+
+	err := service.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := service.withTx(tx)
+
+		product, err := txService.productRepository.FindProductBySlug(slug)
+
+		if err != nil {
+			return err
+		}
+
+		delivered, err := txService.productReviewRepository.HasDeliveredProduct(reviewDto.UserUUID, product.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if !delivered {
+			return ErrReviewNotVerifiedBuyer
+		}
+
+		_, err = txService.productReviewRepository.FindReviewByProductAndUser(product.ID, reviewDto.UserUUID)
+
+		if err == nil {
+			return ErrReviewExists
+		}
+
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		review, err = txService.productReviewRepository.CreateReview(models.ProductReview{
+			ProductID: product.ID,
+			UserID:    reviewDto.UserUUID,
+			Rating:    reviewDto.Rating,
+			Comment:   reviewDto.Comment,
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return dto.ProductReviewDTO{}, err
+	}
+
+	return service.findReview(review.ProductID, review.ID)
+}
+
+// UpdateReviewVisibility implements ProductReviewServiceInterface. A hidden review no longer counts in the product's rating.
+func (service *productReviewService) UpdateReviewVisibility(slug string, reviewId uuid.UUID, isHidden bool) (dto.ProductReviewDTO, error) {
+	product, err := service.productRepository.FindProductBySlug(slug)
+
+	if err != nil {
+		return dto.ProductReviewDTO{}, err
+	}
+
+	if _, err := service.findReview(product.ID, reviewId); err != nil {
+		return dto.ProductReviewDTO{}, err
+	}
+
+	if err := service.productReviewRepository.UpdateReviewVisibility(reviewId, isHidden); err != nil {
+		return dto.ProductReviewDTO{}, err
+	}
+
+	return service.findReview(product.ID, reviewId)
+}
+
+// findReview returns ErrReviewNotFound when the review does not exist or belongs to another product.
+func (service *productReviewService) findReview(productId uuid.UUID, reviewId uuid.UUID) (dto.ProductReviewDTO, error) {
+	review, err := service.productReviewRepository.FindReviewById(reviewId)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && review.ProductID != productId) {
+		return dto.ProductReviewDTO{}, ErrReviewNotFound
+	}
+
+	if err != nil {
+		return dto.ProductReviewDTO{}, err
+	}
+
+	return service.ConvertToDTO(review), nil
+}
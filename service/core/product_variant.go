@@ -0,0 +1,301 @@
+package core_service
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	coreRepository "github.com/developer-afo/instashop-ecommerce-api/repository/core"
+)
+
+var (
+	ErrOptionNotFound       = errors.New("option not found")
+	ErrOptionInUse          = errors.New("option is used by variants of the product, delete them first")
+	ErrProductHasVariants   = errors.New("options cannot be added to a product that has variants, delete them first")
+	ErrVariantValuesInvalid = errors.New("a variant takes one value of every option of the product")
+	ErrVariantExists        = errors.New("the product has a variant with these option values already")
+	ErrSKUTaken             = errors.New("sku is used by another variant")
+)
+
+// ProductVariantServiceInterface manages the options and variants of a product, products are found with ProductServiceInterface.
+type ProductVariantServiceInterface interface {
+	CreateOption(option dto.ProductOptionDTO) (dto.ProductOptionDTO, error)
+	DeleteOption(productId uuid.UUID, optionId uuid.UUID) error
+	CreateVariant(variant dto.ProductVariantDTO) (dto.ProductVariantDTO, error)
+	UpdateVariant(variant dto.ProductVariantDTO) (dto.ProductVariantDTO, error)
+	DeleteVariant(productId uuid.UUID, variantId uuid.UUID) error
+}
+
+type productVariantService struct {
+	database                 database.DatabaseInterface
+	productVariantRepository coreRepository.ProductVariantRepositoryInterface
+	productRepository        coreRepository.ProductRepositoryInterface
+	productService           ProductServiceInterface
+}
+
+func NewProductVariantService(
+	database database.DatabaseInterface,
+	productVariantRepository coreRepository.ProductVariantRepositoryInterface,
+	productRepository coreRepository.ProductRepositoryInterface,
+	productService ProductServiceInterface,
+) ProductVariantServiceInterface {
+	return &productVariantService{
+		database:                 database,
+		productVariantRepository: productVariantRepository,
+		productRepository:        productRepository,
+		productService:           productService,
+	}
+}
+
+func (service *productVariantService) withTx(tx database.DatabaseInterface) *productVariantService {
+	return &productVariantService{
+		database:                 tx,
+		productVariantRepository: service.productVariantRepository.WithTx(tx),
+		productRepository:        service.productRepository.WithTx(tx),
+		productService:           service.productService.WithTx(tx),
+	}
+}
+
+// ConvertVariantToModel keeps only the ids of the option values, they are linked and never written.
+func (service *productVariantService) ConvertVariantToModel(variantDto dto.ProductVariantDTO) (variant models.ProductVariant) {
+
+	variant.ID = variantDto.ID
+	variant.ProductID = variantDto.ProductUUID
+	variant.SKU = variantDto.SKU
+	variant.Price = variantDto.Price
+	variant.SlashPrice = variantDto.SlashPrice
+	variant.Stock = variantDto.Stock
+	for _, value := range variantDto.Values {
+		variant.Values = append(variant.Values, models.ProductOptionValue{BaseModel: database.BaseModel{ID: value.ID}})
+	}
+	for _, image := range variantDto.Images {
+		variant.Images = append(variant.Images, models.Image{Key: image.Key})
+	}
+
+	return variant
+}
+
+// CreateOption implements ProductVariantServiceInterface.
+// Values are kept in the order given, the position of an option comes with the request.
+func (service *productVariantService) CreateOption(optionDto dto.ProductOptionDTO) (dto.ProductOptionDTO, error) {
+	var option models.ProductOption
+
+	err := service.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := service.withTx(tx)
+
+		if _, err := txService.productRepository.FindProductByUUID(optionDto.ProductUUID); err != nil {
+			return err
+		}
+
+		// the variants made so far would miss a value of the new option
+		variants, err := txService.productVariantRepository.FindVariantsByProductId(optionDto.ProductUUID)
+
+		if err != nil {
+			return err
+		}
+
+		if len(variants) > 0 {
+			return ErrProductHasVariants
+		}
+
+		option = models.ProductOption{ProductID: optionDto.ProductUUID, Name: optionDto.Name, Position: optionDto.Position}
+		for i, value := range optionDto.Values {
+			option.Values = append(option.Values, models.ProductOptionValue{Value: value.Value, Position: i})
+		}
+
+		option, err = txService.productVariantRepository.CreateOption(option)
+
+		return err
+	})
+
+	if err != nil {
+		return dto.ProductOptionDTO{}, err
+	}
+
+	return OptionToDTO(option), nil
+}
+
+// DeleteOption implements ProductVariantServiceInterface. An option cannot be deleted while variants use its values.
+func (service *productVariantService) DeleteOption(productId uuid.UUID, optionId uuid.UUID) error {
+	option, err := service.productVariantRepository.FindOptionById(optionId)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && option.ProductID != productId) {
+		return ErrOptionNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	count, err := service.productVariantRepository.CountVariantsByOptionId(optionId)
+
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrOptionInUse
+	}
+
+	return service.productVariantRepository.DeleteOption(optionId)
+}
+
+// checkVariant returns an error when the variant does not take exactly one value of every option of its product,
+// when another variant of the product has the same values, or when another variant has its SKU.
+func (service *productVariantService) checkVariant(variant models.ProductVariant) error {
+	options, err := service.productVariantRepository.FindOptionsByProductId(variant.ProductID)
+
+	if err != nil {
+		return err
+	}
+
+	valueOption := map[uuid.UUID]uuid.UUID{}
+	for _, option := range options {
+		for _, value := range option.Values {
+			valueOption[value.ID] = option.ID
+		}
+	}
+
+	if len(options) == 0 || len(variant.Values) != len(options) {
+		return ErrVariantValuesInvalid
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, value := range variant.Values {
+		optionId, ok := valueOption[value.ID]
+
+		if !ok || seen[optionId] {
+			return ErrVariantValuesInvalid
+		}
+
+		seen[optionId] = true
+	}
+
+	variants, err := service.productVariantRepository.FindVariantsByProductId(variant.ProductID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, other := range variants {
+		if other.ID != variant.ID && variantValuesKey(other) == variantValuesKey(variant) {
+			return ErrVariantExists
+		}
+	}
+
+	other, err := service.productVariantRepository.FindVariantBySKU(variant.SKU)
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err == nil && other.ID != variant.ID {
+		return ErrSKUTaken
+	}
+
+	return nil
+}
+
+// variantValuesKey returns the option values of a variant in an order that does not depend on how they were given.
+func variantValuesKey(variant models.ProductVariant) string {
+	ids := []string{}
+	for _, value := range variant.Values {
+		ids = append(ids, value.ID.String())
+	}
+
+	sort.Strings(ids)
+
+	key := ""
+	for _, id := range ids {
+		key += id + ","
+	}
+
+	return key
+}
+
+// CreateVariant implements ProductVariantServiceInterface.
+func (service *productVariantService) CreateVariant(variantDto dto.ProductVariantDTO) (dto.ProductVariantDTO, error) {
+	var variant models.ProductVariant
+
+	err := service.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := service.withTx(tx)
+
+		if _, err := txService.productRepository.FindProductByUUID(variantDto.ProductUUID); err != nil {
+			return err
+		}
+
+		variant = txService.ConvertVariantToModel(variantDto)
+
+		if err := txService.checkVariant(variant); err != nil {
+			return err
+		}
+
+		var err error
+
+		variant, err = txService.productVariantRepository.CreateVariant(variant)
+
+		return err
+	})
+
+	if err != nil {
+		return dto.ProductVariantDTO{}, err
+	}
+
+	return service.findVariant(variant.ProductID, variant.ID)
+}
+
+// UpdateVariant implements ProductVariantServiceInterface. The option values and images of the variant are replaced.
+func (service *productVariantService) UpdateVariant(variantDto dto.ProductVariantDTO) (dto.ProductVariantDTO, error) {
+	err := service.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := service.withTx(tx)
+
+		if _, err := txService.findVariant(variantDto.ProductUUID, variantDto.ID); err != nil {
+			return err
+		}
+
+		variant := txService.ConvertVariantToModel(variantDto)
+
+		if err := txService.checkVariant(variant); err != nil {
+			return err
+		}
+
+		_, err := txService.productVariantRepository.UpdateVariant(variant)
+
+		return err
+	})
+
+	if err != nil {
+		return dto.ProductVariantDTO{}, err
+	}
+
+	return service.findVariant(variantDto.ProductUUID, variantDto.ID)
+}
+
+// DeleteVariant implements ProductVariantServiceInterface. Orders and carts keep the variant they were made with.
+func (service *productVariantService) DeleteVariant(productId uuid.UUID, variantId uuid.UUID) error {
+	if _, err := service.findVariant(productId, variantId); err != nil {
+		return err
+	}
+
+	return service.productVariantRepository.DeleteVariant(variantId)
+}
+
+// findVariant returns ErrVariantNotFound when the variant does not exist or belongs to another product.
+func (service *productVariantService) findVariant(productId uuid.UUID, variantId uuid.UUID) (dto.ProductVariantDTO, error) {
+	variant, err := service.productVariantRepository.FindVariantById(variantId)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && variant.ProductID != productId) {
+		return dto.ProductVariantDTO{}, ErrVariantNotFound
+	}
+
+	if err != nil {
+		return dto.ProductVariantDTO{}, err
+	}
+
+	return service.productService.VariantToDTO(variant), nil
+}
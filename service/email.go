@@ -1,10 +1,33 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/config"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+	notification_repository "github.com/developer-afo/instashop-ecommerce-api/repository/notification"
+)
+
+var (
+	EmailStatusPending = "pending"
+	EmailStatusSent    = "sent"
+	EmailStatusDead    = "dead"
+
+	// MaxEmailAttempts is how many times an email is tried before it is marked dead.
+	MaxEmailAttempts       = 8
+	EmailRetryBaseDelay    = 30 * time.Second
+	EmailRetryMaxDelay     = 6 * time.Hour
+	DeliverEmailsBatchSize = 50
+
+	ErrEmailNotDead = errors.New("only dead emails can be retried")
 )
 
 type SendEmailParams struct {
@@ -13,26 +36,175 @@ type SendEmailParams struct {
 }
 
 type emailService struct {
-	mail config.EmailInterface
+	mail                  config.EmailInterface
+	outboxEmailRepository notification_repository.OutboxEmailRepositoryInterface
 }
 
 type EmailServiceInterface interface {
 	SendEmail(params SendEmailParams) error
+	DeliverPendingEmails(limit int) (int, error)
+	FindAllOutboxEmails(pageable notification_repository.OutboxEmailPageable) ([]dto.OutboxEmailDTO, repository.Pagination, error)
+	RetryOutboxEmail(emailId uuid.UUID) (dto.OutboxEmailDTO, error)
+	WithTx(tx database.DatabaseInterface) EmailServiceInterface
+}
+
+func NewEmailService(mail config.EmailInterface, outboxEmailRepository notification_repository.OutboxEmailRepositoryInterface) EmailServiceInterface {
+	return &emailService{mail: mail, outboxEmailRepository: outboxEmailRepository}
 }
 
-func NewEmailService(mail config.EmailInterface) EmailServiceInterface {
-	return &emailService{mail: mail}
+// WithTx implements EmailServiceInterface. Emails queued through tx are only delivered if it commits.
+func (e *emailService) WithTx(tx database.DatabaseInterface) EmailServiceInterface {
+	return &emailService{mail: e.mail, outboxEmailRepository: e.outboxEmailRepository.WithTx(tx)}
 }
 
+func (e *emailService) ConvertToDTO(email models.OutboxEmail) dto.OutboxEmailDTO {
+	var emailDto dto.OutboxEmailDTO
+
+	emailDto.ID = email.ID
+	emailDto.Recipient = email.Recipient
+	emailDto.Subject = email.Subject
+	emailDto.Template = email.Template
+	emailDto.Variables = email.Variables
+	emailDto.Status = email.Status
+	emailDto.Attempts = email.Attempts
+	emailDto.NextAttemptAt = email.NextAttemptAt
+	emailDto.LastError = email.LastError
+	emailDto.SentAt = email.SentAt
+	emailDto.CreatedAt = email.CreatedAt
+	emailDto.UpdatedAt = email.UpdatedAt
+
+	return emailDto
+}
+
+// SendEmail queues the email in the outbox. It is sent by DeliverPendingEmails.
 func (e *emailService) SendEmail(params SendEmailParams) error {
-	go func(p SendEmailParams) {
-		err := e.mail.SendWithTemplate(p.To, p.Subject, fmt.Sprintf("templates/%s.html", p.Template), p.Variables)
-		if err != nil {
-			e.SetLogger(p.Subject, p.To, err.Error())
+	variables, err := json.Marshal(params.Variables)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = e.outboxEmailRepository.CreateOutboxEmail(models.OutboxEmail{
+		Recipient:     params.To,
+		Subject:       params.Subject,
+		Template:      params.Template,
+		Variables:     string(variables),
+		Status:        EmailStatusPending,
+		NextAttemptAt: time.Now(),
+	})
+
+	return err
+}
+
+// DeliverPendingEmails sends up to limit emails that are due and returns how many were sent.
+// A failed email is tried again later with an exponential backoff until MaxEmailAttempts is reached.
+func (e *emailService) DeliverPendingEmails(limit int) (int, error) {
+	emails, err := e.outboxEmailRepository.FindDueOutboxEmails(EmailStatusPending, time.Now(), limit)
+
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+
+	for _, email := range emails {
+		fromAttempts := email.Attempts
+
+		email.Attempts++
+
+		if sendErr := e.deliver(email); sendErr != nil {
+			e.SetLogger(email.Subject, email.Recipient, sendErr.Error())
+
+			email.LastError = sendErr.Error()
+			email.NextAttemptAt = time.Now().Add(EmailRetryDelay(email.Attempts))
+
+			if email.Attempts >= MaxEmailAttempts {
+				email.Status = EmailStatusDead
+			}
+		} else {
+			now := time.Now()
+
+			email.Status = EmailStatusSent
+			email.LastError = ""
+			email.SentAt = &now
+			sent++
+		}
+
+		if _, err := e.outboxEmailRepository.UpdateOutboxEmailAttempt(email, EmailStatusPending, fromAttempts); err != nil {
+			return sent, err
 		}
-	}(params)
+	}
+
+	return sent, nil
+}
+
+func (e *emailService) deliver(email models.OutboxEmail) error {
+	var variables map[string]interface{}
+
+	if err := json.Unmarshal([]byte(email.Variables), &variables); err != nil {
+		return err
+	}
+
+	return e.mail.SendWithTemplate(email.Recipient, email.Subject, fmt.Sprintf("templates/%s.html", email.Template), variables)
+}
+
+// EmailRetryDelay is how long to wait before trying an email again after its nth failed attempt.
+func EmailRetryDelay(attempts int) time.Duration {
+	delay := EmailRetryBaseDelay
+
+	for i := 1; i < attempts && delay < EmailRetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > EmailRetryMaxDelay {
+		return EmailRetryMaxDelay
+	}
+
+	return delay
+}
+
+// FindAllOutboxEmails implements EmailServiceInterface.
+func (e *emailService) FindAllOutboxEmails(pageable notification_repository.OutboxEmailPageable) ([]dto.OutboxEmailDTO, repository.Pagination, error) {
+	var emailDtos []dto.OutboxEmailDTO
+
+	emails, pagination, err := e.outboxEmailRepository.FindAllOutboxEmails(pageable)
+
+	for _, email := range emails {
+		emailDtos = append(emailDtos, e.ConvertToDTO(email))
+	}
+
+	return emailDtos, pagination, err
+}
+
+// RetryOutboxEmail puts a dead email back in the queue with a fresh set of attempts.
+func (e *emailService) RetryOutboxEmail(emailId uuid.UUID) (dto.OutboxEmailDTO, error) {
+	email, err := e.outboxEmailRepository.FindOutboxEmailById(emailId)
+
+	if err != nil {
+		return dto.OutboxEmailDTO{}, err
+	}
+
+	if email.Status != EmailStatusDead {
+		return dto.OutboxEmailDTO{}, ErrEmailNotDead
+	}
+
+	fromAttempts := email.Attempts
+
+	email.Status = EmailStatusPending
+	email.Attempts = 0
+	email.NextAttemptAt = time.Now()
+
+	affected, err := e.outboxEmailRepository.UpdateOutboxEmailAttempt(email, EmailStatusDead, fromAttempts)
+
+	if err != nil {
+		return dto.OutboxEmailDTO{}, err
+	}
+
+	if affected == 0 {
+		return dto.OutboxEmailDTO{}, ErrEmailNotDead
+	}
 
-	return nil
+	return e.ConvertToDTO(email), nil
 }
 
 func (e *emailService) SetLogger(sub string, to string, message string) {
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+	notification_repository "github.com/developer-afo/instashop-ecommerce-api/repository/notification"
+)
+
+// fakeMail implements config.EmailInterface, every send fails while err is set.
+type fakeMail struct {
+	err   error
+	sends int
+}
+
+func (m *fakeMail) SendWithTemplate(to, subject, templateFile string, data interface{}) error {
+	m.sends++
+
+	return m.err
+}
+
+// fakeOutbox keeps queued emails in memory.
+type fakeOutbox struct {
+	emails []models.OutboxEmail
+}
+
+func (r *fakeOutbox) CreateOutboxEmail(email models.OutboxEmail) (models.OutboxEmail, error) {
+	email.Prepare()
+	r.emails = append(r.emails, email)
+
+	return email, nil
+}
+
+func (r *fakeOutbox) FindOutboxEmailById(id uuid.UUID) (models.OutboxEmail, error) {
+	for _, email := range r.emails {
+		if email.ID == id {
+			return email, nil
+		}
+	}
+
+	return models.OutboxEmail{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeOutbox) FindAllOutboxEmails(pageable notification_repository.OutboxEmailPageable) ([]models.OutboxEmail, repository.Pagination, error) {
+	return r.emails, repository.Pagination{}, nil
+}
+
+func (r *fakeOutbox) FindDueOutboxEmails(status string, dueBefore time.Time, limit int) (emails []models.OutboxEmail, err error) {
+	for _, email := range r.emails {
+		if email.Status == status && !email.NextAttemptAt.After(dueBefore) && len(emails) < limit {
+			emails = append(emails, email)
+		}
+	}
+
+	return emails, nil
+}
+
+func (r *fakeOutbox) UpdateOutboxEmailAttempt(email models.OutboxEmail, fromStatus string, fromAttempts int) (int64, error) {
+	for i := range r.emails {
+		if r.emails[i].ID == email.ID && r.emails[i].Status == fromStatus && r.emails[i].Attempts == fromAttempts {
+			r.emails[i] = email
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (r *fakeOutbox) WithTx(tx database.DatabaseInterface) notification_repository.OutboxEmailRepositoryInterface {
+	return r
+}
+
+// makeDue moves the email's next attempt to now, as if its backoff had passed.
+func (r *fakeOutbox) makeDue() {
+	r.emails[0].NextAttemptAt = time.Now()
+}
+
+func newTestEmailService(t *testing.T, mail *fakeMail) (EmailServiceInterface, *fakeOutbox) {
+	t.Helper()
+
+	outbox := &fakeOutbox{}
+	emailService := NewEmailService(mail, outbox)
+
+	err := emailService.SendEmail(SendEmailParams{
+		To:        "ada@example.com",
+		Subject:   "Your order has been confirmed",
+		Template:  "order-confirmation",
+		Variables: map[string]interface{}{"Reference": "20240301ABC"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return emailService, outbox
+}
+
+func TestEmailRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		delay    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{8, 64 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, EmailRetryMaxDelay},
+		{100, EmailRetryMaxDelay},
+	}
+
+	for _, tt := range tests {
+		if delay := EmailRetryDelay(tt.attempts); delay != tt.delay {
+			t.Errorf("EmailRetryDelay(%d) = %s, want %s", tt.attempts, delay, tt.delay)
+		}
+	}
+}
+
+func TestDeliverPendingEmailsSends(t *testing.T) {
+	mail := &fakeMail{}
+	emailService, outbox := newTestEmailService(t, mail)
+
+	sent, err := emailService.DeliverPendingEmails(DeliverEmailsBatchSize)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	email := outbox.emails[0]
+
+	if sent != 1 || email.Status != EmailStatusSent || email.Attempts != 1 || email.SentAt == nil {
+		t.Errorf("sent %d, email is %s after %d attempts", sent, email.Status, email.Attempts)
+	}
+
+	// a sent email is not sent again
+	if sent, _ := emailService.DeliverPendingEmails(DeliverEmailsBatchSize); sent != 0 || mail.sends != 1 {
+		t.Errorf("sent %d more, %d sends", sent, mail.sends)
+	}
+}
+
+func TestDeliverPendingEmailsBacksOff(t *testing.T) {
+	mail := &fakeMail{err: errors.New("connection refused")}
+	emailService, outbox := newTestEmailService(t, mail)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		before := time.Now()
+
+		sent, err := emailService.DeliverPendingEmails(DeliverEmailsBatchSize)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		email := outbox.emails[0]
+		wait := email.NextAttemptAt.Sub(before)
+
+		if sent != 0 || email.Status != EmailStatusPending || email.Attempts != attempt || email.LastError != "connection refused" {
+			t.Fatalf("attempt %d: sent %d, email is %s after %d attempts: %q", attempt, sent, email.Status, email.Attempts, email.LastError)
+		}
+
+		if delay := EmailRetryDelay(attempt); wait < delay || wait > delay+time.Second {
+			t.Errorf("attempt %d: next attempt in %s, want %s", attempt, wait, delay)
+		}
+
+		// not tried again before its backoff has passed
+		emailService.DeliverPendingEmails(DeliverEmailsBatchSize)
+
+		if mail.sends != attempt {
+			t.Fatalf("attempt %d: %d sends", attempt, mail.sends)
+		}
+
+		outbox.makeDue()
+	}
+
+	// it is sent once the server is back
+	mail.err = nil
+
+	if sent, _ := emailService.DeliverPendingEmails(DeliverEmailsBatchSize); sent != 1 || outbox.emails[0].LastError != "" {
+		t.Errorf("sent %d, last error %q", sent, outbox.emails[0].LastError)
+	}
+}
+
+func TestDeliverPendingEmailsMarksDead(t *testing.T) {
+	mail := &fakeMail{err: errors.New("mailbox unavailable")}
+	emailService, outbox := newTestEmailService(t, mail)
+
+	for attempt := 1; attempt <= MaxEmailAttempts; attempt++ {
+		emailService.DeliverPendingEmails(DeliverEmailsBatchSize)
+		outbox.makeDue()
+	}
+
+	email := outbox.emails[0]
+
+	if email.Status != EmailStatusDead || email.Attempts != MaxEmailAttempts {
+		t.Fatalf("email is %s after %d attempts, want dead after %d", email.Status, email.Attempts, MaxEmailAttempts)
+	}
+
+	emailService.DeliverPendingEmails(DeliverEmailsBatchSize)
+
+	if mail.sends != MaxEmailAttempts {
+		t.Errorf("%d sends, a dead email is not tried again", mail.sends)
+	}
+}
+
+func TestRetryOutboxEmail(t *testing.T) {
+	mail := &fakeMail{err: errors.New("mailbox unavailable")}
+	emailService, outbox := newTestEmailService(t, mail)
+	id := outbox.emails[0].ID
+
+	if _, err := emailService.RetryOutboxEmail(id); !errors.Is(err, ErrEmailNotDead) {
+		t.Errorf("retrying a pending email: got %v", err)
+	}
+
+	for attempt := 1; attempt <= MaxEmailAttempts; attempt++ {
+		emailService.DeliverPendingEmails(DeliverEmailsBatchSize)
+		outbox.makeDue()
+	}
+
+	email, err := emailService.RetryOutboxEmail(id)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if email.Status != EmailStatusPending || email.Attempts != 0 || email.NextAttemptAt.After(time.Now()) {
+		t.Errorf("retried email is %s after %d attempts, next at %s", email.Status, email.Attempts, email.NextAttemptAt)
+	}
+
+	mail.err = nil
+
+	if sent, _ := emailService.DeliverPendingEmails(DeliverEmailsBatchSize); sent != 1 || outbox.emails[0].Status != EmailStatusSent {
+		t.Errorf("sent %d, email is %s", sent, outbox.emails[0].Status)
+	}
+
+	if _, err := emailService.RetryOutboxEmail(uuid.New()); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("retrying an unknown email: got %v", err)
+	}
+}
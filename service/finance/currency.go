@@ -0,0 +1,172 @@
+package finance_service
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	finance_repository "github.com/developer-afo/instashop-ecommerce-api/repository/finance"
+)
+
+var (
+	ErrCurrencyNotSupported = errors.New("currency is not supported")
+	ErrBaseCurrencyRate     = errors.New("the rate of the base currency cannot be changed")
+)
+
+// CurrencyServiceInterface keeps the exchange rates of the currencies prices can be shown and paid in.
+// The base currency is always supported, at a rate of 1.
+type CurrencyServiceInterface interface {
+	FindCurrencyRates() ([]dto.CurrencyRateDTO, error)
+	ExchangeRate(currency string) (float64, error)
+	SetCurrencyRate(currency string, rate float64) (dto.CurrencyRateDTO, error)
+	DeleteCurrencyRate(currency string) error
+	WithTx(tx database.DatabaseInterface) CurrencyServiceInterface
+}
+
+type currencyService struct {
+	database               database.DatabaseInterface
+	currencyRateRepository finance_repository.CurrencyRateRepositoryInterface
+}
+
+func NewCurrencyService(database database.DatabaseInterface, currencyRateRepository finance_repository.CurrencyRateRepositoryInterface) CurrencyServiceInterface {
+	return &currencyService{database: database, currencyRateRepository: currencyRateRepository}
+}
+
+// WithTx implements CurrencyServiceInterface.
+func (s *currencyService) WithTx(tx database.DatabaseInterface) CurrencyServiceInterface {
+	return s.withTx(tx)
+}
+
+func (s *currencyService) withTx(tx database.DatabaseInterface) *currencyService {
+	return &currencyService{database: tx, currencyRateRepository: s.currencyRateRepository.WithTx(tx)}
+}
+
+func (s *currencyService) ConvertToDTO(rate models.CurrencyRate) (rateDto dto.CurrencyRateDTO) {
+
+	rateDto.ID = rate.ID
+	rateDto.Currency = rate.Currency
+	rateDto.Rate = rate.Rate
+	rateDto.CreatedAt = rate.CreatedAt
+	rateDto.UpdatedAt = rate.UpdatedAt
+
+	return rateDto
+}
+
+// CurrencyCode is currency the way rates are kept, the base currency when it is empty.
+func CurrencyCode(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+
+	if currency == "" {
+		return money.BaseCurrency
+	}
+
+	return currency
+}
+
+// FindCurrencyRates implements CurrencyServiceInterface. The base currency comes first.
+func (s *currencyService) FindCurrencyRates() ([]dto.CurrencyRateDTO, error) {
+	rates := []dto.CurrencyRateDTO{{Currency: money.BaseCurrency, Rate: 1}}
+
+	rateModels, err := s.currencyRateRepository.FindCurrencyRates()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rate := range rateModels {
+		rates = append(rates, s.ConvertToDTO(rate))
+	}
+
+	return rates, nil
+}
+
+// ExchangeRate implements CurrencyServiceInterface. It is the price of one unit of currency in the base currency,
+// to be given to money.Money.Convert, and returns ErrCurrencyNotSupported for a currency without a rate.
+func (s *currencyService) ExchangeRate(currency string) (float64, error) {
+	currency = CurrencyCode(currency)
+
+	if currency == money.BaseCurrency {
+		return 1, nil
+	}
+
+	rate, err := s.currencyRateRepository.FindCurrencyRate(currency)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrCurrencyNotSupported
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return rate.Rate, nil
+}
+
+// SetCurrencyRate implements CurrencyServiceInterface. A currency without a rate is added.
+// Orders keep the rate they were placed at, so a new rate only applies to later orders.
+func (s *currencyService) SetCurrencyRate(currency string, rate float64) (dto.CurrencyRateDTO, error) {
+	var rateModel models.CurrencyRate
+
+	currency = CurrencyCode(currency)
+
+	if currency == money.BaseCurrency {
+		return dto.CurrencyRateDTO{}, ErrBaseCurrencyRate
+	}
+
+	err := s.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := s.withTx(tx)
+
+		var err error
+
+		rateModel, err = txService.currencyRateRepository.FindCurrencyRate(currency)
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			rateModel, err = txService.currencyRateRepository.CreateCurrencyRate(models.CurrencyRate{Currency: currency, Rate: rate})
+
+			return err
+		}
+
+		if err != nil {
+			return err
+		}
+
+		rateModel.Rate = rate
+		rateModel, err = txService.currencyRateRepository.UpdateCurrencyRate(rateModel)
+
+		return err
+	})
+
+	if err != nil {
+		return dto.CurrencyRateDTO{}, err
+	}
+
+	rateModel, err = s.currencyRateRepository.FindCurrencyRate(currency)
+
+	return s.ConvertToDTO(rateModel), err
+}
+
+// DeleteCurrencyRate implements CurrencyServiceInterface. Prices can no longer be shown or paid in the currency.
+func (s *currencyService) DeleteCurrencyRate(currency string) error {
+	currency = CurrencyCode(currency)
+
+	if currency == money.BaseCurrency {
+		return ErrBaseCurrencyRate
+	}
+
+	affected, err := s.currencyRateRepository.DeleteCurrencyRate(currency)
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrCurrencyNotSupported
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package finance_service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+	finance_repository "github.com/developer-afo/instashop-ecommerce-api/repository/finance"
+)
+
+var (
+	LedgerAccountTypeAsset     = "asset"
+	LedgerAccountTypeLiability = "liability"
+	LedgerAccountTypeRevenue   = "revenue"
+	LedgerAccountTypeExpense   = "expense"
+
+	ErrUnbalancedEntry = errors.New("journal entry postings do not add up to zero")
+	ErrEntryCurrency   = errors.New("journal entry postings are not all in the same currency")
+)
+
+// SalesAccount is credited with what customers pay for their orders.
+func SalesAccount() dto.LedgerAccountDTO {
+	return dto.LedgerAccountDTO{Code: "sales", Name: "Sales", Type: LedgerAccountTypeRevenue}
+}
+
+// RefundsAccount is debited with what is given back to customers.
+func RefundsAccount() dto.LedgerAccountDTO {
+	return dto.LedgerAccountDTO{Code: "refunds", Name: "Refunds", Type: LedgerAccountTypeExpense}
+}
+
+// GatewayAccount is what a payment gateway holds for us, until it is settled to the bank.
+func GatewayAccount(vendor string) dto.LedgerAccountDTO {
+	return dto.LedgerAccountDTO{Code: "gateway:" + vendor, Name: vendor, Type: LedgerAccountTypeAsset}
+}
+
+// WalletAccount is what the platform owes the user through their wallet.
+func WalletAccount(userId uuid.UUID) dto.LedgerAccountDTO {
+	return dto.LedgerAccountDTO{Code: "wallet:" + userId.String(), Name: "Wallet", Type: LedgerAccountTypeLiability, UserID: &userId}
+}
+
+// LedgerServiceInterface keeps the double-entry ledger. Every entry is in one currency and its postings add up to zero.
+type LedgerServiceInterface interface {
+	PostEntry(entry dto.JournalEntryDTO) (dto.JournalEntryDTO, error)
+	PostTransaction(transaction dto.TransactionDTO) (dto.JournalEntryDTO, error)
+	FindTrialBalance(currency string, toDate time.Time) ([]dto.TrialBalanceLineDTO, error)
+	FindAccountStatement(code string, pageable finance_repository.LedgerPostingPageable) (dto.AccountStatementDTO, repository.Pagination, error)
+	WithTx(tx database.DatabaseInterface) LedgerServiceInterface
+}
+
+type ledgerService struct {
+	database         database.DatabaseInterface
+	ledgerRepository finance_repository.LedgerRepositoryInterface
+}
+
+func NewLedgerService(database database.DatabaseInterface, ledgerRepository finance_repository.LedgerRepositoryInterface) LedgerServiceInterface {
+	return &ledgerService{database: database, ledgerRepository: ledgerRepository}
+}
+
+// WithTx implements LedgerServiceInterface.
+func (s *ledgerService) WithTx(tx database.DatabaseInterface) LedgerServiceInterface {
+	return s.withTx(tx)
+}
+
+func (s *ledgerService) withTx(tx database.DatabaseInterface) *ledgerService {
+	return &ledgerService{database: tx, ledgerRepository: s.ledgerRepository.WithTx(tx)}
+}
+
+func (s *ledgerService) ConvertAccountToDTO(account models.LedgerAccount) (accountDto dto.LedgerAccountDTO) {
+
+	accountDto.ID = account.ID
+	accountDto.Code = account.Code
+	accountDto.Name = account.Name
+	accountDto.Type = account.Type
+	accountDto.UserID = account.UserID
+	accountDto.CreatedAt = account.CreatedAt
+	accountDto.UpdatedAt = account.UpdatedAt
+
+	return accountDto
+}
+
+func (s *ledgerService) ConvertToDTO(entry models.JournalEntry) (entryDto dto.JournalEntryDTO) {
+
+	entryDto.ID = entry.ID
+	entryDto.TransactionID = entry.TransactionID
+	entryDto.Description = entry.Description
+	for _, posting := range entry.Postings {
+		entryDto.Postings = append(entryDto.Postings, dto.LedgerPostingDTO{
+			DTO:     dto.DTO{ID: posting.ID, CreatedAt: posting.CreatedAt, UpdatedAt: posting.UpdatedAt},
+			Account: s.ConvertAccountToDTO(posting.Account),
+			Amount:  posting.Amount,
+		})
+	}
+	entryDto.CreatedAt = entry.CreatedAt
+	entryDto.UpdatedAt = entry.UpdatedAt
+
+	return entryDto
+}
+
+// PostEntry implements LedgerServiceInterface and opens the accounts the ledger does not have yet.
+// It returns ErrUnbalancedEntry when the postings do not add up to zero, the database checks it again on commit.
+func (s *ledgerService) PostEntry(entryDto dto.JournalEntryDTO) (dto.JournalEntryDTO, error) {
+	var entry models.JournalEntry
+
+	if len(entryDto.Postings) < 2 {
+		return dto.JournalEntryDTO{}, ErrUnbalancedEntry
+	}
+
+	currency := entryDto.Postings[0].Amount.Currency
+	total := money.New(0, currency)
+
+	for _, posting := range entryDto.Postings {
+		if posting.Amount.Currency != currency {
+			return dto.JournalEntryDTO{}, ErrEntryCurrency
+		}
+
+		if posting.Amount.IsZero() {
+			return dto.JournalEntryDTO{}, ErrUnbalancedEntry
+		}
+
+		total = total.Add(posting.Amount)
+	}
+
+	if !total.IsZero() {
+		return dto.JournalEntryDTO{}, ErrUnbalancedEntry
+	}
+
+	err := s.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := s.withTx(tx)
+
+		entry.TransactionID = entryDto.TransactionID
+		entry.Currency = currency
+		entry.Description = entryDto.Description
+
+		for _, posting := range entryDto.Postings {
+			account, err := txService.ledgerRepository.FindOrCreateAccount(models.LedgerAccount{
+				Code:   posting.Account.Code,
+				Name:   posting.Account.Name,
+				Type:   posting.Account.Type,
+				UserID: posting.Account.UserID,
+			})
+
+			if err != nil {
+				return err
+			}
+
+			entry.Postings = append(entry.Postings, models.LedgerPosting{
+				AccountID: account.ID,
+				Account:   account,
+				Amount:    posting.Amount,
+			})
+		}
+
+		var err error
+
+		entry, err = txService.ledgerRepository.CreateJournalEntry(entry)
+
+		return err
+	})
+
+	return s.ConvertToDTO(entry), err
+}
+
+// PostTransaction implements LedgerServiceInterface, it posts a transaction that succeeded.
+// A debit is the customer paying from the gateway or their wallet into sales. A credit gives money back to the
+// gateway or wallet out of refunds, except a wallet top up, which moves it from the gateway into the wallet.
+func (s *ledgerService) PostTransaction(transaction dto.TransactionDTO) (dto.JournalEntryDTO, error) {
+	if transaction.Amount.IsZero() {
+		return dto.JournalEntryDTO{}, nil
+	}
+
+	customer := GatewayAccount(transaction.Vendor)
+	if transaction.Method == TransactionMethodWallet {
+		customer = WalletAccount(transaction.UserID)
+	}
+
+	var debit, credit dto.LedgerAccountDTO
+
+	switch {
+	case transaction.Type == TransactionTypeDebit:
+		debit, credit = customer, SalesAccount()
+	case transaction.ShortDesc == WalletTopUpShortDesc:
+		debit, credit = GatewayAccount(transaction.Vendor), customer
+	default:
+		debit, credit = RefundsAccount(), customer
+	}
+
+	transactionId := transaction.ID
+
+	return s.PostEntry(dto.JournalEntryDTO{
+		TransactionID: &transactionId,
+		Description:   transaction.Description,
+		Postings: []dto.LedgerPostingDTO{
+			{Account: debit, Amount: transaction.Amount},
+			{Account: credit, Amount: transaction.Amount.Neg()},
+		},
+	})
+}
+
+// FindTrialBalance implements LedgerServiceInterface. The debits and credits of each currency add up to the same total.
+func (s *ledgerService) FindTrialBalance(currency string, toDate time.Time) ([]dto.TrialBalanceLineDTO, error) {
+	lines := []dto.TrialBalanceLineDTO{}
+
+	rows, err := s.ledgerRepository.TrialBalance(currency, toDate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		lines = append(lines, dto.TrialBalanceLineDTO{
+			Account: dto.LedgerAccountDTO{
+				DTO:    dto.DTO{ID: row.AccountID},
+				Code:   row.Code,
+				Name:   row.Name,
+				Type:   row.Type,
+				UserID: row.UserID,
+			},
+			Debit:  money.New(row.Debit, row.Currency),
+			Credit: money.New(row.Credit, row.Currency),
+		})
+	}
+
+	return lines, nil
+}
+
+// FindAccountStatement implements LedgerServiceInterface for the account with code.
+func (s *ledgerService) FindAccountStatement(code string, pageable finance_repository.LedgerPostingPageable) (dto.AccountStatementDTO, repository.Pagination, error) {
+	var statement dto.AccountStatementDTO
+	var pagination repository.Pagination
+
+	account, err := s.ledgerRepository.FindAccountByCode(code)
+
+	if err != nil {
+		return statement, pagination, err
+	}
+
+	pageable.AccountID = account.ID
+	pageable.Currency = CurrencyCode(pageable.Currency)
+
+	opening := int64(0)
+	if !pageable.FromDate.IsZero() {
+		if opening, err = s.ledgerRepository.AccountBalance(account.ID, pageable.Currency, pageable.FromDate); err != nil {
+			return statement, pagination, err
+		}
+	}
+
+	closing, err := s.ledgerRepository.AccountBalance(account.ID, pageable.Currency, pageable.ToDate)
+
+	if err != nil {
+		return statement, pagination, err
+	}
+
+	rows, pagination, err := s.ledgerRepository.FindAccountPostings(pageable)
+
+	if err != nil {
+		return statement, pagination, err
+	}
+
+	statement.Account = s.ConvertAccountToDTO(account)
+	statement.OpeningBalance = money.New(opening, pageable.Currency)
+	statement.ClosingBalance = money.New(closing, pageable.Currency)
+	statement.Lines = []dto.AccountStatementLineDTO{}
+
+	for _, row := range rows {
+		statement.Lines = append(statement.Lines, dto.AccountStatementLineDTO{
+			EntryID:              row.EntryID,
+			Description:          row.Description,
+			TransactionReference: row.Reference,
+			Amount:               money.New(row.Amount, pageable.Currency),
+			CreatedAt:            row.CreatedAt,
+		})
+	}
+
+	return statement, pagination, nil
+}
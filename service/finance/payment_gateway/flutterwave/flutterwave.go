@@ -0,0 +1,295 @@
+package flutterwave_gateway
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+)
+
+var (
+	GatewayName = "flutterwave"
+
+	// FlutterwaveBaseURL is used unless FLUTTERWAVE_BASE_URL points somewhere else, like a mock server.
+	FlutterwaveBaseURL = "https://api.flutterwave.com/v3"
+
+	FlutterwaveStatusAbandoned  = "abandoned"
+	FlutterwaveStatusFailed     = "failed"
+	FlutterwaveStatusOngoing    = "ongoing"
+	FlutterwaveStatusPending    = "pending"
+	FlutterwaveStatusProcessing = "processing"
+	FlutterwaveStatusQueued     = "queued"
+	FlutterwaveStatusReversed   = "reversed"
+	FlutterwaveStatusSuccess    = "successful"
+
+	FlutterwaveRefundStatusCompleted = "completed"
+	FlutterwaveRefundStatusFailed    = "failed"
+
+	FlutterwaveResponseSuccess = "success"
+
+	FlutterwaveEventChargeCompleted = "charge.completed"
+	FlutterwaveEventRefundCompleted = "refund.completed"
+)
+
+type flutterwaveProvider struct {
+	httpService service.HttpServiceInterface
+	secretKey   string
+	secretHash  string
+	callbackURL string
+	baseURL     string
+}
+
+func NewFlutterwaveProvider(httpService service.HttpServiceInterface, env constants.Env) payment_gateway_service.Provider {
+	baseURL := env.FLUTTERWAVE_BASE_URL
+	if baseURL == "" {
+		baseURL = FlutterwaveBaseURL
+	}
+
+	return &flutterwaveProvider{
+		httpService: httpService,
+		secretKey:   env.FLUTTERWAVE_SECRET_KEY,
+		secretHash:  env.FLUTTERWAVE_SECRET_HASH,
+		callbackURL: env.PAYMENT_CALLBACK_URL,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (p *flutterwaveProvider) Name() string {
+	return GatewayName
+}
+
+func (p *flutterwaveProvider) headers() map[string]string {
+	return map[string]string{
+		"Authorization": "Bearer " + p.secretKey,
+	}
+}
+
+func (p *flutterwaveProvider) Initialize(paymentDto payment_gateway_dto.PaymentInitializationDTO) (resp payment_gateway_dto.PaymentInitializationResponseDTO, err error) {
+	var flutterwaveDto payment_gateway_dto.InitializeFlutterwaveRequest
+	var data payment_gateway_dto.InitializeFlutterwaveResponse
+
+	flutterwaveDto.Amount = paymentDto.Amount.Major() // flutterwave takes major units
+	flutterwaveDto.Currency = paymentDto.Amount.Currency
+	flutterwaveDto.Customer.Email = paymentDto.Email
+	flutterwaveDto.TxRef = paymentDto.Reference
+	flutterwaveDto.RedirectURL = p.callbackURL + paymentDto.Reference
+	flutterwaveDto.Customizations.Title = "MaziMart"
+
+	httpResp, err := p.httpService.Post(p.baseURL+"/payments", p.headers(), flutterwaveDto)
+
+	if err != nil {
+		return resp, err
+	}
+
+	defer httpResp.Body.Close()
+
+	if err = p.httpService.BodyToDTO(httpResp.Body, &data); err != nil {
+		return resp, err
+	}
+
+	resp.Status = data.Status == FlutterwaveResponseSuccess
+	resp.Message = data.Message
+	resp.PaymentURL = data.Data.Link
+
+	return resp, nil
+}
+
+func (p *flutterwaveProvider) verifyByReference(reference string) (data payment_gateway_dto.VerifyFlutterwaveResponse, err error) {
+	httpResp, err := p.httpService.Get(p.baseURL+"/transactions/verify_by_reference?tx_ref="+reference, p.headers())
+
+	if err != nil {
+		return data, err
+	}
+
+	defer httpResp.Body.Close()
+
+	if err = p.httpService.BodyToDTO(httpResp.Body, &data); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+func (p *flutterwaveProvider) Verify(reference string, amount money.Money) (resp payment_gateway_dto.PaymentVerifyResponseDTO, err error) {
+	data, err := p.verifyByReference(reference)
+
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Status = data.Status == FlutterwaveResponseSuccess
+	resp.Message = data.Data.ProcessorResponse
+	resp.PaymentStatus = p.PaymentStatus(data.Data.Status)
+
+	// the amount and currency of a payment link can be changed by the customer, and paying less must not settle
+	paid := money.FromMajor(data.Data.Amount, data.Data.Currency)
+
+	if resp.PaymentStatus == finance_service.TransactionStatusSuccess && paid != amount {
+		resp.PaymentStatus = finance_service.TransactionStatusFailed
+		resp.Message = fmt.Sprintf("paid %s, expected %s", paid, amount)
+	}
+
+	return resp, nil
+}
+
+// Refund needs Flutterwave's own transaction id, so the payment is looked up by our reference first.
+func (p *flutterwaveProvider) Refund(refundDto payment_gateway_dto.RefundDTO) (resp payment_gateway_dto.RefundResponseDTO, err error) {
+	var data payment_gateway_dto.RefundFlutterwaveResponse
+
+	transaction, err := p.verifyByReference(refundDto.Reference)
+
+	if err != nil {
+		return resp, err
+	}
+
+	if transaction.Status != FlutterwaveResponseSuccess {
+		resp.Message = transaction.Message
+		resp.RefundStatus = finance_service.RefundStatusFailed
+
+		return resp, nil
+	}
+
+	url := fmt.Sprintf("%s/transactions/%d/refund", p.baseURL, transaction.Data.ID)
+
+	httpResp, err := p.httpService.Post(url, p.headers(), payment_gateway_dto.RefundFlutterwaveRequest{Amount: refundDto.Amount.Major()})
+
+	if err != nil {
+		return resp, err
+	}
+
+	defer httpResp.Body.Close()
+
+	if err = p.httpService.BodyToDTO(httpResp.Body, &data); err != nil {
+		return resp, err
+	}
+
+	resp.Status = data.Status == FlutterwaveResponseSuccess
+	resp.Message = data.Message
+	resp.GatewayReference = fmt.Sprintf("%d", data.Data.ID)
+	resp.RefundStatus = p.RefundStatus(data.Data.Status)
+
+	return resp, nil
+}
+
+// ParseWebhook checks the verif-hash header against the secret hash configured on the Flutterwave dashboard.
+func (p *flutterwaveProvider) ParseWebhook(body []byte, headers map[string]string) (event payment_gateway_dto.WebhookEventDTO, err error) {
+	var envelope request.WebhookEvent
+
+	if p.secretHash == "" || subtle.ConstantTimeCompare([]byte(p.secretHash), []byte(headers["verif-hash"])) != 1 {
+		return event, payment_gateway_service.ErrInvalidWebhookSignature
+	}
+
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return event, err
+	}
+
+	event.Event = envelope.Event
+
+	if envelope.Event == FlutterwaveEventRefundCompleted {
+		var refundWebhook request.FlutterwaveRefundWebhook
+
+		if err = json.Unmarshal(body, &refundWebhook); err != nil {
+			return event, err
+		}
+
+		event.RefundReference = fmt.Sprintf("%d", refundWebhook.Data.ID)
+		event.RefundStatus = p.RefundStatus(refundWebhook.Data.Status)
+
+		return event, nil
+	}
+
+	if envelope.Event != FlutterwaveEventChargeCompleted {
+		return event, nil
+	}
+
+	var webhook request.FlutterwaveWebhook
+
+	if err = json.Unmarshal(body, &webhook); err != nil {
+		return event, err
+	}
+
+	event.Reference = webhook.Data.TxRef
+	event.PaymentStatus = p.PaymentStatus(webhook.Data.Status)
+
+	return event, nil
+}
+
+// ListTransactions reads the transaction list page by page. Flutterwave filters on whole days, so the days of from and
+// to are asked for and the payments outside the period are left out.
+func (p *flutterwaveProvider) ListTransactions(from time.Time, to time.Time) (transactions []payment_gateway_dto.GatewayTransactionDTO, err error) {
+	for page := 1; ; page++ {
+		var data payment_gateway_dto.ListFlutterwaveTransactionsResponse
+
+		query := url.Values{}
+		query.Set("from", from.UTC().Format("2006-01-02"))
+		query.Set("to", to.UTC().Format("2006-01-02"))
+		query.Set("page", strconv.Itoa(page))
+
+		httpResp, err := p.httpService.Get(p.baseURL+"/transactions?"+query.Encode(), p.headers())
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.httpService.BodyToDTO(httpResp.Body, &data)
+		httpResp.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if data.Status != FlutterwaveResponseSuccess {
+			return nil, fmt.Errorf("flutterwave: %s", data.Message)
+		}
+
+		for _, transaction := range data.Data {
+			if transaction.CreatedAt.Before(from) || !transaction.CreatedAt.Before(to) {
+				continue
+			}
+
+			transactions = append(transactions, payment_gateway_dto.GatewayTransactionDTO{
+				Reference: transaction.TxRef,
+				Amount:    money.FromMajor(transaction.Amount, transaction.Currency),
+				Status:    p.PaymentStatus(transaction.Status),
+				CreatedAt: transaction.CreatedAt,
+			})
+		}
+
+		if page >= data.Meta.PageInfo.TotalPages || len(data.Data) == 0 {
+			return transactions, nil
+		}
+	}
+}
+
+func (p *flutterwaveProvider) PaymentStatus(status string) string {
+	switch status {
+	case FlutterwaveStatusSuccess:
+		return finance_service.TransactionStatusSuccess
+	case FlutterwaveStatusFailed, FlutterwaveStatusAbandoned, FlutterwaveStatusReversed:
+		return finance_service.TransactionStatusFailed
+	default:
+		return finance_service.TransactionStatusPending
+	}
+}
+
+func (p *flutterwaveProvider) RefundStatus(status string) string {
+	switch status {
+	case FlutterwaveRefundStatusCompleted:
+		return finance_service.RefundStatusProcessed
+	case FlutterwaveRefundStatusFailed:
+		return finance_service.RefundStatusFailed
+	default:
+		return finance_service.RefundStatusPending
+	}
+}
@@ -0,0 +1,202 @@
+package flutterwave_gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+)
+
+const (
+	testSecretKey  = "FLWSECK_TEST-secret"
+	testSecretHash = "webhook-hash"
+)
+
+// newTestProvider points a provider at a mock Flutterwave API serving routes, which checks the secret key is sent.
+func newTestProvider(t *testing.T, routes map[string]http.HandlerFunc) payment_gateway_service.Provider {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	for pattern, handler := range routes {
+		handler := handler
+
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+testSecretKey {
+				t.Errorf("%s sent Authorization %q", r.URL.Path, r.Header.Get("Authorization"))
+			}
+
+			handler(w, r)
+		})
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return NewFlutterwaveProvider(service.NewHTTPService(), constants.Env{
+		FLUTTERWAVE_SECRET_KEY:  testSecretKey,
+		FLUTTERWAVE_SECRET_HASH: testSecretHash,
+		FLUTTERWAVE_BASE_URL:    server.URL,
+		PAYMENT_CALLBACK_URL:    "https://shop.test/verify?reference=",
+	})
+}
+
+// verifyHandler answers a verification of ref-1 with a payment of amount in currency.
+func verifyHandler(t *testing.T, status string, amount float64, currency string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("tx_ref") != "ref-1" {
+			t.Errorf("verified tx_ref %q", r.URL.Query().Get("tx_ref"))
+		}
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"status":  "success",
+			"message": "Transaction fetched successfully",
+			"data": map[string]interface{}{
+				"id":                 4975363,
+				"tx_ref":             "ref-1",
+				"amount":             amount,
+				"currency":           currency,
+				"status":             status,
+				"processor_response": "Approved",
+			},
+		})
+
+		w.Write(body)
+	}
+}
+
+func TestInitialize(t *testing.T) {
+	provider := newTestProvider(t, map[string]http.HandlerFunc{
+		"POST /payments": func(w http.ResponseWriter, r *http.Request) {
+			var body payment_gateway_dto.InitializeFlutterwaveRequest
+			json.NewDecoder(r.Body).Decode(&body)
+
+			// flutterwave takes major units
+			if body.Amount != 12500.5 || body.Currency != "NGN" || body.TxRef != "ref-1" || body.Customer.Email != "buyer@example.com" {
+				t.Errorf("initialized with %+v", body)
+			}
+
+			if body.RedirectURL != "https://shop.test/verify?reference=ref-1" {
+				t.Errorf("redirect_url is %s", body.RedirectURL)
+			}
+
+			w.Write([]byte(`{"status": "success", "message": "Hosted Link", "data": {"link": "https://checkout.flutterwave.com/v3/hosted/pay/abc"}}`))
+		},
+	})
+
+	resp, err := provider.Initialize(payment_gateway_dto.PaymentInitializationDTO{
+		Amount:    money.New(1250050, "NGN"),
+		Email:     "buyer@example.com",
+		Reference: "ref-1",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.Status || resp.PaymentURL != "https://checkout.flutterwave.com/v3/hosted/pay/abc" {
+		t.Errorf("got %+v", resp)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		amount        float64
+		currency      string
+		paymentStatus string
+	}{
+		{"successful", "successful", 5000, "NGN", finance_service.TransactionStatusSuccess},
+		{"failed", "failed", 5000, "NGN", finance_service.TransactionStatusFailed},
+		{"pending", "pending", 5000, "NGN", finance_service.TransactionStatusPending},
+		{"less paid", "successful", 1, "NGN", finance_service.TransactionStatusFailed},
+		{"other currency", "successful", 5000, "USD", finance_service.TransactionStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestProvider(t, map[string]http.HandlerFunc{
+				"GET /transactions/verify_by_reference": verifyHandler(t, tt.status, tt.amount, tt.currency),
+			})
+
+			resp, err := provider.Verify("ref-1", money.New(500000, "NGN"))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !resp.Status || resp.PaymentStatus != tt.paymentStatus {
+				t.Errorf("got %+v, want payment status %s", resp, tt.paymentStatus)
+			}
+		})
+	}
+}
+
+func TestRefund(t *testing.T) {
+	provider := newTestProvider(t, map[string]http.HandlerFunc{
+		"GET /transactions/verify_by_reference": verifyHandler(t, "successful", 5000, "NGN"),
+		"POST /transactions/4975363/refund": func(w http.ResponseWriter, r *http.Request) {
+			var body payment_gateway_dto.RefundFlutterwaveRequest
+			json.NewDecoder(r.Body).Decode(&body)
+
+			if body.Amount != 200 {
+				t.Errorf("refunded %v", body.Amount)
+			}
+
+			w.Write([]byte(`{"status": "success", "message": "Transaction refund initiated", "data": {"id": 75923, "amount_refunded": 200, "status": "completed"}}`))
+		},
+	})
+
+	resp, err := provider.Refund(payment_gateway_dto.RefundDTO{Reference: "ref-1", Amount: money.New(20000, "NGN")})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.Status || resp.GatewayReference != "75923" || resp.RefundStatus != finance_service.RefundStatusProcessed {
+		t.Errorf("got %+v", resp)
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	provider := newTestProvider(t, nil)
+	headers := map[string]string{"verif-hash": testSecretHash}
+
+	charge := []byte(`{"event": "charge.completed", "data": {"id": 4975363, "tx_ref": "ref-1", "amount": 5000, "currency": "NGN", "status": "successful"}}`)
+	refund := []byte(`{"event": "refund.completed", "data": {"id": 75923, "tx_id": 4975363, "amount_refunded": 200, "status": "completed"}}`)
+
+	event, err := provider.ParseWebhook(charge, headers)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.Reference != "ref-1" || event.PaymentStatus != finance_service.TransactionStatusSuccess {
+		t.Errorf("charge: got %+v", event)
+	}
+
+	event, err = provider.ParseWebhook(refund, headers)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.RefundReference != "75923" || event.RefundStatus != finance_service.RefundStatusProcessed {
+		t.Errorf("refund: got %+v", event)
+	}
+
+	for name, hash := range map[string]string{"missing": "", "wrong": "another-hash"} {
+		if _, err := provider.ParseWebhook(charge, map[string]string{"verif-hash": hash}); !errors.Is(err, payment_gateway_service.ErrInvalidWebhookSignature) {
+			t.Errorf("%s hash: got %v", name, err)
+		}
+	}
+}
@@ -3,36 +3,67 @@ package payment_gateway_service
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
-	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 )
 
 var (
-	PaystackPaymentGateway    = "paystack"
-	FlutterwavePaymentGateway = "flutterwave"
-	ErrPaymentInitialization  = errors.New("failed to initialize payment")
+	ErrPaymentInitialization   = errors.New("failed to initialize payment")
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrPaymentGatewayDisabled  = errors.New("payment gateway is not available")
 )
 
 type PaymentGatewayServiceInterface interface {
 	InitializePayment(paymentDto payment_gateway_dto.PaymentInitializationDTO) (payment_gateway_dto.PaymentInitializationResponseDTO, error)
-	VerifyPayment(reference string, gateway string) (payment_gateway_dto.PaymentVerifyResponseDTO, error)
+	VerifyPayment(reference string, amount money.Money, gateway string) (payment_gateway_dto.PaymentVerifyResponseDTO, error)
+	RefundPayment(refundDto payment_gateway_dto.RefundDTO, gateway string) (payment_gateway_dto.RefundResponseDTO, error)
+	ParseWebhook(gateway string, body []byte, headers map[string]string) (payment_gateway_dto.WebhookEventDTO, error)
+	ListTransactions(gateway string, from time.Time, to time.Time) ([]payment_gateway_dto.GatewayTransactionDTO, error)
+	IsEnabled(gateway string) bool
+	EnabledGateways() []string
+	Gateways() []string
 }
 
 type paymentGatewayService struct {
-	paystackService    PaystackServiceInterface
-	flutterwaveService FlutterwaveServiceInterface
+	providers  map[string]Provider
+	registered []string
+	enabled    []string
 }
 
-func NewPaymentGatewayService(
-	paystackService PaystackServiceInterface,
-	flutterwaveService FlutterwaveServiceInterface,
-) PaymentGatewayServiceInterface {
-	return &paymentGatewayService{
-		paystackService:    paystackService,
-		flutterwaveService: flutterwaveService,
+// NewPaymentGatewayService registers the given providers and enables the ones listed in PAYMENT_GATEWAYS.
+// When PAYMENT_GATEWAYS is empty every registered provider is enabled.
+func NewPaymentGatewayService(env constants.Env, providers ...Provider) PaymentGatewayServiceInterface {
+	service := &paymentGatewayService{providers: map[string]Provider{}}
+
+	for _, provider := range providers {
+		service.providers[provider.Name()] = provider
+		service.registered = append(service.registered, provider.Name())
 	}
+
+	if strings.TrimSpace(env.PAYMENT_GATEWAYS) == "" {
+		for _, provider := range providers {
+			service.enabled = append(service.enabled, provider.Name())
+		}
+
+		return service
+	}
+
+	for _, name := range strings.Split(env.PAYMENT_GATEWAYS, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if _, ok := service.providers[name]; !ok {
+			fmt.Println("Unknown payment gateway in PAYMENT_GATEWAYS:", name)
+			continue
+		}
+
+		service.enabled = append(service.enabled, name)
+	}
+
+	return service
 }
 
 func (p *paymentGatewayService) SetLogger(status bool, reference string, message string, gateway string) {
@@ -45,139 +76,111 @@ func (p *paymentGatewayService) SetLogger(status bool, reference string, message
 	fmt.Println(logMessage)
 }
 
-func (p *paymentGatewayService) InitializePayment(paymentDto payment_gateway_dto.PaymentInitializationDTO) (payment_gateway_dto.PaymentInitializationResponseDTO, error) {
-	switch paymentDto.Gateway {
-	case PaystackPaymentGateway:
-		return p.InitializePaystack(paymentDto)
-	case FlutterwavePaymentGateway:
-		return payment_gateway_dto.PaymentInitializationResponseDTO{}, errors.New("flutterwave not supported yet")
-	default:
-		return payment_gateway_dto.PaymentInitializationResponseDTO{}, errors.New("payment gateway must be paystack or flutterwave")
+func (p *paymentGatewayService) IsEnabled(gateway string) bool {
+	for _, name := range p.enabled {
+		if name == gateway {
+			return true
+		}
 	}
+
+	return false
 }
 
-func (p *paymentGatewayService) VerifyPayment(reference string, gateway string) (payment_gateway_dto.PaymentVerifyResponseDTO, error) {
-	switch gateway {
-	case PaystackPaymentGateway:
-		return p.VerifyPaystack(reference)
-	case FlutterwavePaymentGateway:
-		return p.VerifyFlutterwave(reference)
-	default:
-		return payment_gateway_dto.PaymentVerifyResponseDTO{}, errors.New("payment gateway must be paystack or flutterwave")
-	}
+func (p *paymentGatewayService) EnabledGateways() []string {
+	return p.enabled
+}
+
+// Gateways returns every registered provider, enabled or not. A disabled gateway still holds the payments made
+// before it was disabled.
+func (p *paymentGatewayService) Gateways() []string {
+	return p.registered
 }
 
-func (p *paymentGatewayService) InitializePaystack(paymentDto payment_gateway_dto.PaymentInitializationDTO) (payment_gateway_dto.PaymentInitializationResponseDTO, error) {
-	paystackDto := payment_gateway_dto.Paystack{
-		Amount:    paymentDto.Amount,
-		Email:     paymentDto.Email,
-		Reference: paymentDto.Reference,
+// provider returns an enabled provider by name.
+func (p *paymentGatewayService) provider(gateway string) (Provider, error) {
+	if !p.IsEnabled(gateway) {
+		return nil, ErrPaymentGatewayDisabled
 	}
-	initialize, err := p.paystackService.InitializePayment(paystackDto)
 
-	p.SetLogger(initialize.Status, paymentDto.Reference, initialize.Message, "paystack")
+	return p.providers[gateway], nil
+}
+
+func (p *paymentGatewayService) InitializePayment(paymentDto payment_gateway_dto.PaymentInitializationDTO) (payment_gateway_dto.PaymentInitializationResponseDTO, error) {
+	provider, err := p.provider(paymentDto.Gateway)
 
 	if err != nil {
 		return payment_gateway_dto.PaymentInitializationResponseDTO{}, err
 	}
 
-	responseDto := payment_gateway_dto.PaymentInitializationResponseDTO{
-		Status:     initialize.Status,
-		PaymentURL: initialize.Data.AuthorizationURL,
-	}
+	initialize, err := provider.Initialize(paymentDto)
+
+	p.SetLogger(initialize.Status, paymentDto.Reference, initialize.Message, provider.Name())
 
-	return responseDto, nil
+	return initialize, err
 }
 
-func (p *paymentGatewayService) InitializeFlutterwave(paymentDto payment_gateway_dto.PaymentInitializationDTO) (payment_gateway_dto.PaymentInitializationResponseDTO, error) {
-	var flutterwaveDto payment_gateway_dto.InitializeFlutterwaveRequest
-	var status bool
+// VerifyPayment asks the gateway that handled a payment for its current status, amount is what the payment is for.
+// Verification is allowed for disabled gateways so that payments started before a gateway was switched off can settle.
+func (p *paymentGatewayService) VerifyPayment(reference string, amount money.Money, gateway string) (payment_gateway_dto.PaymentVerifyResponseDTO, error) {
+	provider, ok := p.providers[gateway]
 
-	flutterwaveDto.Amount = paymentDto.Amount
-	flutterwaveDto.Customer.Email = paymentDto.Email
-	flutterwaveDto.TxRef = paymentDto.Reference
+	if !ok {
+		return payment_gateway_dto.PaymentVerifyResponseDTO{}, ErrPaymentGatewayDisabled
+	}
 
-	initialize, err := p.flutterwaveService.InitializePayment(flutterwaveDto)
+	verify, err := provider.Verify(reference, amount)
 
-	if initialize.Status == "success" {
-		status = true
-	} else {
-		status = false
-	}
+	p.SetLogger(verify.Status, reference, verify.Message, provider.Name())
 
-	p.SetLogger(status, paymentDto.Reference, initialize.Message, "flutterwave")
+	return verify, err
+}
 
-	if err != nil {
-		return payment_gateway_dto.PaymentInitializationResponseDTO{}, err
-	}
+func (p *paymentGatewayService) RefundPayment(refundDto payment_gateway_dto.RefundDTO, gateway string) (payment_gateway_dto.RefundResponseDTO, error) {
+	provider, ok := p.providers[gateway]
 
-	responseDto := payment_gateway_dto.PaymentInitializationResponseDTO{
-		Status:     status,
-		PaymentURL: initialize.Data.Link,
+	if !ok {
+		return payment_gateway_dto.RefundResponseDTO{}, ErrPaymentGatewayDisabled
 	}
 
-	return responseDto, nil
+	refund, err := provider.Refund(refundDto)
+
+	p.SetLogger(refund.Status, refundDto.Reference, "refund: "+refund.Message, provider.Name())
+
+	return refund, err
 }
 
-func (p *paymentGatewayService) VerifyPaystack(reference string) (resp payment_gateway_dto.PaymentVerifyResponseDTO, err error) {
+// ParseWebhook validates a webhook delivery and returns the payment it refers to.
+// Events that do not concern a charge are returned with an empty reference.
+func (p *paymentGatewayService) ParseWebhook(gateway string, body []byte, headers map[string]string) (payment_gateway_dto.WebhookEventDTO, error) {
+	provider, ok := p.providers[gateway]
 
-	verify, err := p.paystackService.VerifyPayment(reference)
+	if !ok {
+		return payment_gateway_dto.WebhookEventDTO{}, ErrPaymentGatewayDisabled
+	}
 
-	p.SetLogger(verify.Status, verify.Data.Reference, verify.Message, "paystack")
+	event, err := provider.ParseWebhook(body, headers)
 
 	if err != nil {
-		return payment_gateway_dto.PaymentVerifyResponseDTO{}, err
+		return event, err
 	}
 
-	resp.Status = verify.Status
-	resp.Message = verify.Data.Message
-	switch verify.Data.Status {
-	case PaystackStatusSuccess:
-		resp.PaymentStatus = finance_service.TransactionStatusSuccess
-	case PaystackStatusFailed:
-		resp.PaymentStatus = finance_service.TransactionStatusFailed
-	case PaystackStatusAbandoned:
-		resp.PaymentStatus = finance_service.TransactionStatusFailed
-	case PaystackStatusReversed:
-		resp.PaymentStatus = finance_service.TransactionStatusFailed
-	default:
-		resp.PaymentStatus = finance_service.TransactionStatusPending
-	}
+	p.SetLogger(true, event.Reference, "webhook: "+event.Event, provider.Name())
 
-	return resp, nil
+	return event, nil
 }
 
-func (p *paymentGatewayService) VerifyFlutterwave(reference string) (resp payment_gateway_dto.PaymentVerifyResponseDTO, err error) {
-	var status bool
-
-	verify, err := p.flutterwaveService.VerifyPayment(reference)
+// ListTransactions returns the payments a gateway has from from up to to, for reconciliation.
+// Like verification it works for disabled gateways, their payments still need checking.
+func (p *paymentGatewayService) ListTransactions(gateway string, from time.Time, to time.Time) ([]payment_gateway_dto.GatewayTransactionDTO, error) {
+	provider, ok := p.providers[gateway]
 
-	if verify.Status == "success" {
-		status = true
-	} else {
-		status = false
+	if !ok {
+		return nil, ErrPaymentGatewayDisabled
 	}
 
-	p.SetLogger(status, verify.Data.TxRef, verify.Message, "flutterwave")
+	transactions, err := provider.ListTransactions(from, to)
 
-	if err != nil {
-		return payment_gateway_dto.PaymentVerifyResponseDTO{}, err
-	}
-
-	resp.Status = status
-	resp.Message = verify.Data.ProcessorResponse
-	switch verify.Data.Status {
-	case FlutterwaveStatusSuccess:
-		resp.PaymentStatus = finance_service.TransactionStatusSuccess
-	case FlutterwaveStatusFailed:
-		resp.PaymentStatus = finance_service.TransactionStatusFailed
-	case FlutterwaveStatusAbandoned:
-		resp.PaymentStatus = finance_service.TransactionStatusFailed
-	case FlutterwaveStatusReversed:
-		resp.PaymentStatus = finance_service.TransactionStatusFailed
-	default:
-		resp.PaymentStatus = finance_service.TransactionStatusPending
-	}
+	p.SetLogger(err == nil, "", fmt.Sprintf("listed %d transactions from %s to %s", len(transactions), from.Format(time.RFC3339), to.Format(time.RFC3339)), provider.Name())
 
-	return resp, nil
+	return transactions, err
 }
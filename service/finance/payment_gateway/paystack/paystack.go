@@ -0,0 +1,280 @@
+package paystack_gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+)
+
+var (
+	GatewayName = "paystack"
+
+	// PaystackBaseURL is used unless PAYSTACK_BASE_URL points somewhere else, like a mock server.
+	PaystackBaseURL = "https://api.paystack.co"
+
+	PaystackStatusAbandoned  = "abandoned"
+	PaystackStatusFailed     = "failed"
+	PaystackStatusOngoing    = "ongoing"
+	PaystackStatusPending    = "pending"
+	PaystackStatusProcessing = "processing"
+	PaystackStatusQueued     = "queued"
+	PaystackStatusReversed   = "reversed"
+	PaystackStatusSuccess    = "success"
+
+	PaystackRefundStatusProcessed = "processed"
+	PaystackRefundStatusFailed    = "failed"
+
+	PaystackListPageSize = 100
+
+	PaystackEventChargeSuccess = "charge.success"
+	PaystackEventRefundPrefix  = "refund."
+)
+
+type paystackProvider struct {
+	httpService service.HttpServiceInterface
+	secretKey   string
+	callbackURL string
+	baseURL     string
+}
+
+func NewPaystackProvider(httpService service.HttpServiceInterface, env constants.Env) payment_gateway_service.Provider {
+	baseURL := env.PAYSTACK_BASE_URL
+	if baseURL == "" {
+		baseURL = PaystackBaseURL
+	}
+
+	return &paystackProvider{
+		httpService: httpService,
+		secretKey:   env.PAYSTACK_SECRET_KEY,
+		callbackURL: env.PAYMENT_CALLBACK_URL,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (p *paystackProvider) Name() string {
+	return GatewayName
+}
+
+func (p *paystackProvider) headers() map[string]string {
+	return map[string]string{
+		"Authorization": "Bearer " + p.secretKey,
+	}
+}
+
+func (p *paystackProvider) Initialize(paymentDto payment_gateway_dto.PaymentInitializationDTO) (resp payment_gateway_dto.PaymentInitializationResponseDTO, err error) {
+	var data payment_gateway_dto.InitializePaystackResponse
+
+	body := map[string]interface{}{
+		"amount":       paymentDto.Amount.Amount, // paystack takes minor units
+		"currency":     paymentDto.Amount.Currency,
+		"email":        paymentDto.Email,
+		"reference":    paymentDto.Reference,
+		"callback_url": p.callbackURL + paymentDto.Reference,
+	}
+
+	httpResp, err := p.httpService.Post(p.baseURL+"/transaction/initialize", p.headers(), body)
+
+	if err != nil {
+		return resp, err
+	}
+
+	defer httpResp.Body.Close()
+
+	if err = p.httpService.BodyToDTO(httpResp.Body, &data); err != nil {
+		return resp, err
+	}
+
+	resp.Status = data.Status
+	resp.Message = data.Message
+	resp.PaymentURL = data.Data.AuthorizationURL
+
+	return resp, nil
+}
+
+func (p *paystackProvider) Verify(reference string, amount money.Money) (resp payment_gateway_dto.PaymentVerifyResponseDTO, err error) {
+	var data payment_gateway_dto.VerifyPaystackResponse
+
+	httpResp, err := p.httpService.Get(p.baseURL+"/transaction/verify/"+reference, p.headers())
+
+	if err != nil {
+		return resp, err
+	}
+
+	defer httpResp.Body.Close()
+
+	if err = p.httpService.BodyToDTO(httpResp.Body, &data); err != nil {
+		return resp, err
+	}
+
+	resp.Status = data.Status
+	resp.Message = data.Data.Message
+	resp.PaymentStatus = p.PaymentStatus(data.Data.Status)
+
+	// a payment for another amount, e.g. from a tampered checkout, does not pay for the transaction
+	paid := money.New(int64(data.Data.Amount), data.Data.Currency)
+
+	if resp.PaymentStatus == finance_service.TransactionStatusSuccess && paid != amount {
+		resp.PaymentStatus = finance_service.TransactionStatusFailed
+		resp.Message = fmt.Sprintf("paid %s, expected %s", paid, amount)
+	}
+
+	return resp, nil
+}
+
+func (p *paystackProvider) Refund(refundDto payment_gateway_dto.RefundDTO) (resp payment_gateway_dto.RefundResponseDTO, err error) {
+	var data payment_gateway_dto.RefundPaystackResponse
+
+	body := map[string]interface{}{
+		"transaction": refundDto.Reference,
+		"amount":      refundDto.Amount.Amount,
+		"currency":    refundDto.Amount.Currency,
+	}
+
+	httpResp, err := p.httpService.Post(p.baseURL+"/refund", p.headers(), body)
+
+	if err != nil {
+		return resp, err
+	}
+
+	defer httpResp.Body.Close()
+
+	if err = p.httpService.BodyToDTO(httpResp.Body, &data); err != nil {
+		return resp, err
+	}
+
+	resp.Status = data.Status
+	resp.Message = data.Message
+	resp.GatewayReference = strconv.Itoa(data.Data.ID)
+	resp.RefundStatus = p.RefundStatus(data.Data.Status)
+
+	return resp, nil
+}
+
+// ParseWebhook checks the x-paystack-signature header, an HMAC-SHA512 of the raw body signed with the secret key.
+func (p *paystackProvider) ParseWebhook(body []byte, headers map[string]string) (event payment_gateway_dto.WebhookEventDTO, err error) {
+	var envelope request.WebhookEvent
+
+	mac := hmac.New(sha512.New, []byte(p.secretKey))
+	mac.Write(body)
+
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if p.secretKey == "" || !hmac.Equal([]byte(expected), []byte(headers["x-paystack-signature"])) {
+		return event, payment_gateway_service.ErrInvalidWebhookSignature
+	}
+
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return event, err
+	}
+
+	event.Event = envelope.Event
+
+	// refund.pending, refund.processing, refund.processed and refund.failed
+	if strings.HasPrefix(envelope.Event, PaystackEventRefundPrefix) {
+		var refundWebhook request.PaystackRefundWebhook
+
+		if err = json.Unmarshal(body, &refundWebhook); err != nil {
+			return event, err
+		}
+
+		event.Reference = refundWebhook.Data.TransactionReference
+		event.RefundReference = refundWebhook.Data.ID.String()
+		event.RefundStatus = p.RefundStatus(refundWebhook.Data.Status)
+
+		return event, nil
+	}
+
+	if envelope.Event != PaystackEventChargeSuccess {
+		return event, nil
+	}
+
+	var webhook request.PaystackWebhook
+
+	if err = json.Unmarshal(body, &webhook); err != nil {
+		return event, err
+	}
+
+	event.Reference = webhook.Data.Reference
+	event.PaymentStatus = p.PaymentStatus(webhook.Data.Status)
+
+	return event, nil
+}
+
+// ListTransactions reads the transaction list page by page, paystack takes ISO 8601 dates.
+func (p *paystackProvider) ListTransactions(from time.Time, to time.Time) (transactions []payment_gateway_dto.GatewayTransactionDTO, err error) {
+	for page := 1; ; page++ {
+		var data payment_gateway_dto.ListPaystackTransactionsResponse
+
+		query := url.Values{}
+		query.Set("from", from.UTC().Format(time.RFC3339))
+		query.Set("to", to.UTC().Format(time.RFC3339))
+		query.Set("perPage", strconv.Itoa(PaystackListPageSize))
+		query.Set("page", strconv.Itoa(page))
+
+		httpResp, err := p.httpService.Get(p.baseURL+"/transaction?"+query.Encode(), p.headers())
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.httpService.BodyToDTO(httpResp.Body, &data)
+		httpResp.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !data.Status {
+			return nil, fmt.Errorf("paystack: %s", data.Message)
+		}
+
+		for _, transaction := range data.Data {
+			transactions = append(transactions, payment_gateway_dto.GatewayTransactionDTO{
+				Reference: transaction.Reference,
+				Amount:    money.New(transaction.Amount, transaction.Currency),
+				Status:    p.PaymentStatus(transaction.Status),
+				CreatedAt: transaction.CreatedAt,
+			})
+		}
+
+		if page >= data.Meta.PageCount || len(data.Data) == 0 {
+			return transactions, nil
+		}
+	}
+}
+
+func (p *paystackProvider) PaymentStatus(status string) string {
+	switch status {
+	case PaystackStatusSuccess:
+		return finance_service.TransactionStatusSuccess
+	case PaystackStatusFailed, PaystackStatusAbandoned, PaystackStatusReversed:
+		return finance_service.TransactionStatusFailed
+	default:
+		return finance_service.TransactionStatusPending
+	}
+}
+
+func (p *paystackProvider) RefundStatus(status string) string {
+	switch status {
+	case PaystackRefundStatusProcessed:
+		return finance_service.RefundStatusProcessed
+	case PaystackRefundStatusFailed:
+		return finance_service.RefundStatusFailed
+	default:
+		return finance_service.RefundStatusPending
+	}
+}
@@ -0,0 +1,200 @@
+package paystack_gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+)
+
+const testSecretKey = "sk_test_secret"
+
+// newTestProvider points a provider at a mock Paystack API serving routes, which checks the secret key is sent.
+func newTestProvider(t *testing.T, routes map[string]http.HandlerFunc) payment_gateway_service.Provider {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	for pattern, handler := range routes {
+		handler := handler
+
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+testSecretKey {
+				t.Errorf("%s sent Authorization %q", r.URL.Path, r.Header.Get("Authorization"))
+			}
+
+			handler(w, r)
+		})
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return NewPaystackProvider(service.NewHTTPService(), constants.Env{
+		PAYSTACK_SECRET_KEY:  testSecretKey,
+		PAYSTACK_BASE_URL:    server.URL,
+		PAYMENT_CALLBACK_URL: "https://shop.test/verify?reference=",
+	})
+}
+
+func writeJSON(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(body))
+}
+
+func TestInitialize(t *testing.T) {
+	provider := newTestProvider(t, map[string]http.HandlerFunc{
+		"POST /transaction/initialize": func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+
+			// paystack takes minor units
+			if body["amount"] != float64(1250050) || body["currency"] != "NGN" || body["reference"] != "ref-1" {
+				t.Errorf("initialized with %v", body)
+			}
+
+			if body["callback_url"] != "https://shop.test/verify?reference=ref-1" {
+				t.Errorf("callback_url is %v", body["callback_url"])
+			}
+
+			writeJSON(w, `{"status": true, "message": "Authorization URL created", "data": {"authorization_url": "https://checkout.paystack.com/abc", "reference": "ref-1"}}`)
+		},
+	})
+
+	resp, err := provider.Initialize(payment_gateway_dto.PaymentInitializationDTO{
+		Amount:    money.New(1250050, "NGN"),
+		Email:     "buyer@example.com",
+		Reference: "ref-1",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.Status || resp.PaymentURL != "https://checkout.paystack.com/abc" {
+		t.Errorf("got %+v", resp)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		amount        int64
+		currency      string
+		paymentStatus string
+	}{
+		{"success", "success", 500000, "NGN", finance_service.TransactionStatusSuccess},
+		{"abandoned", "abandoned", 500000, "NGN", finance_service.TransactionStatusFailed},
+		{"ongoing", "ongoing", 500000, "NGN", finance_service.TransactionStatusPending},
+		{"less paid", "success", 100, "NGN", finance_service.TransactionStatusFailed},
+		{"other currency", "success", 500000, "GHS", finance_service.TransactionStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestProvider(t, map[string]http.HandlerFunc{
+				"GET /transaction/verify/ref-1": func(w http.ResponseWriter, r *http.Request) {
+					body, _ := json.Marshal(map[string]interface{}{
+						"status":  true,
+						"message": "Verification successful",
+						"data": map[string]interface{}{
+							"status":    tt.status,
+							"reference": "ref-1",
+							"amount":    tt.amount,
+							"currency":  tt.currency,
+						},
+					})
+
+					w.Write(body)
+				},
+			})
+
+			resp, err := provider.Verify("ref-1", money.New(500000, "NGN"))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !resp.Status || resp.PaymentStatus != tt.paymentStatus {
+				t.Errorf("got %+v, want payment status %s", resp, tt.paymentStatus)
+			}
+		})
+	}
+}
+
+func TestRefund(t *testing.T) {
+	provider := newTestProvider(t, map[string]http.HandlerFunc{
+		"POST /refund": func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+
+			if body["transaction"] != "ref-1" || body["amount"] != float64(20000) {
+				t.Errorf("refunded with %v", body)
+			}
+
+			writeJSON(w, `{"status": true, "message": "Refund has been queued for processing", "data": {"id": 3018284, "amount": 20000, "status": "pending"}}`)
+		},
+	})
+
+	resp, err := provider.Refund(payment_gateway_dto.RefundDTO{Reference: "ref-1", Amount: money.New(20000, "NGN")})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.Status || resp.GatewayReference != "3018284" || resp.RefundStatus != finance_service.RefundStatusPending {
+		t.Errorf("got %+v", resp)
+	}
+}
+
+func sign(body []byte, key string) string {
+	mac := hmac.New(sha512.New, []byte(key))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseWebhook(t *testing.T) {
+	provider := newTestProvider(t, nil)
+
+	charge := []byte(`{"event": "charge.success", "data": {"reference": "ref-1", "status": "success", "amount": 500000}}`)
+	refund := []byte(`{"event": "refund.processed", "data": {"id": 3018284, "status": "processed", "transaction_reference": "ref-1", "amount": "20000", "currency": "NGN"}}`)
+
+	event, err := provider.ParseWebhook(charge, map[string]string{"x-paystack-signature": sign(charge, testSecretKey)})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.Reference != "ref-1" || event.PaymentStatus != finance_service.TransactionStatusSuccess {
+		t.Errorf("charge: got %+v", event)
+	}
+
+	event, err = provider.ParseWebhook(refund, map[string]string{"x-paystack-signature": sign(refund, testSecretKey)})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.Reference != "ref-1" || event.RefundReference != "3018284" || event.RefundStatus != finance_service.RefundStatusProcessed {
+		t.Errorf("refund: got %+v", event)
+	}
+
+	for name, signature := range map[string]string{"missing": "", "wrong key": sign(charge, "another key")} {
+		if _, err := provider.ParseWebhook(charge, map[string]string{"x-paystack-signature": signature}); !errors.Is(err, payment_gateway_service.ErrInvalidWebhookSignature) {
+			t.Errorf("%s signature: got %v", name, err)
+		}
+	}
+}
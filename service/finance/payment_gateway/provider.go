@@ -0,0 +1,22 @@
+package payment_gateway_service
+
+import (
+	"time"
+
+	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+)
+
+// Provider is implemented by every payment gateway integration.
+// Each provider lives in its own package and maps the gateway's API onto the shared payment_gateway_dto types.
+type Provider interface {
+	Name() string
+	Initialize(paymentDto payment_gateway_dto.PaymentInitializationDTO) (payment_gateway_dto.PaymentInitializationResponseDTO, error)
+	// Verify reports a payment the gateway took for another amount or currency than amount as failed.
+	Verify(reference string, amount money.Money) (payment_gateway_dto.PaymentVerifyResponseDTO, error)
+	Refund(refundDto payment_gateway_dto.RefundDTO) (payment_gateway_dto.RefundResponseDTO, error)
+	// ParseWebhook validates and decodes a webhook delivery. Header names are lower-cased.
+	ParseWebhook(body []byte, headers map[string]string) (payment_gateway_dto.WebhookEventDTO, error)
+	// ListTransactions returns every payment the gateway has from from up to to, all of its pages.
+	ListTransactions(from time.Time, to time.Time) ([]payment_gateway_dto.GatewayTransactionDTO, error)
+}
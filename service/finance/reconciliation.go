@@ -0,0 +1,328 @@
+package finance_service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+	finance_repository "github.com/developer-afo/instashop-ecommerce-api/repository/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+)
+
+var (
+	ReconciliationMissingLocally    = "missing_locally"
+	ReconciliationMissingAtProvider = "missing_at_provider"
+	ReconciliationAmountDiffers     = "amount_differs"
+	ReconciliationStatusDiffers     = "status_differs"
+
+	// ReconciliationMargin is how far past the period the gateways are listed, so a payment the gateway dated a little
+	// before or after us is still matched.
+	ReconciliationMargin = 24 * time.Hour
+
+	ErrInvalidReconciliationPeriod = errors.New("the reconciliation period must end after it starts")
+)
+
+// OrderPaymentVerifierInterface settles an order's payment after asking its gateway for the status,
+// the order service implements it.
+type OrderPaymentVerifierInterface interface {
+	VerifyOrderPayment(reference string) error
+}
+
+// ReconciliationServiceInterface checks our transactions against the payments the gateways hold.
+type ReconciliationServiceInterface interface {
+	Reconcile(from time.Time, to time.Time) (dto.ReconciliationReportDTO, error)
+	ReconcileDue(every time.Duration) (bool, error)
+	FindReports(pageable repository.Pageable) ([]dto.ReconciliationReportDTO, repository.Pagination, error)
+	FindReportById(id uuid.UUID) (dto.ReconciliationReportDTO, error)
+}
+
+type reconciliationService struct {
+	database                 database.DatabaseInterface
+	reconciliationRepository finance_repository.ReconciliationRepositoryInterface
+	transactionRepository    finance_repository.TransactionRepositoryInterface
+	paymentGatewayService    payment_gateway_service.PaymentGatewayServiceInterface
+	walletService            WalletServiceInterface
+	orderPaymentVerifier     OrderPaymentVerifierInterface
+}
+
+func NewReconciliationService(
+	database database.DatabaseInterface,
+	reconciliationRepository finance_repository.ReconciliationRepositoryInterface,
+	transactionRepository finance_repository.TransactionRepositoryInterface,
+	paymentGatewayService payment_gateway_service.PaymentGatewayServiceInterface,
+	walletService WalletServiceInterface,
+	orderPaymentVerifier OrderPaymentVerifierInterface,
+) ReconciliationServiceInterface {
+	return &reconciliationService{
+		database:                 database,
+		reconciliationRepository: reconciliationRepository,
+		transactionRepository:    transactionRepository,
+		paymentGatewayService:    paymentGatewayService,
+		walletService:            walletService,
+		orderPaymentVerifier:     orderPaymentVerifier,
+	}
+}
+
+func (s *reconciliationService) ConvertToDTO(report models.ReconciliationReport) (reportDto dto.ReconciliationReportDTO) {
+
+	reportDto.ID = report.ID
+	reportDto.FromDate = report.FromDate
+	reportDto.ToDate = report.ToDate
+	reportDto.Matched = report.Matched
+	reportDto.Discrepancies = report.Discrepancies
+	reportDto.Healed = report.Healed
+	reportDto.Errors = []string{}
+	if report.Errors != "" {
+		reportDto.Errors = strings.Split(report.Errors, "\n")
+	}
+	for _, item := range report.Items {
+		reportDto.Items = append(reportDto.Items, dto.ReconciliationItemDTO{
+			DTO:            dto.DTO{ID: item.ID, CreatedAt: item.CreatedAt, UpdatedAt: item.UpdatedAt},
+			Gateway:        item.Gateway,
+			Reference:      item.Reference,
+			TransactionID:  item.TransactionID,
+			Kind:           item.Kind,
+			LocalStatus:    item.LocalStatus,
+			LocalAmount:    item.LocalAmount,
+			ProviderStatus: item.ProviderStatus,
+			ProviderAmount: item.ProviderAmount,
+			Healed:         item.Healed,
+			Note:           item.Note,
+		})
+	}
+	reportDto.CreatedAt = report.CreatedAt
+	reportDto.UpdatedAt = report.UpdatedAt
+
+	return reportDto
+}
+
+// Reconcile implements ReconciliationServiceInterface for the payments made from from up to to through every registered
+// gateway, disabled ones included, and saves the report. Payments are matched on our reference.
+// A pending payment the gateway has settled is healed through the same verification as the webhook, nothing else is
+// changed: a payment missing on either side, a different amount or a payment we settled differently is only reported.
+func (s *reconciliationService) Reconcile(from time.Time, to time.Time) (dto.ReconciliationReportDTO, error) {
+	var report models.ReconciliationReport
+	var errs []string
+
+	if !to.After(from) {
+		return dto.ReconciliationReportDTO{}, ErrInvalidReconciliationPeriod
+	}
+
+	report.FromDate = from
+	report.ToDate = to
+
+	for _, gateway := range s.paymentGatewayService.Gateways() {
+		if err := s.reconcileGateway(&report, gateway, from, to); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", gateway, err.Error()))
+		}
+	}
+
+	report.Errors = strings.Join(errs, "\n")
+	report.Discrepancies = len(report.Items)
+
+	for _, item := range report.Items {
+		if item.Healed {
+			report.Healed++
+		}
+	}
+
+	report, err := s.reconciliationRepository.CreateReport(report)
+
+	return s.ConvertToDTO(report), err
+}
+
+// ReconcileDue implements ReconciliationServiceInterface. It reconciles the last two days, so a payment settled after
+// the previous run is picked up, when no report reaches within every of now. Reports are kept in the database, so
+// the runs keep their pace across restarts. It returns whether it reconciled.
+func (s *reconciliationService) ReconcileDue(every time.Duration) (bool, error) {
+	now := time.Now()
+
+	latest, err := s.reconciliationRepository.FindLatestReport()
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	// a report made before its period ended only reaches when it was made
+	reached := latest.ToDate
+
+	if latest.CreatedAt.Before(reached) {
+		reached = latest.CreatedAt
+	}
+
+	if err == nil && now.Sub(reached) < every {
+		return false, nil
+	}
+
+	_, err = s.Reconcile(now.AddDate(0, 0, -2), now)
+
+	return err == nil, err
+}
+
+func (s *reconciliationService) reconcileGateway(report *models.ReconciliationReport, gateway string, from time.Time, to time.Time) error {
+	payments, err := s.paymentGatewayService.ListTransactions(gateway, from.Add(-ReconciliationMargin), to.Add(ReconciliationMargin))
+
+	if err != nil {
+		return err
+	}
+
+	transactions, err := s.transactionRepository.FindGatewayPayments(gateway, from, to)
+
+	if err != nil {
+		return err
+	}
+
+	local := map[string]models.Transaction{}
+	for _, transaction := range transactions {
+		local[transaction.Reference] = transaction
+	}
+
+	var items []models.ReconciliationItem
+
+	for _, payment := range payments {
+		transaction, ok := local[payment.Reference]
+		delete(local, payment.Reference)
+
+		// payments in the margin are only there to be matched, a later run reports them
+		inPeriod := !payment.CreatedAt.Before(from) && payment.CreatedAt.Before(to)
+
+		if !ok && !inPeriod {
+			continue
+		}
+
+		if !ok {
+			// we may have dated it outside the period
+			transaction, err = s.transactionRepository.FindTransactionByReference(payment.Reference)
+
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				items = append(items, reconciliationItem(gateway, ReconciliationMissingLocally, nil, &payment))
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		item, matched := s.compare(gateway, transaction, payment)
+
+		if matched {
+			report.Matched++
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	for _, transaction := range local {
+		transaction := transaction
+
+		items = append(items, reconciliationItem(gateway, ReconciliationMissingAtProvider, &transaction, nil))
+	}
+
+	report.Items = append(report.Items, items...)
+
+	return nil
+}
+
+// compare matches a transaction with the gateway's payment, and heals a pending transaction the gateway has settled.
+func (s *reconciliationService) compare(gateway string, transaction models.Transaction, payment payment_gateway_dto.GatewayTransactionDTO) (models.ReconciliationItem, bool) {
+	if transaction.Amount != payment.Amount {
+		return reconciliationItem(gateway, ReconciliationAmountDiffers, &transaction, &payment), false
+	}
+
+	if transaction.Status == payment.Status {
+		return models.ReconciliationItem{}, true
+	}
+
+	item := reconciliationItem(gateway, ReconciliationStatusDiffers, &transaction, &payment)
+
+	if transaction.Status != TransactionStatusPending || payment.Status == TransactionStatusPending {
+		return item, false
+	}
+
+	var err error
+
+	if transaction.ShortDesc == WalletTopUpShortDesc {
+		err = s.walletService.VerifyTopUp(transaction.Reference)
+	} else {
+		err = s.orderPaymentVerifier.VerifyOrderPayment(transaction.Reference)
+	}
+
+	item.Healed = err == nil
+	if err != nil {
+		item.Note = err.Error()
+	}
+
+	return item, false
+}
+
+// reconciliationItem records a discrepancy, transaction or payment is nil when that side does not have it.
+func reconciliationItem(gateway string, kind string, transaction *models.Transaction, payment *payment_gateway_dto.GatewayTransactionDTO) models.ReconciliationItem {
+	item := models.ReconciliationItem{Gateway: gateway, Kind: kind}
+
+	if transaction != nil {
+		transactionId := transaction.ID
+
+		item.Reference = transaction.Reference
+		item.TransactionID = &transactionId
+		item.LocalStatus = transaction.Status
+		item.LocalAmount = transaction.Amount
+		item.LocalCurrency = transaction.Currency
+	}
+
+	if payment != nil {
+		item.Reference = payment.Reference
+		item.ProviderStatus = payment.Status
+		item.ProviderAmount = payment.Amount
+		item.ProviderCurrency = payment.Amount.Currency
+	}
+
+	// the side that is missing is recorded in the currency of the other
+	if transaction == nil {
+		item.LocalCurrency = item.ProviderCurrency
+	}
+
+	if payment == nil {
+		item.ProviderCurrency = item.LocalCurrency
+	}
+
+	return item
+}
+
+// FindReports implements ReconciliationServiceInterface, without the reports' items.
+func (s *reconciliationService) FindReports(pageable repository.Pageable) ([]dto.ReconciliationReportDTO, repository.Pagination, error) {
+	reports := []dto.ReconciliationReportDTO{}
+
+	reportModels, pagination, err := s.reconciliationRepository.FindReports(pageable)
+
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	for _, report := range reportModels {
+		reports = append(reports, s.ConvertToDTO(report))
+	}
+
+	return reports, pagination, nil
+}
+
+// FindReportById implements ReconciliationServiceInterface.
+func (s *reconciliationService) FindReportById(id uuid.UUID) (dto.ReconciliationReportDTO, error) {
+	report, err := s.reconciliationRepository.FindReportById(id)
+
+	if err != nil {
+		return dto.ReconciliationReportDTO{}, err
+	}
+
+	return s.ConvertToDTO(report), nil
+}
@@ -1,9 +1,12 @@
 package finance_service
 
 import (
+	"errors"
+
 	"github.com/google/uuid"
 
 	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/helper"
 	"github.com/developer-afo/instashop-ecommerce-api/models"
 	"github.com/developer-afo/instashop-ecommerce-api/repository"
@@ -21,6 +24,12 @@ var (
 	TransactionMethodTransfer = "transfer"
 	TransactionVendorPayStack = "paystack"
 	TransactionVendorMazimart = "instashop"
+
+	RefundStatusPending   = "pending"
+	RefundStatusProcessed = "processed"
+	RefundStatusFailed    = "failed"
+
+	ErrTransactionAlreadyProcessed = errors.New("transaction has already been processed")
 )
 
 type TransactionServiceInterface interface {
@@ -32,14 +41,39 @@ type TransactionServiceInterface interface {
 	ConfirmTransaction(transactionId string) (dto.TransactionDTO, error)
 	FailTransaction(transactionId string) (dto.TransactionDTO, error)
 	ConvertToDTO(transaction models.Transaction) dto.TransactionDTO
+	WithTx(tx database.DatabaseInterface) TransactionServiceInterface
 }
 
+// transactionService posts every transaction that succeeds to the ledger, in the same database transaction.
 type transactionService struct {
+	database              database.DatabaseInterface
 	transactionRepository finance_repository.TransactionRepositoryInterface
+	ledgerService         LedgerServiceInterface
+}
+
+func NewTransactionService(
+	database database.DatabaseInterface,
+	transactionRepository finance_repository.TransactionRepositoryInterface,
+	ledgerService LedgerServiceInterface,
+) TransactionServiceInterface {
+	return &transactionService{
+		database:              database,
+		transactionRepository: transactionRepository,
+		ledgerService:         ledgerService,
+	}
+}
+
+// WithTx implements TransactionServiceInterface.
+func (t *transactionService) WithTx(tx database.DatabaseInterface) TransactionServiceInterface {
+	return t.withTx(tx)
 }
 
-func NewTransactionService(transactionRepository finance_repository.TransactionRepositoryInterface) TransactionServiceInterface {
-	return &transactionService{transactionRepository: transactionRepository}
+func (t *transactionService) withTx(tx database.DatabaseInterface) *transactionService {
+	return &transactionService{
+		database:              tx,
+		transactionRepository: t.transactionRepository.WithTx(tx),
+		ledgerService:         t.ledgerService.WithTx(tx),
+	}
 }
 
 func (t *transactionService) ConvertToDTO(transaction models.Transaction) (transactionDto dto.TransactionDTO) {
@@ -54,6 +88,7 @@ func (t *transactionService) ConvertToDTO(transaction models.Transaction) (trans
 	transactionDto.Status = transaction.Status
 	transactionDto.Method = transaction.Method
 	transactionDto.Vendor = transaction.Vendor
+	transactionDto.ParentID = transaction.ParentID
 	transactionDto.CreatedAt = transaction.CreatedAt
 	transactionDto.UpdatedAt = transaction.UpdatedAt
 	transactionDto.DeletedAt = transaction.DeletedAt.Time
@@ -66,6 +101,8 @@ func (t *transactionService) ConvertToModel(transactionDto dto.TransactionDTO) (
 	transaction.ID = transactionDto.ID
 	transaction.UserID = transactionDto.UserID
 	transaction.Amount = transactionDto.Amount
+	transaction.Currency = CurrencyCode(transactionDto.Amount.Currency)
+	transaction.Amount.Currency = transaction.Currency
 	transaction.Type = transactionDto.Type
 	transaction.Reference = transactionDto.Reference
 	transaction.Description = transactionDto.Description
@@ -73,6 +110,7 @@ func (t *transactionService) ConvertToModel(transactionDto dto.TransactionDTO) (
 	transaction.Status = transactionDto.Status
 	transaction.Method = transactionDto.Method
 	transaction.Vendor = transactionDto.Vendor
+	transaction.ParentID = transactionDto.ParentID
 	transaction.CreatedAt = transactionDto.CreatedAt
 	transaction.UpdatedAt = transactionDto.UpdatedAt
 	transaction.DeletedAt.Time = transactionDto.DeletedAt
@@ -136,7 +174,20 @@ func (t *transactionService) CreateTransaction(transaction dto.TransactionDTO) (
 	transaction.Reference = helper.Int64ToString(reference)
 
 	transactionModel := t.ConvertToModel(transaction)
-	transactionModel, err = t.transactionRepository.CreateTransaction(transactionModel)
+
+	err = t.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := t.withTx(tx)
+
+		transactionModel, err = txService.transactionRepository.CreateTransaction(transactionModel)
+
+		if err != nil || transactionModel.Status != TransactionStatusSuccess {
+			return err
+		}
+
+		_, err = txService.ledgerService.PostTransaction(t.ConvertToDTO(transactionModel))
+
+		return err
+	})
 
 	return t.ConvertToDTO(transactionModel), err
 }
@@ -152,26 +203,53 @@ func (t *transactionService) UpdateTransaction(transaction dto.TransactionDTO) (
 
 // ConfirmTransaction implements TransactionServiceInterface.
 func (t *transactionService) ConfirmTransaction(transactionId string) (dto.TransactionDTO, error) {
+	return t.settleTransaction(transactionId, TransactionStatusSuccess)
+}
+
+// FailTransaction implements TransactionServiceInterface.
+func (t *transactionService) FailTransaction(transactionId string) (dto.TransactionDTO, error) {
+	return t.settleTransaction(transactionId, TransactionStatusFailed)
+}
+
+// settleTransaction moves a pending transaction to its final status, and posts it to the ledger when it succeeded.
+// It returns ErrTransactionAlreadyProcessed when another caller settled it first.
+func (t *transactionService) settleTransaction(transactionId string, status string) (dto.TransactionDTO, error) {
 
 	transaction, err := t.FindTransactionByUUID(transactionId)
 	if err != nil {
 		return dto.TransactionDTO{}, err
 	}
 
-	transaction.Status = TransactionStatusSuccess
+	err = t.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := t.withTx(tx)
 
-	return t.UpdateTransaction(transaction)
-}
+		affected, err := txService.transactionRepository.UpdateTransactionStatus(transaction.ID, TransactionStatusPending, status)
+		if err != nil {
+			return err
+		}
 
-// FailTransaction implements TransactionServiceInterface.
-func (t *transactionService) FailTransaction(transactionId string) (dto.TransactionDTO, error) {
+		if affected == 0 {
+			return ErrTransactionAlreadyProcessed
+		}
+
+		transaction.Status = status
+
+		if status != TransactionStatusSuccess {
+			return nil
+		}
+
+		_, err = txService.ledgerService.PostTransaction(transaction)
+
+		return err
+	})
+
+	if err == ErrTransactionAlreadyProcessed {
+		return transaction, err
+	}
 
-	transaction, err := t.FindTransactionByUUID(transactionId)
 	if err != nil {
 		return dto.TransactionDTO{}, err
 	}
 
-	transaction.Status = TransactionStatusFailed
-
-	return t.UpdateTransaction(transaction)
+	return transaction, nil
 }
@@ -0,0 +1,251 @@
+package finance_service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+	finance_repository "github.com/developer-afo/instashop-ecommerce-api/repository/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+	user_service "github.com/developer-afo/instashop-ecommerce-api/service/user"
+)
+
+var (
+	WalletTopUpDescription = "Wallet top up"
+	WalletTopUpShortDesc   = "wallet_top_up"
+
+	ErrInsufficientWalletBalance = errors.New("wallet balance is not enough")
+	ErrWalletCurrency            = errors.New("the wallet only holds " + money.BaseCurrency)
+	ErrNotWalletTopUp            = errors.New("transaction is not a wallet top up")
+)
+
+// WalletServiceInterface moves money in and out of a user's wallet. The wallet is the user's transactions with
+// TransactionMethodWallet, it is always in money.BaseCurrency.
+type WalletServiceInterface interface {
+	FindBalance(userId uuid.UUID) (money.Money, error)
+	FindWalletTransactions(pageable finance_repository.TransactionPageable) ([]dto.TransactionDTO, repository.Pagination, error)
+	TopUp(userId uuid.UUID, amount money.Money, gateway string) (string, dto.TransactionDTO, error)
+	VerifyTopUp(reference string) error
+	Debit(transaction dto.TransactionDTO) (dto.TransactionDTO, error)
+	Credit(transaction dto.TransactionDTO) (dto.TransactionDTO, error)
+	WithTx(tx database.DatabaseInterface) WalletServiceInterface
+}
+
+type walletService struct {
+	database              database.DatabaseInterface
+	transactionRepository finance_repository.TransactionRepositoryInterface
+	transactionService    TransactionServiceInterface
+	paymentGatewayService payment_gateway_service.PaymentGatewayServiceInterface
+	userService           user_service.UserServiceInterface
+}
+
+func NewWalletService(
+	database database.DatabaseInterface,
+	transactionRepository finance_repository.TransactionRepositoryInterface,
+	transactionService TransactionServiceInterface,
+	paymentGatewayService payment_gateway_service.PaymentGatewayServiceInterface,
+	userService user_service.UserServiceInterface,
+) WalletServiceInterface {
+	return &walletService{
+		database:              database,
+		transactionRepository: transactionRepository,
+		transactionService:    transactionService,
+		paymentGatewayService: paymentGatewayService,
+		userService:           userService,
+	}
+}
+
+// WithTx implements WalletServiceInterface.
+func (s *walletService) WithTx(tx database.DatabaseInterface) WalletServiceInterface {
+	return s.withTx(tx)
+}
+
+func (s *walletService) withTx(tx database.DatabaseInterface) *walletService {
+	txService := *s
+
+	txService.database = tx
+	txService.transactionRepository = s.transactionRepository.WithTx(tx)
+	txService.transactionService = s.transactionService.WithTx(tx)
+
+	return &txService
+}
+
+// FindBalance implements WalletServiceInterface. Debits still waiting on their order's payment are taken off.
+func (s *walletService) FindBalance(userId uuid.UUID) (money.Money, error) {
+	return s.transactionRepository.WalletBalance(userId)
+}
+
+// FindWalletTransactions implements WalletServiceInterface.
+func (s *walletService) FindWalletTransactions(pageable finance_repository.TransactionPageable) ([]dto.TransactionDTO, repository.Pagination, error) {
+	pageable.Method = TransactionMethodWallet
+
+	return s.transactionService.FindAllTransactions(pageable)
+}
+
+// TopUp implements WalletServiceInterface and returns the gateway's payment url.
+// The credit stays pending, and out of the balance, until VerifyTopUp sees the payment succeed.
+// It is committed before the gateway is called, so a webhook that comes back quickly finds it, and is failed
+// when the gateway cannot start the payment.
+func (s *walletService) TopUp(userId uuid.UUID, amount money.Money, gateway string) (string, dto.TransactionDTO, error) {
+	var transaction dto.TransactionDTO
+
+	if amount.Currency != money.BaseCurrency {
+		return "", transaction, ErrWalletCurrency
+	}
+
+	if !s.paymentGatewayService.IsEnabled(gateway) {
+		return "", transaction, payment_gateway_service.ErrPaymentGatewayDisabled
+	}
+
+	user, err := s.userService.FindUserById(userId.String())
+
+	if err != nil {
+		return "", transaction, err
+	}
+
+	transaction, err = s.transactionService.CreateTransaction(dto.TransactionDTO{
+		UserID:      userId,
+		Amount:      amount,
+		Type:        TransactionTypeCredit,
+		Description: WalletTopUpDescription,
+		ShortDesc:   WalletTopUpShortDesc,
+		Status:      TransactionStatusPending,
+		Method:      TransactionMethodWallet,
+		Vendor:      gateway,
+	})
+
+	if err != nil {
+		return "", transaction, err
+	}
+
+	initialize, err := s.paymentGatewayService.InitializePayment(payment_gateway_dto.PaymentInitializationDTO{
+		Amount:    transaction.Amount,
+		Email:     user.Email,
+		Reference: transaction.Reference,
+		Gateway:   gateway,
+	})
+
+	if err == nil && !initialize.Status {
+		err = payment_gateway_service.ErrPaymentInitialization
+	}
+
+	if err != nil {
+		// reconciliation reports a payment the gateway took for it anyway
+		_, _ = s.transactionService.FailTransaction(transaction.ID.String())
+
+		return "", transaction, err
+	}
+
+	return initialize.PaymentURL, transaction, nil
+}
+
+// VerifyTopUp implements WalletServiceInterface.
+// It is safe to call repeatedly for the same reference, e.g. from a client poll and a gateway webhook.
+func (s *walletService) VerifyTopUp(reference string) error {
+	transaction, err := s.transactionService.FindTransactionByReference(reference)
+
+	if err != nil {
+		return err
+	}
+
+	if transaction.ShortDesc != WalletTopUpShortDesc {
+		return ErrNotWalletTopUp
+	}
+
+	// already settled by an earlier verification or webhook delivery
+	if transaction.Status != TransactionStatusPending {
+		return nil
+	}
+
+	gatewayResp, err := s.paymentGatewayService.VerifyPayment(reference, transaction.Amount, transaction.Vendor)
+
+	if err != nil {
+		return err
+	}
+
+	if !gatewayResp.Status {
+		return fmt.Errorf("payment verification failed: %s", gatewayResp.Message)
+	}
+
+	switch gatewayResp.PaymentStatus {
+	case TransactionStatusPending:
+		return fmt.Errorf("payment verification is still pending: %s", gatewayResp.Message)
+	case TransactionStatusSuccess:
+		_, err = s.transactionService.ConfirmTransaction(transaction.ID.String())
+	default:
+		_, err = s.transactionService.FailTransaction(transaction.ID.String())
+	}
+
+	if err == ErrTransactionAlreadyProcessed {
+		return nil
+	}
+
+	return err
+}
+
+// Debit implements WalletServiceInterface. It takes transaction.Amount out of the wallet, as a pending debit unless
+// a status is given, and returns ErrInsufficientWalletBalance when the wallet does not hold it.
+// The wallet is locked while its balance is checked, so concurrent debits cannot overdraw it.
+func (s *walletService) Debit(transaction dto.TransactionDTO) (dto.TransactionDTO, error) {
+	var debit dto.TransactionDTO
+
+	if transaction.Amount.Currency != money.BaseCurrency {
+		return debit, ErrWalletCurrency
+	}
+
+	err := s.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := s.withTx(tx)
+
+		if err := txService.transactionRepository.LockWallet(transaction.UserID); err != nil {
+			return err
+		}
+
+		balance, err := txService.transactionRepository.WalletBalance(transaction.UserID)
+
+		if err != nil {
+			return err
+		}
+
+		if balance.Amount < transaction.Amount.Amount {
+			return ErrInsufficientWalletBalance
+		}
+
+		debit, err = txService.transactionService.CreateTransaction(walletTransaction(transaction, TransactionTypeDebit))
+
+		return err
+	})
+
+	return debit, err
+}
+
+// Credit implements WalletServiceInterface. It puts transaction.Amount in the wallet, the credit is pending unless
+// a status is given and only counts once it succeeds.
+func (s *walletService) Credit(transaction dto.TransactionDTO) (dto.TransactionDTO, error) {
+	if transaction.Amount.Currency != money.BaseCurrency {
+		return dto.TransactionDTO{}, ErrWalletCurrency
+	}
+
+	return s.transactionService.CreateTransaction(walletTransaction(transaction, TransactionTypeCredit))
+}
+
+// walletTransaction fills in what every wallet movement of transactionType has in common.
+func walletTransaction(transaction dto.TransactionDTO, transactionType string) dto.TransactionDTO {
+	transaction.Type = transactionType
+	transaction.Method = TransactionMethodWallet
+
+	if transaction.Status == "" {
+		transaction.Status = TransactionStatusPending
+	}
+
+	if transaction.Vendor == "" {
+		transaction.Vendor = TransactionVendorMazimart
+	}
+
+	return transaction
+}
@@ -0,0 +1,345 @@
+package order_service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
+)
+
+var (
+	CartWarningUnavailable       = "unavailable"
+	CartWarningOutOfStock        = "out_of_stock"
+	CartWarningInsufficientStock = "insufficient_stock"
+
+	ErrCartEmpty        = errors.New("cart is empty")
+	ErrCartItemNotFound = errors.New("product is not in the cart")
+	ErrProductNotFound  = errors.New("product is not found on this platform")
+)
+
+// CartServiceInterface finds the cart of userId, or the guest cart with token when userId is uuid.Nil.
+type CartServiceInterface interface {
+	FindCart(userId uuid.UUID, token string) (dto.CartDTO, error)
+	AddCartItem(userId uuid.UUID, token string, productId uuid.UUID, variantId *uuid.UUID, quantity int) (dto.CartDTO, error)
+	UpdateCartItem(userId uuid.UUID, token string, productId uuid.UUID, variantId *uuid.UUID, quantity int) (dto.CartDTO, error)
+	RemoveCartItem(userId uuid.UUID, token string, productId uuid.UUID, variantId *uuid.UUID) (dto.CartDTO, error)
+	ClearCart(userId uuid.UUID, token string) error
+	MergeCart(token string, userId uuid.UUID) error
+	CheckoutItems(userId uuid.UUID) ([]dto.CreateOrderItemDTO, error)
+	WithTx(tx database.DatabaseInterface) CartServiceInterface
+}
+
+type cartService struct {
+	database       database.DatabaseInterface
+	cartRepository order_repository.CartRepositoryInterface
+	productService core_service.ProductServiceInterface
+}
+
+func NewCartService(
+	database database.DatabaseInterface,
+	cartRepository order_repository.CartRepositoryInterface,
+	productService core_service.ProductServiceInterface,
+) CartServiceInterface {
+	return &cartService{
+		database:       database,
+		cartRepository: cartRepository,
+		productService: productService,
+	}
+}
+
+// WithTx implements CartServiceInterface.
+func (s *cartService) WithTx(tx database.DatabaseInterface) CartServiceInterface {
+	return s.withTx(tx)
+}
+
+func (s *cartService) withTx(tx database.DatabaseInterface) *cartService {
+	return &cartService{
+		database:       tx,
+		cartRepository: s.cartRepository.WithTx(tx),
+		productService: s.productService.WithTx(tx),
+	}
+}
+
+// ConvertToDTO prices the cart with the current price and stock of its products.
+func (s *cartService) ConvertToDTO(cart models.Cart) dto.CartDTO {
+	var cartDto dto.CartDTO
+
+	cartDto.ID = cart.ID
+	cartDto.UserUUID = cart.UserID
+	cartDto.Token = cart.Token
+	cartDto.Items = []dto.CartItemDTO{}
+	cartDto.TotalPrice = money.New(0, money.BaseCurrency)
+	for _, item := range cart.Items {
+		itemDto := dto.CartItemDTO{
+			DTO:         dto.DTO{ID: item.ID, CreatedAt: item.CreatedAt, UpdatedAt: item.UpdatedAt},
+			CartUUID:    item.CartID,
+			ProductUUID: item.ProductID,
+			VariantUUID: item.VariantID,
+			Quantity:    item.Quantity,
+		}
+
+		// a variant that was deleted, or a product that has variants since, can no longer be ordered
+		product, variant, err := findOrderItemProduct(s.productService, item.ProductID.String(), cartItemVariantId(item))
+
+		switch {
+		case err != nil:
+			itemDto.Warning = CartWarningUnavailable
+		case core_service.VariantStock(product, variant) <= 0:
+			itemDto.Warning = CartWarningOutOfStock
+		case core_service.VariantStock(product, variant) < item.Quantity:
+			itemDto.Warning = CartWarningInsufficientStock
+		}
+
+		if err == nil {
+			itemDto.Product = product
+			itemDto.Variant = variant
+			itemDto.UnitPrice = ProductUnitPrice(product, variant)
+			itemDto.Price = itemDto.UnitPrice.Mul(item.Quantity)
+			cartDto.TotalPrice = cartDto.TotalPrice.Add(itemDto.Price)
+		}
+
+		cartDto.Items = append(cartDto.Items, itemDto)
+	}
+	cartDto.CreatedAt = cart.CreatedAt
+	cartDto.UpdatedAt = cart.UpdatedAt
+
+	return cartDto
+}
+
+func cartItemVariantId(item models.CartItem) string {
+	if item.VariantID == nil {
+		return ""
+	}
+
+	return item.VariantID.String()
+}
+
+// findCart returns the cart of userId, or the guest cart with token.
+func (s *cartService) findCart(userId uuid.UUID, token string) (models.Cart, error) {
+	if userId != uuid.Nil {
+		return s.cartRepository.FindCartByUserId(userId)
+	}
+
+	if token == "" {
+		return models.Cart{}, gorm.ErrRecordNotFound
+	}
+
+	return s.cartRepository.FindGuestCartByToken(token)
+}
+
+// findOrCreateCart returns the cart of userId or the guest cart with token, and starts one when there is none.
+// A new guest cart gets a new token, the one the client sent may belong to a cart that was merged.
+func (s *cartService) findOrCreateCart(userId uuid.UUID, token string) (models.Cart, error) {
+	cart, err := s.findCart(userId, token)
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return cart, err
+	}
+
+	cart = models.Cart{Token: uuid.NewString()}
+
+	if userId != uuid.Nil {
+		cart.UserID = &userId
+	}
+
+	return s.cartRepository.CreateCart(cart)
+}
+
+// FindCart implements CartServiceInterface. A user or guest without a cart gets an empty one.
+func (s *cartService) FindCart(userId uuid.UUID, token string) (dto.CartDTO, error) {
+	cart, err := s.findCart(userId, token)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.ConvertToDTO(models.Cart{}), nil
+	}
+
+	if err != nil {
+		return dto.CartDTO{}, err
+	}
+
+	return s.ConvertToDTO(cart), nil
+}
+
+// AddCartItem implements CartServiceInterface.
+// The quantity is added to what is already in the cart. Stock is only checked at checkout, the cart warns about it.
+// A product with variants is added as one of its variants.
+func (s *cartService) AddCartItem(userId uuid.UUID, token string, productId uuid.UUID, variantId *uuid.UUID, quantity int) (dto.CartDTO, error) {
+	var cart models.Cart
+
+	item := models.CartItem{ProductID: productId, VariantID: variantId, Quantity: quantity}
+
+	if _, err := s.productService.FindProductByUUID(productId.String()); err != nil {
+		return dto.CartDTO{}, ErrProductNotFound
+	}
+
+	if _, _, err := findOrderItemProduct(s.productService, productId.String(), cartItemVariantId(item)); err != nil {
+		return dto.CartDTO{}, err
+	}
+
+	err := s.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := s.withTx(tx)
+
+		var err error
+
+		cart, err = txService.findOrCreateCart(userId, token)
+
+		if err != nil {
+			return err
+		}
+
+		item.CartID = cart.ID
+
+		return txService.cartRepository.AddCartItem(item)
+	})
+
+	if err != nil {
+		return dto.CartDTO{}, err
+	}
+
+	return s.findCartByOwner(userId, cart.Token)
+}
+
+// UpdateCartItem implements CartServiceInterface. A quantity of 0 removes the product from the cart.
+func (s *cartService) UpdateCartItem(userId uuid.UUID, token string, productId uuid.UUID, variantId *uuid.UUID, quantity int) (dto.CartDTO, error) {
+	if quantity == 0 {
+		return s.RemoveCartItem(userId, token, productId, variantId)
+	}
+
+	cart, err := s.findCart(userId, token)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.CartDTO{}, ErrCartItemNotFound
+	}
+
+	if err != nil {
+		return dto.CartDTO{}, err
+	}
+
+	affected, err := s.cartRepository.UpdateCartItemQuantity(cart.ID, productId, variantId, quantity)
+
+	if err != nil {
+		return dto.CartDTO{}, err
+	}
+
+	if affected == 0 {
+		return dto.CartDTO{}, ErrCartItemNotFound
+	}
+
+	return s.findCartByOwner(userId, cart.Token)
+}
+
+// RemoveCartItem implements CartServiceInterface.
+func (s *cartService) RemoveCartItem(userId uuid.UUID, token string, productId uuid.UUID, variantId *uuid.UUID) (dto.CartDTO, error) {
+	cart, err := s.findCart(userId, token)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.ConvertToDTO(models.Cart{}), nil
+	}
+
+	if err != nil {
+		return dto.CartDTO{}, err
+	}
+
+	if err := s.cartRepository.DeleteCartItem(cart.ID, productId, variantId); err != nil {
+		return dto.CartDTO{}, err
+	}
+
+	return s.findCartByOwner(userId, cart.Token)
+}
+
+func (s *cartService) findCartByOwner(userId uuid.UUID, token string) (dto.CartDTO, error) {
+	cart, err := s.findCart(userId, token)
+
+	if err != nil {
+		return dto.CartDTO{}, err
+	}
+
+	return s.ConvertToDTO(cart), nil
+}
+
+// ClearCart implements CartServiceInterface.
+func (s *cartService) ClearCart(userId uuid.UUID, token string) error {
+	cart, err := s.findCart(userId, token)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return s.cartRepository.DeleteCartItems(cart.ID)
+}
+
+// MergeCart implements CartServiceInterface.
+// The items of the guest cart with token are added to the cart of userId and the guest cart is deleted.
+func (s *cartService) MergeCart(token string, userId uuid.UUID) error {
+	return s.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := s.withTx(tx)
+
+		guestCart, err := txService.cartRepository.FindGuestCartByToken(token)
+
+		// nothing to merge, the cart was merged already or never existed
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		cart, err := txService.findOrCreateCart(userId, "")
+
+		if err != nil {
+			return err
+		}
+
+		for _, item := range guestCart.Items {
+			err := txService.cartRepository.AddCartItem(models.CartItem{
+				CartID:    cart.ID,
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Quantity:  item.Quantity,
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return txService.cartRepository.DeleteCart(guestCart.ID)
+	})
+}
+
+// CheckoutItems implements CartServiceInterface. It returns the items of the user's cart to place an order with.
+func (s *cartService) CheckoutItems(userId uuid.UUID) ([]dto.CreateOrderItemDTO, error) {
+	var items []dto.CreateOrderItemDTO
+
+	cart, err := s.cartRepository.FindCartByUserId(userId)
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	for _, item := range cart.Items {
+		items = append(items, dto.CreateOrderItemDTO{
+			ProductUUID: item.ProductID.String(),
+			VariantUUID: cartItemVariantId(item),
+			Quantity:    item.Quantity,
+		})
+	}
+
+	if len(items) == 0 {
+		return nil, ErrCartEmpty
+	}
+
+	return items, nil
+}
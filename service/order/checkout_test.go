@@ -0,0 +1,301 @@
+package order_service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/config"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/seed"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	core_repository "github.com/developer-afo/instashop-ecommerce-api/repository/core"
+	finance_repository "github.com/developer-afo/instashop-ecommerce-api/repository/finance"
+	notification_repository "github.com/developer-afo/instashop-ecommerce-api/repository/notification"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+	user_repository "github.com/developer-afo/instashop-ecommerce-api/repository/user"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+	user_service "github.com/developer-afo/instashop-ecommerce-api/service/user"
+)
+
+// testDatabase connects to TEST_DB_NAME with the DB_ variables and migrates it. The tests that need Postgres are
+// skipped without it, as they write to the database.
+func testDatabase(t *testing.T) database.DatabaseInterface {
+	t.Helper()
+
+	name := os.Getenv("TEST_DB_NAME")
+	if name == "" && os.Getenv("CI") != "" {
+		t.Fatal("TEST_DB_NAME is not set, CI runs the database tests")
+	}
+
+	if name == "" {
+		t.Skip("TEST_DB_NAME is not set")
+	}
+
+	env := constants.GetEnv()
+	env.DB_NAME = name
+
+	db := database.StartDatabaseClient(env)
+
+	database.MigrationDir = filepath.Join("..", "..", "migrations")
+	database.Migrate(db)
+
+	files, _ := filepath.Glob(filepath.Join(database.MigrationDir, "*.sql"))
+
+	var applied int64
+	db.Connection().Model(&database.MigrationRecord{}).Count(&applied)
+
+	if int(applied) != len(files) {
+		t.Fatalf("%d of %d migrations applied", applied, len(files))
+	}
+
+	seed.NewSeeder(db).Seed()
+
+	return db
+}
+
+// fakeGateway stands in for a payment gateway, Initialize fails while fail is set.
+type fakeGateway struct {
+	mu   sync.Mutex
+	fail bool
+}
+
+func (g *fakeGateway) Name() string { return "fakepay" }
+
+func (g *fakeGateway) Initialize(paymentDto payment_gateway_dto.PaymentInitializationDTO) (payment_gateway_dto.PaymentInitializationResponseDTO, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.fail {
+		return payment_gateway_dto.PaymentInitializationResponseDTO{}, errors.New("gateway is down")
+	}
+
+	return payment_gateway_dto.PaymentInitializationResponseDTO{Status: true, PaymentURL: "https://pay.test/" + paymentDto.Reference}, nil
+}
+
+func (g *fakeGateway) Verify(reference string, amount money.Money) (payment_gateway_dto.PaymentVerifyResponseDTO, error) {
+	return payment_gateway_dto.PaymentVerifyResponseDTO{Status: true, PaymentStatus: finance_service.TransactionStatusPending}, nil
+}
+
+func (g *fakeGateway) Refund(refundDto payment_gateway_dto.RefundDTO) (payment_gateway_dto.RefundResponseDTO, error) {
+	return payment_gateway_dto.RefundResponseDTO{}, errors.New("not supported")
+}
+
+func (g *fakeGateway) ParseWebhook(body []byte, headers map[string]string) (payment_gateway_dto.WebhookEventDTO, error) {
+	return payment_gateway_dto.WebhookEventDTO{}, errors.New("not supported")
+}
+
+func (g *fakeGateway) ListTransactions(from time.Time, to time.Time) ([]payment_gateway_dto.GatewayTransactionDTO, error) {
+	return nil, nil
+}
+
+// newTestOrderService builds the order service the way the order router does, paying through gateway.
+func newTestOrderService(db database.DatabaseInterface, gateway payment_gateway_service.Provider) OrderServiceInterface {
+	env := constants.Env{}
+
+	userRepository := user_repository.NewUserRepository(db)
+	orderRepository := order_repository.NewOrderRepository(db)
+	productRepository := core_repository.NewProductRepository(db)
+	transactionRepository := finance_repository.NewTransactionRepository(db)
+
+	emailService := service.NewEmailService(config.NewEmail(env), notification_repository.NewOutboxEmailRepository(db))
+	productService := core_service.NewProductService(db, productRepository, core_repository.NewCategoryRepository(db), core_service.NewImageService(core_repository.NewImageRepository(db)))
+	inventoryService := core_service.NewInventoryService(productRepository, core_repository.NewInventoryMovementRepository(db))
+	orderStatusService := NewOrderStatusService(order_repository.NewOrderStatusRepository(db))
+	locationService := NewLocationService(db, order_repository.NewLocationRepository(db))
+
+	ledgerService := finance_service.NewLedgerService(db, finance_repository.NewLedgerRepository(db))
+	transactionService := finance_service.NewTransactionService(db, transactionRepository, ledgerService)
+	currencyService := finance_service.NewCurrencyService(db, finance_repository.NewCurrencyRateRepository(db))
+	paymentGatewayService := payment_gateway_service.NewPaymentGatewayService(env, gateway)
+	userService := user_service.NewUserService(userRepository)
+	walletService := finance_service.NewWalletService(db, transactionRepository, transactionService, paymentGatewayService, userService)
+	refundService := NewRefundService(db, order_repository.NewRefundRepository(db), orderRepository, transactionService, walletService, paymentGatewayService)
+
+	return NewOrderService(
+		db,
+		orderRepository,
+		NewOrderItemService(order_repository.NewOrderItemRepository(db), productService),
+		orderStatusService,
+		NewOrderStatusHistoryService(order_repository.NewOrderStatusHistoryRepository(db), orderStatusService),
+		NewShippingAddressService(db, order_repository.NewShippingAddressRepository(db), locationService),
+		NewShippingTypeService(db, order_repository.NewShippingTypeRepository(db)),
+		NewCouponService(db, order_repository.NewCouponRepository(db), orderRepository),
+		NewCartService(db, order_repository.NewCartRepository(db), productService),
+		productService,
+		inventoryService,
+		transactionService,
+		currencyService,
+		walletService,
+		paymentGatewayService,
+		refundService,
+		userService,
+		emailService,
+	)
+}
+
+// checkoutFixture is a customer with a default address, a shipping type and a product with stock to buy.
+type checkoutFixture struct {
+	user         models.User
+	shippingType models.ShippingType
+	product      models.Product
+}
+
+func newCheckoutFixture(t *testing.T, db database.DatabaseInterface, stock int) checkoutFixture {
+	t.Helper()
+
+	var f checkoutFixture
+
+	country := models.Country{Name: "Nigeria"}
+	country.Prepare()
+	state := models.State{CountryID: country.ID, Name: "Lagos"}
+	state.Prepare()
+	city := models.City{StateID: state.ID, Name: "Ikeja", Price: money.New(0, money.BaseCurrency)}
+	city.Prepare()
+
+	f.user = models.User{FirstName: "Test", LastName: "Buyer", Email: uuid.NewString() + "@example.com", IsEmailVerified: true, Password: "-", Role: "customer"}
+	f.user.Prepare()
+
+	address := models.ShippingAddress{UserID: f.user.ID, FirstName: "Test", LastName: "Buyer", Phone: "08000000000", Address: "1 Test Street", CityID: city.ID, StateID: state.ID, IsDefault: true}
+	address.Prepare()
+
+	f.shippingType = models.ShippingType{Name: "Standard", Price: money.New(100000, money.BaseCurrency), PricePerKg: money.New(0, money.BaseCurrency), IsActive: true}
+	f.shippingType.Prepare()
+
+	f.product = models.Product{Slug: uuid.NewString(), Name: "Test product", Price: money.New(500000, money.BaseCurrency), SlashPrice: money.New(0, money.BaseCurrency), Stock: stock, Brand: "Test"}
+	f.product.Prepare()
+
+	for _, row := range []interface{}{&country, &state, &city, &f.user, &address, &f.shippingType, &f.product} {
+		if err := db.Connection().Omit(clause.Associations).Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return f
+}
+
+func (f checkoutFixture) order(quantity int) dto.CreateOrderDTO {
+	return dto.CreateOrderDTO{
+		UserID:         f.user.ID,
+		ShippingTypeID: f.shippingType.ID,
+		PaymentMethod:  "fakepay",
+		Items:          []dto.CreateOrderItemDTO{{ProductUUID: f.product.ID.String(), Quantity: quantity}},
+	}
+}
+
+func countRows(t *testing.T, db database.DatabaseInterface, model interface{}, query string, args ...interface{}) int64 {
+	t.Helper()
+
+	var count int64
+
+	if err := db.Connection().Model(model).Where(query, args...).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return count
+}
+
+func TestCheckoutOrderDoesNotOversell(t *testing.T) {
+	db := testDatabase(t)
+	orderService := newTestOrderService(db, &fakeGateway{})
+
+	const stock, buyers = 3, 12
+
+	f := newCheckoutFixture(t, db, stock)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	placed, outOfStock := 0, 0
+
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, code, err := orderService.CheckoutOrder(f.order(1))
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case err == nil:
+				placed++
+			case code == constants.ItemOutOfStock:
+				outOfStock++
+			default:
+				t.Errorf("checkout failed: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if placed != stock || outOfStock != buyers-stock {
+		t.Errorf("placed %d and %d out of stock, want %d and %d", placed, outOfStock, stock, buyers-stock)
+	}
+
+	var product models.Product
+	if err := db.Connection().Select("stock").Where("id = ?", f.product.ID).First(&product).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if product.Stock != 0 {
+		t.Errorf("stock is %d, want 0", product.Stock)
+	}
+
+	// the checkouts that failed left nothing behind
+	if orders := countRows(t, db, &models.Order{}, "user_id = ?", f.user.ID); orders != int64(placed) {
+		t.Errorf("%d orders saved, want %d", orders, placed)
+	}
+
+	if transactions := countRows(t, db, &models.Transaction{}, "user_id = ?", f.user.ID); transactions != int64(placed) {
+		t.Errorf("%d transactions saved, want %d", transactions, placed)
+	}
+}
+
+func TestCheckoutOrderCancelsWhenGatewayFails(t *testing.T) {
+	db := testDatabase(t)
+	orderService := newTestOrderService(db, &fakeGateway{fail: true})
+
+	f := newCheckoutFixture(t, db, 2)
+
+	if _, _, err := orderService.CheckoutOrder(f.order(2)); err == nil {
+		t.Fatal("checkout succeeded with the gateway down")
+	}
+
+	var product models.Product
+	if err := db.Connection().Select("stock").Where("id = ?", f.product.ID).First(&product).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if product.Stock != 2 {
+		t.Errorf("stock is %d, want it given back to 2", product.Stock)
+	}
+
+	cancelled := countRows(t, db, &models.Order{},
+		"user_id = ? AND status_id = (SELECT id FROM order_statuses WHERE short_name = ?)", f.user.ID, CANCELLED)
+
+	if cancelled != 1 {
+		t.Errorf("%d orders cancelled, want 1", cancelled)
+	}
+
+	failed := countRows(t, db, &models.Transaction{}, "user_id = ? AND status = ?", f.user.ID, finance_service.TransactionStatusFailed)
+
+	if failed != 1 {
+		t.Errorf("%d transactions failed, want 1", failed)
+	}
+}
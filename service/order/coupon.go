@@ -0,0 +1,333 @@
+package order_service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+)
+
+var (
+	CouponTypePercentage = "percentage"
+	CouponTypeFixed      = "fixed"
+
+	ErrInvalidCoupon           = errors.New("coupon is not valid")
+	ErrCouponExpired           = errors.New("coupon has expired")
+	ErrCouponMinimumNotMet     = errors.New("order does not reach the coupon's minimum amount")
+	ErrCouponUsageLimitReached = errors.New("coupon has reached its usage limit")
+	ErrCouponNotApplicable     = errors.New("coupon does not apply to any item in the order")
+	ErrCouponCodeTaken         = errors.New("a coupon with this code already exists")
+	ErrCouponInUse             = errors.New("coupon has been used on orders, deactivate it instead")
+)
+
+type CouponServiceInterface interface {
+	CreateCoupon(coupon dto.CouponDTO) (dto.CouponDTO, error)
+	FindCouponById(couponId uuid.UUID) (dto.CouponDTO, error)
+	FindAllCoupons(pageable repository.Pageable) ([]dto.CouponDTO, repository.Pagination, error)
+	UpdateCoupon(coupon dto.CouponDTO) (dto.CouponDTO, error)
+	DeleteCoupon(couponId uuid.UUID) error
+	RedeemCoupon(code string, userId uuid.UUID, items []dto.OrderItemDTO) (dto.CouponDTO, []money.Money, error)
+	WithTx(tx database.DatabaseInterface) CouponServiceInterface
+}
+
+type couponService struct {
+	database         database.DatabaseInterface
+	couponRepository order_repository.CouponRepositoryInterface
+	orderRepository  order_repository.OrderRepositoryInterface
+}
+
+func NewCouponService(
+	database database.DatabaseInterface,
+	couponRepository order_repository.CouponRepositoryInterface,
+	orderRepository order_repository.OrderRepositoryInterface,
+) CouponServiceInterface {
+	return &couponService{
+		database:         database,
+		couponRepository: couponRepository,
+		orderRepository:  orderRepository,
+	}
+}
+
+// WithTx implements CouponServiceInterface.
+func (s *couponService) WithTx(tx database.DatabaseInterface) CouponServiceInterface {
+	return s.withTx(tx)
+}
+
+func (s *couponService) withTx(tx database.DatabaseInterface) *couponService {
+	return &couponService{
+		database:         tx,
+		couponRepository: s.couponRepository.WithTx(tx),
+		orderRepository:  s.orderRepository.WithTx(tx),
+	}
+}
+
+func (s *couponService) ConvertToDTO(coupon models.Coupon) dto.CouponDTO {
+	var couponDto dto.CouponDTO
+
+	couponDto.ID = coupon.ID
+	couponDto.Code = coupon.Code
+	couponDto.Description = coupon.Description
+	couponDto.Type = coupon.Type
+	couponDto.Value = coupon.Value
+	couponDto.MinOrderAmount = coupon.MinOrderAmount
+	couponDto.ExpiresAt = coupon.ExpiresAt
+	couponDto.UsageLimit = coupon.UsageLimit
+	couponDto.UsageLimitPerUser = coupon.UsageLimitPerUser
+	couponDto.IsActive = coupon.IsActive
+	for _, product := range coupon.Products {
+		couponDto.ProductUUIDs = append(couponDto.ProductUUIDs, product.ID)
+	}
+	couponDto.CreatedAt = coupon.CreatedAt
+	couponDto.UpdatedAt = coupon.UpdatedAt
+
+	return couponDto
+}
+
+func (s *couponService) ConvertToModel(couponDto dto.CouponDTO) models.Coupon {
+	var coupon models.Coupon
+
+	coupon.ID = couponDto.ID
+	coupon.Code = strings.ToUpper(strings.TrimSpace(couponDto.Code))
+	coupon.Description = couponDto.Description
+	coupon.Type = couponDto.Type
+	coupon.Value = couponDto.Value
+	coupon.MinOrderAmount = couponDto.MinOrderAmount
+	coupon.ExpiresAt = couponDto.ExpiresAt
+	coupon.UsageLimit = couponDto.UsageLimit
+	coupon.UsageLimitPerUser = couponDto.UsageLimitPerUser
+	coupon.IsActive = couponDto.IsActive
+	for _, productId := range couponDto.ProductUUIDs {
+		var product models.Product
+
+		product.ID = productId
+		coupon.Products = append(coupon.Products, product)
+	}
+
+	return coupon
+}
+
+// checkCodeAvailable returns ErrCouponCodeTaken when another coupon than couponId has the code.
+func (s *couponService) checkCodeAvailable(code string, couponId uuid.UUID) error {
+	coupon, err := s.couponRepository.FindCouponByCode(strings.ToUpper(strings.TrimSpace(code)))
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if coupon.ID != couponId {
+		return ErrCouponCodeTaken
+	}
+
+	return nil
+}
+
+// CreateCoupon implements CouponServiceInterface. Codes are stored in upper case.
+func (s *couponService) CreateCoupon(couponDto dto.CouponDTO) (dto.CouponDTO, error) {
+	var coupon models.Coupon
+
+	err := s.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := s.withTx(tx)
+
+		if err := txService.checkCodeAvailable(couponDto.Code, uuid.Nil); err != nil {
+			return err
+		}
+
+		var err error
+
+		coupon, err = txService.couponRepository.CreateCoupon(txService.ConvertToModel(couponDto))
+
+		return err
+	})
+
+	if err != nil {
+		return dto.CouponDTO{}, err
+	}
+
+	return s.FindCouponById(coupon.ID)
+}
+
+// FindCouponById implements CouponServiceInterface.
+func (s *couponService) FindCouponById(couponId uuid.UUID) (dto.CouponDTO, error) {
+	coupon, err := s.couponRepository.FindCouponById(couponId)
+
+	if err != nil {
+		return dto.CouponDTO{}, err
+	}
+
+	return s.ConvertToDTO(coupon), nil
+}
+
+// FindAllCoupons implements CouponServiceInterface.
+func (s *couponService) FindAllCoupons(pageable repository.Pageable) ([]dto.CouponDTO, repository.Pagination, error) {
+	coupons := []dto.CouponDTO{}
+
+	couponModels, pagination, err := s.couponRepository.FindAllCoupons(pageable)
+
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	for _, coupon := range couponModels {
+		coupons = append(coupons, s.ConvertToDTO(coupon))
+	}
+
+	return coupons, pagination, nil
+}
+
+// UpdateCoupon implements CouponServiceInterface.
+func (s *couponService) UpdateCoupon(couponDto dto.CouponDTO) (dto.CouponDTO, error) {
+	err := s.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := s.withTx(tx)
+
+		if err := txService.checkCodeAvailable(couponDto.Code, couponDto.ID); err != nil {
+			return err
+		}
+
+		_, err := txService.couponRepository.UpdateCoupon(txService.ConvertToModel(couponDto))
+
+		return err
+	})
+
+	if err != nil {
+		return dto.CouponDTO{}, err
+	}
+
+	return s.FindCouponById(couponDto.ID)
+}
+
+// DeleteCoupon implements CouponServiceInterface.
+// Coupons already used on orders are kept so the orders still show what they were discounted with.
+func (s *couponService) DeleteCoupon(couponId uuid.UUID) error {
+	used, err := s.orderRepository.CheckOrderExistByCouponId(couponId)
+
+	if err != nil {
+		return err
+	}
+
+	if used {
+		return ErrCouponInUse
+	}
+
+	return s.couponRepository.DeleteCoupon(couponId)
+}
+
+// RedeemCoupon implements CouponServiceInterface.
+// It checks the coupon can be used by userId on an order of items and returns the discount on each item.
+// It expects s to be bound to the transaction that creates the order, the coupon stays locked until it ends.
+// Cancelled orders do not count towards the usage limits.
+func (s *couponService) RedeemCoupon(code string, userId uuid.UUID, items []dto.OrderItemDTO) (dto.CouponDTO, []money.Money, error) {
+	coupon, err := s.couponRepository.FindCouponByCodeForUpdate(strings.ToUpper(strings.TrimSpace(code)))
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.CouponDTO{}, nil, ErrInvalidCoupon
+	}
+
+	if err != nil {
+		return dto.CouponDTO{}, nil, err
+	}
+
+	couponDto := s.ConvertToDTO(coupon)
+
+	if !couponDto.IsActive {
+		return couponDto, nil, ErrInvalidCoupon
+	}
+
+	if couponDto.ExpiresAt != nil && couponDto.ExpiresAt.Before(time.Now()) {
+		return couponDto, nil, ErrCouponExpired
+	}
+
+	var itemsTotal money.Money
+	for _, item := range items {
+		itemsTotal = itemsTotal.Add(item.Price)
+	}
+
+	if itemsTotal.Amount < couponDto.MinOrderAmount.Amount {
+		return couponDto, nil, ErrCouponMinimumNotMet
+	}
+
+	if couponDto.UsageLimit > 0 {
+		used, err := s.couponRepository.CountCouponRedemptions(coupon.ID, uuid.Nil, CANCELLED)
+
+		if err != nil {
+			return couponDto, nil, err
+		}
+
+		if used >= int64(couponDto.UsageLimit) {
+			return couponDto, nil, ErrCouponUsageLimitReached
+		}
+	}
+
+	if couponDto.UsageLimitPerUser > 0 {
+		used, err := s.couponRepository.CountCouponRedemptions(coupon.ID, userId, CANCELLED)
+
+		if err != nil {
+			return couponDto, nil, err
+		}
+
+		if used >= int64(couponDto.UsageLimitPerUser) {
+			return couponDto, nil, ErrCouponUsageLimitReached
+		}
+	}
+
+	discounts := CouponDiscounts(couponDto, items)
+
+	if !money.Sum(discounts...).IsPositive() {
+		return couponDto, nil, ErrCouponNotApplicable
+	}
+
+	return couponDto, discounts, nil
+}
+
+// CouponDiscounts splits the coupon's discount between the items it applies to, in proportion to their price.
+// The discount never takes an item below zero, and the shares always add up to the discount.
+func CouponDiscounts(coupon dto.CouponDTO, items []dto.OrderItemDTO) []money.Money {
+	weights := make([]int64, len(items))
+
+	var eligibleTotal money.Money
+
+	for i, item := range items {
+		if couponAppliesTo(coupon, item.ProductUUID) {
+			weights[i] = item.Price.Amount
+			eligibleTotal = eligibleTotal.Add(item.Price)
+		}
+	}
+
+	if !eligibleTotal.IsPositive() {
+		return make([]money.Money, len(items))
+	}
+
+	discount := money.New(coupon.Value, eligibleTotal.Currency)
+
+	if coupon.Type == CouponTypePercentage {
+		discount = eligibleTotal.Percent(coupon.Value)
+	}
+
+	return discount.Min(eligibleTotal).Allocate(weights)
+}
+
+func couponAppliesTo(coupon dto.CouponDTO, productId uuid.UUID) bool {
+	if len(coupon.ProductUUIDs) == 0 {
+		return true
+	}
+
+	for _, id := range coupon.ProductUUIDs {
+		if id == productId {
+			return true
+		}
+	}
+
+	return false
+}
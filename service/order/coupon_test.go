@@ -0,0 +1,83 @@
+package order_service
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+)
+
+// couponOrder is a coupon and the items of an order it is redeemed on.
+type couponOrder struct {
+	Coupon dto.CouponDTO
+	Items  []dto.OrderItemDTO
+}
+
+func (couponOrder) Generate(r *rand.Rand, size int) reflect.Value {
+	var c couponOrder
+
+	for i := r.Intn(6); i >= 0; i-- {
+		item := dto.OrderItemDTO{ProductUUID: uuid.New(), Quantity: 1 + r.Intn(5)}
+		item.Price = money.New(r.Int63n(10_000_000), money.BaseCurrency).Mul(item.Quantity)
+
+		// a coupon for some of the products only
+		if r.Intn(3) == 0 {
+			c.Coupon.ProductUUIDs = append(c.Coupon.ProductUUIDs, item.ProductUUID)
+		}
+
+		c.Items = append(c.Items, item)
+	}
+
+	c.Coupon.Type = CouponTypeFixed
+	c.Coupon.Value = r.Int63n(60_000_000)
+
+	if r.Intn(2) == 0 {
+		c.Coupon.Type = CouponTypePercentage
+		c.Coupon.Value = r.Int63n(10_001) // basis points, up to 100%
+	}
+
+	return reflect.ValueOf(c)
+}
+
+func TestCouponDiscountsAddUp(t *testing.T) {
+	property := func(c couponOrder) bool {
+		discounts := CouponDiscounts(c.Coupon, c.Items)
+
+		if len(discounts) != len(c.Items) {
+			return false
+		}
+
+		var eligible money.Money
+
+		for i, item := range c.Items {
+			applies := couponAppliesTo(c.Coupon, item.ProductUUID)
+
+			if applies {
+				eligible = eligible.Add(item.Price)
+			}
+
+			// an item is never taken below zero, and only the items of the coupon get a share
+			if discounts[i].Amount < 0 || discounts[i].Amount > item.Price.Amount || (!applies && discounts[i].Amount != 0) {
+				t.Logf("item %d of %v gets %v", i, item.Price, discounts[i])
+				return false
+			}
+		}
+
+		want := money.New(c.Coupon.Value, money.BaseCurrency)
+
+		if c.Coupon.Type == CouponTypePercentage {
+			want = eligible.Percent(c.Coupon.Value)
+		}
+
+		return money.Sum(discounts...).Amount == want.Min(eligible).Amount
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,278 @@
+package order_service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+)
+
+var ErrLocationInUse = errors.New("location still has states or cities under it")
+
+type LocationServiceInterface interface {
+	CreateCountry(country dto.CountryDTO) (dto.CountryDTO, error)
+	FindAllCountries() ([]dto.CountryDTO, error)
+	UpdateCountry(country dto.CountryDTO) (dto.CountryDTO, error)
+	DeleteCountry(countryId uuid.UUID) error
+	CreateState(state dto.StateDTO) (dto.StateDTO, error)
+	FindStatesByCountryId(countryId uuid.UUID) ([]dto.StateDTO, error)
+	UpdateState(state dto.StateDTO) (dto.StateDTO, error)
+	DeleteState(stateId uuid.UUID) error
+	CreateCity(city dto.CityDTO) (dto.CityDTO, error)
+	FindCitiesByStateId(stateId uuid.UUID) ([]dto.CityDTO, error)
+	FindCityById(cityId uuid.UUID) (dto.CityDTO, error)
+	UpdateCity(city dto.CityDTO) (dto.CityDTO, error)
+	DeleteCity(cityId uuid.UUID) error
+	CreateLocation(location request.CreateLocationRequest) ([]dto.StateDTO, error)
+	WithTx(tx database.DatabaseInterface) LocationServiceInterface
+}
+
+type locationService struct {
+	database           database.DatabaseInterface
+	locationRepository order_repository.LocationRepositoryInterface
+}
+
+func NewLocationService(database database.DatabaseInterface, locationRepository order_repository.LocationRepositoryInterface) LocationServiceInterface {
+	return &locationService{database: database, locationRepository: locationRepository}
+}
+
+// WithTx implements LocationServiceInterface.
+func (l *locationService) WithTx(tx database.DatabaseInterface) LocationServiceInterface {
+	return &locationService{database: tx, locationRepository: l.locationRepository.WithTx(tx)}
+}
+
+func (l *locationService) ConvertCountryToDTO(country models.Country) dto.CountryDTO {
+	var countryDto dto.CountryDTO
+
+	countryDto.ID = country.ID
+	countryDto.Name = country.Name
+	countryDto.CreatedAt = country.CreatedAt
+	countryDto.UpdatedAt = country.UpdatedAt
+
+	return countryDto
+}
+
+func (l *locationService) ConvertStateToDTO(state models.State) dto.StateDTO {
+	var stateDto dto.StateDTO
+
+	stateDto.ID = state.ID
+	stateDto.CountryUUID = state.CountryID
+	stateDto.Name = state.Name
+	stateDto.Country = l.ConvertCountryToDTO(state.Country)
+	stateDto.CreatedAt = state.CreatedAt
+	stateDto.UpdatedAt = state.UpdatedAt
+
+	return stateDto
+}
+
+func (l *locationService) ConvertCityToDTO(city models.City) dto.CityDTO {
+	var cityDto dto.CityDTO
+
+	cityDto.ID = city.ID
+	cityDto.StateUUID = city.StateID
+	cityDto.Name = city.Name
+	cityDto.Price = city.Price
+	cityDto.State = l.ConvertStateToDTO(city.State)
+	cityDto.CreatedAt = city.CreatedAt
+	cityDto.UpdatedAt = city.UpdatedAt
+
+	return cityDto
+}
+
+// CreateCountry implements LocationServiceInterface.
+func (l *locationService) CreateCountry(countryDto dto.CountryDTO) (dto.CountryDTO, error) {
+	country, err := l.locationRepository.CreateCountry(models.Country{Name: countryDto.Name})
+
+	return l.ConvertCountryToDTO(country), err
+}
+
+// FindAllCountries implements LocationServiceInterface.
+func (l *locationService) FindAllCountries() ([]dto.CountryDTO, error) {
+	var countryDtos []dto.CountryDTO
+
+	countries, err := l.locationRepository.FindAllCountries()
+
+	for _, country := range countries {
+		countryDtos = append(countryDtos, l.ConvertCountryToDTO(country))
+	}
+
+	return countryDtos, err
+}
+
+// UpdateCountry implements LocationServiceInterface.
+func (l *locationService) UpdateCountry(countryDto dto.CountryDTO) (dto.CountryDTO, error) {
+	var country models.Country
+
+	country.ID = countryDto.ID
+	country.Name = countryDto.Name
+
+	country, err := l.locationRepository.UpdateCountry(country)
+
+	return l.ConvertCountryToDTO(country), err
+}
+
+// DeleteCountry implements LocationServiceInterface. A country with states cannot be deleted.
+func (l *locationService) DeleteCountry(countryId uuid.UUID) error {
+	states, err := l.locationRepository.FindStatesByCountryId(countryId)
+
+	if err != nil {
+		return err
+	}
+
+	if len(states) > 0 {
+		return ErrLocationInUse
+	}
+
+	return l.locationRepository.DeleteCountry(countryId)
+}
+
+// CreateState implements LocationServiceInterface.
+func (l *locationService) CreateState(stateDto dto.StateDTO) (dto.StateDTO, error) {
+	country, err := l.locationRepository.FindCountryById(stateDto.CountryUUID)
+
+	if err != nil {
+		return dto.StateDTO{}, err
+	}
+
+	state, err := l.locationRepository.CreateState(models.State{CountryID: country.ID, Name: stateDto.Name})
+	state.Country = country
+
+	return l.ConvertStateToDTO(state), err
+}
+
+// FindStatesByCountryId implements LocationServiceInterface.
+func (l *locationService) FindStatesByCountryId(countryId uuid.UUID) ([]dto.StateDTO, error) {
+	var stateDtos []dto.StateDTO
+
+	states, err := l.locationRepository.FindStatesByCountryId(countryId)
+
+	for _, state := range states {
+		stateDtos = append(stateDtos, l.ConvertStateToDTO(state))
+	}
+
+	return stateDtos, err
+}
+
+// UpdateState implements LocationServiceInterface.
+func (l *locationService) UpdateState(stateDto dto.StateDTO) (dto.StateDTO, error) {
+	var state models.State
+
+	state.ID = stateDto.ID
+	state.Name = stateDto.Name
+
+	state, err := l.locationRepository.UpdateState(state)
+
+	return l.ConvertStateToDTO(state), err
+}
+
+// DeleteState implements LocationServiceInterface. A state with cities cannot be deleted.
+func (l *locationService) DeleteState(stateId uuid.UUID) error {
+	cities, err := l.locationRepository.FindCitiesByStateId(stateId)
+
+	if err != nil {
+		return err
+	}
+
+	if len(cities) > 0 {
+		return ErrLocationInUse
+	}
+
+	return l.locationRepository.DeleteState(stateId)
+}
+
+// CreateCity implements LocationServiceInterface.
+func (l *locationService) CreateCity(cityDto dto.CityDTO) (dto.CityDTO, error) {
+	state, err := l.locationRepository.FindStateById(cityDto.StateUUID)
+
+	if err != nil {
+		return dto.CityDTO{}, err
+	}
+
+	city, err := l.locationRepository.CreateCity(models.City{StateID: state.ID, Name: cityDto.Name, Price: cityDto.Price})
+	city.State = state
+
+	return l.ConvertCityToDTO(city), err
+}
+
+// FindCitiesByStateId implements LocationServiceInterface.
+func (l *locationService) FindCitiesByStateId(stateId uuid.UUID) ([]dto.CityDTO, error) {
+	var cityDtos []dto.CityDTO
+
+	cities, err := l.locationRepository.FindCitiesByStateId(stateId)
+
+	for _, city := range cities {
+		cityDtos = append(cityDtos, l.ConvertCityToDTO(city))
+	}
+
+	return cityDtos, err
+}
+
+// FindCityById implements LocationServiceInterface.
+func (l *locationService) FindCityById(cityId uuid.UUID) (dto.CityDTO, error) {
+	city, err := l.locationRepository.FindCityById(cityId)
+
+	return l.ConvertCityToDTO(city), err
+}
+
+// UpdateCity implements LocationServiceInterface.
+func (l *locationService) UpdateCity(cityDto dto.CityDTO) (dto.CityDTO, error) {
+	var city models.City
+
+	city.ID = cityDto.ID
+	city.Name = cityDto.Name
+	city.Price = cityDto.Price
+
+	city, err := l.locationRepository.UpdateCity(city)
+
+	return l.ConvertCityToDTO(city), err
+}
+
+// DeleteCity implements LocationServiceInterface.
+func (l *locationService) DeleteCity(cityId uuid.UUID) error {
+	return l.locationRepository.DeleteCity(cityId)
+}
+
+// CreateLocation adds states and their cities to a country in one go.
+func (l *locationService) CreateLocation(location request.CreateLocationRequest) ([]dto.StateDTO, error) {
+	var stateDtos []dto.StateDTO
+
+	countryId, err := uuid.Parse(location.CountryID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := l.WithTx(tx)
+
+		for _, state := range location.States {
+			stateDto, err := txService.CreateState(dto.StateDTO{CountryUUID: countryId, Name: state.Name})
+
+			if err != nil {
+				return err
+			}
+
+			for _, city := range state.Cities {
+				if _, err := txService.CreateCity(dto.CityDTO{StateUUID: stateDto.ID, Name: city.Name, Price: money.FromMajor(city.Price, money.BaseCurrency)}); err != nil {
+					return err
+				}
+			}
+
+			stateDtos = append(stateDtos, stateDto)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stateDtos, nil
+}
@@ -0,0 +1,108 @@
+package order_service
+
+import (
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+)
+
+// OrderEmail is a templates/<Template>.html email sent to the customer when an order changes status.
+// Essential emails are sent even when the customer opted out of order updates.
+type OrderEmail struct {
+	Template  string
+	Subject   string
+	Essential bool
+}
+
+type OrderEmailItem struct {
+	Name     string
+	Quantity int
+	Price    string
+}
+
+type OrderEmailCharge struct {
+	Name   string
+	Amount string
+}
+
+type OrderEmailStatus struct {
+	Name string
+	Date string
+}
+
+var (
+	OrderConfirmationEmail = OrderEmail{Template: "order-confirmation", Subject: "Your order has been confirmed", Essential: true}
+	OrderShippedEmail      = OrderEmail{Template: "order-shipped", Subject: "Your order is out for delivery"}
+	OrderDeliveredEmail    = OrderEmail{Template: "order-delivered", Subject: "Your order has been delivered"}
+)
+
+// notifyCustomer returns a hook that emails the customer about the order.
+func notifyCustomer(email OrderEmail) OrderTransitionHook {
+	return func(o *orderService, order models.Order) error {
+		return o.sendOrderEmail(email, order)
+	}
+}
+
+func (o *orderService) sendOrderEmail(email OrderEmail, order models.Order) error {
+	if !email.Essential && !order.User.NotifyOrderUpdates {
+		return nil
+	}
+
+	histories, err := o.orderStatusHistoryService.FindOrderStatusHistoriesByOrderId(order.ID.String())
+
+	if err != nil {
+		return err
+	}
+
+	items := []OrderEmailItem{}
+
+	for _, item := range order.OrderItems {
+		items = append(items, OrderEmailItem{
+			Name:     item.Product.Name,
+			Quantity: item.Quantity,
+			Price:    item.Price.String(),
+		})
+	}
+
+	charges := []OrderEmailCharge{}
+
+	for _, charge := range order.Charges {
+		charges = append(charges, OrderEmailCharge{
+			Name:   charge.Name,
+			Amount: charge.Amount.String(),
+		})
+	}
+
+	// left empty so the templates can skip it
+	discount := ""
+
+	if order.Discount.IsPositive() {
+		discount = order.Discount.String()
+	}
+
+	statusHistory := []OrderEmailStatus{}
+	status := ""
+
+	for _, history := range histories {
+		status = history.Status.Name
+		statusHistory = append(statusHistory, OrderEmailStatus{
+			Name: history.Status.Name,
+			Date: history.CreatedAt.Format("02 Jan 2006, 15:04"),
+		})
+	}
+
+	return o.emailService.SendEmail(service.SendEmailParams{
+		To:       order.User.Email,
+		Subject:  email.Subject,
+		Template: email.Template,
+		Variables: map[string]interface{}{
+			"FullName":      order.User.FirstName + " " + order.User.LastName,
+			"Reference":     order.Reference,
+			"Items":         items,
+			"Charges":       charges,
+			"Discount":      discount,
+			"TotalAmount":   order.TotalPrice.String(),
+			"Status":        status,
+			"StatusHistory": statusHistory,
+		},
+	})
+}
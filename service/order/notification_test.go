@@ -0,0 +1,286 @@
+package order_service
+
+import (
+	"bytes"
+	"html/template"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	"github.com/developer-afo/instashop-ecommerce-api/repository"
+	notification_repository "github.com/developer-afo/instashop-ecommerce-api/repository/notification"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
+)
+
+// sentEmail is an email the fake mailer was asked to send, with its rendered body.
+type sentEmail struct {
+	To, Subject, TemplateFile string
+	Variables                 map[string]interface{}
+	Body                      string
+}
+
+// fakeMail implements config.EmailInterface. It renders the template like the real mailer, but fails on a variable
+// the template uses and the email does not set.
+type fakeMail struct {
+	t    *testing.T
+	sent []sentEmail
+}
+
+func (m *fakeMail) SendWithTemplate(to, subject, templateFile string, data interface{}) error {
+	root := filepath.Join("..", "..")
+
+	t, err := template.New("").Option("missingkey=error").ParseFiles(filepath.Join(root, templateFile), filepath.Join(root, "templates", "layout.html"))
+	if err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+
+	if err = t.ExecuteTemplate(buf, "layout", data); err != nil {
+		m.t.Errorf("%s: %v", templateFile, err)
+		return err
+	}
+
+	m.sent = append(m.sent, sentEmail{To: to, Subject: subject, TemplateFile: templateFile, Variables: data.(map[string]interface{}), Body: buf.String()})
+
+	return nil
+}
+
+// fakeOutbox keeps queued emails in memory.
+type fakeOutbox struct {
+	emails []models.OutboxEmail
+}
+
+func (r *fakeOutbox) CreateOutboxEmail(email models.OutboxEmail) (models.OutboxEmail, error) {
+	email.Prepare()
+	r.emails = append(r.emails, email)
+
+	return email, nil
+}
+
+func (r *fakeOutbox) FindOutboxEmailById(id uuid.UUID) (models.OutboxEmail, error) {
+	for _, email := range r.emails {
+		if email.ID == id {
+			return email, nil
+		}
+	}
+
+	return models.OutboxEmail{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeOutbox) FindAllOutboxEmails(pageable notification_repository.OutboxEmailPageable) ([]models.OutboxEmail, repository.Pagination, error) {
+	return r.emails, repository.Pagination{}, nil
+}
+
+func (r *fakeOutbox) FindDueOutboxEmails(status string, dueBefore time.Time, limit int) (emails []models.OutboxEmail, err error) {
+	for _, email := range r.emails {
+		if email.Status == status && !email.NextAttemptAt.After(dueBefore) && len(emails) < limit {
+			emails = append(emails, email)
+		}
+	}
+
+	return emails, nil
+}
+
+func (r *fakeOutbox) UpdateOutboxEmailAttempt(email models.OutboxEmail, fromStatus string, fromAttempts int) (int64, error) {
+	for i := range r.emails {
+		if r.emails[i].ID == email.ID && r.emails[i].Status == fromStatus && r.emails[i].Attempts == fromAttempts {
+			r.emails[i] = email
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (r *fakeOutbox) WithTx(tx database.DatabaseInterface) notification_repository.OutboxEmailRepositoryInterface {
+	return r
+}
+
+// fakeStatusHistory returns the same history for every order.
+type fakeStatusHistory struct {
+	OrderStatusHistoryServiceInterface
+
+	histories []dto.OrderStatusHistoryDTO
+}
+
+func (s *fakeStatusHistory) FindOrderStatusHistoriesByOrderId(orderId string) ([]dto.OrderStatusHistoryDTO, error) {
+	return s.histories, nil
+}
+
+func statusHistory(names ...string) []dto.OrderStatusHistoryDTO {
+	var histories []dto.OrderStatusHistoryDTO
+
+	for i, name := range names {
+		history := dto.OrderStatusHistoryDTO{Status: dto.OrderStatusDTO{Name: name}}
+		history.CreatedAt = time.Date(2024, 3, 1+i, 10, 30, 0, 0, time.UTC)
+
+		histories = append(histories, history)
+	}
+
+	return histories
+}
+
+// sendOrderEmails runs the email hook of each transition for order and delivers what was queued.
+func sendOrderEmails(t *testing.T, order models.Order, emails ...OrderEmail) []sentEmail {
+	t.Helper()
+
+	mail := &fakeMail{t: t}
+	outbox := &fakeOutbox{}
+
+	o := &orderService{
+		orderStatusHistoryService: &fakeStatusHistory{histories: statusHistory("Order Placed", "Awaiting Confirmation", "Out for Delivery")},
+		emailService:              service.NewEmailService(mail, outbox),
+	}
+
+	for _, email := range emails {
+		if err := notifyCustomer(email)(o, order); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := o.emailService.DeliverPendingEmails(len(outbox.emails) + 1); err != nil {
+		t.Fatal(err)
+	}
+
+	return mail.sent
+}
+
+func testOrder(notifyOrderUpdates bool) models.Order {
+	order := models.Order{
+		Reference:  "20240301ABC",
+		TotalPrice: money.New(1150000, money.BaseCurrency),
+		Discount:   money.New(50000, money.BaseCurrency),
+		User: models.User{
+			FirstName:          "Ada",
+			LastName:           "Obi",
+			Email:              "ada@example.com",
+			NotifyOrderUpdates: notifyOrderUpdates,
+		},
+		OrderItems: []models.OrderItem{
+			{Quantity: 2, Price: money.New(500000, money.BaseCurrency), Product: models.Product{Name: "Plantain chips"}},
+		},
+		Charges: []models.OrderCharge{
+			{Name: "Shipping", Amount: money.New(200000, money.BaseCurrency)},
+		},
+	}
+
+	order.Prepare()
+
+	return order
+}
+
+func TestOrderEmailsRespectOptOut(t *testing.T) {
+	tests := []struct {
+		name               string
+		email              OrderEmail
+		notifyOrderUpdates bool
+		sent               bool
+	}{
+		{"confirmation opted in", OrderConfirmationEmail, true, true},
+		{"confirmation opted out", OrderConfirmationEmail, false, true},
+		{"shipped opted in", OrderShippedEmail, true, true},
+		{"shipped opted out", OrderShippedEmail, false, false},
+		{"delivered opted in", OrderDeliveredEmail, true, true},
+		{"delivered opted out", OrderDeliveredEmail, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := sendOrderEmails(t, testOrder(tt.notifyOrderUpdates), tt.email)
+
+			if !tt.sent {
+				if len(sent) != 0 {
+					t.Errorf("%d emails sent to a customer who opted out", len(sent))
+				}
+
+				return
+			}
+
+			if len(sent) != 1 {
+				t.Fatalf("%d emails sent, want 1", len(sent))
+			}
+
+			if sent[0].Subject != tt.email.Subject || sent[0].TemplateFile != "templates/"+tt.email.Template+".html" {
+				t.Errorf("sent %q with %s", sent[0].Subject, sent[0].TemplateFile)
+			}
+		})
+	}
+}
+
+func TestOrderEmailVariables(t *testing.T) {
+	sent := sendOrderEmails(t, testOrder(false), OrderConfirmationEmail)
+
+	if len(sent) != 1 {
+		t.Fatalf("%d emails sent, want 1", len(sent))
+	}
+
+	email := sent[0]
+
+	if email.To != "ada@example.com" {
+		t.Errorf("sent to %s", email.To)
+	}
+
+	want := map[string]interface{}{
+		"FullName":    "Ada Obi",
+		"Reference":   "20240301ABC",
+		"Discount":    "NGN 500.00",
+		"TotalAmount": "NGN 11500.00",
+		"Status":      "Out for Delivery",
+	}
+
+	for key, value := range want {
+		if email.Variables[key] != value {
+			t.Errorf("%s is %v, want %v", key, email.Variables[key], value)
+		}
+	}
+
+	items := email.Variables["Items"].([]interface{})
+	item := items[0].(map[string]interface{})
+
+	if len(items) != 1 || item["Name"] != "Plantain chips" || item["Quantity"] != float64(2) || item["Price"] != "NGN 5000.00" {
+		t.Errorf("Items is %v", items)
+	}
+
+	charges := email.Variables["Charges"].([]interface{})
+	charge := charges[0].(map[string]interface{})
+
+	if len(charges) != 1 || charge["Name"] != "Shipping" || charge["Amount"] != "NGN 2000.00" {
+		t.Errorf("Charges is %v", charges)
+	}
+
+	history := email.Variables["StatusHistory"].([]interface{})
+
+	if len(history) != 3 || history[0].(map[string]interface{})["Date"] != "01 Mar 2024, 10:30" {
+		t.Errorf("StatusHistory is %v", history)
+	}
+
+	for _, text := range []string{"Hi Ada Obi", "#MAZ-20240301ABC", "Plantain chips&nbsp;x2", "Discount:&nbsp;-NGN 500.00", "Shipping:&nbsp;NGN 2000.00", "Out for Delivery&nbsp;-&nbsp;03 Mar 2024, 10:30"} {
+		if !strings.Contains(email.Body, text) {
+			t.Errorf("body does not contain %q", text)
+		}
+	}
+}
+
+func TestOrderEmailWithoutDiscount(t *testing.T) {
+	order := testOrder(true)
+	order.Discount = money.New(0, money.BaseCurrency)
+
+	sent := sendOrderEmails(t, order, OrderConfirmationEmail, OrderShippedEmail, OrderDeliveredEmail)
+
+	if len(sent) != 3 {
+		t.Fatalf("%d emails sent, want 3", len(sent))
+	}
+
+	if sent[0].Variables["Discount"] != "" || strings.Contains(sent[0].Body, "Discount:") {
+		t.Errorf("Discount is %q", sent[0].Variables["Discount"])
+	}
+}
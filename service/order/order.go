@@ -1,17 +1,23 @@
 package order_service
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/developer-afo/instashop-ecommerce-api/dto"
 	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/constants"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
 	"github.com/developer-afo/instashop-ecommerce-api/lib/helper"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
 	"github.com/developer-afo/instashop-ecommerce-api/models"
 	"github.com/developer-afo/instashop-ecommerce-api/repository"
 	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+	"github.com/developer-afo/instashop-ecommerce-api/service"
 	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
 	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
 	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
@@ -21,54 +27,119 @@ import (
 var (
 	TransactionDescription = "Payment for order"
 	TransactionShortDesc   = "order"
+
+	// DefaultOrderPaymentTTL is how long an order waits for its payment when ORDER_PAYMENT_TTL is not set.
+	DefaultOrderPaymentTTL      = 30 * time.Minute
+	ExpireUnpaidOrdersBatchSize = 100
+	// UnpaidOrderMaxAge is how long an order waits on a payment its gateway still reports as pending before it is
+	// cancelled anyway.
+	UnpaidOrderMaxAge = 24 * time.Hour
+
+	ErrWalletAmountExceedsTotal  = errors.New("wallet amount is more than the order total")
+	ErrCancelledOrderNotRefunded = errors.New("order is cancelled but its refund failed, refund it again")
 )
 
 type OrderServiceInterface interface {
 	CheckoutOrder(order dto.CreateOrderDTO) (string, int, error)
-	CancelOrder(orderId uuid.UUID) error
+	CancelOrder(orderId uuid.UUID, userId uuid.UUID) error
+	CancelPaidOrder(orderId uuid.UUID, refund dto.CreateRefundDTO) (dto.RefundDTO, error)
 	FindOrderById(uuid uuid.UUID) (dto.OrderDTO, error)
 	FindOrderByReference(reference string) (dto.OrderDTO, error)
 	FindAllOrders(pageable order_repository.OrderPageable) ([]dto.OrderDTO, repository.Pagination, error)
 	VerifyOrderPayment(reference string) error
+	ExpireUnpaidOrders(ttl time.Duration) (int, error)
+	GetOrderTransitions() []dto.OrderTransitionDTO
 	ProcessOrder(orderId uuid.UUID) error
 	OutForDelivery(orderId uuid.UUID) error
 	Delivered(orderId uuid.UUID) error
 }
 
 type orderService struct {
+	database                  database.DatabaseInterface
 	orderRepository           order_repository.OrderRepositoryInterface
 	orderItemService          OrderItemServiceInterface
 	orderStatusService        OrderStatusServiceInterface
 	orderStatusHistoryService OrderStatusHistoryServiceInterface
+	shippingAddressService    ShippingAddressServiceInterface
+	shippingTypeService       ShippingTypeServiceInterface
+	couponService             CouponServiceInterface
+	cartService               CartServiceInterface
 	productService            core_service.ProductServiceInterface
+	inventoryService          core_service.InventoryServiceInterface
 	transactionService        finance_service.TransactionServiceInterface
+	currencyService           finance_service.CurrencyServiceInterface
+	walletService             finance_service.WalletServiceInterface
 	paymentGatewayService     payment_gateway_service.PaymentGatewayServiceInterface
+	refundService             RefundServiceInterface
 	userService               userService.UserServiceInterface
+	emailService              service.EmailServiceInterface
 }
 
 func NewOrderService(
+	database database.DatabaseInterface,
 	orderRepository order_repository.OrderRepositoryInterface,
 	orderItemService OrderItemServiceInterface,
 	orderStatusService OrderStatusServiceInterface,
 	orderStatusHistoryService OrderStatusHistoryServiceInterface,
+	shippingAddressService ShippingAddressServiceInterface,
+	shippingTypeService ShippingTypeServiceInterface,
+	couponService CouponServiceInterface,
+	cartService CartServiceInterface,
 	productService core_service.ProductServiceInterface,
+	inventoryService core_service.InventoryServiceInterface,
 	transactionService finance_service.TransactionServiceInterface,
+	currencyService finance_service.CurrencyServiceInterface,
+	walletService finance_service.WalletServiceInterface,
 	paymentGatewayService payment_gateway_service.PaymentGatewayServiceInterface,
+	refundService RefundServiceInterface,
 	userService userService.UserServiceInterface,
+	emailService service.EmailServiceInterface,
 ) OrderServiceInterface {
 
 	return &orderService{
+		database:                  database,
 		orderRepository:           orderRepository,
 		orderItemService:          orderItemService,
 		orderStatusService:        orderStatusService,
 		orderStatusHistoryService: orderStatusHistoryService,
+		shippingAddressService:    shippingAddressService,
+		shippingTypeService:       shippingTypeService,
+		couponService:             couponService,
+		cartService:               cartService,
 		productService:            productService,
+		inventoryService:          inventoryService,
 		transactionService:        transactionService,
+		currencyService:           currencyService,
+		walletService:             walletService,
 		paymentGatewayService:     paymentGatewayService,
+		refundService:             refundService,
 		userService:               userService,
+		emailService:              emailService,
 	}
 }
 
+// withTx returns a copy of the service whose writes go through tx.
+func (o *orderService) withTx(tx database.DatabaseInterface) *orderService {
+	txService := *o
+
+	txService.database = tx
+	txService.orderRepository = o.orderRepository.WithTx(tx)
+	txService.orderItemService = o.orderItemService.WithTx(tx)
+	txService.orderStatusHistoryService = o.orderStatusHistoryService.WithTx(tx)
+	txService.shippingAddressService = o.shippingAddressService.WithTx(tx)
+	txService.shippingTypeService = o.shippingTypeService.WithTx(tx)
+	txService.couponService = o.couponService.WithTx(tx)
+	txService.cartService = o.cartService.WithTx(tx)
+	txService.productService = o.productService.WithTx(tx)
+	txService.inventoryService = o.inventoryService.WithTx(tx)
+	txService.transactionService = o.transactionService.WithTx(tx)
+	txService.currencyService = o.currencyService.WithTx(tx)
+	txService.walletService = o.walletService.WithTx(tx)
+	txService.emailService = o.emailService.WithTx(tx)
+
+	return &txService
+}
+
 func (o *orderService) ConvertToDTO(order models.Order) dto.OrderDTO {
 	var orderDTO dto.OrderDTO
 
@@ -78,13 +149,30 @@ func (o *orderService) ConvertToDTO(order models.Order) dto.OrderDTO {
 	orderDTO.PaymentMethod = order.PaymentMethod
 	orderDTO.Reference = order.Reference
 	orderDTO.TotalPrice = order.TotalPrice
+	orderDTO.ExchangeRate = order.ExchangeRate
+	orderDTO.WalletAmount = order.WalletAmount
+	orderDTO.WalletTransactionID = order.WalletTransactionID
+	orderDTO.CouponID = order.CouponID
+	orderDTO.Discount = order.Discount
 	orderDTO.StatusUUID = order.StatusID
+	orderDTO.ShippingAddressID = order.ShippingAddressID
+	orderDTO.ShippingAddress = dto.OrderShippingAddressDTO(order.ShippingAddress)
+	orderDTO.ShippingTypeID = order.ShippingTypeID
 	orderDTO.User = o.userService.ConvertToDTO(order.User)
 	orderDTO.Status = o.orderStatusService.ConvertToDTO(order.Status)
 	orderDTO.Transaction = o.transactionService.ConvertToDTO(order.Transaction)
 	for _, item := range order.OrderItems {
 		orderDTO.Items = append(orderDTO.Items, o.orderItemService.ConvertToDTO(item))
 	}
+	for _, charge := range order.Charges {
+		orderDTO.Charges = append(orderDTO.Charges, dto.OrderChargeDTO{
+			DTO:       dto.DTO{ID: charge.ID, CreatedAt: charge.CreatedAt, UpdatedAt: charge.UpdatedAt},
+			OrderUUID: charge.OrderID,
+			Type:      charge.Type,
+			Name:      charge.Name,
+			Amount:    charge.Amount,
+		})
+	}
 	for _, item := range order.StatusHistory {
 		orderDTO.StatusHistory = append(orderDTO.StatusHistory, o.orderStatusHistoryService.ConvertToDTO(item))
 	}
@@ -104,7 +192,24 @@ func (o *orderService) ConvertToModel(orderDTO dto.OrderDTO) models.Order {
 	order.PaymentMethod = orderDTO.PaymentMethod
 	order.Reference = orderDTO.Reference
 	order.TotalPrice = orderDTO.TotalPrice
+	order.Currency = orderDTO.TotalPrice.Currency
+	order.ExchangeRate = orderDTO.ExchangeRate
+	order.WalletAmount = orderDTO.WalletAmount
+	order.WalletTransactionID = orderDTO.WalletTransactionID
+	order.CouponID = orderDTO.CouponID
+	order.Discount = orderDTO.Discount
 	order.StatusID = orderDTO.StatusUUID
+	order.ShippingAddressID = orderDTO.ShippingAddressID
+	order.ShippingAddress = models.OrderShippingAddress(orderDTO.ShippingAddress)
+	order.ShippingTypeID = orderDTO.ShippingTypeID
+	for _, charge := range orderDTO.Charges {
+		order.Charges = append(order.Charges, models.OrderCharge{
+			OrderID: charge.OrderUUID,
+			Type:    charge.Type,
+			Name:    charge.Name,
+			Amount:  charge.Amount,
+		})
+	}
 	order.CreatedAt = orderDTO.CreatedAt
 	order.UpdatedAt = orderDTO.UpdatedAt
 	order.DeletedAt.Time = orderDTO.DeletedAt
@@ -113,11 +218,15 @@ func (o *orderService) ConvertToModel(orderDTO dto.OrderDTO) models.Order {
 }
 
 // CheckoutOrder implements OrderServiceInterface.
+// The stock reservation, payment transaction, order and its items are written in a single database transaction,
+// so a failure at any of those steps leaves nothing behind. The gateway is called once they are committed, so the
+// stock and coupon locks are not held while it answers, and a payment can never be made for an order that was not saved.
+// When the gateway fails the order is cancelled, which gives back its stock, coupon and wallet hold. Should that fail
+// too, the order waits for ExpireUnpaidOrders. A payment made anyway, e.g. when the gateway answered after we gave up,
+// is reported by reconciliation as status_differs for the order to be refunded.
 func (o *orderService) CheckoutOrder(order dto.CreateOrderDTO) (string, int, error) {
-	var orderDto dto.OrderDTO
-	var totalPrice float64
-	var paymentUrl string
-	totalPrice, calcErr := o.CalculateTotalPrice(order)
+	var newOrder models.Order
+	var trans dto.TransactionDTO
 
 	snowflake, err := helper.GenerateSnowflakeID()
 
@@ -125,265 +234,352 @@ func (o *orderService) CheckoutOrder(order dto.CreateOrderDTO) (string, int, err
 		return "", constants.ServerErrorServiceUnavailable, err
 	}
 
-	if calcErr != nil {
-		return "", constants.ServerErrorServiceUnavailable, calcErr
-	}
-
-	trans, paymentUrl, err := o.PayWithGateway(order.UserID, totalPrice, order.PaymentMethod)
-	if err != nil {
-		if err == payment_gateway_service.ErrPaymentInitialization {
-			return "", constants.PaymentGatewayError, err
-		}
+	err = o.database.Transaction(func(tx database.DatabaseInterface) error {
+		newOrder, trans, err = o.withTx(tx).placeOrder(order, helper.Int64ToString(snowflake))
 
-		return "", constants.ServerErrorServiceUnavailable, err
-	}
+		return err
+	})
 
-	orderStatus, err := o.orderStatusService.StatusOrderPlaced()
 	if err != nil {
-		return "", constants.ServerErrorServiceUnavailable, err
+		return "", checkoutErrorCode(err), err
 	}
 
-	// Create order
-	orderDto.UserID = order.UserID
-	orderDto.TransactionID = trans.ID
-	orderDto.CouponID = order.CouponID
-	orderDto.StatusUUID = orderStatus.ID
-	orderDto.PaymentMethod = order.PaymentMethod
-	orderDto.Reference = helper.Int64ToString(snowflake)
-	orderDto.TotalPrice = totalPrice
+	var paymentUrl string
 
-	// Save order
-	newOrder := o.ConvertToModel(orderDto)
+	if trans.Method != finance_service.TransactionMethodWallet {
+		paymentUrl, err = o.InitializeGatewayPayment(trans)
 
-	newOrder, err = o.orderRepository.CreateOrder(newOrder)
+		if err != nil {
+			// the error the customer sees is the gateway's, not the cancellation's
+			_ = o.settlePayment(trans.ID, newOrder.ID, finance_service.TransactionStatusFailed)
 
-	if err != nil {
-		return "", constants.ServerErrorServiceUnavailable, err
+			return "", checkoutErrorCode(err), err
+		}
 	}
 
-	// create order items
-	if err := o.CreateOrderItems(newOrder.ID, order.Items); err != nil {
-		return "", constants.ServerErrorServiceUnavailable, err
+	// the order is placed, a cart that could not be emptied is only left as it was
+	if order.FromCart {
+		_ = o.cartService.ClearCart(order.UserID, "")
 	}
 
-	// remove quantity from product stock
-	if err := o.RemoveQuantityFromProductStock(order.Items); err != nil {
-		return "", constants.ServerErrorServiceUnavailable, err
+	return paymentUrl, constants.OrderPlacedSuccessfully, nil
+}
+
+// checkoutErrorCode is the status code CheckoutOrder answers err with.
+func checkoutErrorCode(err error) int {
+	switch {
+	case errors.Is(err, core_service.ErrInsufficientStock):
+		return constants.ItemOutOfStock
+	case errors.Is(err, ErrCartEmpty):
+		return constants.CartIsEmpty
+	case errors.Is(err, core_service.ErrVariantRequired), errors.Is(err, core_service.ErrVariantNotFound):
+		return constants.InvalidItemID
+	case errors.Is(err, ErrShippingAddressRequired):
+		return constants.InvalidShippingAddress
+	case errors.Is(err, ErrShippingTypeUnavailable):
+		return constants.ShippingMethodNotAvailable
+	case errors.Is(err, ErrCouponMinimumNotMet):
+		return constants.MinimumOrderAmountNotMet
+	case errors.Is(err, ErrCouponUsageLimitReached):
+		return constants.CouponUsageLimitReached
+	case errors.Is(err, ErrInvalidCoupon), errors.Is(err, ErrCouponExpired), errors.Is(err, ErrCouponNotApplicable):
+		return constants.InvalidCoupon
+	case errors.Is(err, payment_gateway_service.ErrPaymentInitialization):
+		return constants.PaymentGatewayError
+	case errors.Is(err, payment_gateway_service.ErrPaymentGatewayDisabled):
+		return constants.InvalidPaymentMethod
+	case errors.Is(err, finance_service.ErrCurrencyNotSupported), errors.Is(err, finance_service.ErrWalletCurrency):
+		return constants.CurrencyNotSupported
+	case errors.Is(err, finance_service.ErrInsufficientWalletBalance), errors.Is(err, ErrWalletAmountExceedsTotal):
+		return constants.InsufficientFunds
 	}
 
-	// create status history
-	_, err = o.orderStatusHistoryService.CreateOrderStatusHistory(dto.OrderStatusHistoryDTO{
-		OrderUUID:  newOrder.ID,
-		StatusUUID: newOrder.StatusID,
-	})
+	return constants.ServerErrorServiceUnavailable
+}
 
-	if err != nil {
-		return "", constants.ServerErrorServiceUnavailable, err
-	}
+// placeOrder runs the checkout steps and returns the order with the transaction that pays for it.
+// It expects o to be bound to a transaction. An order from the cart takes the cart's items. The order is priced in the
+// base currency, then converted to the currency the customer pays in at today's rate.
+// The wallet amount is held in the wallet until the gateway payment settles the order, an order paid in full
+// from the wallet is confirmed straight away.
+func (o *orderService) placeOrder(order dto.CreateOrderDTO, reference string) (models.Order, dto.TransactionDTO, error) {
+	var orderDto dto.OrderDTO
 
-	return paymentUrl, constants.OrderPlacedSuccessfully, nil
+	if order.FromCart {
+		items, err := o.cartService.CheckoutItems(order.UserID)
 
-}
+		if err != nil {
+			return models.Order{}, dto.TransactionDTO{}, err
+		}
+
+		order.Items = items
+	}
 
-// CancelOrder implements OrderServiceInterface.
-func (o *orderService) CancelOrder(orderId uuid.UUID) error {
-	// get order
-	order, err := o.FindOrderById(orderId)
+	shippingAddress, err := o.shippingAddressService.FindOrderShippingAddress(order.UserID, order.ShippingAddressID)
 
 	if err != nil {
-		return err
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	// check order statuses
-	switch order.Status.ShortName {
-	case ORDER_PLACED:
-		break
-	case AWAITING_CONFIRMATION:
-		return fmt.Errorf("order is awaiting confirmation")
-	case ORDER_PROCESSING:
-		return fmt.Errorf("order is processing")
-	case OUT_FOR_DELIVERY:
-		return fmt.Errorf("order is out for delivery")
-	case DELIVERED:
-		return fmt.Errorf("order is already delivered")
-	case CANCELLED:
-		return fmt.Errorf("order is already cancelled")
-	}
-
-	// update order status to cancelled
-	status, err := o.orderStatusService.StatusCancelled()
+	weight, err := o.CalculateTotalWeight(order.Items)
+
 	if err != nil {
-		return err
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	// update order status
-	if err = o.UpdateOrderStatus(orderId, status.ID); err != nil {
-		return err
-	}
+	// clients from before shipping types choose none, their orders go with the default one
+	if order.ShippingTypeID == uuid.Nil {
+		shippingType, err := o.shippingTypeService.DefaultShippingType()
 
-	return err
-}
+		if err != nil {
+			return models.Order{}, dto.TransactionDTO{}, err
+		}
 
-// Update order status to awaiting confirmation
-func (o *orderService) ConfirmOrder(id uuid.UUID) error {
+		order.ShippingTypeID = shippingType.ID
+	}
+
+	shippingCharge, err := o.shippingTypeService.ShippingCharge(order.ShippingTypeID, shippingAddress.StateUUID, weight)
 
-	// update order status to awaiting confirmation
-	statusConfirm, err := o.orderStatusService.StatusAwaitingConfirmation()
 	if err != nil {
-		return err
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	err = o.UpdateOrderStatus(id, statusConfirm.ID)
+	charges := []dto.OrderChargeDTO{shippingCharge}
+
+	price, err := o.CalculateTotalPrice(order, charges)
 
 	if err != nil {
-		return err
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	return err
-}
-
-func (o *orderService) ProcessOrder(orderId uuid.UUID) error {
-	// get order
-	order, err := o.FindOrderById(orderId)
+	currency := finance_service.CurrencyCode(order.Currency)
+	exchangeRate, err := o.currencyService.ExchangeRate(currency)
 
 	if err != nil {
-		return err
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	// check order statuses
-	switch order.Status.ShortName {
-	case ORDER_PLACED:
-		return fmt.Errorf("order is not yet confirmed")
-	case ORDER_PROCESSING:
-		return fmt.Errorf("order is already processing")
-	case OUT_FOR_DELIVERY:
-		return fmt.Errorf("order is out for delivery")
-	case DELIVERED:
-		return fmt.Errorf("order is already delivered")
-	case CANCELLED:
-		return fmt.Errorf("order is cancelled")
+	price, charges = ConvertOrderPrice(price, charges, currency, exchangeRate)
+
+	walletAmount := money.New(order.WalletAmount.Amount, currency)
+
+	if walletAmount.IsPositive() && currency != money.BaseCurrency {
+		return models.Order{}, dto.TransactionDTO{}, finance_service.ErrWalletCurrency
 	}
 
-	// update order status to order processing
-	statusProcessing, err := o.orderStatusService.StatusOrderProcessing()
-	if err != nil {
-		return err
+	if walletAmount.Amount > price.TotalPrice.Amount {
+		return models.Order{}, dto.TransactionDTO{}, ErrWalletAmountExceedsTotal
 	}
 
-	err = o.UpdateOrderStatus(orderId, statusProcessing.ID)
+	var trans, walletTrans dto.TransactionDTO
 
-	if err != nil {
-		return err
+	if walletAmount.IsPositive() {
+		walletTrans, err = o.walletService.Debit(dto.TransactionDTO{
+			UserID:      order.UserID,
+			Amount:      walletAmount,
+			Description: TransactionDescription,
+			ShortDesc:   TransactionShortDesc,
+		})
+
+		if err != nil {
+			return models.Order{}, dto.TransactionDTO{}, err
+		}
 	}
 
-	return err
-}
+	if gatewayAmount := price.TotalPrice.Sub(walletAmount); gatewayAmount.IsPositive() || !walletAmount.IsPositive() {
+		trans, err = o.CreatePaymentTransaction(order.UserID, gatewayAmount, order.PaymentMethod)
 
-func (o *orderService) OutForDelivery(orderId uuid.UUID) error {
-	// get order
-	order, err := o.FindOrderById(orderId)
+		if err != nil {
+			return models.Order{}, dto.TransactionDTO{}, err
+		}
+
+		if walletAmount.IsPositive() {
+			orderDto.WalletTransactionID = &walletTrans.ID
+		}
+	} else {
+		// paid in full from the wallet
+		trans = walletTrans
+	}
 
+	orderStatus, err := o.orderStatusService.StatusOrderPlaced()
 	if err != nil {
-		return err
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	// check order statuses
-	switch order.Status.ShortName {
-	case ORDER_PLACED:
-		return fmt.Errorf("order is not yet confirmed")
-	case AWAITING_CONFIRMATION:
-		return fmt.Errorf("order is awaiting confirmation")
-	case OUT_FOR_DELIVERY:
-		return fmt.Errorf("order is already out for delivery")
-	case DELIVERED:
-		return fmt.Errorf("order is already delivered")
-	case CANCELLED:
-		return fmt.Errorf("order is cancelled")
+	// Create order
+	orderDto.UserID = order.UserID
+	orderDto.TransactionID = trans.ID
+	orderDto.CouponID = price.CouponID
+	orderDto.Discount = price.Discount
+	orderDto.StatusUUID = orderStatus.ID
+	orderDto.PaymentMethod = order.PaymentMethod
+	if trans.Method == finance_service.TransactionMethodWallet {
+		orderDto.PaymentMethod = finance_service.TransactionMethodWallet
 	}
+	orderDto.Reference = reference
+	orderDto.TotalPrice = price.TotalPrice
+	orderDto.ExchangeRate = exchangeRate
+	orderDto.WalletAmount = walletAmount
+	orderDto.ShippingAddressID = &shippingAddress.ID
+	orderDto.ShippingAddress = OrderShippingAddress(shippingAddress)
+	orderDto.ShippingTypeID = &order.ShippingTypeID
+	orderDto.Charges = charges
+
+	// Save order
+	newOrder := o.ConvertToModel(orderDto)
+
+	newOrder, err = o.orderRepository.CreateOrder(newOrder)
 
-	// update order status to out for delivery
-	status, err := o.orderStatusService.StatusOutForDelivery()
 	if err != nil {
-		return err
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	// update order status
-	if err = o.UpdateOrderStatus(orderId, status.ID); err != nil {
-		return err
+	// create order items
+	if err := o.CreateOrderItems(newOrder.ID, price.Items); err != nil {
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	return err
-}
-
-func (o *orderService) Delivered(orderId uuid.UUID) error {
-	// get order
-	order, err := o.FindOrderById(orderId)
+	orderItems, err := o.orderItemService.FindOrderItemsByOrderId(newOrder.ID)
 
 	if err != nil {
-		return err
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	// check order statuses
-	switch order.Status.ShortName {
-	case ORDER_PLACED:
-		return fmt.Errorf("order is not yet confirmed")
-	case AWAITING_CONFIRMATION:
-		return fmt.Errorf("order is awaiting confirmation")
-	case ORDER_PROCESSING:
-		return fmt.Errorf("order is processing")
-	case DELIVERED:
-		return fmt.Errorf("order is already delivered")
-	case CANCELLED:
-		return fmt.Errorf("order is cancelled")
+	// take the ordered quantities off the stock
+	if err := o.inventoryService.ReserveOrderItems(newOrder.Reference, orderItems); err != nil {
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	// update order status to delivered
-	status, err := o.orderStatusService.StatusDelivered()
+	// create status history
+	_, err = o.orderStatusHistoryService.CreateOrderStatusHistory(dto.OrderStatusHistoryDTO{
+		OrderUUID:  newOrder.ID,
+		StatusUUID: newOrder.StatusID,
+	})
+
 	if err != nil {
-		return err
+		return models.Order{}, dto.TransactionDTO{}, err
 	}
 
-	// update order status
-	if err = o.UpdateOrderStatus(orderId, status.ID); err != nil {
-		return err
+	if trans.Method == finance_service.TransactionMethodWallet {
+		return newOrder, trans, o.settlePayment(trans.ID, newOrder.ID, finance_service.TransactionStatusSuccess)
 	}
 
-	return err
+	return newOrder, trans, nil
 }
 
-// CreateOrderItems
-func (o *orderService) CreateOrderItems(orderId uuid.UUID, items []dto.CreateOrderItemDTO) error {
-	var orderItemDtos []dto.OrderItemDTO
+// CancelOrder implements OrderServiceInterface for the customer who placed the order, it is not found for anyone else.
+// Only an order that is not paid for can be cancelled by its customer, an admin cancels a paid one with CancelPaidOrder.
+func (o *orderService) CancelOrder(orderId uuid.UUID, userId uuid.UUID) error {
+	return o.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := o.withTx(tx)
 
-	for _, item := range items {
-		var price float64
+		// locked, so the payment cannot confirm the order while it is cancelled
+		order, err := txService.orderRepository.FindOrderByIdForUpdate(orderId)
 
-		// Get product
-		product, err := o.productService.FindProductByUUID(item.ProductUUID)
 		if err != nil {
 			return err
 		}
 
-		price = product.Price * float64(item.Quantity)
+		if order.UserID != userId {
+			return gorm.ErrRecordNotFound
+		}
 
-		orderItemDtos = append(orderItemDtos, dto.OrderItemDTO{
-			OrderUUID:   orderId,
-			ProductUUID: product.ID,
-			Quantity:    item.Quantity,
-			Price:       price,
-		})
+		status, err := txService.orderStatusService.FindOrderStatusById(order.StatusID)
+
+		if err != nil {
+			return err
+		}
+
+		if status.ShortName != ORDER_PLACED {
+			return &IllegalTransitionError{From: status.ShortName, To: CANCELLED, Reason: "only an order that is not paid for can be cancelled, ask for a refund instead"}
+		}
+
+		cancelled, err := txService.orderStatusService.StatusCancelled()
+
+		if err != nil {
+			return err
+		}
+
+		return txService.updateOrderStatus(orderId, cancelled.ID)
+	})
+}
+
+// CancelPaidOrder implements OrderServiceInterface for admins. The order is cancelled and its stock released, and
+// whatever is left of its payment is refunded with refund's reason, to the wallet when ToWallet is set.
+// The cancellation and the refund are saved together, so an order is not cancelled when its refund cannot be made,
+// e.g. a gateway refund of an order paid partly from the wallet. An order that was not paid for is only cancelled and
+// the refund returned is empty. When the gateway turns the refund down the order stays cancelled and
+// ErrCancelledOrderNotRefunded is returned, the refund can be made again through RefundOrder.
+func (o *orderService) CancelPaidOrder(orderId uuid.UUID, refund dto.CreateRefundDTO) (dto.RefundDTO, error) {
+	var refundDto dto.RefundDTO
+
+	err := o.database.Transaction(func(tx database.DatabaseInterface) error {
+		if err := o.withTx(tx).moveOrderTo(orderId, CANCELLED); err != nil {
+			return err
+		}
+
+		var err error
+
+		refundDto, err = o.refundService.WithTx(tx).CreateRefund(orderId, dto.CreateRefundDTO{Reason: refund.Reason, ToWallet: refund.ToWallet})
+
+		if errors.Is(err, ErrOrderNotPaid) || errors.Is(err, ErrNothingToRefund) {
+			return nil
+		}
+
+		return err
+	})
+
+	if err != nil || refundDto.ID == uuid.Nil {
+		return dto.RefundDTO{}, err
+	}
+
+	refundDto, err = o.refundService.SendRefund(refundDto.ID)
+
+	if err != nil {
+		return dto.RefundDTO{}, fmt.Errorf("%w: %w", ErrCancelledOrderNotRefunded, err)
 	}
 
-	err := o.orderItemService.BatchCreateOrderItem(orderId.String(), orderItemDtos)
+	return refundDto, nil
+}
+
+// Update order status to awaiting confirmation
+func (o *orderService) ConfirmOrder(id uuid.UUID) error {
+	return o.moveOrderTo(id, AWAITING_CONFIRMATION)
+}
+
+func (o *orderService) ProcessOrder(orderId uuid.UUID) error {
+	return o.moveOrderTo(orderId, ORDER_PROCESSING)
+}
+
+func (o *orderService) OutForDelivery(orderId uuid.UUID) error {
+	return o.moveOrderTo(orderId, OUT_FOR_DELIVERY)
+}
 
+func (o *orderService) Delivered(orderId uuid.UUID) error {
+	return o.moveOrderTo(orderId, DELIVERED)
+}
+
+// moveOrderTo moves the order to the status with the given short name.
+func (o *orderService) moveOrderTo(orderId uuid.UUID, shortName string) error {
+	status, err := o.orderStatusService.FindOrderStatusByShortName(shortName)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return o.UpdateOrderStatus(orderId, status.ID)
+}
+
+// CreateOrderItems saves the order lines worked out by CalculateTotalPrice.
+func (o *orderService) CreateOrderItems(orderId uuid.UUID, items []dto.OrderItemDTO) error {
+	for i := range items {
+		items[i].OrderUUID = orderId
+	}
+
+	return o.orderItemService.BatchCreateOrderItem(orderId.String(), items)
 }
 
 // verify order by payment reference
+// It is safe to call repeatedly for the same reference, e.g. from a client poll and a gateway webhook.
 func (o *orderService) VerifyOrderPayment(reference string) error {
 	// get transaction
 	transaction, err := o.transactionService.FindTransactionByReference(reference)
@@ -392,12 +588,13 @@ func (o *orderService) VerifyOrderPayment(reference string) error {
 		return err
 	}
 
-	if transaction.Status == finance_service.TransactionStatusSuccess {
+	// already settled by an earlier verification or webhook delivery
+	if transaction.Status != finance_service.TransactionStatusPending {
 		return nil
 	}
 
 	// verify transaction from payment gateway
-	gatewayResp, err := o.paymentGatewayService.VerifyPayment(reference, transaction.Vendor)
+	gatewayResp, err := o.paymentGatewayService.VerifyPayment(reference, transaction.Amount, transaction.Vendor)
 
 	if err != nil {
 		return err
@@ -417,33 +614,112 @@ func (o *orderService) VerifyOrderPayment(reference string) error {
 		return fmt.Errorf("payment verification is still pending: %s", gatewayResp.Message)
 	}
 
-	if gatewayResp.PaymentStatus == finance_service.TransactionStatusFailed {
-		orderStatus, err := o.orderStatusService.StatusCancelled()
-		if err != nil {
-			return err
+	return o.settlePayment(transaction.ID, order.ID, gatewayResp.PaymentStatus)
+}
+
+// settlePayment confirms or cancels the order together with the transaction that pays for it.
+// It does nothing when the transaction was settled by someone else first.
+func (o *orderService) settlePayment(transactionId uuid.UUID, orderId uuid.UUID, paymentStatus string) error {
+	return o.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := o.withTx(tx)
+
+		if paymentStatus == finance_service.TransactionStatusFailed {
+			_, err := txService.transactionService.FailTransaction(transactionId.String())
+			if err == finance_service.ErrTransactionAlreadyProcessed {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			orderStatus, err := txService.orderStatusService.StatusCancelled()
+			if err != nil {
+				return err
+			}
+
+			return txService.UpdateOrderStatus(orderId, orderStatus.ID)
 		}
 
-		if err = o.UpdateOrderStatus(order.ID, orderStatus.ID); err != nil {
-			return err
+		_, err := txService.transactionService.ConfirmTransaction(transactionId.String())
+		if err == finance_service.ErrTransactionAlreadyProcessed {
+			return nil
 		}
 
-		_, err = o.transactionService.FailTransaction(transaction.ID.String())
 		if err != nil {
 			return err
 		}
 
-		return nil
+		return txService.ConfirmOrder(orderId)
+	})
+}
+
+// ExpireUnpaidOrders implements OrderServiceInterface.
+// Every order unpaid for longer than ttl is checked with its gateway one last time. Paid orders are confirmed, and
+// orders whose payment failed are cancelled and their stock released. A payment that is still pending, e.g. while the
+// customer is on the payment page, is left to its webhook until the order is older than UnpaidOrderMaxAge.
+// An order that cannot be settled does not hold up the others, the errors are returned together with the number of
+// orders cancelled.
+func (o *orderService) ExpireUnpaidOrders(ttl time.Duration) (int, error) {
+	var cancelled int
+	var errs []error
+	var last models.Order
+
+	now := time.Now()
+
+	for {
+		orders, err := o.orderRepository.FindUnpaidOrders(now.Add(-ttl), last.CreatedAt, last.ID, ExpireUnpaidOrdersBatchSize)
+
+		if err != nil {
+			return cancelled, errors.Join(append(errs, err)...)
+		}
+
+		for _, order := range orders {
+			last = order
+
+			paymentStatus, settle := o.unpaidOrderStatus(order, now)
+
+			if !settle {
+				continue
+			}
+
+			if err := o.settlePayment(order.TransactionID, order.ID, paymentStatus); err != nil {
+				fmt.Printf("Failed to expire order %s: %s\n", order.ID, err)
+				errs = append(errs, fmt.Errorf("order %s: %w", order.ID, err))
+
+				continue
+			}
+
+			if paymentStatus == finance_service.TransactionStatusFailed {
+				cancelled++
+			}
+		}
+
+		if len(orders) < ExpireUnpaidOrdersBatchSize {
+			return cancelled, errors.Join(errs...)
+		}
 	}
+}
 
-	_, err = o.transactionService.ConfirmTransaction(transaction.ID.String())
+// unpaidOrderStatus is what an unpaid order's payment is settled as, and false when it is left for now.
+func (o *orderService) unpaidOrderStatus(order models.Order, now time.Time) (string, bool) {
+	gatewayResp, err := o.paymentGatewayService.VerifyPayment(order.Transaction.Reference, order.Transaction.Amount, order.Transaction.Vendor)
 
+	// the gateway could not be reached, try again on the next run
 	if err != nil {
-		return err
+		return "", false
 	}
 
-	err = o.ConfirmOrder(order.ID)
+	if gatewayResp.Status && gatewayResp.PaymentStatus != finance_service.TransactionStatusPending {
+		return gatewayResp.PaymentStatus, true
+	}
 
-	return err
+	// still pending, or a payment the gateway does not know yet because the customer never opened it
+	if order.CreatedAt.Before(now.Add(-UnpaidOrderMaxAge)) {
+		return finance_service.TransactionStatusFailed, true
+	}
+
+	return "", false
 }
 
 // FindOrderById implements OrderServiceInterface.
@@ -487,7 +763,16 @@ func (o *orderService) FindAllOrders(pageable order_repository.OrderPageable) ([
 
 }
 
+// UpdateOrderStatus moves the order to statusId and records it in the status history.
+// The move must be one of OrderTransitions and pass its guards, otherwise an IllegalTransitionError is returned.
+// The transition's hooks run in the same database transaction as the status change.
 func (o *orderService) UpdateOrderStatus(orderId uuid.UUID, statusId uuid.UUID) error {
+	return o.database.Transaction(func(tx database.DatabaseInterface) error {
+		return o.withTx(tx).updateOrderStatus(orderId, statusId)
+	})
+}
+
+func (o *orderService) updateOrderStatus(orderId uuid.UUID, statusId uuid.UUID) error {
 
 	order, err := o.orderRepository.FindOrderById(orderId)
 
@@ -501,99 +786,230 @@ func (o *orderService) UpdateOrderStatus(orderId uuid.UUID, statusId uuid.UUID)
 		return err
 	}
 
-	order.StatusID = orderStatus.ID
+	transition, err := findTransition(order.Status.ShortName, orderStatus.ShortName)
+
+	if err != nil {
+		return err
+	}
+
+	for _, guard := range transition.Guards {
+		if err := guard.Check(o, order); err != nil {
+			return &IllegalTransitionError{From: transition.From, To: transition.To, Reason: err.Error()}
+		}
+	}
 
-	_, err = o.orderRepository.UpdateOrder(order)
+	affected, err := o.orderRepository.UpdateOrderStatus(order.ID, order.StatusID, orderStatus.ID)
 
 	if err != nil {
 		return err
 	}
 
+	// someone else changed the status since the order was read
+	if affected == 0 {
+		return &IllegalTransitionError{From: transition.From, To: transition.To, Reason: "order status has changed"}
+	}
+
 	_, err = o.orderStatusHistoryService.CreateOrderStatusHistory(dto.OrderStatusHistoryDTO{
 		OrderUUID:  order.ID,
 		StatusUUID: orderStatus.ID,
 	})
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range transition.Hooks {
+		if err := hook(o, order); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
+// OrderPrice is what an order costs, worked out by CalculateTotalPrice.
+type OrderPrice struct {
+	Items      []dto.OrderItemDTO // the order lines, each with its share of the discount
+	CouponID   *uuid.UUID
+	Discount   money.Money
+	TotalPrice money.Money
 }
 
-func (o *orderService) CalculateTotalPrice(order dto.CreateOrderDTO) (float64, error) {
-	var totalPrice float64
+// CalculateTotalPrice is what the customer pays for the order's items and its charges, like the shipping fee,
+// less the discount of the order's coupon. The coupon is checked and locked, so o must be bound to a transaction.
+func (o *orderService) CalculateTotalPrice(order dto.CreateOrderDTO, charges []dto.OrderChargeDTO) (OrderPrice, error) {
+	var price OrderPrice
 
 	// calculate total price in order items
-	totalItemsPrice, err := o.CalculateTotalItemsPrice(order.Items)
+	items, err := o.CalculateOrderItems(order.Items)
 
 	if err != nil {
-		return 0, err
+		return price, err
 	}
 
-	totalPrice += totalItemsPrice
+	price.Items = items
+	price.Discount = money.New(0, money.BaseCurrency)
+	price.TotalPrice = money.New(0, money.BaseCurrency)
 
-	return totalPrice, nil
-}
-
-func (o *orderService) RemoveQuantityFromProductStock(items []dto.CreateOrderItemDTO) error {
-	for _, item := range items {
-		product, err := o.productService.FindProductByUUID(item.ProductUUID)
+	if order.CouponCode != "" {
+		coupon, discounts, err := o.couponService.RedeemCoupon(order.CouponCode, order.UserID, items)
 
 		if err != nil {
-			return err
+			return price, err
+		}
+
+		price.CouponID = &coupon.ID
+
+		for i := range price.Items {
+			price.Items[i].Discount = discounts[i]
+			price.Discount = price.Discount.Add(discounts[i])
 		}
+	}
 
-		product.Stock = product.Stock - item.Quantity
+	for _, item := range price.Items {
+		price.TotalPrice = price.TotalPrice.Add(item.Price).Sub(item.Discount)
+	}
 
-		_, err = o.productService.UpdateProduct(product)
+	for _, charge := range charges {
+		price.TotalPrice = price.TotalPrice.Add(charge.Amount)
+	}
 
-		if err != nil {
-			return err
+	return price, nil
+}
+
+// ConvertOrderPrice is price and its charges in currency, rate being the price of one unit of currency in the base currency.
+// Each line is converted on its own and the totals are added up from them, so an order always adds up in its currency.
+func ConvertOrderPrice(price OrderPrice, charges []dto.OrderChargeDTO, currency string, rate float64) (OrderPrice, []dto.OrderChargeDTO) {
+	converted := OrderPrice{
+		CouponID:   price.CouponID,
+		Discount:   money.New(0, currency),
+		TotalPrice: money.New(0, currency),
+	}
+	convertedCharges := []dto.OrderChargeDTO{}
+
+	for _, item := range price.Items {
+		item.Price = item.Price.Convert(currency, rate)
+		item.Discount = item.Discount.Convert(currency, rate)
+
+		converted.Items = append(converted.Items, item)
+		converted.Discount = converted.Discount.Add(item.Discount)
+		converted.TotalPrice = converted.TotalPrice.Add(item.Price).Sub(item.Discount)
+	}
+
+	for _, charge := range charges {
+		charge.Amount = charge.Amount.Convert(currency, rate)
+
+		convertedCharges = append(convertedCharges, charge)
+		converted.TotalPrice = converted.TotalPrice.Add(charge.Amount)
+	}
+
+	return converted, convertedCharges
+}
+
+// OrderShippingAddress is the copy of an address kept on the order, so later edits to the address do not change it.
+func OrderShippingAddress(address dto.ShippingAddressDTO) dto.OrderShippingAddressDTO {
+	return dto.OrderShippingAddressDTO{
+		FirstName:       address.FirstName,
+		LastName:        address.LastName,
+		Phone:           address.Phone,
+		AlternatePhone:  address.AlternatePhone,
+		Address:         address.Address,
+		City:            address.City.Name,
+		State:           address.State.Name,
+		Country:         address.State.Country.Name,
+		ClosestLandmark: address.ClosestLandmark,
+	}
+}
+
+// ProductUnitPrice is the price a product, or its variant when it is not nil, sells at. It is the sales price when there is one.
+func ProductUnitPrice(product dto.ProductDTO, variant *dto.ProductVariantDTO) money.Money {
+	price, slashPrice := core_service.VariantPrices(product, variant)
+
+	if slashPrice.IsPositive() {
+		return slashPrice
+	}
+
+	return price
+}
+
+// findOrderItemProduct returns the product of an ordered item and the variant that was chosen, nil when it has none.
+func findOrderItemProduct(productService core_service.ProductServiceInterface, productId string, variantId string) (dto.ProductDTO, *dto.ProductVariantDTO, error) {
+	product, err := productService.FindProductByUUID(productId)
+	if err != nil {
+		return dto.ProductDTO{}, nil, fmt.Errorf("product: %s is not found on this platform", productId)
+	}
+
+	var variantUUID uuid.UUID
+
+	if variantId != "" {
+		if variantUUID, err = uuid.Parse(variantId); err != nil {
+			return product, nil, fmt.Errorf("product: %s: %w", product.Name, core_service.ErrVariantNotFound)
 		}
 	}
 
-	return nil
+	variant, err := core_service.ProductVariant(product, variantUUID)
+	if err != nil {
+		return product, nil, fmt.Errorf("product: %s: %w", product.Name, err)
+	}
+
+	return product, variant, nil
 }
 
-func (o *orderService) CalculateTotalItemsPrice(items []dto.CreateOrderItemDTO) (float64, error) {
-	var totalPrice float64
+// CalculateOrderItems prices the ordered items. The price of a line is its total, refunds are worked out from it.
+func (o *orderService) CalculateOrderItems(items []dto.CreateOrderItemDTO) ([]dto.OrderItemDTO, error) {
+	var orderItems []dto.OrderItemDTO
 
 	for _, item := range items {
-		var price float64
-
-		// Get product
-		product, err := o.productService.FindProductByUUID(item.ProductUUID)
+		product, variant, err := findOrderItemProduct(o.productService, item.ProductUUID, item.VariantUUID)
 		if err != nil {
-			return 0, fmt.Errorf("product: %s is not found on this platform", product.Name)
+			return nil, err
+		}
+
+		// check the stock, the reservation makes the final decision
+		if core_service.VariantStock(product, variant) < item.Quantity {
+			return nil, fmt.Errorf("product: %s: %w", product.Name, core_service.ErrInsufficientStock)
 		}
 
-		// check product stock
-		if product.Stock < item.Quantity {
-			return 0, fmt.Errorf("product: %s stock is not enough", product.Name)
+		orderItem := dto.OrderItemDTO{
+			ProductUUID: product.ID,
+			Quantity:    item.Quantity,
+			Price:       ProductUnitPrice(product, variant).Mul(item.Quantity),
 		}
 
-		// calculate price
-		// check if sales price is not zero
-		if product.SlashPrice > 0 {
-			price = product.SlashPrice * float64(item.Quantity)
-		} else {
-			price = product.Price * float64(item.Quantity)
+		if variant != nil {
+			orderItem.VariantUUID = &variant.ID
 		}
 
-		// Calculate total price
-		totalPrice += price
+		orderItems = append(orderItems, orderItem)
 	}
 
-	return totalPrice, nil
+	return orderItems, nil
 }
 
-func (o *orderService) PayWithGateway(UserID uuid.UUID, amount float64, gateway string) (dto.TransactionDTO, string, error) {
-	user, err := o.userService.FindUserById(UserID.String())
+// CalculateTotalWeight is the weight of the ordered items in kg.
+func (o *orderService) CalculateTotalWeight(items []dto.CreateOrderItemDTO) (float64, error) {
+	var weight float64
 
-	if err != nil {
-		return dto.TransactionDTO{}, "", err
+	for _, item := range items {
+		product, err := o.productService.FindProductByUUID(item.ProductUUID)
+		if err != nil {
+			return 0, fmt.Errorf("product: %s is not found on this platform", item.ProductUUID)
+		}
+
+		weight += product.Weight * float64(item.Quantity)
+	}
+
+	return weight, nil
+}
+
+// CreatePaymentTransaction records the pending debit the gateway payment will settle.
+func (o *orderService) CreatePaymentTransaction(UserID uuid.UUID, amount money.Money, gateway string) (dto.TransactionDTO, error) {
+	if !o.paymentGatewayService.IsEnabled(gateway) {
+		return dto.TransactionDTO{}, payment_gateway_service.ErrPaymentGatewayDisabled
 	}
 
 	// Create transaction
-	newTransaction, err := o.transactionService.CreateTransaction(dto.TransactionDTO{
+	return o.transactionService.CreateTransaction(dto.TransactionDTO{
 		UserID:      UserID,
 		Amount:      amount,
 		Type:        finance_service.TransactionTypeDebit,
@@ -601,28 +1017,33 @@ func (o *orderService) PayWithGateway(UserID uuid.UUID, amount float64, gateway
 		ShortDesc:   TransactionShortDesc,
 		Status:      finance_service.TransactionStatusPending,
 		Method:      finance_service.TransactionMethodGateway,
-		Vendor:      finance_service.TransactionVendorPayStack,
+		Vendor:      gateway, // the vendor decides which gateway VerifyOrderPayment asks later
 	})
+}
+
+// InitializeGatewayPayment starts the payment for transaction and returns the gateway's payment url.
+func (o *orderService) InitializeGatewayPayment(transaction dto.TransactionDTO) (string, error) {
+	user, err := o.userService.FindUserById(transaction.UserID.String())
 
 	if err != nil {
-		return dto.TransactionDTO{}, "", err
+		return "", err
 	}
 
 	// Initialize payment
 	initialize, err := o.paymentGatewayService.InitializePayment(payment_gateway_dto.PaymentInitializationDTO{
-		Amount:    amount,
+		Amount:    transaction.Amount,
 		Email:     user.Email,
-		Reference: newTransaction.Reference,
-		Gateway:   gateway,
+		Reference: transaction.Reference,
+		Gateway:   transaction.Vendor,
 	})
 
 	if err != nil {
-		return dto.TransactionDTO{}, "", err
+		return "", err
 	}
 
 	if !initialize.Status {
-		return dto.TransactionDTO{}, "", payment_gateway_service.ErrPaymentInitialization
+		return "", payment_gateway_service.ErrPaymentInitialization
 	}
 
-	return newTransaction, initialize.PaymentURL, nil
+	return initialize.PaymentURL, nil
 }
@@ -2,6 +2,7 @@ package order_service
 
 import (
 	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
 	"github.com/developer-afo/instashop-ecommerce-api/models"
 	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
 	core_service "github.com/developer-afo/instashop-ecommerce-api/service/core"
@@ -10,7 +11,9 @@ import (
 
 type OrderItemServiceInterface interface {
 	BatchCreateOrderItem(orderUuid string, items []dto.OrderItemDTO) error
+	FindOrderItemsByOrderId(orderId uuid.UUID) ([]dto.OrderItemDTO, error)
 	ConvertToDTO(orderItem models.OrderItem) dto.OrderItemDTO
+	WithTx(tx database.DatabaseInterface) OrderItemServiceInterface
 }
 
 type orderItemService struct {
@@ -28,16 +31,32 @@ func NewOrderItemService(
 	}
 }
 
+// WithTx implements OrderItemServiceInterface.
+func (s *orderItemService) WithTx(tx database.DatabaseInterface) OrderItemServiceInterface {
+	return &orderItemService{
+		orderItemRepository: s.orderItemRepository.WithTx(tx),
+		productService:      s.productService,
+	}
+}
+
 func (s *orderItemService) ConvertToDTO(orderItem models.OrderItem) dto.OrderItemDTO {
 	var orderItemDTO dto.OrderItemDTO
 
+	orderItemDTO.ID = orderItem.ID
 	orderItemDTO.OrderUUID = orderItem.OrderID
 	orderItemDTO.ProductUUID = orderItem.ProductID
+	orderItemDTO.VariantUUID = orderItem.VariantID
 	orderItemDTO.Quantity = orderItem.Quantity
 	orderItemDTO.Price = orderItem.Price
+	orderItemDTO.Discount = orderItem.Discount
 
 	orderItemDTO.Product = s.productService.ConvertToDTO(orderItem.Product)
 
+	if orderItem.Variant != nil {
+		variant := s.productService.VariantToDTO(*orderItem.Variant)
+		orderItemDTO.Variant = &variant
+	}
+
 	return orderItemDTO
 }
 
@@ -47,8 +66,10 @@ func (s *orderItemService) ConvertToModel(orderItemDTO dto.OrderItemDTO) models.
 	orderItem.ID = orderItemDTO.ID
 	orderItem.OrderID = orderItemDTO.OrderUUID
 	orderItem.ProductID = orderItemDTO.ProductUUID
+	orderItem.VariantID = orderItemDTO.VariantUUID
 	orderItem.Quantity = orderItemDTO.Quantity
 	orderItem.Price = orderItemDTO.Price
+	orderItem.Discount = orderItemDTO.Discount
 
 	return orderItem
 }
@@ -68,8 +89,10 @@ func (s *orderItemService) BatchCreateOrderItem(orderUuid string, items []dto.Or
 		orderItem.ID, _ = uuid.NewV7()
 		orderItem.OrderID = orderId
 		orderItem.ProductID = item.ProductUUID
+		orderItem.VariantID = item.VariantUUID
 		orderItem.Quantity = item.Quantity
 		orderItem.Price = item.Price
+		orderItem.Discount = item.Discount
 
 		orderItems = append(orderItems, orderItem)
 	}
@@ -78,3 +101,19 @@ func (s *orderItemService) BatchCreateOrderItem(orderUuid string, items []dto.Or
 
 	return err
 }
+
+func (s *orderItemService) FindOrderItemsByOrderId(orderId uuid.UUID) ([]dto.OrderItemDTO, error) {
+	orderItems := []dto.OrderItemDTO{}
+
+	orderItemModels, err := s.orderItemRepository.FindOrderItemsByOrderId(orderId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, orderItem := range orderItemModels {
+		orderItems = append(orderItems, s.ConvertToDTO(orderItem))
+	}
+
+	return orderItems, nil
+}
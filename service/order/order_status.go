@@ -22,6 +22,7 @@ type OrderStatusServiceInterface interface {
 	CreateOrderStatus(orderStatus models.OrderStatus) (dto.OrderStatusDTO, error)
 	GetOrderStatuses() ([]dto.OrderStatusDTO, error)
 	FindOrderStatusById(id uuid.UUID) (dto.OrderStatusDTO, error)
+	FindOrderStatusByShortName(shortName string) (dto.OrderStatusDTO, error)
 	StatusOrderPlaced() (dto.OrderStatusDTO, error)
 	StatusAwaitingConfirmation() (dto.OrderStatusDTO, error)
 	StatusOrderProcessing() (dto.OrderStatusDTO, error)
@@ -99,6 +100,17 @@ func (o *orderStatusService) FindOrderStatusById(id uuid.UUID) (dto.OrderStatusD
 	return o.ConvertToDTO(orderStatus), nil
 }
 
+// FindOrderStatusByShortName implements OrderStatusServiceInterface.
+func (o *orderStatusService) FindOrderStatusByShortName(shortName string) (dto.OrderStatusDTO, error) {
+	orderStatus, err := o.orderStatusRepository.FindOrderStatusByShortName(shortName)
+
+	if err != nil {
+		return dto.OrderStatusDTO{}, err
+	}
+
+	return o.ConvertToDTO(orderStatus), nil
+}
+
 // StatusOrderPlaced implements OrderStatusServiceInterface.
 func (o *orderStatusService) StatusOrderPlaced() (dto.OrderStatusDTO, error) {
 	orderStatus, err := o.orderStatusRepository.FindOrderStatusByShortName(ORDER_PLACED)
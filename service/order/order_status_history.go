@@ -4,6 +4,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
 	"github.com/developer-afo/instashop-ecommerce-api/models"
 	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
 )
@@ -13,6 +14,7 @@ type OrderStatusHistoryServiceInterface interface {
 	FindOrderStatusHistoryById(uuid uuid.UUID) (dto.OrderStatusHistoryDTO, error)
 	FindOrderStatusHistoriesByOrderId(orderId string) ([]dto.OrderStatusHistoryDTO, error)
 	ConvertToDTO(orderStatusHistory models.OrderStatusHistory) dto.OrderStatusHistoryDTO
+	WithTx(tx database.DatabaseInterface) OrderStatusHistoryServiceInterface
 }
 
 type orderStatusHistoryService struct {
@@ -30,6 +32,14 @@ func NewOrderStatusHistoryService(
 	}
 }
 
+// WithTx implements OrderStatusHistoryServiceInterface.
+func (s *orderStatusHistoryService) WithTx(tx database.DatabaseInterface) OrderStatusHistoryServiceInterface {
+	return &orderStatusHistoryService{
+		orderStatusHistoryRepository: s.orderStatusHistoryRepository.WithTx(tx),
+		orderStatusService:           s.orderStatusService,
+	}
+}
+
 func (s *orderStatusHistoryService) ConvertToDTO(orderStatusHistory models.OrderStatusHistory) (orderStatusHistoryDto dto.OrderStatusHistoryDTO) {
 
 	orderStatusHistoryDto.ID = orderStatusHistory.ID
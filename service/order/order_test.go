@@ -0,0 +1,93 @@
+package order_service
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+)
+
+// pricedOrder is the price of an order in the base currency, its charges and the rate of the currency it is paid in.
+type pricedOrder struct {
+	Price    OrderPrice
+	Charges  []dto.OrderChargeDTO
+	Currency string
+	Rate     float64
+}
+
+func (pricedOrder) Generate(r *rand.Rand, size int) reflect.Value {
+	var p pricedOrder
+
+	p.Price.Discount = money.New(0, money.BaseCurrency)
+	p.Price.TotalPrice = money.New(0, money.BaseCurrency)
+
+	for i := r.Intn(6); i >= 0; i-- {
+		var item dto.OrderItemDTO
+
+		item.Price = money.New(r.Int63n(10_000_000), money.BaseCurrency)
+		item.Discount = money.New(r.Int63n(item.Price.Amount+1), money.BaseCurrency)
+
+		p.Price.Items = append(p.Price.Items, item)
+		p.Price.Discount = p.Price.Discount.Add(item.Discount)
+		p.Price.TotalPrice = p.Price.TotalPrice.Add(item.Price).Sub(item.Discount)
+	}
+
+	for i := r.Intn(3); i > 0; i-- {
+		charge := dto.OrderChargeDTO{Name: "Shipping", Amount: money.New(r.Int63n(1_000_000), money.BaseCurrency)}
+
+		p.Charges = append(p.Charges, charge)
+		p.Price.TotalPrice = p.Price.TotalPrice.Add(charge.Amount)
+	}
+
+	// naira per unit, from a weak currency to a strong one
+	p.Currency = []string{"USD", "GBP", "GHS", "XOF"}[r.Intn(4)]
+	p.Rate = 0.01 + r.Float64()*2000
+
+	if r.Intn(5) == 0 {
+		p.Currency, p.Rate = money.BaseCurrency, 1
+	}
+
+	return reflect.ValueOf(p)
+}
+
+func TestConvertOrderPriceAddsUp(t *testing.T) {
+	property := func(p pricedOrder) bool {
+		price, charges := ConvertOrderPrice(p.Price, p.Charges, p.Currency, p.Rate)
+
+		if len(price.Items) != len(p.Price.Items) || len(charges) != len(p.Charges) {
+			return false
+		}
+
+		discount := money.New(0, p.Currency)
+		total := money.New(0, p.Currency)
+
+		// the money.Money arithmetic panics on a line left in another currency
+		for _, item := range price.Items {
+			if item.Discount.Amount > item.Price.Amount {
+				return false
+			}
+
+			discount = discount.Add(item.Discount)
+			total = total.Add(item.Price).Sub(item.Discount)
+		}
+
+		for _, charge := range charges {
+			total = total.Add(charge.Amount)
+		}
+
+		if price.Discount != discount || price.TotalPrice != total {
+			t.Logf("discount %v and total %v, the lines add up to %v and %v", price.Discount, price.TotalPrice, discount, total)
+			return false
+		}
+
+		// the base currency is not converted at all
+		return p.Currency != money.BaseCurrency || reflect.DeepEqual(price, p.Price)
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,418 @@
+package order_service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	payment_gateway_dto "github.com/developer-afo/instashop-ecommerce-api/dto/payment_gateway"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+	payment_gateway_service "github.com/developer-afo/instashop-ecommerce-api/service/finance/payment_gateway"
+)
+
+var (
+	RefundTransactionDescription = "Refund for order"
+	RefundTransactionShortDesc   = "refund"
+
+	ErrOrderNotPaid       = errors.New("order has not been paid for")
+	ErrNothingToRefund    = errors.New("there is nothing left to refund on this order")
+	ErrRefundExceedsOrder = errors.New("refund quantity is more than what is left on the order item")
+	ErrInvalidOrderItem   = errors.New("order item does not belong to this order")
+
+	ErrRefundExceedsGatewayPayment = errors.New("refund is more than what is left of the gateway payment, refund it to the wallet instead")
+)
+
+type RefundServiceInterface interface {
+	RefundOrder(orderId uuid.UUID, refund dto.CreateRefundDTO) (dto.RefundDTO, error)
+	CreateRefund(orderId uuid.UUID, refund dto.CreateRefundDTO) (dto.RefundDTO, error)
+	SendRefund(refundId uuid.UUID) (dto.RefundDTO, error)
+	FindRefundsByOrderId(orderId uuid.UUID) ([]dto.RefundDTO, error)
+	HandleRefundEvent(event payment_gateway_dto.WebhookEventDTO) error
+	WithTx(tx database.DatabaseInterface) RefundServiceInterface
+}
+
+type refundService struct {
+	database              database.DatabaseInterface
+	refundRepository      order_repository.RefundRepositoryInterface
+	orderRepository       order_repository.OrderRepositoryInterface
+	transactionService    finance_service.TransactionServiceInterface
+	walletService         finance_service.WalletServiceInterface
+	paymentGatewayService payment_gateway_service.PaymentGatewayServiceInterface
+}
+
+func NewRefundService(
+	database database.DatabaseInterface,
+	refundRepository order_repository.RefundRepositoryInterface,
+	orderRepository order_repository.OrderRepositoryInterface,
+	transactionService finance_service.TransactionServiceInterface,
+	walletService finance_service.WalletServiceInterface,
+	paymentGatewayService payment_gateway_service.PaymentGatewayServiceInterface,
+) RefundServiceInterface {
+	return &refundService{
+		database:              database,
+		refundRepository:      refundRepository,
+		orderRepository:       orderRepository,
+		transactionService:    transactionService,
+		walletService:         walletService,
+		paymentGatewayService: paymentGatewayService,
+	}
+}
+
+func (s *refundService) withTx(tx database.DatabaseInterface) *refundService {
+	txService := *s
+
+	txService.database = tx
+	txService.refundRepository = s.refundRepository.WithTx(tx)
+	txService.orderRepository = s.orderRepository.WithTx(tx)
+	txService.transactionService = s.transactionService.WithTx(tx)
+	txService.walletService = s.walletService.WithTx(tx)
+
+	return &txService
+}
+
+// WithTx implements RefundServiceInterface.
+func (s *refundService) WithTx(tx database.DatabaseInterface) RefundServiceInterface {
+	return s.withTx(tx)
+}
+
+func (s *refundService) ConvertToDTO(refund models.Refund) (refundDto dto.RefundDTO) {
+
+	refundDto.ID = refund.ID
+	refundDto.OrderUUID = refund.OrderID
+	refundDto.TransactionUUID = refund.TransactionID
+	refundDto.Amount = refund.Amount
+	refundDto.Status = refund.Status
+	refundDto.GatewayReference = refund.GatewayReference
+	refundDto.Reason = refund.Reason
+	for _, item := range refund.Items {
+		refundDto.Items = append(refundDto.Items, dto.RefundItemDTO{
+			DTO:             dto.DTO{ID: item.ID, CreatedAt: item.CreatedAt, UpdatedAt: item.UpdatedAt},
+			RefundUUID:      item.RefundID,
+			OrderItemUUID:   item.OrderItemID,
+			OrderChargeUUID: item.OrderChargeID,
+			Quantity:        item.Quantity,
+			Amount:          item.Amount,
+		})
+	}
+	refundDto.CreatedAt = refund.CreatedAt
+	refundDto.UpdatedAt = refund.UpdatedAt
+	refundDto.DeletedAt = refund.DeletedAt.Time
+
+	return refundDto
+}
+
+// RefundOrder implements RefundServiceInterface. The refund is created and committed, then sent.
+// The refund and its credit transaction are saved before the gateway is called, so money never leaves without a record.
+func (s *refundService) RefundOrder(orderId uuid.UUID, refundDto dto.CreateRefundDTO) (dto.RefundDTO, error) {
+	var refund dto.RefundDTO
+
+	err := s.database.Transaction(func(tx database.DatabaseInterface) error {
+		var err error
+
+		refund, err = s.withTx(tx).CreateRefund(orderId, refundDto)
+
+		return err
+	})
+
+	if err != nil {
+		return dto.RefundDTO{}, err
+	}
+
+	return s.SendRefund(refund.ID)
+}
+
+// CreateRefund implements RefundServiceInterface, s must be bound to a transaction as the order is locked.
+// Items without a quantity are refunded in full, and no items at all refunds whatever is left on the order,
+// charges included. Otherwise charges such as the shipping fee are only refunded when RefundCharges is set.
+// A refund to the wallet, and any refund of an order paid in full from the wallet, is processed straight away,
+// a refund to the gateway stays pending until SendRefund.
+func (s *refundService) CreateRefund(orderId uuid.UUID, refundDto dto.CreateRefundDTO) (dto.RefundDTO, error) {
+	refund, err := s.createRefund(orderId, refundDto)
+
+	if err != nil {
+		return dto.RefundDTO{}, err
+	}
+
+	return s.ConvertToDTO(refund), nil
+}
+
+// SendRefund implements RefundServiceInterface. It asks the gateway for a pending refund, other refunds are returned
+// as they are. A refund the gateway turns down is failed. A refund the gateway did not answer for may have been
+// made anyway, so it stays pending for the gateway's refund webhook instead of being made twice.
+func (s *refundService) SendRefund(refundId uuid.UUID) (dto.RefundDTO, error) {
+	refund, err := s.refundRepository.FindRefundById(refundId)
+
+	if err != nil || refund.Status != finance_service.RefundStatusPending {
+		return s.ConvertToDTO(refund), err
+	}
+
+	order, err := s.orderRepository.FindOrderById(refund.OrderID)
+
+	if err != nil {
+		return dto.RefundDTO{}, err
+	}
+
+	gatewayResp, err := s.paymentGatewayService.RefundPayment(payment_gateway_dto.RefundDTO{
+		Reference: order.Transaction.Reference,
+		Amount:    refund.Amount,
+	}, order.Transaction.Vendor)
+
+	if err != nil {
+		fmt.Printf("Refund %s is pending, the gateway did not answer: %s\n", refund.ID, err)
+
+		return s.ConvertToDTO(refund), nil
+	}
+
+	if !gatewayResp.Status {
+		if err := s.settleRefund(refund.ID, finance_service.RefundStatusFailed, ""); err != nil {
+			return dto.RefundDTO{}, err
+		}
+
+		return dto.RefundDTO{}, fmt.Errorf("refund failed: %s", gatewayResp.Message)
+	}
+
+	if err := s.settleRefund(refund.ID, gatewayResp.RefundStatus, gatewayResp.GatewayReference); err != nil {
+		return dto.RefundDTO{}, err
+	}
+
+	refund, err = s.refundRepository.FindRefundById(refund.ID)
+
+	return s.ConvertToDTO(refund), err
+}
+
+// createRefund locks the order so concurrent refunds cannot take more than was paid for.
+// Only an order that was paid for, and confirmed, can be refunded.
+func (s *refundService) createRefund(orderId uuid.UUID, refundDto dto.CreateRefundDTO) (models.Refund, error) {
+	var refund models.Refund
+
+	order, err := s.orderRepository.FindOrderByIdForUpdate(orderId)
+
+	if err != nil {
+		return refund, err
+	}
+
+	if order.Transaction.Status != finance_service.TransactionStatusSuccess || order.Status.ShortName == ORDER_PLACED {
+		return refund, ErrOrderNotPaid
+	}
+
+	refunded, err := s.refundRepository.RefundedQuantities(order.ID, finance_service.RefundStatusFailed)
+
+	if err != nil {
+		return refund, err
+	}
+
+	refundedCharges, err := s.refundRepository.RefundedCharges(order.ID, finance_service.RefundStatusFailed)
+
+	if err != nil {
+		return refund, err
+	}
+
+	requested := map[uuid.UUID]int{}
+	for _, item := range refundDto.Items {
+		requested[item.OrderItemUUID] += item.Quantity
+	}
+
+	for _, item := range order.OrderItems {
+		remaining := item.Quantity - refunded[item.ID]
+
+		quantity, ok := requested[item.ID]
+		delete(requested, item.ID)
+
+		if len(refundDto.Items) > 0 && !ok {
+			continue
+		}
+
+		if quantity == 0 {
+			quantity = remaining
+		}
+
+		if quantity > remaining {
+			return refund, ErrRefundExceedsOrder
+		}
+
+		if quantity <= 0 {
+			continue
+		}
+
+		// the item price is the line total, refund the share of what was paid after the coupon discount.
+		// The share of the quantities refunded before is taken off, so the refunds of a line add up to what was paid for it.
+		paid := item.Price.Sub(item.Discount)
+		amount := paid.Share(int64(refunded[item.ID]+quantity), int64(item.Quantity)).
+			Sub(paid.Share(int64(refunded[item.ID]), int64(item.Quantity)))
+
+		orderItemId := item.ID
+
+		refund.Items = append(refund.Items, models.RefundItem{
+			OrderItemID: &orderItemId,
+			Quantity:    quantity,
+			Amount:      amount,
+		})
+		refund.Amount = refund.Amount.Add(amount)
+	}
+
+	if len(requested) > 0 {
+		return refund, ErrInvalidOrderItem
+	}
+
+	if len(refundDto.Items) == 0 || refundDto.RefundCharges {
+		for _, charge := range order.Charges {
+			amount := charge.Amount.Sub(refundedCharges[charge.ID])
+
+			if !amount.IsPositive() {
+				continue
+			}
+
+			orderChargeId := charge.ID
+
+			refund.Items = append(refund.Items, models.RefundItem{
+				OrderChargeID: &orderChargeId,
+				Quantity:      1,
+				Amount:        amount,
+			})
+			refund.Amount = refund.Amount.Add(amount)
+		}
+	}
+
+	if len(refund.Items) == 0 {
+		return refund, ErrNothingToRefund
+	}
+
+	parentId := order.Transaction.ID
+	toWallet := refundDto.ToWallet || order.Transaction.Method == finance_service.TransactionMethodWallet
+
+	var credit dto.TransactionDTO
+
+	if toWallet {
+		// the wallet holds the base currency, the refund goes back at the rate the order was paid at
+		credit, err = s.walletService.Credit(dto.TransactionDTO{
+			UserID:      order.UserID,
+			Amount:      refund.Amount.Convert(money.BaseCurrency, 1/order.ExchangeRate),
+			Description: RefundTransactionDescription,
+			ShortDesc:   RefundTransactionShortDesc,
+			Status:      finance_service.TransactionStatusSuccess,
+			ParentID:    &parentId,
+		})
+	} else {
+		// part of the order was paid from the wallet, the gateway can only give back what it took
+		if order.WalletTransactionID != nil {
+			gatewayRefunded, err := s.refundRepository.RefundedByMethod(order.ID, finance_service.TransactionMethodGateway, finance_service.RefundStatusFailed)
+
+			if err != nil {
+				return refund, err
+			}
+
+			if refund.Amount.Amount > order.Transaction.Amount.Amount-gatewayRefunded.Amount {
+				return refund, ErrRefundExceedsGatewayPayment
+			}
+		}
+
+		credit, err = s.transactionService.CreateTransaction(dto.TransactionDTO{
+			UserID:      order.UserID,
+			Amount:      refund.Amount,
+			Type:        finance_service.TransactionTypeCredit,
+			Description: RefundTransactionDescription,
+			ShortDesc:   RefundTransactionShortDesc,
+			Status:      finance_service.TransactionStatusPending,
+			Method:      finance_service.TransactionMethodGateway,
+			Vendor:      order.Transaction.Vendor,
+			ParentID:    &parentId,
+		})
+	}
+
+	if err != nil {
+		return refund, err
+	}
+
+	refund.OrderID = order.ID
+	refund.Currency = order.Currency
+	refund.TransactionID = credit.ID
+	refund.Status = finance_service.RefundStatusPending
+
+	if toWallet {
+		refund.Status = finance_service.RefundStatusProcessed
+	}
+	refund.Reason = refundDto.Reason
+
+	refund, err = s.refundRepository.CreateRefund(refund)
+
+	return refund, err
+}
+
+// FindRefundsByOrderId implements RefundServiceInterface.
+func (s *refundService) FindRefundsByOrderId(orderId uuid.UUID) ([]dto.RefundDTO, error) {
+	refunds := []dto.RefundDTO{}
+
+	refundModels, err := s.refundRepository.FindRefundsByOrderId(orderId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, refund := range refundModels {
+		refunds = append(refunds, s.ConvertToDTO(refund))
+	}
+
+	return refunds, nil
+}
+
+// HandleRefundEvent implements RefundServiceInterface.
+// The refund is matched on the gateway's refund reference, or on the payment reference when the gateway does not send one.
+func (s *refundService) HandleRefundEvent(event payment_gateway_dto.WebhookEventDTO) error {
+	var refund models.Refund
+	var err error
+
+	if event.RefundReference != "" {
+		refund, err = s.refundRepository.FindRefundByGatewayReference(event.RefundReference)
+	}
+
+	// a refund the gateway did not answer for has no gateway reference yet
+	if event.RefundReference == "" || (errors.Is(err, gorm.ErrRecordNotFound) && event.Reference != "") {
+		refund, err = s.refundRepository.FindPendingRefundByPaymentReference(event.Reference)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return s.settleRefund(refund.ID, event.RefundStatus, event.RefundReference)
+}
+
+// settleRefund moves a pending refund to status and settles its credit transaction with it.
+// Refunds that already left pending are not changed.
+func (s *refundService) settleRefund(refundId uuid.UUID, status string, gatewayReference string) error {
+	return s.database.Transaction(func(tx database.DatabaseInterface) error {
+		txService := s.withTx(tx)
+
+		affected, err := txService.refundRepository.UpdateRefundStatus(refundId, finance_service.RefundStatusPending, status, gatewayReference)
+
+		if err != nil || affected == 0 {
+			return err
+		}
+
+		refund, err := txService.refundRepository.FindRefundById(refundId)
+
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case finance_service.RefundStatusProcessed:
+			_, err = txService.transactionService.ConfirmTransaction(refund.TransactionID.String())
+		case finance_service.RefundStatusFailed:
+			_, err = txService.transactionService.FailTransaction(refund.TransactionID.String())
+		}
+
+		if err == finance_service.ErrTransactionAlreadyProcessed {
+			return nil
+		}
+
+		return err
+	})
+}
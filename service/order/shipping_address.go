@@ -0,0 +1,250 @@
+package order_service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+)
+
+var (
+	ErrInvalidShippingAddress  = errors.New("city does not belong to the selected state")
+	ErrShippingAddressRequired = errors.New("a shipping address is required, add one or choose one for the order")
+)
+
+type ShippingAddressServiceInterface interface {
+	CreateShippingAddress(address dto.ShippingAddressDTO) (dto.ShippingAddressDTO, error)
+	FindShippingAddressesByUserId(userId uuid.UUID) ([]dto.ShippingAddressDTO, error)
+	FindUserShippingAddress(userId uuid.UUID, addressId uuid.UUID) (dto.ShippingAddressDTO, error)
+	FindOrderShippingAddress(userId uuid.UUID, addressId uuid.UUID) (dto.ShippingAddressDTO, error)
+	UpdateShippingAddress(address dto.ShippingAddressDTO) (dto.ShippingAddressDTO, error)
+	SetDefaultShippingAddress(userId uuid.UUID, addressId uuid.UUID) error
+	DeleteShippingAddress(userId uuid.UUID, addressId uuid.UUID) error
+	WithTx(tx database.DatabaseInterface) ShippingAddressServiceInterface
+}
+
+type shippingAddressService struct {
+	database                  database.DatabaseInterface
+	shippingAddressRepository order_repository.ShippingAddressRepositoryInterface
+	locationService           LocationServiceInterface
+}
+
+func NewShippingAddressService(
+	database database.DatabaseInterface,
+	shippingAddressRepository order_repository.ShippingAddressRepositoryInterface,
+	locationService LocationServiceInterface,
+) ShippingAddressServiceInterface {
+	return &shippingAddressService{
+		database:                  database,
+		shippingAddressRepository: shippingAddressRepository,
+		locationService:           locationService,
+	}
+}
+
+// WithTx implements ShippingAddressServiceInterface.
+func (s *shippingAddressService) WithTx(tx database.DatabaseInterface) ShippingAddressServiceInterface {
+	return &shippingAddressService{
+		database:                  tx,
+		shippingAddressRepository: s.shippingAddressRepository.WithTx(tx),
+		locationService:           s.locationService.WithTx(tx),
+	}
+}
+
+func (s *shippingAddressService) ConvertToDTO(address models.ShippingAddress) dto.ShippingAddressDTO {
+	var addressDto dto.ShippingAddressDTO
+
+	addressDto.ID = address.ID
+	addressDto.UserUUID = address.UserID
+	addressDto.FirstName = address.FirstName
+	addressDto.LastName = address.LastName
+	addressDto.Phone = address.Phone
+	addressDto.AlternatePhone = address.AlternatePhone
+	addressDto.Address = address.Address
+	addressDto.CityUUID = address.CityID
+	addressDto.StateUUID = address.StateID
+	addressDto.ClosestLandmark = address.ClosestLandmark
+	addressDto.IsDefault = address.IsDefault
+	addressDto.City.ID = address.City.ID
+	addressDto.City.StateUUID = address.City.StateID
+	addressDto.City.Name = address.City.Name
+	addressDto.City.Price = address.City.Price
+	addressDto.State.ID = address.State.ID
+	addressDto.State.CountryUUID = address.State.CountryID
+	addressDto.State.Name = address.State.Name
+	addressDto.State.Country.ID = address.State.Country.ID
+	addressDto.State.Country.Name = address.State.Country.Name
+	addressDto.CreatedAt = address.CreatedAt
+	addressDto.UpdatedAt = address.UpdatedAt
+
+	return addressDto
+}
+
+func (s *shippingAddressService) ConvertToModel(addressDto dto.ShippingAddressDTO) models.ShippingAddress {
+	var address models.ShippingAddress
+
+	address.ID = addressDto.ID
+	address.UserID = addressDto.UserUUID
+	address.FirstName = addressDto.FirstName
+	address.LastName = addressDto.LastName
+	address.Phone = addressDto.Phone
+	address.AlternatePhone = addressDto.AlternatePhone
+	address.Address = addressDto.Address
+	address.CityID = addressDto.CityUUID
+	address.StateID = addressDto.StateUUID
+	address.ClosestLandmark = addressDto.ClosestLandmark
+	address.IsDefault = addressDto.IsDefault
+
+	return address
+}
+
+// validateLocation checks the city exists and is in the state the address names.
+func (s *shippingAddressService) validateLocation(addressDto dto.ShippingAddressDTO) error {
+	city, err := s.locationService.FindCityById(addressDto.CityUUID)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidShippingAddress
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if city.StateUUID != addressDto.StateUUID {
+		return ErrInvalidShippingAddress
+	}
+
+	return nil
+}
+
+// CreateShippingAddress implements ShippingAddressServiceInterface.
+// A user's first address becomes their default.
+func (s *shippingAddressService) CreateShippingAddress(addressDto dto.ShippingAddressDTO) (dto.ShippingAddressDTO, error) {
+	var address models.ShippingAddress
+
+	if err := s.validateLocation(addressDto); err != nil {
+		return dto.ShippingAddressDTO{}, err
+	}
+
+	err := s.database.Transaction(func(tx database.DatabaseInterface) error {
+		repo := s.shippingAddressRepository.WithTx(tx)
+
+		_, err := repo.FindDefaultShippingAddress(addressDto.UserUUID)
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			addressDto.IsDefault = true
+		} else if err != nil {
+			return err
+		}
+
+		makeDefault := addressDto.IsDefault
+		addressDto.IsDefault = false
+
+		address, err = repo.CreateShippingAddress(s.ConvertToModel(addressDto))
+
+		if err != nil || !makeDefault {
+			return err
+		}
+
+		address.IsDefault = true
+
+		return repo.SetDefaultShippingAddress(address.UserID, address.ID)
+	})
+
+	if err != nil {
+		return dto.ShippingAddressDTO{}, err
+	}
+
+	return s.FindUserShippingAddress(address.UserID, address.ID)
+}
+
+// FindShippingAddressesByUserId implements ShippingAddressServiceInterface.
+func (s *shippingAddressService) FindShippingAddressesByUserId(userId uuid.UUID) ([]dto.ShippingAddressDTO, error) {
+	var addressDtos []dto.ShippingAddressDTO
+
+	addresses, err := s.shippingAddressRepository.FindShippingAddressesByUserId(userId)
+
+	for _, address := range addresses {
+		addressDtos = append(addressDtos, s.ConvertToDTO(address))
+	}
+
+	return addressDtos, err
+}
+
+// FindUserShippingAddress returns the address if it belongs to the user, gorm.ErrRecordNotFound otherwise.
+func (s *shippingAddressService) FindUserShippingAddress(userId uuid.UUID, addressId uuid.UUID) (dto.ShippingAddressDTO, error) {
+	address, err := s.shippingAddressRepository.FindShippingAddressById(addressId)
+
+	if err != nil {
+		return dto.ShippingAddressDTO{}, err
+	}
+
+	if address.UserID != userId {
+		return dto.ShippingAddressDTO{}, gorm.ErrRecordNotFound
+	}
+
+	return s.ConvertToDTO(address), nil
+}
+
+// FindOrderShippingAddress returns the address an order ships to, the user's default when addressId is uuid.Nil.
+func (s *shippingAddressService) FindOrderShippingAddress(userId uuid.UUID, addressId uuid.UUID) (dto.ShippingAddressDTO, error) {
+	if addressId != uuid.Nil {
+		address, err := s.FindUserShippingAddress(userId, addressId)
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.ShippingAddressDTO{}, ErrShippingAddressRequired
+		}
+
+		return address, err
+	}
+
+	address, err := s.shippingAddressRepository.FindDefaultShippingAddress(userId)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.ShippingAddressDTO{}, ErrShippingAddressRequired
+	}
+
+	return s.ConvertToDTO(address), err
+}
+
+// UpdateShippingAddress implements ShippingAddressServiceInterface.
+func (s *shippingAddressService) UpdateShippingAddress(addressDto dto.ShippingAddressDTO) (dto.ShippingAddressDTO, error) {
+	if _, err := s.FindUserShippingAddress(addressDto.UserUUID, addressDto.ID); err != nil {
+		return dto.ShippingAddressDTO{}, err
+	}
+
+	if err := s.validateLocation(addressDto); err != nil {
+		return dto.ShippingAddressDTO{}, err
+	}
+
+	if _, err := s.shippingAddressRepository.UpdateShippingAddress(s.ConvertToModel(addressDto)); err != nil {
+		return dto.ShippingAddressDTO{}, err
+	}
+
+	return s.FindUserShippingAddress(addressDto.UserUUID, addressDto.ID)
+}
+
+// SetDefaultShippingAddress implements ShippingAddressServiceInterface.
+func (s *shippingAddressService) SetDefaultShippingAddress(userId uuid.UUID, addressId uuid.UUID) error {
+	if _, err := s.FindUserShippingAddress(userId, addressId); err != nil {
+		return err
+	}
+
+	return s.database.Transaction(func(tx database.DatabaseInterface) error {
+		return s.shippingAddressRepository.WithTx(tx).SetDefaultShippingAddress(userId, addressId)
+	})
+}
+
+// DeleteShippingAddress implements ShippingAddressServiceInterface.
+// Orders keep their own copy of the address, so deleting it does not change them.
+func (s *shippingAddressService) DeleteShippingAddress(userId uuid.UUID, addressId uuid.UUID) error {
+	if _, err := s.FindUserShippingAddress(userId, addressId); err != nil {
+		return err
+	}
+
+	return s.shippingAddressRepository.DeleteShippingAddress(addressId)
+}
@@ -0,0 +1,202 @@
+package order_service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/database"
+	"github.com/developer-afo/instashop-ecommerce-api/lib/money"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+)
+
+var (
+	OrderChargeTypeShipping = "shipping"
+
+	ErrShippingTypeUnavailable = errors.New("shipping type is not available")
+)
+
+type ShippingTypeServiceInterface interface {
+	CreateShippingType(shippingType dto.ShippingTypeDTO) (dto.ShippingTypeDTO, error)
+	FindShippingTypeById(shippingTypeId uuid.UUID) (dto.ShippingTypeDTO, error)
+	FindAllShippingTypes(activeOnly bool) ([]dto.ShippingTypeDTO, error)
+	DefaultShippingType() (dto.ShippingTypeDTO, error)
+	UpdateShippingType(shippingType dto.ShippingTypeDTO) (dto.ShippingTypeDTO, error)
+	DeleteShippingType(shippingTypeId uuid.UUID) error
+	ShippingCharge(shippingTypeId uuid.UUID, stateId uuid.UUID, weight float64) (dto.OrderChargeDTO, error)
+	WithTx(tx database.DatabaseInterface) ShippingTypeServiceInterface
+}
+
+type shippingTypeService struct {
+	database               database.DatabaseInterface
+	shippingTypeRepository order_repository.ShippingTypeRepositoryInterface
+}
+
+func NewShippingTypeService(database database.DatabaseInterface, shippingTypeRepository order_repository.ShippingTypeRepositoryInterface) ShippingTypeServiceInterface {
+	return &shippingTypeService{database: database, shippingTypeRepository: shippingTypeRepository}
+}
+
+// WithTx implements ShippingTypeServiceInterface.
+func (s *shippingTypeService) WithTx(tx database.DatabaseInterface) ShippingTypeServiceInterface {
+	return &shippingTypeService{database: tx, shippingTypeRepository: s.shippingTypeRepository.WithTx(tx)}
+}
+
+func (s *shippingTypeService) ConvertToDTO(shippingType models.ShippingType) dto.ShippingTypeDTO {
+	var shippingTypeDto dto.ShippingTypeDTO
+
+	shippingTypeDto.ID = shippingType.ID
+	shippingTypeDto.Name = shippingType.Name
+	shippingTypeDto.Description = shippingType.Description
+	shippingTypeDto.Price = shippingType.Price
+	shippingTypeDto.PricePerKg = shippingType.PricePerKg
+	shippingTypeDto.IsActive = shippingType.IsActive
+	for _, rate := range shippingType.Rates {
+		shippingTypeDto.Rates = append(shippingTypeDto.Rates, dto.ShippingRateDTO{
+			DTO:              dto.DTO{ID: rate.ID, CreatedAt: rate.CreatedAt, UpdatedAt: rate.UpdatedAt},
+			ShippingTypeUUID: rate.ShippingTypeID,
+			StateUUID:        rate.StateID,
+			Price:            rate.Price,
+		})
+	}
+	shippingTypeDto.CreatedAt = shippingType.CreatedAt
+	shippingTypeDto.UpdatedAt = shippingType.UpdatedAt
+
+	return shippingTypeDto
+}
+
+func (s *shippingTypeService) ConvertToModel(shippingTypeDto dto.ShippingTypeDTO) models.ShippingType {
+	var shippingType models.ShippingType
+
+	shippingType.ID = shippingTypeDto.ID
+	shippingType.Name = shippingTypeDto.Name
+	shippingType.Description = shippingTypeDto.Description
+	shippingType.Price = shippingTypeDto.Price
+	shippingType.PricePerKg = shippingTypeDto.PricePerKg
+	shippingType.IsActive = shippingTypeDto.IsActive
+	for _, rate := range shippingTypeDto.Rates {
+		shippingType.Rates = append(shippingType.Rates, models.ShippingRate{
+			ShippingTypeID: shippingTypeDto.ID,
+			StateID:        rate.StateUUID,
+			Price:          rate.Price,
+		})
+	}
+
+	return shippingType
+}
+
+// CreateShippingType implements ShippingTypeServiceInterface.
+func (s *shippingTypeService) CreateShippingType(shippingTypeDto dto.ShippingTypeDTO) (dto.ShippingTypeDTO, error) {
+	shippingType, err := s.shippingTypeRepository.CreateShippingType(s.ConvertToModel(shippingTypeDto))
+
+	if err != nil {
+		return dto.ShippingTypeDTO{}, err
+	}
+
+	return s.ConvertToDTO(shippingType), nil
+}
+
+// FindShippingTypeById implements ShippingTypeServiceInterface.
+func (s *shippingTypeService) FindShippingTypeById(shippingTypeId uuid.UUID) (dto.ShippingTypeDTO, error) {
+	shippingType, err := s.shippingTypeRepository.FindShippingTypeById(shippingTypeId)
+
+	if err != nil {
+		return dto.ShippingTypeDTO{}, err
+	}
+
+	return s.ConvertToDTO(shippingType), nil
+}
+
+// FindAllShippingTypes implements ShippingTypeServiceInterface.
+func (s *shippingTypeService) FindAllShippingTypes(activeOnly bool) ([]dto.ShippingTypeDTO, error) {
+	shippingTypes := []dto.ShippingTypeDTO{}
+
+	shippingTypeModels, err := s.shippingTypeRepository.FindAllShippingTypes(activeOnly)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, shippingType := range shippingTypeModels {
+		shippingTypes = append(shippingTypes, s.ConvertToDTO(shippingType))
+	}
+
+	return shippingTypes, nil
+}
+
+// DefaultShippingType implements ShippingTypeServiceInterface.
+// It returns the cheapest active shipping type, the one an order is sent with when none is chosen.
+func (s *shippingTypeService) DefaultShippingType() (dto.ShippingTypeDTO, error) {
+	shippingTypes, err := s.FindAllShippingTypes(true)
+
+	if err != nil {
+		return dto.ShippingTypeDTO{}, err
+	}
+
+	if len(shippingTypes) == 0 {
+		return dto.ShippingTypeDTO{}, ErrShippingTypeUnavailable
+	}
+
+	return shippingTypes[0], nil
+}
+
+// UpdateShippingType implements ShippingTypeServiceInterface.
+// The type and its new rates are saved together.
+func (s *shippingTypeService) UpdateShippingType(shippingTypeDto dto.ShippingTypeDTO) (dto.ShippingTypeDTO, error) {
+	var shippingType models.ShippingType
+
+	err := s.database.Transaction(func(tx database.DatabaseInterface) error {
+		var err error
+
+		shippingType, err = s.shippingTypeRepository.WithTx(tx).UpdateShippingType(s.ConvertToModel(shippingTypeDto))
+
+		return err
+	})
+
+	if err != nil {
+		return dto.ShippingTypeDTO{}, err
+	}
+
+	return s.FindShippingTypeById(shippingType.ID)
+}
+
+// DeleteShippingType implements ShippingTypeServiceInterface.
+func (s *shippingTypeService) DeleteShippingType(shippingTypeId uuid.UUID) error {
+	return s.shippingTypeRepository.DeleteShippingType(shippingTypeId)
+}
+
+// ShippingCharge implements ShippingTypeServiceInterface.
+// It returns the order charge for sending weight kg to an address in stateId with the shipping type.
+func (s *shippingTypeService) ShippingCharge(shippingTypeId uuid.UUID, stateId uuid.UUID, weight float64) (dto.OrderChargeDTO, error) {
+	shippingType, err := s.FindShippingTypeById(shippingTypeId)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !shippingType.IsActive) {
+		return dto.OrderChargeDTO{}, ErrShippingTypeUnavailable
+	}
+
+	if err != nil {
+		return dto.OrderChargeDTO{}, err
+	}
+
+	return dto.OrderChargeDTO{
+		Type:   OrderChargeTypeShipping,
+		Name:   shippingType.Name,
+		Amount: ShippingFee(shippingType, stateId, weight),
+	}, nil
+}
+
+// ShippingFee is the state's rate, or the type's price when the state has none, plus PricePerKg for every kg.
+func ShippingFee(shippingType dto.ShippingTypeDTO, stateId uuid.UUID, weight float64) money.Money {
+	fee := shippingType.Price
+
+	for _, rate := range shippingType.Rates {
+		if rate.StateUUID == stateId {
+			fee = rate.Price
+			break
+		}
+	}
+
+	return fee.Add(shippingType.PricePerKg.Scale(weight))
+}
@@ -0,0 +1,152 @@
+package order_service
+
+import (
+	"fmt"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+)
+
+// OrderGuard must pass before a transition is allowed.
+type OrderGuard struct {
+	Name  string
+	Check func(o *orderService, order models.Order) error
+}
+
+// OrderTransitionHook runs after a transition, in the same database transaction as the status change.
+type OrderTransitionHook func(o *orderService, order models.Order) error
+
+type OrderTransition struct {
+	From   string
+	To     string
+	Guards []OrderGuard
+	Hooks  []OrderTransitionHook
+}
+
+// IllegalTransitionError is returned when an order cannot move to the requested status.
+type IllegalTransitionError struct {
+	From   string
+	To     string
+	Reason string
+}
+
+func (e *IllegalTransitionError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("order cannot move from %s to %s: %s", e.From, e.To, e.Reason)
+	}
+
+	return fmt.Sprintf("order cannot move from %s to %s", e.From, e.To)
+}
+
+var (
+	GuardPaymentConfirmed = OrderGuard{
+		Name: "payment_confirmed",
+		Check: func(o *orderService, order models.Order) error {
+			if order.Transaction.Status != finance_service.TransactionStatusSuccess {
+				return fmt.Errorf("payment is not confirmed")
+			}
+
+			return nil
+		},
+	}
+
+	// OrderTransitions lists every status change an order can go through. Anything else is illegal.
+	OrderTransitions = []OrderTransition{
+		{From: ORDER_PLACED, To: AWAITING_CONFIRMATION, Guards: []OrderGuard{GuardPaymentConfirmed}, Hooks: []OrderTransitionHook{sellOrderStock, confirmWalletPayment, notifyCustomer(OrderConfirmationEmail)}},
+		{From: ORDER_PLACED, To: CANCELLED, Hooks: []OrderTransitionHook{releaseOrderStock, failPendingPayment}},
+		{From: AWAITING_CONFIRMATION, To: ORDER_PROCESSING},
+		{From: AWAITING_CONFIRMATION, To: CANCELLED, Hooks: []OrderTransitionHook{releaseOrderStock}}, // CancelPaidOrder refunds the payment
+		{From: ORDER_PROCESSING, To: OUT_FOR_DELIVERY, Hooks: []OrderTransitionHook{notifyCustomer(OrderShippedEmail)}},
+		{From: OUT_FOR_DELIVERY, To: DELIVERED, Hooks: []OrderTransitionHook{notifyCustomer(OrderDeliveredEmail)}},
+	}
+)
+
+// findTransition returns the transition from one status to another, or an IllegalTransitionError.
+func findTransition(from string, to string) (OrderTransition, error) {
+	for _, transition := range OrderTransitions {
+		if transition.From == from && transition.To == to {
+			return transition, nil
+		}
+	}
+
+	return OrderTransition{}, &IllegalTransitionError{From: from, To: to}
+}
+
+// GetOrderTransitions implements OrderServiceInterface.
+func (o *orderService) GetOrderTransitions() []dto.OrderTransitionDTO {
+	var transitions []dto.OrderTransitionDTO
+
+	for _, transition := range OrderTransitions {
+		guards := []string{}
+
+		for _, guard := range transition.Guards {
+			guards = append(guards, guard.Name)
+		}
+
+		transitions = append(transitions, dto.OrderTransitionDTO{
+			From:   transition.From,
+			To:     transition.To,
+			Guards: guards,
+		})
+	}
+
+	return transitions
+}
+
+func (o *orderService) orderItems(order models.Order) []dto.OrderItemDTO {
+	var items []dto.OrderItemDTO
+
+	for _, item := range order.OrderItems {
+		items = append(items, o.orderItemService.ConvertToDTO(item))
+	}
+
+	return items
+}
+
+// releaseOrderStock gives the stock reserved at checkout back.
+func releaseOrderStock(o *orderService, order models.Order) error {
+	return o.inventoryService.ReleaseOrderItems(order.Reference, o.orderItems(order))
+}
+
+// sellOrderStock marks the stock reserved at checkout as sold.
+func sellOrderStock(o *orderService, order models.Order) error {
+	return o.inventoryService.SellOrderItems(order.Reference, o.orderItems(order))
+}
+
+// failPendingPayment stops a payment made after the order was cancelled from confirming it,
+// and gives back what was held in the wallet for it.
+func failPendingPayment(o *orderService, order models.Order) error {
+	_, err := o.transactionService.FailTransaction(order.TransactionID.String())
+
+	if err != nil && err != finance_service.ErrTransactionAlreadyProcessed {
+		return err
+	}
+
+	if order.WalletTransactionID == nil {
+		return nil
+	}
+
+	_, err = o.transactionService.FailTransaction(order.WalletTransactionID.String())
+
+	if err == finance_service.ErrTransactionAlreadyProcessed {
+		return nil
+	}
+
+	return err
+}
+
+// confirmWalletPayment spends what was held in the wallet for the order once the gateway paid the rest.
+func confirmWalletPayment(o *orderService, order models.Order) error {
+	if order.WalletTransactionID == nil {
+		return nil
+	}
+
+	_, err := o.transactionService.ConfirmTransaction(order.WalletTransactionID.String())
+
+	if err == finance_service.ErrTransactionAlreadyProcessed {
+		return nil
+	}
+
+	return err
+}
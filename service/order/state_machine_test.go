@@ -0,0 +1,107 @@
+package order_service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/developer-afo/instashop-ecommerce-api/dto"
+	"github.com/developer-afo/instashop-ecommerce-api/models"
+	order_repository "github.com/developer-afo/instashop-ecommerce-api/repository/order"
+	finance_service "github.com/developer-afo/instashop-ecommerce-api/service/finance"
+)
+
+var orderStatuses = []string{ONGOING, ORDER_PLACED, AWAITING_CONFIRMATION, ORDER_PROCESSING, OUT_FOR_DELIVERY, DELIVERED, CANCELLED}
+
+func TestOrderTransitions(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{ORDER_PLACED, AWAITING_CONFIRMATION}:     true,
+		{ORDER_PLACED, CANCELLED}:                 true,
+		{AWAITING_CONFIRMATION, ORDER_PROCESSING}: true,
+		{AWAITING_CONFIRMATION, CANCELLED}:        true,
+		{ORDER_PROCESSING, OUT_FOR_DELIVERY}:      true,
+		{OUT_FOR_DELIVERY, DELIVERED}:             true,
+	}
+
+	if len(OrderTransitions) != len(allowed) {
+		t.Errorf("%d transitions, want %d", len(OrderTransitions), len(allowed))
+	}
+
+	// every pair of statuses, going back and staying put included
+	for _, from := range orderStatuses {
+		for _, to := range orderStatuses {
+			_, err := findTransition(from, to)
+
+			if allowed[[2]string{from, to}] {
+				if err != nil {
+					t.Errorf("%s to %s: %v", from, to, err)
+				}
+
+				continue
+			}
+
+			var illegal *IllegalTransitionError
+
+			if !errors.As(err, &illegal) || illegal.From != from || illegal.To != to {
+				t.Errorf("%s to %s: got %v, want an IllegalTransitionError", from, to, err)
+			}
+		}
+	}
+}
+
+// fakeOrders returns the same order for every id.
+type fakeOrders struct {
+	order_repository.OrderRepositoryInterface
+
+	order models.Order
+}
+
+func (r *fakeOrders) FindOrderById(id uuid.UUID) (models.Order, error) {
+	return r.order, nil
+}
+
+// fakeStatuses returns a status with the short name it was given for every id.
+type fakeStatuses struct {
+	OrderStatusServiceInterface
+
+	shortName string
+}
+
+func (s *fakeStatuses) FindOrderStatusById(id uuid.UUID) (dto.OrderStatusDTO, error) {
+	return dto.OrderStatusDTO{ShortName: s.shortName}, nil
+}
+
+func TestPaymentConfirmedGuard(t *testing.T) {
+	tests := []struct {
+		payment string
+		err     bool
+	}{
+		{finance_service.TransactionStatusPending, true},
+		{finance_service.TransactionStatusFailed, true},
+		{finance_service.TransactionStatusSuccess, false},
+	}
+
+	for _, tt := range tests {
+		order := models.Order{Status: models.OrderStatus{ShortName: ORDER_PLACED}, Transaction: models.Transaction{Status: tt.payment}}
+
+		if err := GuardPaymentConfirmed.Check(nil, order); (err != nil) != tt.err {
+			t.Errorf("payment %s: got %v", tt.payment, err)
+		}
+
+		if !tt.err {
+			continue
+		}
+
+		// the guard stops the order before its status is changed
+		o := &orderService{orderRepository: &fakeOrders{order: order}, orderStatusService: &fakeStatuses{shortName: AWAITING_CONFIRMATION}}
+
+		var illegal *IllegalTransitionError
+
+		err := o.updateOrderStatus(uuid.New(), uuid.New())
+
+		if !errors.As(err, &illegal) || illegal.From != ORDER_PLACED || illegal.To != AWAITING_CONFIRMATION || illegal.Reason != "payment is not confirmed" {
+			t.Errorf("payment %s: got %v", tt.payment, err)
+		}
+	}
+}
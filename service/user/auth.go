@@ -103,6 +103,7 @@ func (service *authService) Login(email, password string) (dto.LoginResponseDTO,
 	tokenDto := dto.LoginResponseDTO{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
+		UserID:       user.ID,
 	}
 
 	return tokenDto, constants.SuccessOperationCompleted, nil
@@ -16,6 +16,7 @@ import (
 var (
 	UserRoleCustomer = "customer"
 	UserRoleAdmin    = "admin"
+	UserRoleFinance  = "finance"
 )
 
 type userService struct {
@@ -29,6 +30,7 @@ type UserServiceInterface interface {
 	FindUserByEmail(email string) (userDto.UserDTO, error)
 	FindUserByReferralCode(referralCode string) (userDto.UserDTO, error)
 	UpdateUser(dto userDto.UserDTO) (userDto.UserDTO, error)
+	UpdateNotificationPreferences(userId uuid.UUID, notifyOrderUpdates bool) (userDto.UserDTO, error)
 	DeleteUser(uuid uuid.UUID) error
 	ConvertToDTO(user models.User) (userDto userDto.UserDTO)
 	ConvertToModel(userDto userDto.UserDTO) (user models.User)
@@ -47,6 +49,7 @@ func (service *userService) ConvertToDTO(user models.User) (userDto userDto.User
 	userDto.IsEmailVerified = user.IsEmailVerified
 	userDto.Password = user.Password
 	userDto.Role = user.Role
+	userDto.NotifyOrderUpdates = user.NotifyOrderUpdates
 	userDto.CreatedAt = user.CreatedAt
 	userDto.UpdatedAt = user.UpdatedAt
 	userDto.DeletedAt = user.DeletedAt.Time
@@ -63,6 +66,7 @@ func (service *userService) ConvertToModel(userDto userDto.UserDTO) (user models
 	user.IsEmailVerified = userDto.IsEmailVerified
 	user.Password = userDto.Password
 	user.Role = userDto.Role
+	user.NotifyOrderUpdates = userDto.NotifyOrderUpdates
 	user.CreatedAt = userDto.CreatedAt
 	user.UpdatedAt = userDto.UpdatedAt
 	user.DeletedAt.Time = userDto.DeletedAt
@@ -139,6 +143,14 @@ func (service *userService) UpdateUser(userDto userDto.UserDTO) (userDto.UserDTO
 	return service.ConvertToDTO(updatedRecord), err
 }
 
+// UpdateNotificationPreferences implements UserServiceInterface.
+func (service *userService) UpdateNotificationPreferences(userId uuid.UUID, notifyOrderUpdates bool) (userDto.UserDTO, error) {
+
+	user, err := service.userRepository.UpdateNotificationPreferences(userId, notifyOrderUpdates)
+
+	return service.ConvertToDTO(user), err
+}
+
 // DeleteUser implements UserServiceInterface.
 func (service *userService) DeleteUser(uuid uuid.UUID) error {
 	return service.userRepository.DeleteUser(uuid)
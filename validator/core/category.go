@@ -0,0 +1,26 @@
+package core_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type CategoryValidator struct {
+	validator.Validator[request.CreateCategoryRequest]
+}
+
+func (validator *CategoryValidator) CategoryValidate(req request.CreateCategoryRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Name, validation.Required, validation.Length(2, 100)),
+		validation.Field(&req.ParentID, is.UUID),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
@@ -2,6 +2,7 @@ package core_validator
 
 import (
 	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
 
 	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
 	"github.com/developer-afo/instashop-ecommerce-api/validator"
@@ -18,8 +19,11 @@ func (validator *ProductValidator) CreateProductValidate(req request.CreateProdu
 		validation.Field(&req.Specification, validation.Required),
 		validation.Field(&req.Price, validation.Required, validation.Min(0)),
 		validation.Field(&req.Stock, validation.Required, validation.Min(0)),
+		validation.Field(&req.Brand, validation.Length(0, 255)),
+		validation.Field(&req.Weight, validation.Min(0.0)),
 		validation.Field(&req.SlashPrice, validation.Max(req.Price)),
 		validation.Field(&req.Images, validation.Required, validation.Each(validation.Required, validation.Length(3, 100))),
+		validation.Field(&req.CategoryIDs, validation.Each(is.UUID)),
 	)
 
 	if err != nil {
@@ -36,7 +40,10 @@ func (validator *ProductValidator) UpdateProductValidate(req request.UpdateProdu
 		validation.Field(&req.Specification, validation.Required),
 		validation.Field(&req.Price, validation.Required, validation.Min(0)), // TODO: add is.Int validation on fields like this
 		validation.Field(&req.Stock, validation.Min(0)),
+		validation.Field(&req.Brand, validation.Length(0, 255)),
+		validation.Field(&req.Weight, validation.Min(0.0)),
 		validation.Field(&req.SlashPrice, validation.Max(req.Price)),
+		validation.Field(&req.CategoryIDs, validation.Each(is.UUID)),
 	)
 
 	if err != nil {
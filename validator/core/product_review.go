@@ -0,0 +1,37 @@
+package core_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type ProductReviewValidator struct {
+	validator.Validator[request.CreateProductReviewRequest]
+}
+
+func (validator *ProductReviewValidator) CreateReviewValidate(req request.CreateProductReviewRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Rating, validation.Required, validation.Min(1), validation.Max(5)),
+		validation.Field(&req.Comment, validation.Length(0, 2000)),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
+
+func (validator *ProductReviewValidator) ModerateReviewValidate(req request.ModerateProductReviewRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.IsHidden, validation.NotNil),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,61 @@
+package core_validator
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type ProductVariantValidator struct {
+	validator.Validator[request.CreateProductVariantRequest]
+}
+
+func (validator *ProductVariantValidator) CreateOptionValidate(req request.CreateProductOptionRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Name, validation.Required, validation.Length(1, 50)),
+		validation.Field(&req.Position, validation.Min(0)),
+		validation.Field(&req.Values, validation.Required, validation.Each(validation.Required, validation.Length(1, 50))),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
+
+func (validator *ProductVariantValidator) VariantValidate(req request.CreateProductVariantRequest) (map[string]interface{}, error) {
+	slashPriceRules := []validation.Rule{}
+
+	// a variant without its own price sells at the product's prices, slash price included
+	if req.SlashPrice != nil {
+		slashPriceRules = append(slashPriceRules, validation.Min(0.0))
+
+		if req.Price == nil {
+			slashPriceRules = append(slashPriceRules, validation.By(func(interface{}) error {
+				return errors.New("must be blank when the variant has no price")
+			}))
+		} else {
+			slashPriceRules = append(slashPriceRules, validation.Max(*req.Price))
+		}
+	}
+
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.SKU, validation.Required, validation.Length(1, 64)),
+		validation.Field(&req.Price, validation.Min(0.0)),
+		validation.Field(&req.SlashPrice, slashPriceRules...),
+		validation.Field(&req.Stock, validation.Min(0)),
+		validation.Field(&req.OptionValueIDs, validation.Required, validation.Each(validation.Required, is.UUID)),
+		validation.Field(&req.Images, validation.Each(validation.Required, validation.Length(3, 100))),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,27 @@
+package finance_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+var currencyCode = validator.CurrencyCode
+
+type CurrencyValidator struct {
+	validator.Validator[request.SetCurrencyRateRequest]
+}
+
+func (validator *CurrencyValidator) SetCurrencyRateValidate(req request.SetCurrencyRateRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Currency, validation.Required, currencyCode),
+		validation.Field(&req.Rate, validation.Required, validation.Min(0.0)),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
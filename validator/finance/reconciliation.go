@@ -0,0 +1,25 @@
+package finance_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type ReconciliationValidator struct {
+	validator.Validator[request.ReconcileRequest]
+}
+
+func (validator *ReconciliationValidator) ReconcileValidate(req request.ReconcileRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.FromDate, validation.Required, validation.Date("2006-01-02")),
+		validation.Field(&req.ToDate, validation.Required, validation.Date("2006-01-02")),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
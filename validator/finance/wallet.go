@@ -0,0 +1,25 @@
+package finance_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type WalletValidator struct {
+	validator.Validator[request.WalletTopUpRequest]
+}
+
+func (validator *WalletValidator) TopUpValidate(req request.WalletTopUpRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Amount, validation.Required, validation.Min(0.0)),
+		validation.Field(&req.PaymentMethod, validation.Required),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
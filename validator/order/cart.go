@@ -0,0 +1,39 @@
+package order_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type CartValidator struct {
+	validator.Validator[request.AddCartItemRequest]
+}
+
+func (validator *CartValidator) AddCartItemValidate(req request.AddCartItemRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.ProductID, validation.Required, is.UUID),
+		validation.Field(&req.VariantID, is.UUID),
+		validation.Field(&req.Quantity, validation.Required, validation.Min(1)),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
+
+func (validator *CartValidator) UpdateCartItemValidate(req request.UpdateCartItemRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Quantity, validation.Min(0)),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,38 @@
+package order_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type CouponValidator struct {
+	validator.Validator[request.CreateCouponRequest]
+}
+
+func (validator *CouponValidator) CouponValidate(req request.CreateCouponRequest) (map[string]interface{}, error) {
+	valueRules := []validation.Rule{validation.Required, validation.Min(0.01)}
+
+	// a percentage coupon cannot take more than the whole price
+	if req.Type == "percentage" {
+		valueRules = append(valueRules, validation.Max(100.0))
+	}
+
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Code, validation.Required, validation.Length(3, 50), is.Alphanumeric),
+		validation.Field(&req.Type, validation.Required, validation.In("percentage", "fixed")),
+		validation.Field(&req.Value, valueRules...),
+		validation.Field(&req.MinOrderAmount, validation.Min(0.0)),
+		validation.Field(&req.UsageLimit, validation.Min(0)),
+		validation.Field(&req.UsageLimitPerUser, validation.Min(0)),
+		validation.Field(&req.ProductIDs, validation.Each(is.UUID)),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,75 @@
+package order_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type LocationValidator struct {
+	validator.Validator[request.CreateLocationRequest]
+}
+
+func (validator *LocationValidator) CountryValidate(req request.CreateCountryRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Name, validation.Required, validation.Length(2, 100)),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
+
+func (validator *LocationValidator) StateValidate(req request.CreateStateRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Name, validation.Required, validation.Length(2, 100)),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
+
+func (validator *LocationValidator) CityValidate(req request.CreateCityRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Name, validation.Required, validation.Length(2, 100)),
+		validation.Field(&req.Price, validation.Min(0.0)),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
+
+func (validator *LocationValidator) CreateLocationValidate(req request.CreateLocationRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.CountryID, validation.Required, is.UUID),
+		validation.Field(&req.States, validation.Required),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	for _, state := range req.States {
+		if vEs, err := validator.StateValidate(request.CreateStateRequest{Name: state.Name}); err != nil {
+			return vEs, err
+		}
+
+		for _, city := range state.Cities {
+			if vEs, err := validator.CityValidate(request.CreateCityRequest{Name: city.Name, Price: city.Price}); err != nil {
+				return vEs, err
+			}
+		}
+	}
+
+	return nil, nil
+}
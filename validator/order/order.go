@@ -2,19 +2,40 @@ package order_validator
 
 import (
 	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
 
 	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
 	"github.com/developer-afo/instashop-ecommerce-api/validator"
 )
 
+var currencyCode = validator.CurrencyCode
+
 type OrderValidator struct {
 	validator.Validator[request.CreateOrderRequest]
 }
 
 func (validator *OrderValidator) CreateOrderValidate(req request.CreateOrderRequest) (map[string]interface{}, error) {
+	itemsRules := []validation.Rule{validation.Each(validation.Required, validation.By(validateOrderItem))}
+
+	// an order from the cart takes its items from the cart
+	if !req.FromCart {
+		itemsRules = append(itemsRules, validation.Required)
+	}
+
+	paymentMethodRules := []validation.Rule{}
+
+	// the wallet may pay for the whole order, checkout tells when it does not
+	if req.WalletAmount == 0 {
+		paymentMethodRules = append(paymentMethodRules, validation.Required)
+	}
+
 	err := validation.ValidateStruct(&req,
-		validation.Field(&req.PaymentMethod, validation.Required),
-		validation.Field(&req.Items, validation.Required, validation.Each(validation.Required)),
+		validation.Field(&req.PaymentMethod, paymentMethodRules...),
+		validation.Field(&req.WalletAmount, validation.Min(0.0)),
+		validation.Field(&req.ShippingAddressID, is.UUID),
+		validation.Field(&req.ShippingTypeID, is.UUID),
+		validation.Field(&req.Currency, currencyCode),
+		validation.Field(&req.Items, itemsRules...),
 	)
 
 	if err != nil {
@@ -23,3 +44,25 @@ func (validator *OrderValidator) CreateOrderValidate(req request.CreateOrderRequ
 
 	return nil, nil
 }
+
+func (validator *OrderValidator) CancelOrderValidate(req request.CancelOrderRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Reason, validation.Length(0, 500)),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
+
+func validateOrderItem(value interface{}) error {
+	item, _ := value.(request.CreateOrderRequestItem)
+
+	return validation.ValidateStruct(&item,
+		validation.Field(&item.ProductID, validation.Required),
+		validation.Field(&item.VariantID, is.UUID),
+		validation.Field(&item.Quantity, validation.Required, validation.Min(1)),
+	)
+}
@@ -0,0 +1,34 @@
+package order_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type RefundValidator struct {
+	validator.Validator[request.CreateRefundRequest]
+}
+
+func (validator *RefundValidator) CreateRefundValidate(req request.CreateRefundRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Reason, validation.Length(0, 500)),
+		validation.Field(&req.Items, validation.Each(validation.By(validateRefundItem))),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
+
+func validateRefundItem(value interface{}) error {
+	item, _ := value.(request.CreateRefundRequestItem)
+
+	return validation.ValidateStruct(&item,
+		validation.Field(&item.OrderItemID, validation.Required),
+		validation.Field(&item.Quantity, validation.Min(0)),
+	)
+}
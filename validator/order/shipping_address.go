@@ -0,0 +1,32 @@
+package order_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type ShippingAddressValidator struct {
+	validator.Validator[request.CreateShippingAddressRequest]
+}
+
+func (validator *ShippingAddressValidator) ShippingAddressValidate(req request.CreateShippingAddressRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.FirstName, validation.Required, validation.Length(2, 100)),
+		validation.Field(&req.LastName, validation.Required, validation.Length(2, 100)),
+		validation.Field(&req.Phone, validation.Required, validation.Length(7, 20)),
+		validation.Field(&req.AlternatePhone, validation.Length(7, 20)),
+		validation.Field(&req.Address, validation.Required, validation.Length(5, 500)),
+		validation.Field(&req.CityID, validation.Required, is.UUID),
+		validation.Field(&req.StateID, validation.Required, is.UUID),
+		validation.Field(&req.ClosestLandmark, validation.Length(0, 255)),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
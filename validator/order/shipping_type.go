@@ -0,0 +1,37 @@
+package order_validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+	"github.com/developer-afo/instashop-ecommerce-api/validator"
+)
+
+type ShippingTypeValidator struct {
+	validator.Validator[request.CreateShippingTypeRequest]
+}
+
+func (validator *ShippingTypeValidator) ShippingTypeValidate(req request.CreateShippingTypeRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.Name, validation.Required, validation.Length(2, 255)),
+		validation.Field(&req.Price, validation.Min(0.0)),
+		validation.Field(&req.PricePerKg, validation.Min(0.0)),
+		validation.Field(&req.Rates, validation.Each(validation.By(validateShippingRate))),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
+
+func validateShippingRate(value interface{}) error {
+	rate, _ := value.(request.CreateShippingRateRequest)
+
+	return validation.ValidateStruct(&rate,
+		validation.Field(&rate.StateID, validation.Required, is.UUID),
+		validation.Field(&rate.Price, validation.Min(0.0)),
+	)
+}
@@ -0,0 +1,23 @@
+package validator
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+
+	"github.com/developer-afo/instashop-ecommerce-api/payload/request"
+)
+
+type UserValidator struct {
+	Validator[request.NotificationPreferencesRequest]
+}
+
+func (validator *UserValidator) NotificationPreferencesValidate(req request.NotificationPreferencesRequest) (map[string]interface{}, error) {
+	err := validation.ValidateStruct(&req,
+		validation.Field(&req.NotifyOrderUpdates, validation.NotNil),
+	)
+
+	if err != nil {
+		return validator.ValidateErr(err)
+	}
+
+	return nil, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"reflect"
+	"regexp"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/google/uuid"
@@ -14,6 +15,9 @@ import (
 
 type Validator[T any] struct{}
 
+// CurrencyCode checks a currency is written as an ISO 4217 code, like NGN or usd.
+var CurrencyCode = validation.Match(regexp.MustCompile(`^[A-Za-z]{3}$`)).Error("must be a three letter currency code")
+
 func (validator *Validator[T]) ValidateDBUnique(structure T, tableName string, uniqueField string, parameters map[string]interface{}) validation.RuleFunc {
 	db := database.DatabaseFacade
 	result := map[string]interface{}{}